@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// An ExternalOperationType is a type of operation performed on an external
+// resource.
+type ExternalOperationType string
+
+// Types of operation performed on an external resource.
+const (
+	ExternalOperationCreate ExternalOperationType = "Create"
+	ExternalOperationUpdate ExternalOperationType = "Update"
+	ExternalOperationDelete ExternalOperationType = "Delete"
+)
+
+// An ExternalOperationResult is the result of an operation performed on an
+// external resource.
+type ExternalOperationResult string
+
+// Results of an operation performed on an external resource.
+const (
+	ExternalOperationSucceeded ExternalOperationResult = "Succeeded"
+	ExternalOperationFailed    ExternalOperationResult = "Failed"
+)
+
+// An ExternalOperation records an operation performed on an external
+// resource.
+type ExternalOperation struct {
+	// Type of the operation.
+	Type ExternalOperationType `json:"type"`
+
+	// Time at which the operation was performed.
+	Time v1.Time `json:"time"`
+
+	// Result of the operation.
+	Result ExternalOperationResult `json:"result"`
+
+	// Digest of the outcome of the operation; of the error returned by a
+	// failed operation, or of the connection details returned by a
+	// successful operation. Digests may be compared to determine whether two
+	// operations had the same outcome without revealing sensitive details.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// An AuditStatus records the operations most recently performed on a managed
+// resource's external resource.
+type AuditStatus struct {
+	// LastExternalOperation is the operation that was most recently performed
+	// on the managed resource's external resource.
+	// +optional
+	LastExternalOperation *ExternalOperation `json:"lastExternalOperation,omitempty"`
+}
+
+// SetAuditStatus sets the audit status of the resource.
+func (s *AuditStatus) SetAuditStatus(a AuditStatus) {
+	*s = a
+}
+
+// GetAuditStatus gets the audit status of the resource.
+func (s *AuditStatus) GetAuditStatus() AuditStatus {
+	return *s
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A BackoffStatus represents the state of a managed resource's reconcile
+// backoff. It is populated while a managed resource is failing to reconcile,
+// and cleared once it reconciles successfully.
+type BackoffStatus struct {
+	// NextReconcileTime is the time at which the managed resource is next
+	// expected to be reconciled after a failure.
+	// +optional
+	NextReconcileTime *v1.Time `json:"nextReconcileTime,omitempty"`
+
+	// BackoffStep is the number of consecutive times the managed resource
+	// has failed to reconcile. The delay before the next reconcile grows
+	// with each step.
+	// +optional
+	BackoffStep int32 `json:"backoffStep,omitempty"`
+}
+
+// SetBackoffStatus sets the backoff status of the resource.
+func (s *BackoffStatus) SetBackoffStatus(b BackoffStatus) {
+	*s = b
+}
+
+// GetBackoffStatus gets the backoff status of the resource.
+func (s *BackoffStatus) GetBackoffStatus() BackoffStatus {
+	return *s
+}
@@ -33,6 +33,10 @@ const (
 	AnnotationDefaultClassValue = "true"
 )
 
+// LabelKeyProviderName is added to usages of a provider. Its value is the
+// name of the provider that is in use.
+const LabelKeyProviderName = "crossplane.io/provider-name"
+
 const (
 	// ResourceCredentialsSecretEndpointKey is the key inside a connection secret for the connection endpoint
 	ResourceCredentialsSecretEndpointKey = "endpoint"
@@ -83,6 +87,174 @@ type SecretKeySelector struct {
 	Key string `json:"key"`
 }
 
+// ConnectionSecretMetadata is the metadata of a connection secret published to
+// a secret store.
+type ConnectionSecretMetadata struct {
+	// Labels are the labels, or tags, to be added to the connection secret.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the annotations to be added to the connection secret.
+	// They are ignored by secret stores that do not support annotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Type is the type of the connection secret. It is only applicable to
+	// Kubernetes secret stores.
+	// +optional
+	Type *corev1.SecretType `json:"type,omitempty"`
+}
+
+// PublishConnectionDetailsTo specifies a connection secret, and the secret
+// store to which it should be published.
+type PublishConnectionDetailsTo struct {
+	// Name of the connection secret.
+	Name string `json:"name"`
+
+	// Metadata to be added to the connection secret.
+	// +optional
+	Metadata *ConnectionSecretMetadata `json:"metadata,omitempty"`
+
+	// Templates of additional connection details to derive from those
+	// published by the resource, for example a JDBC URL assembled from its
+	// endpoint, port, username, and password.
+	// +optional
+	Templates []ConnectionDetailTemplate `json:"templates,omitempty"`
+
+	// Format of the connection secret. Connection details are written as
+	// individual keys of the connection secret by default.
+	// +optional
+	Format *ConnectionSecretFormat `json:"format,omitempty"`
+
+	// SecretStoreConfigRef references the secret store config to which the
+	// connection secret should be published. The store config named "default"
+	// is used if no reference is supplied.
+	// +optional
+	SecretStoreConfigRef *Reference `json:"configRef,omitempty"`
+}
+
+// A ConnectionDetailTemplate derives a connection detail from other connection
+// details.
+type ConnectionDetailTemplate struct {
+	// Key of the derived connection detail.
+	Key string `json:"key"`
+
+	// Template from which the connection detail is derived. Templates use Go
+	// text/template syntax, and may reference other connection details by key,
+	// for example: jdbc:postgresql://{{ .endpoint }}:{{ .port }}/postgres
+	Template string `json:"template"`
+}
+
+// A ConnectionSecretFormatType determines how connection details are written to
+// a connection secret.
+type ConnectionSecretFormatType string
+
+// Connection secret format types.
+const (
+	// ConnectionSecretFormatKeys writes each connection detail to its own key.
+	ConnectionSecretFormatKeys ConnectionSecretFormatType = "Keys"
+
+	// ConnectionSecretFormatJSON writes all connection details to a single
+	// key, as a JSON object.
+	ConnectionSecretFormatJSON ConnectionSecretFormatType = "JSON"
+
+	// ConnectionSecretFormatEnv writes all connection details to a single
+	// key, as an environment file of KEY=value lines.
+	ConnectionSecretFormatEnv ConnectionSecretFormatType = "Env"
+)
+
+// A ConnectionSecretFormat specifies how connection details are written to a
+// connection secret.
+type ConnectionSecretFormat struct {
+	// Type of format.
+	// +kubebuilder:validation:Enum=Keys;JSON;Env
+	Type ConnectionSecretFormatType `json:"type"`
+
+	// Key to which connection details are written when they are written to a
+	// single key. Defaults to connection.json for the JSON format, and to
+	// connection.env for the Env format.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// A SecretStoreType identifies a kind of secret store.
+type SecretStoreType string
+
+// Secret store types.
+const (
+	// SecretStoreKubernetes stores connection secrets as Kubernetes Secrets.
+	SecretStoreKubernetes SecretStoreType = "Kubernetes"
+
+	// SecretStoreAWSSecretsManager stores connection secrets in AWS Secrets
+	// Manager.
+	SecretStoreAWSSecretsManager SecretStoreType = "AWSSecretsManager"
+)
+
+// A SecretStoreConfig configures a secret store to which connection secrets
+// may be published.
+type SecretStoreConfig struct {
+	// Type of the secret store. Only the configuration for this type of store
+	// is used; configuration for other types is ignored. Defaults to
+	// Kubernetes.
+	// +optional
+	// +kubebuilder:validation:Enum=Kubernetes;AWSSecretsManager
+	Type *SecretStoreType `json:"type,omitempty"`
+
+	// DefaultScope of connection secrets published by cluster scoped
+	// resources. For Kubernetes secret stores this is the namespace to which
+	// their connection secrets are written. For AWS Secrets Manager it is the
+	// prefix of their secret names.
+	DefaultScope string `json:"defaultScope"`
+
+	// Kubernetes configures a Kubernetes secret store.
+	// +optional
+	Kubernetes *KubernetesSecretStoreConfig `json:"kubernetes,omitempty"`
+
+	// AWSSecretsManager configures an AWS Secrets Manager secret store.
+	// +optional
+	AWSSecretsManager *AWSSecretsManagerStoreConfig `json:"awsSecretsManager,omitempty"`
+}
+
+// A KubernetesSecretStoreConfig configures a Kubernetes secret store.
+type KubernetesSecretStoreConfig struct {
+	// Namespace to which all connection secrets are written, for example a
+	// namespace dedicated to a team's credentials. By default connection
+	// secrets are written to the namespace of the resource that publishes
+	// them, or to the default scope if that resource is cluster scoped.
+	// Ownership of connection secrets is tracked using labels, so resources in
+	// different namespaces cannot overwrite one another's connection secrets.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// An AWSSecretsManagerStoreConfig configures an AWS Secrets Manager secret
+// store.
+type AWSSecretsManagerStoreConfig struct {
+	// Region in which connection secrets are stored.
+	Region string `json:"region"`
+
+	// KMSKeyID is the ARN, key ID, or alias of the KMS key used to encrypt
+	// connection secrets. The AWS managed key aws/secretsmanager is used if no
+	// key is specified.
+	// +optional
+	KMSKeyID *string `json:"kmsKeyID,omitempty"`
+
+	// CredentialsSecretRef references a Secret containing the access_key_id,
+	// secret_access_key, and optionally session_token used to authenticate to
+	// AWS. The default AWS credential chain, for example an IAM role for the
+	// service account, is used if no Secret is referenced.
+	// +optional
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// GetType returns the type of the secret store, defaulting to Kubernetes.
+func (c SecretStoreConfig) GetType() SecretStoreType {
+	if c.Type == nil {
+		return SecretStoreKubernetes
+	}
+	return *c.Type
+}
+
 // A TypedReference refers to an object by Name, Kind, and APIVersion. It is
 // commonly used to reference cluster-scoped objects or objects where the
 // namespace is already known.
@@ -114,6 +286,115 @@ func (obj *TypedReference) GroupVersionKind() schema.GroupVersionKind {
 // GetObjectKind get the ObjectKind of a TypedReference.
 func (obj *TypedReference) GetObjectKind() schema.ObjectKind { return obj }
 
+// A ResolvePolicy specifies when a reference should be resolved.
+type ResolvePolicy string
+
+// Resolve policies.
+const (
+	// ResolvePolicyIfNotPresent resolves a reference only if the field it
+	// resolves is not already set. This is the default.
+	ResolvePolicyIfNotPresent ResolvePolicy = "IfNotPresent"
+
+	// ResolvePolicyAlways resolves a reference every time the referencing
+	// resource is reconciled, overwriting any value previously resolved.
+	ResolvePolicyAlways ResolvePolicy = "Always"
+)
+
+// A ResolutionPolicy specifies whether a reference must be resolved.
+type ResolutionPolicy string
+
+// Resolution policies.
+const (
+	// ResolutionPolicyRequired blocks reconciliation of the referencing
+	// resource until the reference can be resolved. This is the default.
+	ResolutionPolicyRequired ResolutionPolicy = "Required"
+
+	// ResolutionPolicyOptional allows the referencing resource to be
+	// reconciled even if the reference cannot be resolved.
+	ResolutionPolicyOptional ResolutionPolicy = "Optional"
+)
+
+// A Policy specifies how a reference or selector should be resolved.
+type Policy struct {
+	// Resolve specifies when this reference should be resolved. The default
+	// is 'IfNotPresent', which will attempt to resolve the reference only when
+	// the corresponding field is not present. Use 'Always' to resolve the
+	// reference on every reconcile.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent
+	Resolve *ResolvePolicy `json:"resolve,omitempty"`
+
+	// Resolution specifies whether resolution of this reference is required.
+	// The default is 'Required', which means the reconcile will fail if the
+	// reference cannot be resolved. 'Optional' means this reference will be
+	// a no-op if it cannot be resolved.
+	// +optional
+	// +kubebuilder:validation:Enum=Required;Optional
+	Resolution *ResolutionPolicy `json:"resolution,omitempty"`
+}
+
+// IsResolvePolicyAlways returns true if the resolve policy is Always.
+func (p *Policy) IsResolvePolicyAlways() bool {
+	if p == nil || p.Resolve == nil {
+		return false
+	}
+	return *p.Resolve == ResolvePolicyAlways
+}
+
+// IsResolutionPolicyOptional returns true if the resolution policy is
+// Optional.
+func (p *Policy) IsResolutionPolicyOptional() bool {
+	if p == nil || p.Resolution == nil {
+		return false
+	}
+	return *p.Resolution == ResolutionPolicyOptional
+}
+
+// A Reference to a named object.
+type Reference struct {
+	// Name of the referenced object.
+	Name string `json:"name"`
+
+	// Namespace of the referenced object. Defaults to the namespace of the
+	// referencing object. Referencing an object in another namespace must be
+	// explicitly allowed by the resolver.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Policies for resolution of this reference.
+	// +optional
+	Policy *Policy `json:"policy,omitempty"`
+}
+
+// A ProviderUsage indicates that a resource is using a provider.
+type ProviderUsage struct {
+	// ProviderReference to the provider being used.
+	ProviderReference Reference `json:"providerRef"`
+
+	// ResourceReference to the resource that is using the provider.
+	ResourceReference TypedReference `json:"resourceRef"`
+}
+
+// A Selector selects an object.
+type Selector struct {
+	// MatchLabels ensures an object with matching labels is selected.
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// MatchControllerRef ensures an object with the same controller reference
+	// as the selecting object is selected.
+	MatchControllerRef *bool `json:"matchControllerRef,omitempty"`
+
+	// Namespace in which to select an object. Defaults to the namespace of the
+	// selecting object. Selecting an object in another namespace must be
+	// explicitly allowed by the resolver.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Policies for selection.
+	// +optional
+	Policy *Policy `json:"policy,omitempty"`
+}
+
 // A ResourceClaimSpec defines the desired state of a resource claim.
 type ResourceClaimSpec struct {
 	// WriteConnectionSecretToReference specifies the name of a Secret, in the
@@ -165,6 +446,13 @@ type ResourceSpec struct {
 	// +optional
 	WriteConnectionSecretToReference *SecretReference `json:"writeConnectionSecretToRef,omitempty"`
 
+	// PublishConnectionDetailsTo specifies the connection secret config, and
+	// the secret store config to which any connection details for this managed
+	// resource should be published. It may be used in addition to, or instead
+	// of, WriteConnectionSecretToReference.
+	// +optional
+	PublishConnectionDetailsTo *PublishConnectionDetailsTo `json:"publishConnectionDetailsTo,omitempty"`
+
 	// ClaimReference specifies the resource claim to which this managed
 	// resource will be bound. ClaimReference is set automatically during
 	// dynamic provisioning. Crossplane does not currently support setting this
@@ -179,9 +467,18 @@ type ResourceSpec struct {
 	// +optional
 	ClassReference *corev1.ObjectReference `json:"classRef,omitempty"`
 
+	// ProviderConfigReference specifies how the provider that will be used to
+	// create, observe, update, and delete this managed resource should be
+	// configured. It supersedes ProviderReference.
+	// +optional
+	ProviderConfigReference *Reference `json:"providerConfigRef,omitempty"`
+
 	// ProviderReference specifies the provider that will be used to create,
 	// observe, update, and delete this managed resource.
-	ProviderReference *corev1.ObjectReference `json:"providerRef"`
+	//
+	// Deprecated: Use ProviderConfigReference.
+	// +optional
+	ProviderReference *corev1.ObjectReference `json:"providerRef,omitempty"`
 
 	// ReclaimPolicy specifies what will happen to this managed resource when
 	// its resource claim is deleted, and what will happen to the underlying
@@ -202,6 +499,8 @@ type ResourceSpec struct {
 type ResourceStatus struct {
 	ConditionedStatus `json:",inline"`
 	BindingStatus     `json:",inline"`
+	BackoffStatus     `json:",inline"`
+	AuditStatus       `json:",inline"`
 }
 
 // A ClassSpecTemplate defines a template that will be used to create the
@@ -234,6 +533,66 @@ type ClassSpecTemplate struct {
 	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
 }
 
+// A CredentialsSource is a source from which provider credentials may be
+// acquired.
+type CredentialsSource string
+
+// Sources from which provider credentials may be acquired.
+const (
+	// CredentialsSourceNone indicates that a provider does not require
+	// credentials.
+	CredentialsSourceNone CredentialsSource = "None"
+
+	// CredentialsSourceSecret indicates that a provider should acquire
+	// credentials from a secret.
+	CredentialsSourceSecret CredentialsSource = "Secret"
+
+	// CredentialsSourceInjectedIdentity indicates that a provider should use
+	// credentials via its (pod's) identity; i.e. via IRSA for AWS, Workload
+	// Identity for GCP, Pod Identity for Azure, or in-cluster authentication
+	// for the Kubernetes API.
+	CredentialsSourceInjectedIdentity CredentialsSource = "InjectedIdentity"
+
+	// CredentialsSourceEnvironment indicates that a provider should acquire
+	// credentials from an environment variable.
+	CredentialsSourceEnvironment CredentialsSource = "Environment"
+
+	// CredentialsSourceFilesystem indicates that a provider should acquire
+	// credentials from the filesystem.
+	CredentialsSourceFilesystem CredentialsSource = "Filesystem"
+)
+
+// CommonCredentialSelectors provides common selectors for extracting
+// credentials.
+type CommonCredentialSelectors struct {
+	// Fs is a reference to a filesystem location that contains credentials that
+	// must be used to connect to the provider.
+	// +optional
+	Fs *FsSelector `json:"fs,omitempty"`
+
+	// Env is a reference to an environment variable that contains credentials
+	// that must be used to connect to the provider.
+	// +optional
+	Env *EnvSelector `json:"env,omitempty"`
+
+	// A SecretRef is a reference to a secret key that contains the credentials
+	// that must be used to connect to the provider.
+	// +optional
+	SecretRef *SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// EnvSelector selects an environment variable.
+type EnvSelector struct {
+	// Name is the name of an environment variable.
+	Name string `json:"name"`
+}
+
+// FsSelector selects a filesystem location.
+type FsSelector struct {
+	// Path is a filesystem path.
+	Path string `json:"path"`
+}
+
 // A ProviderSpec defines the common way to get to the necessary objects to connect
 // to the provider.
 type ProviderSpec struct {
@@ -25,6 +25,70 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSecretsManagerStoreConfig) DeepCopyInto(out *AWSSecretsManagerStoreConfig) {
+	*out = *in
+	if in.KMSKeyID != nil {
+		in, out := &in.KMSKeyID, &out.KMSKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSecretsManagerStoreConfig.
+func (in *AWSSecretsManagerStoreConfig) DeepCopy() *AWSSecretsManagerStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSecretsManagerStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditStatus) DeepCopyInto(out *AuditStatus) {
+	*out = *in
+	if in.LastExternalOperation != nil {
+		in, out := &in.LastExternalOperation, &out.LastExternalOperation
+		*out = new(ExternalOperation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditStatus.
+func (in *AuditStatus) DeepCopy() *AuditStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackoffStatus) DeepCopyInto(out *BackoffStatus) {
+	*out = *in
+	if in.NextReconcileTime != nil {
+		in, out := &in.NextReconcileTime, &out.NextReconcileTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackoffStatus.
+func (in *BackoffStatus) DeepCopy() *BackoffStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackoffStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingStatus) DeepCopyInto(out *BindingStatus) {
 	*out = *in
@@ -60,6 +124,36 @@ func (in *ClassSpecTemplate) DeepCopy() *ClassSpecTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonCredentialSelectors) DeepCopyInto(out *CommonCredentialSelectors) {
+	*out = *in
+	if in.Fs != nil {
+		in, out := &in.Fs, &out.Fs
+		*out = new(FsSelector)
+		**out = **in
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(EnvSelector)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonCredentialSelectors.
+func (in *CommonCredentialSelectors) DeepCopy() *CommonCredentialSelectors {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonCredentialSelectors)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -98,6 +192,131 @@ func (in *ConditionedStatus) DeepCopy() *ConditionedStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDetailTemplate) DeepCopyInto(out *ConnectionDetailTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetailTemplate.
+func (in *ConnectionDetailTemplate) DeepCopy() *ConnectionDetailTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDetailTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionSecretFormat) DeepCopyInto(out *ConnectionSecretFormat) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionSecretFormat.
+func (in *ConnectionSecretFormat) DeepCopy() *ConnectionSecretFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionSecretFormat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionSecretMetadata) DeepCopyInto(out *ConnectionSecretMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(corev1.SecretType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionSecretMetadata.
+func (in *ConnectionSecretMetadata) DeepCopy() *ConnectionSecretMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionSecretMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvSelector) DeepCopyInto(out *EnvSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvSelector.
+func (in *EnvSelector) DeepCopy() *EnvSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalOperation) DeepCopyInto(out *ExternalOperation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalOperation.
+func (in *ExternalOperation) DeepCopy() *ExternalOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FsSelector) DeepCopyInto(out *FsSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FsSelector.
+func (in *FsSelector) DeepCopy() *FsSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(FsSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesSecretStoreConfig) DeepCopyInto(out *KubernetesSecretStoreConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesSecretStoreConfig.
+func (in *KubernetesSecretStoreConfig) DeepCopy() *KubernetesSecretStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesSecretStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
 	*out = *in
@@ -113,6 +332,31 @@ func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Policy) DeepCopyInto(out *Policy) {
+	*out = *in
+	if in.Resolve != nil {
+		in, out := &in.Resolve, &out.Resolve
+		*out = new(ResolvePolicy)
+		**out = **in
+	}
+	if in.Resolution != nil {
+		in, out := &in.Resolution, &out.Resolution
+		*out = new(ResolutionPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
+func (in *Policy) DeepCopy() *Policy {
+	if in == nil {
+		return nil
+	}
+	out := new(Policy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
@@ -133,6 +377,78 @@ func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderUsage) DeepCopyInto(out *ProviderUsage) {
+	*out = *in
+	in.ProviderReference.DeepCopyInto(&out.ProviderReference)
+	out.ResourceReference = in.ResourceReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderUsage.
+func (in *ProviderUsage) DeepCopy() *ProviderUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishConnectionDetailsTo) DeepCopyInto(out *PublishConnectionDetailsTo) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(ConnectionSecretMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]ConnectionDetailTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(ConnectionSecretFormat)
+		**out = **in
+	}
+	if in.SecretStoreConfigRef != nil {
+		in, out := &in.SecretStoreConfigRef, &out.SecretStoreConfigRef
+		*out = new(Reference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishConnectionDetailsTo.
+func (in *PublishConnectionDetailsTo) DeepCopy() *PublishConnectionDetailsTo {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishConnectionDetailsTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Reference) DeepCopyInto(out *Reference) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(Policy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Reference.
+func (in *Reference) DeepCopy() *Reference {
+	if in == nil {
+		return nil
+	}
+	out := new(Reference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceClaimSpec) DeepCopyInto(out *ResourceClaimSpec) {
 	*out = *in
@@ -193,6 +509,11 @@ func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
 		*out = new(SecretReference)
 		**out = **in
 	}
+	if in.PublishConnectionDetailsTo != nil {
+		in, out := &in.PublishConnectionDetailsTo, &out.PublishConnectionDetailsTo
+		*out = new(PublishConnectionDetailsTo)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClaimReference != nil {
 		in, out := &in.ClaimReference, &out.ClaimReference
 		*out = new(corev1.ObjectReference)
@@ -203,6 +524,11 @@ func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.ProviderConfigReference != nil {
+		in, out := &in.ProviderConfigReference, &out.ProviderConfigReference
+		*out = new(Reference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ProviderReference != nil {
 		in, out := &in.ProviderReference, &out.ProviderReference
 		*out = new(corev1.ObjectReference)
@@ -225,6 +551,8 @@ func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 	out.BindingStatus = in.BindingStatus
+	in.BackoffStatus.DeepCopyInto(&out.BackoffStatus)
+	in.AuditStatus.DeepCopyInto(&out.AuditStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
@@ -268,6 +596,68 @@ func (in *SecretReference) DeepCopy() *SecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreConfig) DeepCopyInto(out *SecretStoreConfig) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(SecretStoreType)
+		**out = **in
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesSecretStoreConfig)
+		**out = **in
+	}
+	if in.AWSSecretsManager != nil {
+		in, out := &in.AWSSecretsManager, &out.AWSSecretsManager
+		*out = new(AWSSecretsManagerStoreConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreConfig.
+func (in *SecretStoreConfig) DeepCopy() *SecretStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Selector) DeepCopyInto(out *Selector) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchControllerRef != nil {
+		in, out := &in.MatchControllerRef, &out.MatchControllerRef
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(Policy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Selector.
+func (in *Selector) DeepCopy() *Selector {
+	if in == nil {
+		return nil
+	}
+	out := new(Selector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetSpec) DeepCopyInto(out *TargetSpec) {
 	*out = *in
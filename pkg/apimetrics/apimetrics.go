@@ -0,0 +1,187 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apimetrics records metrics about the calls providers make to
+// external APIs, so that error rates and rate limit exhaustion are visible
+// before they break reconciliation.
+package apimetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Codes recorded for calls that did not return an API specific error code.
+const (
+	CodeOK    = "OK"
+	CodeError = "Error"
+)
+
+// A Call made to an external API.
+type Call struct {
+	// Service that was called, for example "ec2" or "sqladmin".
+	Service string
+
+	// Operation that was called, for example "DescribeInstances".
+	Operation string
+
+	// Duration of the call.
+	Duration time.Duration
+
+	// Code returned by the call. CodeOK if the call succeeded.
+	Code string
+
+	// Throttled is true if the call was rejected because a rate limit was
+	// exceeded.
+	Throttled bool
+}
+
+// A Recorder records calls made to external APIs.
+type Recorder interface {
+	Record(c Call)
+}
+
+// A RecorderFn is a function that satisfies the Recorder interface.
+type RecorderFn func(c Call)
+
+// Record calls RecorderFn.
+func (fn RecorderFn) Record(c Call) {
+	fn(c)
+}
+
+// NewNopRecorder returns a Recorder that does nothing.
+func NewNopRecorder() Recorder {
+	return RecorderFn(func(_ Call) {})
+}
+
+// An ErrorClassifier returns the code of the supplied error, and whether it
+// indicates that a call was throttled. Providers typically supply a classifier
+// that understands the errors returned by their cloud provider's SDK.
+type ErrorClassifier func(err error) (code string, throttled bool)
+
+// DefaultErrorClassifier returns CodeOK if the supplied error is nil, or
+// CodeError if it is not. It never considers a call to be throttled.
+func DefaultErrorClassifier(err error) (string, bool) {
+	if err != nil {
+		return CodeError, false
+	}
+	return CodeOK, false
+}
+
+// A Timer times calls made to external APIs, and records them to a Recorder.
+type Timer struct {
+	recorder Recorder
+	classify ErrorClassifier
+}
+
+// A TimerOption configures a Timer.
+type TimerOption func(*Timer)
+
+// WithErrorClassifier specifies how a Timer should determine the code of a
+// call, and whether the call was throttled.
+func WithErrorClassifier(fn ErrorClassifier) TimerOption {
+	return func(t *Timer) {
+		t.classify = fn
+	}
+}
+
+// NewTimer returns a Timer that records calls to the supplied Recorder.
+func NewTimer(r Recorder, o ...TimerOption) *Timer {
+	t := &Timer{recorder: r, classify: DefaultErrorClassifier}
+	for _, fn := range o {
+		fn(t)
+	}
+	return t
+}
+
+// Start timing a call to the supplied operation of the supplied service. The
+// returned function must be called with the error returned by the call, if
+// any, once it completes. For example:
+//
+//	done := t.Start("ec2", "DescribeInstances")
+//	out, err := client.DescribeInstances(in)
+//	done(err)
+func (t *Timer) Start(service, operation string) func(err error) {
+	started := time.Now()
+	return func(err error) {
+		code, throttled := t.classify(err)
+		t.recorder.Record(Call{
+			Service:   service,
+			Operation: operation,
+			Duration:  time.Since(started),
+			Code:      code,
+			Throttled: throttled,
+		})
+	}
+}
+
+// A PrometheusRecorder records calls made to external APIs as Prometheus
+// metrics. It must be registered with a Prometheus registry, for example
+// controller-runtime's metrics.Registry, to be exported.
+type PrometheusRecorder struct {
+	calls     *prometheus.CounterVec
+	throttled *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+}
+
+// NewPrometheusRecorder returns a new PrometheusRecorder.
+func NewPrometheusRecorder() *PrometheusRecorder {
+	return &PrometheusRecorder{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "crossplane",
+			Subsystem: "external_api",
+			Name:      "calls_total",
+			Help:      "The number of calls made to external APIs.",
+		}, []string{"service", "operation", "code"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "crossplane",
+			Subsystem: "external_api",
+			Name:      "throttled_calls_total",
+			Help:      "The number of calls made to external APIs that were throttled.",
+		}, []string{"service", "operation"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "crossplane",
+			Subsystem: "external_api",
+			Name:      "call_duration_seconds",
+			Help:      "The time taken by calls made to external APIs.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"service", "operation"}),
+	}
+}
+
+// Record the supplied call.
+func (r *PrometheusRecorder) Record(c Call) {
+	r.calls.WithLabelValues(c.Service, c.Operation, c.Code).Inc()
+	r.duration.WithLabelValues(c.Service, c.Operation).Observe(c.Duration.Seconds())
+	if c.Throttled {
+		r.throttled.WithLabelValues(c.Service, c.Operation).Inc()
+	}
+}
+
+// Describe sends the descriptors of each metric to the supplied channel.
+func (r *PrometheusRecorder) Describe(ch chan<- *prometheus.Desc) {
+	r.calls.Describe(ch)
+	r.throttled.Describe(ch)
+	r.duration.Describe(ch)
+}
+
+// Collect sends each metric to the supplied channel.
+func (r *PrometheusRecorder) Collect(ch chan<- prometheus.Metric) {
+	r.calls.Collect(ch)
+	r.throttled.Collect(ch)
+	r.duration.Collect(ch)
+}
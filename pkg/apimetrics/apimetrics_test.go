@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apimetrics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestTimer(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		o      []TimerOption
+		err    error
+		want   Call
+	}{
+		"Success": {
+			reason: "A successful call should be recorded with CodeOK",
+			want:   Call{Service: "ec2", Operation: "DescribeInstances", Code: CodeOK},
+		},
+		"Error": {
+			reason: "A failed call should be recorded with CodeError by default",
+			err:    errBoom,
+			want:   Call{Service: "ec2", Operation: "DescribeInstances", Code: CodeError},
+		},
+		"Throttled": {
+			reason: "A failed call should be classified by the supplied ErrorClassifier",
+			o: []TimerOption{WithErrorClassifier(func(err error) (string, bool) {
+				return "Throttling", true
+			})},
+			err:  errBoom,
+			want: Call{Service: "ec2", Operation: "DescribeInstances", Code: "Throttling", Throttled: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got Call
+			tm := NewTimer(RecorderFn(func(c Call) { got = c }), tc.o...)
+			tm.Start("ec2", "DescribeInstances")(tc.err)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(Call{}, "Duration")); diff != "" {
+				t.Errorf("\n%s\ntm.Start(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrometheusRecorder(t *testing.T) {
+	r := NewPrometheusRecorder()
+	r.Record(Call{Service: "ec2", Operation: "DescribeInstances", Code: CodeOK})
+	r.Record(Call{Service: "ec2", Operation: "DescribeInstances", Code: "Throttling", Throttled: true})
+	r.Record(Call{Service: "ec2", Operation: "DescribeInstances", Code: "Throttling", Throttled: true})
+
+	cases := map[string]struct {
+		reason string
+		got    float64
+		want   float64
+	}{
+		"Calls": {
+			reason: "Successful calls should be counted by code",
+			got:    testutil.ToFloat64(r.calls.WithLabelValues("ec2", "DescribeInstances", CodeOK)),
+			want:   1,
+		},
+		"ErrorCalls": {
+			reason: "Failed calls should be counted by code",
+			got:    testutil.ToFloat64(r.calls.WithLabelValues("ec2", "DescribeInstances", "Throttling")),
+			want:   2,
+		},
+		"Throttled": {
+			reason: "Throttled calls should be counted",
+			got:    testutil.ToFloat64(r.throttled.WithLabelValues("ec2", "DescribeInstances")),
+			want:   2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("\n%s\n-want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certificates loads TLS certificate material and builds TLS
+// configurations from it, for use when connecting to external secret stores
+// and other out-of-cluster services.
+package certificates
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Keys of a Secret containing TLS certificate material. These match the keys
+// used by Secrets of type kubernetes.io/tls.
+const (
+	KeyCA   = "ca.crt"
+	KeyCert = "tls.crt"
+	KeyKey  = "tls.key"
+)
+
+// Error strings.
+const (
+	errGetSecret     = "cannot get TLS certificate secret"
+	errReadCA        = "cannot read CA certificate file"
+	errReadCert      = "cannot read certificate file"
+	errReadKey       = "cannot read key file"
+	errStatFile      = "cannot stat certificate file"
+	errParseCA       = "cannot parse CA certificate"
+	errParseKeyPair  = "cannot parse certificate and key"
+	errNoCertificate = "a certificate and key are required to serve TLS"
+)
+
+// Material is PEM encoded TLS certificate material. Any field may be empty.
+type Material struct {
+	// CA is the PEM encoded certificate of the certificate authority used to
+	// verify peers.
+	CA []byte
+
+	// Cert is the PEM encoded certificate presented to peers.
+	Cert []byte
+
+	// Key is the PEM encoded private key of Cert.
+	Key []byte
+}
+
+// FromSecret loads certificate material from the referenced Secret.
+func FromSecret(ctx context.Context, c client.Reader, ref v1alpha1.SecretReference) (*Material, error) {
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(err, errGetSecret)
+	}
+	return &Material{CA: s.Data[KeyCA], Cert: s.Data[KeyCert], Key: s.Data[KeyKey]}, nil
+}
+
+// Files are the paths of files containing PEM encoded certificate material.
+// Empty paths are ignored.
+type Files struct {
+	CA   string
+	Cert string
+	Key  string
+}
+
+func (f Files) paths() []string {
+	p := make([]string, 0, 3)
+	for _, path := range []string{f.CA, f.Cert, f.Key} {
+		if path != "" {
+			p = append(p, path)
+		}
+	}
+	return p
+}
+
+// FromFiles loads certificate material from the supplied files.
+func FromFiles(f Files) (*Material, error) {
+	m := &Material{}
+	var err error
+	if f.CA != "" {
+		if m.CA, err = ioutil.ReadFile(f.CA); err != nil {
+			return nil, errors.Wrap(err, errReadCA)
+		}
+	}
+	if f.Cert != "" {
+		if m.Cert, err = ioutil.ReadFile(f.Cert); err != nil {
+			return nil, errors.Wrap(err, errReadCert)
+		}
+	}
+	if f.Key != "" {
+		if m.Key, err = ioutil.ReadFile(f.Key); err != nil {
+			return nil, errors.Wrap(err, errReadKey)
+		}
+	}
+	return m, nil
+}
+
+func (m *Material) pool() (*x509.CertPool, error) {
+	if len(m.CA) == 0 {
+		return nil, nil
+	}
+	p := x509.NewCertPool()
+	if !p.AppendCertsFromPEM(m.CA) {
+		return nil, errors.New(errParseCA)
+	}
+	return p, nil
+}
+
+func (m *Material) certificate() (*tls.Certificate, error) {
+	if len(m.Cert) == 0 && len(m.Key) == 0 {
+		return nil, nil
+	}
+	c, err := tls.X509KeyPair(m.Cert, m.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseKeyPair)
+	}
+	return &c, nil
+}
+
+// ClientConfig returns a TLS configuration suitable for connecting to a server.
+// The server is verified using the CA certificate, if any, or else the system
+// certificate pool. The certificate and key, if any, are presented to the
+// server.
+func (m *Material) ClientConfig() (*tls.Config, error) {
+	pool, err := m.pool()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := m.certificate()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool}
+	if cert != nil {
+		cfg.Certificates = []tls.Certificate{*cert}
+	}
+	return cfg, nil
+}
+
+// ServerConfig returns a TLS configuration suitable for serving TLS. A
+// certificate and key are required. Clients must present a certificate signed
+// by the CA certificate, if any.
+func (m *Material) ServerConfig() (*tls.Config, error) {
+	pool, err := m.pool()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := m.certificate()
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, errors.New(errNoCertificate)
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{*cert}}
+	if pool != nil {
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// A Reloader loads certificate material from files, reloading it whenever any
+// of the files are modified. This allows certificates that are rotated on
+// disk, for example by updating a mounted Secret, to be used without
+// restarting.
+type Reloader struct {
+	files Files
+
+	mu       sync.Mutex
+	modified map[string]time.Time
+	material *Material
+}
+
+// NewReloader returns a Reloader that loads certificate material from the
+// supplied files. An error is returned if the files cannot be loaded.
+func NewReloader(f Files) (*Reloader, error) {
+	r := &Reloader{files: f}
+	_, err := r.Material()
+	return r, err
+}
+
+// Material returns the current certificate material, reloading it from disk
+// if any of its files have been modified since it was last loaded.
+func (r *Reloader) Material() (*Material, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modified := make(map[string]time.Time, len(r.modified))
+	changed := r.material == nil
+	for _, p := range r.files.paths() {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, errors.Wrap(err, errStatFile)
+		}
+		modified[p] = fi.ModTime()
+		if !fi.ModTime().Equal(r.modified[p]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return r.material, nil
+	}
+
+	m, err := FromFiles(r.files)
+	if err != nil {
+		return nil, err
+	}
+
+	// We only record the new modification times once we've successfully
+	// loaded the material, so that we retry if it was partially written.
+	r.material, r.modified = m, modified
+	return m, nil
+}
+
+// ClientConfig returns a TLS configuration suitable for connecting to a
+// server. The client certificate is reloaded whenever it changes on disk. The
+// CA certificate is loaded when the configuration is built; callers that wish
+// to honour CA rotation should call ClientConfig for each new connection.
+func (r *Reloader) ClientConfig() (*tls.Config, error) {
+	m, err := r.Material()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := m.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Certificates) == 0 {
+		return cfg, nil
+	}
+	cfg.Certificates = nil
+	cfg.GetClientCertificate = func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		m, err := r.Material()
+		if err != nil {
+			return nil, err
+		}
+		return m.certificate()
+	}
+	return cfg, nil
+}
+
+// ServerConfig returns a TLS configuration suitable for serving TLS. Both the
+// certificate and the CA certificate are reloaded whenever they change on
+// disk.
+func (r *Reloader) ServerConfig() (*tls.Config, error) {
+	m, err := r.Material()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.ServerConfig(); err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+			m, err := r.Material()
+			if err != nil {
+				return nil, err
+			}
+			return m.ServerConfig()
+		},
+	}
+	return cfg, nil
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// selfSigned returns a PEM encoded self-signed certificate and its key.
+func selfSigned(t *testing.T, cn string) (cert, key []byte) {
+	t.Helper()
+
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(1 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &k.PublicKey, k)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	}
+	kder, err := x509.MarshalECPrivateKey(k)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey(...): %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+}
+
+func commonName(t *testing.T, c *tls.Certificate) string {
+	t.Helper()
+	x, err := x509.ParseCertificate(c.Certificate[0])
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate(...): %v", err)
+	}
+	return x.Subject.CommonName
+}
+
+func TestFromSecret(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		m   *Material
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		c      *test.MockClient
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting the secret should be returned",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errGetSecret),
+			},
+		},
+		"Success": {
+			reason: "Certificate material should be loaded from the well-known keys of the secret",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+				s := o.(*corev1.Secret)
+				s.Data = map[string][]byte{KeyCA: []byte("ca"), KeyCert: []byte("cert"), KeyKey: []byte("key")}
+				return nil
+			})},
+			want: want{
+				m: &Material{CA: []byte("ca"), Cert: []byte("cert"), Key: []byte("key")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := FromSecret(context.Background(), tc.c, v1alpha1.SecretReference{Namespace: "cool", Name: "certs"})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nFromSecret(...): %s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.m, m); diff != "" {
+				t.Errorf("\nFromSecret(...): %s\n-want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClientConfig(t *testing.T) {
+	cert, key := selfSigned(t, "cool")
+
+	type want struct {
+		pool  bool
+		certs int
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		m      *Material
+		want   want
+	}{
+		"Empty": {
+			reason: "Empty material should produce a configuration that uses the system certificate pool",
+			m:      &Material{},
+			want:   want{},
+		},
+		"InvalidCA": {
+			reason: "An unparseable CA certificate should return an error",
+			m:      &Material{CA: []byte("nope")},
+			want:   want{err: errors.New(errParseCA)},
+		},
+		"Success": {
+			reason: "The CA should be used to verify servers and the certificate presented to them",
+			m:      &Material{CA: cert, Cert: cert, Key: key},
+			want:   want{pool: true, certs: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := tc.m.ClientConfig()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\nm.ClientConfig(): %s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.pool, cfg.RootCAs != nil); diff != "" {
+				t.Errorf("\nm.ClientConfig(): %s\n-want RootCAs, +got RootCAs:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.certs, len(cfg.Certificates)); diff != "" {
+				t.Errorf("\nm.ClientConfig(): %s\n-want certificates, +got certificates:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServerConfig(t *testing.T) {
+	cert, key := selfSigned(t, "cool")
+
+	type want struct {
+		auth tls.ClientAuthType
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		m      *Material
+		want   want
+	}{
+		"NoCertificate": {
+			reason: "A certificate is required to serve TLS",
+			m:      &Material{CA: cert},
+			want:   want{err: errors.New(errNoCertificate)},
+		},
+		"NoCA": {
+			reason: "Client certificates should not be required if no CA was supplied",
+			m:      &Material{Cert: cert, Key: key},
+			want:   want{auth: tls.NoClientCert},
+		},
+		"MutualTLS": {
+			reason: "Client certificates should be required and verified if a CA was supplied",
+			m:      &Material{CA: cert, Cert: cert, Key: key},
+			want:   want{auth: tls.RequireAndVerifyClientCert},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := tc.m.ServerConfig()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\nm.ServerConfig(): %s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.auth, cfg.ClientAuth); diff != "" {
+				t.Errorf("\nm.ServerConfig(): %s\n-want ClientAuth, +got ClientAuth:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certificates")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(...): %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := Files{Cert: filepath.Join(dir, KeyCert), Key: filepath.Join(dir, KeyKey)}
+	write := func(cn string, mtime time.Time) {
+		cert, key := selfSigned(t, cn)
+		for p, data := range map[string][]byte{f.Cert: cert, f.Key: key} {
+			if err := ioutil.WriteFile(p, data, 0600); err != nil {
+				t.Fatalf("ioutil.WriteFile(...): %v", err)
+			}
+			if err := os.Chtimes(p, mtime, mtime); err != nil {
+				t.Fatalf("os.Chtimes(...): %v", err)
+			}
+		}
+	}
+
+	then := time.Now().Add(-1 * time.Hour)
+	write("original", then)
+
+	r, err := NewReloader(f)
+	if err != nil {
+		t.Fatalf("NewReloader(...): %v", err)
+	}
+	cfg, err := r.ClientConfig()
+	if err != nil {
+		t.Fatalf("r.ClientConfig(): %v", err)
+	}
+
+	c, err := cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	if err != nil {
+		t.Fatalf("cfg.GetClientCertificate(...): %v", err)
+	}
+	if diff := cmp.Diff("original", commonName(t, c)); diff != "" {
+		t.Errorf("\ncfg.GetClientCertificate(...): the originally loaded certificate should be returned\n-want, +got:\n%s", diff)
+	}
+
+	write("rotated", then.Add(1*time.Minute))
+
+	c, err = cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	if err != nil {
+		t.Fatalf("cfg.GetClientCertificate(...): %v", err)
+	}
+	if diff := cmp.Diff("rotated", commonName(t, c)); diff != "" {
+		t.Errorf("\ncfg.GetClientCertificate(...): the certificate should be reloaded when its files are modified\n-want, +got:\n%s", diff)
+	}
+
+	scfg, err := r.ServerConfig()
+	if err != nil {
+		t.Fatalf("r.ServerConfig(): %v", err)
+	}
+	hello, err := scfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("scfg.GetConfigForClient(...): %v", err)
+	}
+	if diff := cmp.Diff("rotated", commonName(t, &hello.Certificates[0])); diff != "" {
+		t.Errorf("\nscfg.GetConfigForClient(...): the current certificate should be served\n-want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package connection publishes the connection details of managed resources to
+// secret stores.
+package connection
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// LabelKeyOwnerUID is the label used to record the UID of the resource that
+// owns a connection secret in a secret store.
+const LabelKeyOwnerUID = "secret.crossplane.io/owner-uid"
+
+// Error strings.
+const (
+	errNotOwned      = "refusing to modify existing connection secret that is not owned by any resource"
+	errFmtNotOwnedBy = "existing connection secret is not owned by UID %q"
+)
+
+type notFoundError struct{ error }
+
+func (e notFoundError) Unwrap() error { return e.error }
+
+// NewNotFound returns an error indicating that a connection secret does not
+// exist in a secret store. Stores should return it from ReadKeyValues, wrapping
+// their underlying error.
+func NewNotFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return notFoundError{err}
+}
+
+// IsNotFound returns true if the supplied error indicates that a connection
+// secret does not exist in a secret store.
+func IsNotFound(err error) bool {
+	var nf notFoundError
+	return errors.As(err, &nf)
+}
+
+// KeyValues are the connection details stored in a connection secret.
+type KeyValues map[string][]byte
+
+// A ScopedName uniquely identifies a connection secret within a secret store.
+type ScopedName struct {
+	// Name of the connection secret.
+	Name string
+
+	// Scope of the connection secret, for example a Kubernetes namespace.
+	// The secret store's default scope is used if Scope is empty.
+	Scope string
+}
+
+// A Secret is a connection secret in a secret store.
+type Secret struct {
+	ScopedName
+
+	// Metadata of the connection secret.
+	Metadata *v1alpha1.ConnectionSecretMetadata
+
+	// Data of the connection secret.
+	Data KeyValues
+}
+
+// GetOwnerUID returns the UID of the resource that owns the secret, if any.
+func (s *Secret) GetOwnerUID() string {
+	if s.Metadata == nil {
+		return ""
+	}
+	return s.Metadata.Labels[LabelKeyOwnerUID]
+}
+
+// A WriteOption is called before the desired connection secret is written
+// over the current one. The current secret is nil if it does not yet exist.
+type WriteOption func(ctx context.Context, current, desired *Secret) error
+
+// A DeleteOption is called before the current connection secret is deleted.
+type DeleteOption func(ctx context.Context, current *Secret) error
+
+// A Store stores connection secrets.
+type Store interface {
+	// ReadKeyValues reads the connection secret with the supplied name into
+	// the supplied Secret. It returns an error that satisfies IsNotFound if
+	// the secret does not exist.
+	ReadKeyValues(ctx context.Context, n ScopedName, s *Secret) error
+
+	// WriteKeyValues creates or updates the supplied connection secret.
+	WriteKeyValues(ctx context.Context, s *Secret, wo ...WriteOption) error
+
+	// DeleteKeyValues deletes the supplied connection secret. It returns
+	// successfully if the secret does not exist.
+	DeleteKeyValues(ctx context.Context, s *Secret, do ...DeleteOption) error
+}
+
+// A StoreBuilderFn builds a Store per the supplied SecretStoreConfig. The
+// supplied client may be used to read any Kubernetes resources the store
+// requires, such as credentials.
+type StoreBuilderFn func(ctx context.Context, local client.Client, cfg v1alpha1.SecretStoreConfig) (Store, error)
+
+// SecretToWriteMustBeOwnedBy requires that the current connection secret, if
+// any, is owned by the supplied object. A current secret that is not owned by
+// any object may be written only if it is an empty connection secret.
+func SecretToWriteMustBeOwnedBy(so metav1.Object) WriteOption {
+	return func(_ context.Context, current, _ *Secret) error {
+		if current == nil {
+			return nil
+		}
+		return mustBeOwnedBy(current, so)
+	}
+}
+
+// SecretToDeleteMustBeOwnedBy requires that the current connection secret is
+// owned by the supplied object. A current secret that is not owned by any
+// object may be deleted only if it is an empty connection secret.
+func SecretToDeleteMustBeOwnedBy(so metav1.Object) DeleteOption {
+	return func(_ context.Context, current *Secret) error {
+		return mustBeOwnedBy(current, so)
+	}
+}
+
+func mustBeOwnedBy(s *Secret, so metav1.Object) error {
+	switch uid := s.GetOwnerUID(); {
+	case uid == string(so.GetUID()):
+		return nil
+	case uid != "":
+		return errors.Errorf(errFmtNotOwnedBy, so.GetUID())
+	case len(s.Data) > 0 || !isConnectionSecret(s):
+		// We only adopt unowned secrets that we appear to have created,
+		// and that contain nothing that could be overwritten.
+		return errors.New(errNotOwned)
+	}
+	return nil
+}
+
+// isConnectionSecret returns true if the supplied secret is of the type used
+// by Crossplane for connection secrets.
+func isConnectionSecret(s *Secret) bool {
+	return s.Metadata != nil && s.Metadata.Type != nil && *s.Metadata.Type == resource.SecretTypeConnection
+}
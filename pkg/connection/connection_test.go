@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSecretToWriteMustBeOwnedBy(t *testing.T) {
+	owner := &metav1.ObjectMeta{UID: "very-unique"}
+	connectionType := resource.SecretTypeConnection
+	opaqueType := corev1.SecretTypeOpaque
+
+	cases := map[string]struct {
+		reason  string
+		current *Secret
+		want    error
+	}{
+		"NoCurrentSecret": {
+			reason: "A secret that does not yet exist may be written.",
+			want:   nil,
+		},
+		"UnownedEmptyConnectionSecret": {
+			reason:  "An empty connection secret that is not owned by any resource may be written.",
+			current: &Secret{Metadata: &v1alpha1.ConnectionSecretMetadata{Type: &connectionType}},
+			want:    nil,
+		},
+		"UnownedWithData": {
+			reason: "A pre-existing connection secret that is not owned by any resource and has data may not be written.",
+			current: &Secret{
+				Metadata: &v1alpha1.ConnectionSecretMetadata{Type: &connectionType},
+				Data:     KeyValues{"password": []byte("secret")},
+			},
+			want: errors.New(errNotOwned),
+		},
+		"UnownedOtherType": {
+			reason:  "A pre-existing empty secret that is not owned by any resource and is not a connection secret may not be written.",
+			current: &Secret{Metadata: &v1alpha1.ConnectionSecretMetadata{Type: &opaqueType}},
+			want:    errors.New(errNotOwned),
+		},
+		"UnownedNoMetadata": {
+			reason:  "A pre-existing secret with no owner label may not be written.",
+			current: &Secret{},
+			want:    errors.New(errNotOwned),
+		},
+		"OwnedBySupplied": {
+			reason:  "A secret that is owned by the supplied resource may be written.",
+			current: &Secret{Metadata: &v1alpha1.ConnectionSecretMetadata{Labels: map[string]string{LabelKeyOwnerUID: "very-unique"}}},
+			want:    nil,
+		},
+		"OwnedByAnother": {
+			reason:  "A secret that is owned by another resource may not be written.",
+			current: &Secret{Metadata: &v1alpha1.ConnectionSecretMetadata{Labels: map[string]string{LabelKeyOwnerUID: "another"}}},
+			want:    errors.Errorf(errFmtNotOwnedBy, "very-unique"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SecretToWriteMustBeOwnedBy(owner)(context.Background(), tc.current, &Secret{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nSecretToWriteMustBeOwnedBy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
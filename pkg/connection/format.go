@@ -0,0 +1,194 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Default keys to which formatted connection details are written.
+const (
+	DefaultKeyJSON = "connection.json"
+	DefaultKeyEnv  = "connection.env"
+)
+
+// Error strings.
+const (
+	errMarshalJSON      = "cannot marshal connection details to JSON"
+	errUnmarshalJSON    = "cannot unmarshal connection details from JSON"
+	errFmtUnknownFormat = "unknown connection secret format %q"
+)
+
+// Characters that cause an Env value to be quoted.
+const envSpecialCharacters = " \t\n\"'\\#$`"
+
+// Format the supplied connection details per the supplied format. Connection
+// details are returned unchanged if the format is nil or of type Keys. The JSON
+// and Env formats write all connection details to a single key, omitting any
+// nil values. Env keys are upper cased, and any character that is not a
+// letter, digit, or underscore is replaced with an underscore; i.e. the
+// 'endpoint' key becomes 'ENDPOINT'.
+func Format(kv KeyValues, f *v1alpha1.ConnectionSecretFormat) (KeyValues, error) {
+	if f == nil {
+		return kv, nil
+	}
+
+	switch f.Type {
+	case v1alpha1.ConnectionSecretFormatKeys:
+		return kv, nil
+	case v1alpha1.ConnectionSecretFormatJSON:
+		m := make(map[string]string, len(kv))
+		for k, v := range kv {
+			if v == nil {
+				continue
+			}
+			m[k] = string(v)
+		}
+		j, err := json.Marshal(m)
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalJSON)
+		}
+		return KeyValues{keyOr(f.Key, DefaultKeyJSON): j}, nil
+	case v1alpha1.ConnectionSecretFormatEnv:
+		return KeyValues{keyOr(f.Key, DefaultKeyEnv): toEnv(kv)}, nil
+	}
+
+	return nil, errors.Errorf(errFmtUnknownFormat, f.Type)
+}
+
+// Parse connection details that were formatted per the supplied format,
+// returning the connection details they were formatted from. Connection
+// details are returned unchanged if the format is nil or of type Keys. Env
+// keys cannot be parsed back into the keys they were formatted from, so they
+// are returned as formatted; i.e. the 'ENDPOINT' key remains 'ENDPOINT'.
+func Parse(kv KeyValues, f *v1alpha1.ConnectionSecretFormat) (KeyValues, error) {
+	if f == nil {
+		return kv, nil
+	}
+
+	switch f.Type {
+	case v1alpha1.ConnectionSecretFormatKeys:
+		return kv, nil
+	case v1alpha1.ConnectionSecretFormatJSON:
+		out := KeyValues{}
+		j, ok := kv[keyOr(f.Key, DefaultKeyJSON)]
+		if !ok {
+			return out, nil
+		}
+		m := map[string]string{}
+		if err := json.Unmarshal(j, &m); err != nil {
+			return nil, errors.Wrap(err, errUnmarshalJSON)
+		}
+		for k, v := range m {
+			out[k] = []byte(v)
+		}
+		return out, nil
+	case v1alpha1.ConnectionSecretFormatEnv:
+		return fromEnv(kv[keyOr(f.Key, DefaultKeyEnv)]), nil
+	}
+
+	return nil, errors.Errorf(errFmtUnknownFormat, f.Type)
+}
+
+func keyOr(key, def string) string {
+	if key == "" {
+		return def
+	}
+	return key
+}
+
+func toEnv(kv KeyValues) []byte {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return envKey(keys[i]) < envKey(keys[j]) })
+
+	b := &strings.Builder{}
+	for _, k := range keys {
+		if kv[k] == nil {
+			continue
+		}
+		b.WriteString(envKey(k))
+		b.WriteString("=")
+		b.WriteString(envValue(string(kv[k])))
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+func envKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, k)
+}
+
+// envValue double quotes values that contain whitespace, quotes, or other
+// characters that are special to shells and environment file parsers.
+func envValue(v string) string {
+	if !strings.ContainsAny(v, envSpecialCharacters) {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`, "`", "\\`")
+	return `"` + r.Replace(v) + `"`
+}
+
+func fromEnv(env []byte) KeyValues {
+	kv := KeyValues{}
+	for _, line := range strings.Split(string(env), "\n") {
+		i := strings.Index(line, "=")
+		if i < 1 {
+			continue
+		}
+		kv[line[:i]] = []byte(parseEnvValue(line[i+1:]))
+	}
+	return kv
+}
+
+// parseEnvValue reverses envValue.
+func parseEnvValue(v string) string {
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return v
+	}
+	v = v[1 : len(v)-1]
+
+	b := &strings.Builder{}
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i == len(v)-1 {
+			b.WriteByte(v[i])
+			continue
+		}
+		i++
+		if v[i] == 'n' {
+			b.WriteByte('\n')
+			continue
+		}
+		b.WriteByte(v[i])
+	}
+	return b.String()
+}
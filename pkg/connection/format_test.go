@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFormat(t *testing.T) {
+	kv := KeyValues{
+		"endpoint": []byte("example.org"),
+		"password": []byte(`so "secret"`),
+	}
+
+	type want struct {
+		kv  KeyValues
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		f      *v1alpha1.ConnectionSecretFormat
+		want   want
+	}{
+		"NoFormat": {
+			reason: "Connection details should be returned unchanged if no format is specified.",
+			want:   want{kv: kv},
+		},
+		"Keys": {
+			reason: "Connection details should be returned unchanged if the Keys format is specified.",
+			f:      &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatKeys},
+			want:   want{kv: kv},
+		},
+		"JSON": {
+			reason: "Connection details should be written as a JSON object to the default key.",
+			f:      &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatJSON},
+			want: want{kv: KeyValues{
+				DefaultKeyJSON: []byte(`{"endpoint":"example.org","password":"so \"secret\""}`),
+			}},
+		},
+		"Env": {
+			reason: "Connection details should be written as an environment file to the supplied key.",
+			f:      &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatEnv, Key: "app.env"},
+			want: want{kv: KeyValues{
+				"app.env": []byte("ENDPOINT=example.org\nPASSWORD=\"so \\\"secret\\\"\"\n"),
+			}},
+		},
+		"Unknown": {
+			reason: "An error should be returned for unknown formats.",
+			f:      &v1alpha1.ConnectionSecretFormat{Type: "YAML"},
+			want:   want{err: errors.Errorf(errFmtUnknownFormat, "YAML")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Format(kv, tc.f)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nFormat(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kv, got); diff != "" {
+				t.Errorf("\nReason: %s\nFormat(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	errInvalid := func() error {
+		err := json.Unmarshal([]byte("wat"), &map[string]string{})
+		return errors.Wrap(err, errUnmarshalJSON)
+	}()
+
+	type args struct {
+		kv KeyValues
+		f  *v1alpha1.ConnectionSecretFormat
+	}
+	type want struct {
+		kv  KeyValues
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoFormat": {
+			reason: "Connection details should be returned unchanged if no format is specified.",
+			args:   args{kv: KeyValues{"endpoint": []byte("example.org")}},
+			want:   want{kv: KeyValues{"endpoint": []byte("example.org")}},
+		},
+		"JSON": {
+			reason: "Connection details should be parsed from a JSON object at the default key.",
+			args: args{
+				kv: KeyValues{DefaultKeyJSON: []byte(`{"endpoint":"example.org","password":"so \"secret\""}`)},
+				f:  &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatJSON},
+			},
+			want: want{kv: KeyValues{"endpoint": []byte("example.org"), "password": []byte(`so "secret"`)}},
+		},
+		"JSONMissingKey": {
+			reason: "No connection details should be returned if the JSON key does not exist.",
+			args: args{
+				kv: KeyValues{},
+				f:  &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatJSON},
+			},
+			want: want{kv: KeyValues{}},
+		},
+		"JSONInvalid": {
+			reason: "An error should be returned if the JSON key is not a JSON object.",
+			args: args{
+				kv: KeyValues{DefaultKeyJSON: []byte("wat")},
+				f:  &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatJSON},
+			},
+			want: want{err: errInvalid},
+		},
+		"Env": {
+			reason: "Connection details should be parsed from an environment file at the supplied key, with their keys as formatted.",
+			args: args{
+				kv: KeyValues{"app.env": []byte("ENDPOINT=example.org\nPASSWORD=\"so \\\"secret\\\" \\$HOME\\nline\"\n")},
+				f:  &v1alpha1.ConnectionSecretFormat{Type: v1alpha1.ConnectionSecretFormatEnv, Key: "app.env"},
+			},
+			want: want{kv: KeyValues{"ENDPOINT": []byte("example.org"), "PASSWORD": []byte("so \"secret\" $HOME\nline")}},
+		},
+		"Unknown": {
+			reason: "An error should be returned for unknown formats.",
+			args: args{
+				f: &v1alpha1.ConnectionSecretFormat{Type: "YAML"},
+			},
+			want: want{err: errors.Errorf(errFmtUnknownFormat, "YAML")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tc.args.kv, tc.args.f)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kv, got); diff != "" {
+				t.Errorf("\nReason: %s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
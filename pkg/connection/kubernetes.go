@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errGetSecret    = "cannot get secret"
+	errApplySecret  = "cannot apply secret"
+	errDeleteSecret = "cannot delete secret"
+)
+
+// A KubernetesStore stores connection secrets as Kubernetes Secrets.
+type KubernetesStore struct {
+	client           resource.ClientApplicator
+	defaultNamespace string
+	namespace        string
+}
+
+// NewKubernetesStore returns a Store that stores connection secrets as
+// Kubernetes Secrets using the supplied client. Secrets with no scope are
+// stored in the supplied config's default scope namespace. All secrets are
+// stored in the configured namespace, if any, regardless of their scope.
+func NewKubernetesStore(_ context.Context, local client.Client, cfg v1alpha1.SecretStoreConfig) (Store, error) {
+	ss := &KubernetesStore{
+		client: resource.ClientApplicator{
+			Client:     local,
+			Applicator: resource.NewAPIPatchingApplicator(local),
+		},
+		defaultNamespace: cfg.DefaultScope,
+	}
+	if cfg.Kubernetes != nil {
+		ss.namespace = cfg.Kubernetes.Namespace
+	}
+	return ss, nil
+}
+
+func (ss *KubernetesStore) namespaceFor(scope string) string {
+	switch {
+	case ss.namespace != "":
+		return ss.namespace
+	case scope == "":
+		return ss.defaultNamespace
+	default:
+		return scope
+	}
+}
+
+// ReadKeyValues reads the Secret with the supplied name into the supplied
+// connection secret.
+func (ss *KubernetesStore) ReadKeyValues(ctx context.Context, n ScopedName, s *Secret) error {
+	ks := &corev1.Secret{}
+	err := ss.client.Get(ctx, types.NamespacedName{Namespace: ss.namespaceFor(n.Scope), Name: n.Name}, ks)
+	if kerrors.IsNotFound(err) {
+		err = NewNotFound(err)
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetSecret)
+	}
+	s.ScopedName = n
+	s.Metadata = metadataOf(ks)
+	s.Data = KeyValues(ks.Data)
+	return nil
+}
+
+// WriteKeyValues creates or updates the Secret corresponding to the supplied
+// connection secret.
+func (ss *KubernetesStore) WriteKeyValues(ctx context.Context, s *Secret, wo ...WriteOption) error {
+	ks := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.Name,
+			Namespace: ss.namespaceFor(s.Scope),
+		},
+		Type: resource.SecretTypeConnection,
+		Data: s.Data,
+	}
+	if s.Metadata != nil {
+		ks.SetLabels(s.Metadata.Labels)
+		ks.SetAnnotations(s.Metadata.Annotations)
+		if s.Metadata.Type != nil {
+			ks.Type = *s.Metadata.Type
+		}
+	}
+
+	ao := func(ctx context.Context, current, _ runtime.Object) error {
+		cs := current.(*corev1.Secret)
+		c := &Secret{
+			ScopedName: s.ScopedName,
+			Metadata:   metadataOf(cs),
+			Data:       KeyValues(cs.Data),
+		}
+		for _, fn := range wo {
+			if err := fn(ctx, c, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return errors.Wrap(ss.client.Apply(ctx, ks, ao), errApplySecret)
+}
+
+// DeleteKeyValues deletes the Secret corresponding to the supplied connection
+// secret.
+func (ss *KubernetesStore) DeleteKeyValues(ctx context.Context, s *Secret, do ...DeleteOption) error {
+	ks := &corev1.Secret{}
+	err := ss.client.Get(ctx, types.NamespacedName{Namespace: ss.namespaceFor(s.Scope), Name: s.Name}, ks)
+	if resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetSecret)
+	}
+	if err != nil {
+		return nil
+	}
+
+	c := &Secret{ScopedName: s.ScopedName, Metadata: metadataOf(ks), Data: KeyValues(ks.Data)}
+	for _, fn := range do {
+		if err := fn(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(resource.IgnoreNotFound(ss.client.Delete(ctx, ks)), errDeleteSecret)
+}
+
+func metadataOf(s *corev1.Secret) *v1alpha1.ConnectionSecretMetadata {
+	t := s.Type
+	return &v1alpha1.ConnectionSecretMetadata{
+		Labels:      s.GetLabels(),
+		Annotations: s.GetAnnotations(),
+		Type:        &t,
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Store = &KubernetesStore{}
+
+func TestKubernetesStoreReadKeyValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+	st := corev1.SecretType("cool")
+
+	type want struct {
+		s   *Secret
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		want   want
+	}{
+		"NotFound": {
+			reason: "We should return an error that satisfies IsNotFound if the secret does not exist.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errNotFound)},
+			want:   want{s: &Secret{}, err: errors.Wrap(NewNotFound(errNotFound), errGetSecret)},
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting the secret.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{s: &Secret{}, err: errors.Wrap(errBoom, errGetSecret)},
+		},
+		"Success": {
+			reason: "We should read the secret's metadata and data.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+				*o.(*corev1.Secret) = corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "label"}},
+					Type:       st,
+					Data:       map[string][]byte{"key": []byte("value")},
+				}
+				return nil
+			})},
+			want: want{s: &Secret{
+				ScopedName: ScopedName{Name: "cool"},
+				Metadata:   &v1alpha1.ConnectionSecretMetadata{Labels: map[string]string{"cool": "label"}, Type: &st},
+				Data:       KeyValues{"key": []byte("value")},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := &KubernetesStore{client: resource.ClientApplicator{Client: tc.c}, defaultNamespace: "crossplane-system"}
+			got := &Secret{}
+			err := ss.ReadKeyValues(context.Background(), ScopedName{Name: "cool"}, got)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nss.ReadKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil && IsNotFound(err) != IsNotFound(tc.want.err) {
+				t.Errorf("\nReason: %s\nIsNotFound(...): want %t, got %t", tc.reason, IsNotFound(tc.want.err), IsNotFound(err))
+			}
+			if diff := cmp.Diff(tc.want.s, got); diff != "" {
+				t.Errorf("\nReason: %s\nss.ReadKeyValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubernetesStoreWriteKeyValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	owner := &metav1.ObjectMeta{UID: "very-unique"}
+
+	type args struct {
+		c    client.Client
+		kube *v1alpha1.KubernetesSecretStoreConfig
+		s    *Secret
+		wo   []WriteOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"CreateSecret": {
+			reason: "A secret that does not exist should be created in the default namespace if the connection secret has no scope.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil, func(o runtime.Object) error {
+						want := &corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "cool",
+								Namespace: "crossplane-system",
+								Labels:    map[string]string{LabelKeyOwnerUID: "very-unique"},
+							},
+							Type: resource.SecretTypeConnection,
+							Data: map[string][]byte{"key": []byte("value")},
+						}
+						if diff := cmp.Diff(want, o); diff != "" {
+							t.Errorf("\nCreate(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				s: &Secret{
+					ScopedName: ScopedName{Name: "cool"},
+					Metadata:   &v1alpha1.ConnectionSecretMetadata{Labels: map[string]string{LabelKeyOwnerUID: "very-unique"}},
+					Data:       KeyValues{"key": []byte("value")},
+				},
+			},
+			want: nil,
+		},
+		"CreateSecretInConfiguredNamespace": {
+			reason: "A secret should be created in the configured namespace regardless of the connection secret's scope.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil, func(o runtime.Object) error {
+						if diff := cmp.Diff("team-credentials", o.(*corev1.Secret).GetNamespace()); diff != "" {
+							t.Errorf("\nCreate(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				kube: &v1alpha1.KubernetesSecretStoreConfig{Namespace: "team-credentials"},
+				s:    &Secret{ScopedName: ScopedName{Name: "cool", Scope: "cool-namespace"}},
+			},
+			want: nil,
+		},
+		"NotOwned": {
+			reason: "We should not overwrite a secret that is owned by another resource.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+						o.(*corev1.Secret).SetLabels(map[string]string{LabelKeyOwnerUID: "another"})
+						return nil
+					}),
+				},
+				s:  &Secret{ScopedName: ScopedName{Name: "cool", Scope: "cool-namespace"}},
+				wo: []WriteOption{SecretToWriteMustBeOwnedBy(owner)},
+			},
+			want: errors.Wrap(errors.Errorf(errFmtNotOwnedBy, "very-unique"), errApplySecret),
+		},
+		"PreExistingUnowned": {
+			reason: "We should not overwrite a pre-existing secret that is not owned by any resource.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+						*o.(*corev1.Secret) = corev1.Secret{
+							Type: corev1.SecretTypeOpaque,
+							Data: map[string][]byte{"password": []byte("not-ours")},
+						}
+						return nil
+					}),
+				},
+				s:  &Secret{ScopedName: ScopedName{Name: "cool", Scope: "cool-namespace"}},
+				wo: []WriteOption{SecretToWriteMustBeOwnedBy(owner)},
+			},
+			want: errors.Wrap(errors.New(errNotOwned), errApplySecret),
+		},
+		"PatchError": {
+			reason: "We should return any error encountered updating an existing secret.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				s:  &Secret{ScopedName: ScopedName{Name: "cool", Scope: "cool-namespace"}},
+				wo: []WriteOption{SecretToWriteMustBeOwnedBy(owner)},
+			},
+			want: errors.Wrap(errors.Wrap(errBoom, "cannot patch object"), errApplySecret),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := v1alpha1.SecretStoreConfig{DefaultScope: "crossplane-system", Kubernetes: tc.args.kube}
+			ss, _ := NewKubernetesStore(context.Background(), tc.args.c, cfg)
+			err := ss.WriteKeyValues(context.Background(), tc.args.s, tc.args.wo...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nss.WriteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKubernetesStoreDeleteKeyValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	owner := &metav1.ObjectMeta{UID: "very-unique"}
+
+	type args struct {
+		c  client.Client
+		s  *Secret
+		do []DeleteOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotFound": {
+			reason: "Deleting a secret that does not exist should succeed.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				s: &Secret{ScopedName: ScopedName{Name: "cool"}},
+			},
+			want: nil,
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting the secret.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s: &Secret{ScopedName: ScopedName{Name: "cool"}},
+			},
+			want: errors.Wrap(errBoom, errGetSecret),
+		},
+		"NotOwned": {
+			reason: "We should not delete a secret that is owned by another resource.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+						o.(*corev1.Secret).SetLabels(map[string]string{LabelKeyOwnerUID: "another"})
+						return nil
+					}),
+				},
+				s:  &Secret{ScopedName: ScopedName{Name: "cool"}},
+				do: []DeleteOption{SecretToDeleteMustBeOwnedBy(owner)},
+			},
+			want: errors.Errorf(errFmtNotOwnedBy, "very-unique"),
+		},
+		"Success": {
+			reason: "We should delete a secret that is owned by the supplied resource.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+						o.(*corev1.Secret).SetLabels(map[string]string{LabelKeyOwnerUID: "very-unique"})
+						return nil
+					}),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				s:  &Secret{ScopedName: ScopedName{Name: "cool"}},
+				do: []DeleteOption{SecretToDeleteMustBeOwnedBy(owner)},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss, _ := NewKubernetesStore(context.Background(), tc.args.c, v1alpha1.SecretStoreConfig{DefaultScope: "crossplane-system"})
+			err := ss.DeleteKeyValues(context.Background(), tc.args.s, tc.args.do...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nss.DeleteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultStoreConfigName is the name of the store config used when a managed
+// resource does not reference one.
+const DefaultStoreConfigName = "default"
+
+// Error strings.
+const (
+	errGetStoreConfig = "cannot get store config"
+	errBuildStore     = "cannot build secret store"
+	errReadStore      = "cannot read connection secret from secret store"
+	errWriteStore     = "cannot write connection secret to secret store"
+	errDeleteStore    = "cannot delete connection secret from secret store"
+	errFormat         = "cannot format connection details"
+	errParse          = "cannot parse existing connection details"
+	errDerive         = "cannot derive connection details"
+
+	errFmtUnknownStoreType = "no secret store is registered for store type %q"
+)
+
+// A DetailsManager publishes the connection details of managed resources to
+// the secret stores they reference. It satisfies the
+// managed.ConnectionPublisher interface, and may be used alongside a
+// managed.APISecretPublisher, for example:
+//
+//	d := connection.NewDetailsManager(c, scheme, resource.StoreConfigKind(v1alpha1.StoreConfigGroupVersionKind))
+//	managed.WithConnectionPublishers(managed.NewAPISecretPublisher(c, scheme), d)
+type DetailsManager struct {
+	client    client.Client
+	newConfig func() resource.StoreConfig
+	stores    map[v1alpha1.SecretStoreType]StoreBuilderFn
+}
+
+// A DetailsManagerOption configures a DetailsManager.
+type DetailsManagerOption func(*DetailsManager)
+
+// WithStoreBuilder registers the supplied StoreBuilderFn for the supplied type
+// of secret store. A KubernetesStore is registered by default.
+func WithStoreBuilder(t v1alpha1.SecretStoreType, fn StoreBuilderFn) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.stores[t] = fn
+	}
+}
+
+// NewDetailsManager returns a DetailsManager that publishes connection details
+// to the secret stores configured by store configs of the supplied kind.
+func NewDetailsManager(c client.Client, oc runtime.ObjectCreater, of resource.StoreConfigKind, o ...DetailsManagerOption) *DetailsManager {
+	nc := func() resource.StoreConfig {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), oc).(resource.StoreConfig)
+	}
+
+	// Panic early if we've been asked to use a store config kind that has not
+	// been registered with the supplied scheme.
+	_ = nc()
+
+	m := &DetailsManager{
+		client:    c,
+		newConfig: nc,
+		stores: map[v1alpha1.SecretStoreType]StoreBuilderFn{
+			v1alpha1.SecretStoreKubernetes: NewKubernetesStore,
+		},
+	}
+
+	for _, fn := range o {
+		fn(m)
+	}
+
+	return m
+}
+
+// PublishConnection publishes the supplied ConnectionDetails to the secret
+// store referenced by the supplied managed resource, if any. It refuses to
+// overwrite a connection secret owned by another resource. The supplied
+// ConnectionDetails need not be complete; they are merged with any that were
+// previously published.
+func (m *DetailsManager) PublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	p := mg.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return nil
+	}
+
+	ss, err := m.connectStore(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	kv, err := details(ctx, ss, secretFor(mg, nil).ScopedName, p, KeyValues(c))
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(ss.WriteKeyValues(ctx, secretFor(mg, kv), SecretToWriteMustBeOwnedBy(mg)), errWriteStore)
+}
+
+// details returns the connection details that should be written to the
+// supplied store. Stores merge the details they are asked to write with any
+// existing details, but formats that write all details to a single key would
+// replace any existing details, and templates may reference details that were
+// not supplied. We therefore merge the supplied details with any existing
+// details before deriving and formatting them.
+func details(ctx context.Context, ss Store, n ScopedName, p *v1alpha1.PublishConnectionDetailsTo, kv KeyValues) (KeyValues, error) {
+	formatted := p.Format != nil && p.Format.Type != v1alpha1.ConnectionSecretFormatKeys
+	if formatted || len(p.Templates) > 0 {
+		current := &Secret{}
+		if err := ss.ReadKeyValues(ctx, n, current); err != nil && !IsNotFound(err) {
+			return nil, errors.Wrap(err, errReadStore)
+		}
+		existing, err := Parse(current.Data, p.Format)
+		if err != nil {
+			return nil, errors.Wrap(err, errParse)
+		}
+		kv = merge(existing, kv, p.Format)
+	}
+
+	kv, err := Derive(kv, p.Templates...)
+	if err != nil {
+		return nil, errors.Wrap(err, errDerive)
+	}
+
+	kv, err = Format(kv, p.Format)
+	return kv, errors.Wrap(err, errFormat)
+}
+
+// UnpublishConnection deletes the connection secret published to the secret
+// store referenced by the supplied managed resource, if any. Unlike Kubernetes
+// Secrets, connection secrets in external secret stores are not garbage
+// collected when their managed resource is deleted.
+func (m *DetailsManager) UnpublishConnection(ctx context.Context, mg resource.Managed, _ managed.ConnectionDetails) error {
+	p := mg.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return nil
+	}
+
+	ss, err := m.connectStore(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(ss.DeleteKeyValues(ctx, secretFor(mg, nil), SecretToDeleteMustBeOwnedBy(mg)), errDeleteStore)
+}
+
+func (m *DetailsManager) connectStore(ctx context.Context, p *v1alpha1.PublishConnectionDetailsTo) (Store, error) {
+	name := DefaultStoreConfigName
+	if p.SecretStoreConfigRef != nil {
+		name = p.SecretStoreConfigRef.Name
+	}
+
+	sc := m.newConfig()
+	if err := m.client.Get(ctx, types.NamespacedName{Name: name}, sc); err != nil {
+		return nil, errors.Wrap(err, errGetStoreConfig)
+	}
+
+	cfg := sc.GetStoreConfig()
+	build, ok := m.stores[cfg.GetType()]
+	if !ok {
+		return nil, errors.Errorf(errFmtUnknownStoreType, cfg.GetType())
+	}
+
+	ss, err := build(ctx, m.client, cfg)
+	return ss, errors.Wrap(err, errBuildStore)
+}
+
+// merge returns the supplied existing connection details updated with the
+// supplied connection details. Nil values are retained so that stores delete
+// their keys. Existing details that were formatted as Env cannot be parsed back
+// into their original keys, so any that would be formatted with the same key
+// as one of the supplied details are replaced.
+func merge(existing, kv KeyValues, f *v1alpha1.ConnectionSecretFormat) KeyValues {
+	out := make(KeyValues, len(existing)+len(kv))
+	for k, v := range existing {
+		out[k] = v
+	}
+	for k, v := range kv {
+		if f != nil && f.Type == v1alpha1.ConnectionSecretFormatEnv {
+			delete(out, envKey(k))
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// secretFor returns the connection secret the supplied managed resource
+// publishes to a secret store. The secret is labelled with the managed
+// resource's external tags, and with its UID in order to record its ownership.
+func secretFor(mg resource.Managed, kv KeyValues) *Secret {
+	p := mg.GetPublishConnectionDetailsTo()
+
+	md := &v1alpha1.ConnectionSecretMetadata{Labels: map[string]string{}}
+	if p.Metadata != nil {
+		md = p.Metadata.DeepCopy()
+		if md.Labels == nil {
+			md.Labels = map[string]string{}
+		}
+	}
+	for k, v := range resource.GetExternalTags(mg) {
+		md.Labels[k] = v
+	}
+	md.Labels[LabelKeyOwnerUID] = string(mg.GetUID())
+
+	return &Secret{
+		ScopedName: ScopedName{Name: p.Name, Scope: mg.GetNamespace()},
+		Metadata:   md,
+		Data:       kv,
+	}
+}
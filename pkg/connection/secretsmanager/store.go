@@ -0,0 +1,303 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretsmanager implements a connection secret store backed by AWS
+// Secrets Manager. Register it with a connection.DetailsManager using:
+//
+//	connection.WithStoreBuilder(v1alpha1.SecretStoreAWSSecretsManager, secretsmanager.NewStore)
+package secretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Keys of the Secret referenced by an AWS Secrets Manager store config's
+// credentials secret reference.
+const (
+	CredentialsKeyAccessKeyID     = "access_key_id"
+	CredentialsKeySecretAccessKey = "secret_access_key"
+	CredentialsKeySessionToken    = "session_token"
+)
+
+// Error strings.
+const (
+	errNoConfig       = "no AWS Secrets Manager configuration was supplied"
+	errGetCredentials = "cannot get AWS credentials secret"
+	errNewSession     = "cannot create AWS session"
+	errDescribeSecret = "cannot describe secret"
+	errGetSecretValue = "cannot get secret value"
+	errCreateSecret   = "cannot create secret"
+	errPutSecretValue = "cannot put secret value"
+	errTagSecret      = "cannot tag secret"
+	errDeleteSecret   = "cannot delete secret"
+	errMarshal        = "cannot marshal connection details to JSON"
+	errUnmarshal      = "cannot unmarshal connection details from JSON"
+)
+
+// A Store stores connection secrets in AWS Secrets Manager. Each connection
+// secret is stored as a JSON object of connection detail keys to base64
+// encoded values, named <scope>/<name>. Connection secret labels are stored as secret tags.
+type Store struct {
+	client       secretsmanageriface.SecretsManagerAPI
+	kmsKeyID     *string
+	defaultScope string
+}
+
+// NewStore returns a connection.Store backed by AWS Secrets Manager, per the
+// supplied config. Secrets are encrypted using the configured KMS key, if any.
+func NewStore(ctx context.Context, local client.Client, cfg v1alpha1.SecretStoreConfig) (connection.Store, error) {
+	c := cfg.AWSSecretsManager
+	if c == nil {
+		return nil, errors.New(errNoConfig)
+	}
+
+	ac := aws.NewConfig().WithRegion(c.Region)
+	if ref := c.CredentialsSecretRef; ref != nil {
+		s := &corev1.Secret{}
+		if err := local.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return nil, errors.Wrap(err, errGetCredentials)
+		}
+		ac = ac.WithCredentials(credentials.NewStaticCredentials(
+			string(s.Data[CredentialsKeyAccessKeyID]),
+			string(s.Data[CredentialsKeySecretAccessKey]),
+			string(s.Data[CredentialsKeySessionToken]),
+		))
+	}
+
+	sess, err := session.NewSession(ac)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewSession)
+	}
+
+	return &Store{client: secretsmanager.New(sess), kmsKeyID: c.KMSKeyID, defaultScope: cfg.DefaultScope}, nil
+}
+
+func (ss *Store) id(n connection.ScopedName) *string {
+	if n.Scope == "" {
+		return aws.String(path.Join(ss.defaultScope, n.Name))
+	}
+	return aws.String(path.Join(n.Scope, n.Name))
+}
+
+// ReadKeyValues reads the secret with the supplied name into the supplied
+// connection secret.
+func (ss *Store) ReadKeyValues(ctx context.Context, n connection.ScopedName, s *connection.Secret) error {
+	d, err := ss.client.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: ss.id(n)})
+	if isNotFound(err) {
+		err = connection.NewNotFound(err)
+	}
+	if err != nil {
+		return errors.Wrap(err, errDescribeSecret)
+	}
+	kv, err := ss.read(ctx, n)
+	if err != nil {
+		return err
+	}
+	s.ScopedName = n
+	s.Metadata = &v1alpha1.ConnectionSecretMetadata{Labels: labelsOf(d.Tags)}
+	s.Data = kv
+	return nil
+}
+
+// WriteKeyValues creates or updates the secret corresponding to the supplied
+// connection secret. The supplied connection details are merged into those of
+// any existing secret; a nil value deletes its key.
+func (ss *Store) WriteKeyValues(ctx context.Context, s *connection.Secret, wo ...connection.WriteOption) error {
+	var tags []*secretsmanager.Tag
+	if s.Metadata != nil {
+		tags = tagsOf(s.Metadata.Labels)
+	}
+
+	d, err := ss.client.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: ss.id(s.ScopedName)})
+	if isNotFound(err) {
+		str, err := toSecretString(merge(nil, s.Data))
+		if err != nil {
+			return err
+		}
+		_, err = ss.client.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
+			Name:         ss.id(s.ScopedName),
+			KmsKeyId:     ss.kmsKeyID,
+			SecretString: str,
+			Tags:         tags,
+		})
+		return errors.Wrap(err, errCreateSecret)
+	}
+	if err != nil {
+		return errors.Wrap(err, errDescribeSecret)
+	}
+
+	kv, err := ss.read(ctx, s.ScopedName)
+	if err != nil {
+		return err
+	}
+	current := &connection.Secret{
+		ScopedName: s.ScopedName,
+		Metadata:   &v1alpha1.ConnectionSecretMetadata{Labels: labelsOf(d.Tags)},
+		Data:       kv,
+	}
+	for _, fn := range wo {
+		if err := fn(ctx, current, s); err != nil {
+			return err
+		}
+	}
+
+	// Secrets Manager replaces the entire value of a secret, so we must
+	// merge our connection details into the existing ones. We avoid creating
+	// a new version of the secret if nothing changed.
+	if merged := merge(kv, s.Data); !reflect.DeepEqual(merged, kv) {
+		str, err := toSecretString(merged)
+		if err != nil {
+			return err
+		}
+		if _, err := ss.client.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{SecretId: d.ARN, SecretString: str}); err != nil {
+			return errors.Wrap(err, errPutSecretValue)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err = ss.client.TagResourceWithContext(ctx, &secretsmanager.TagResourceInput{SecretId: d.ARN, Tags: tags})
+	return errors.Wrap(err, errTagSecret)
+}
+
+// DeleteKeyValues deletes the secret corresponding to the supplied connection
+// secret. The secret is deleted without a recovery window so that a connection
+// secret of the same name may be published again immediately.
+func (ss *Store) DeleteKeyValues(ctx context.Context, s *connection.Secret, do ...connection.DeleteOption) error {
+	d, err := ss.client.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: ss.id(s.ScopedName)})
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errDescribeSecret)
+	}
+
+	current := &connection.Secret{
+		ScopedName: s.ScopedName,
+		Metadata:   &v1alpha1.ConnectionSecretMetadata{Labels: labelsOf(d.Tags)},
+	}
+	for _, fn := range do {
+		if err := fn(ctx, current); err != nil {
+			return err
+		}
+	}
+
+	_, err = ss.client.DeleteSecretWithContext(ctx, &secretsmanager.DeleteSecretInput{
+		SecretId:                   d.ARN,
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	return errors.Wrap(err, errDeleteSecret)
+}
+
+func (ss *Store) read(ctx context.Context, n connection.ScopedName) (connection.KeyValues, error) {
+	v, err := ss.client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{SecretId: ss.id(n)})
+	if err != nil {
+		return nil, errors.Wrap(err, errGetSecretValue)
+	}
+	return fromSecretString(v.SecretString)
+}
+
+// merge returns the supplied existing connection details updated with the
+// supplied connection details. Nil values delete their key.
+func merge(existing, kv connection.KeyValues) connection.KeyValues {
+	out := make(connection.KeyValues, len(existing)+len(kv))
+	for k, v := range existing {
+		out[k] = v
+	}
+	for k, v := range kv {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func isNotFound(err error) bool {
+	ae, ok := err.(awserr.Error)
+	return ok && ae.Code() == secretsmanager.ErrCodeResourceNotFoundException
+}
+
+// toSecretString returns a JSON object of the supplied connection details.
+// Values are base64 encoded, because connection details may be binary data
+// that is not valid UTF-8, such as a DER encoded certificate.
+func toSecretString(kv connection.KeyValues) (*string, error) {
+	j, err := json.Marshal(map[string][]byte(kv))
+	return aws.String(string(j)), errors.Wrap(err, errMarshal)
+}
+
+// fromSecretString returns the connection details encoded by toSecretString.
+func fromSecretString(s *string) (connection.KeyValues, error) {
+	kv := connection.KeyValues{}
+	if s == nil {
+		return kv, nil
+	}
+	m := map[string][]byte{}
+	if err := json.Unmarshal([]byte(*s), &m); err != nil {
+		return nil, errors.Wrap(err, errUnmarshal)
+	}
+	for k, v := range m {
+		kv[k] = v
+	}
+	return kv, nil
+}
+
+func tagsOf(labels map[string]string) []*secretsmanager.Tag {
+	if len(labels) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]*secretsmanager.Tag, len(keys))
+	for i, k := range keys {
+		tags[i] = &secretsmanager.Tag{Key: aws.String(k), Value: aws.String(labels[k])}
+	}
+	return tags
+}
+
+func labelsOf(tags []*secretsmanager.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(tags))
+	for _, t := range tags {
+		labels[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return labels
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ connection.Store = &Store{}
+
+type mockClient struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	MockDescribeSecret func(*secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error)
+	MockGetSecretValue func(*secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
+	MockCreateSecret   func(*secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error)
+	MockPutSecretValue func(*secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error)
+	MockTagResource    func(*secretsmanager.TagResourceInput) (*secretsmanager.TagResourceOutput, error)
+	MockDeleteSecret   func(*secretsmanager.DeleteSecretInput) (*secretsmanager.DeleteSecretOutput, error)
+}
+
+func (m *mockClient) DescribeSecretWithContext(_ aws.Context, i *secretsmanager.DescribeSecretInput, _ ...request.Option) (*secretsmanager.DescribeSecretOutput, error) {
+	return m.MockDescribeSecret(i)
+}
+
+func (m *mockClient) GetSecretValueWithContext(_ aws.Context, i *secretsmanager.GetSecretValueInput, _ ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	return m.MockGetSecretValue(i)
+}
+
+func (m *mockClient) CreateSecretWithContext(_ aws.Context, i *secretsmanager.CreateSecretInput, _ ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+	return m.MockCreateSecret(i)
+}
+
+func (m *mockClient) PutSecretValueWithContext(_ aws.Context, i *secretsmanager.PutSecretValueInput, _ ...request.Option) (*secretsmanager.PutSecretValueOutput, error) {
+	return m.MockPutSecretValue(i)
+}
+
+func (m *mockClient) TagResourceWithContext(_ aws.Context, i *secretsmanager.TagResourceInput, _ ...request.Option) (*secretsmanager.TagResourceOutput, error) {
+	return m.MockTagResource(i)
+}
+
+func (m *mockClient) DeleteSecretWithContext(_ aws.Context, i *secretsmanager.DeleteSecretInput, _ ...request.Option) (*secretsmanager.DeleteSecretOutput, error) {
+	return m.MockDeleteSecret(i)
+}
+
+var errNotFound = awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "not found", nil)
+
+func TestWriteKeyValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	arn := aws.String("arn:aws:secretsmanager:us-west-2:123456789012:secret:cool")
+
+	type args struct {
+		client secretsmanageriface.SecretsManagerAPI
+		kmsKey *string
+		s      *connection.Secret
+		wo     []connection.WriteOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"CreateSecret": {
+			reason: "A secret that does not exist should be created in the default scope, encrypted and tagged.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return nil, errNotFound
+					},
+					MockCreateSecret: func(got *secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error) {
+						want := &secretsmanager.CreateSecretInput{
+							Name:         aws.String("crossplane-system/cool"),
+							KmsKeyId:     aws.String("alias/cool"),
+							SecretString: aws.String(`{"key":"dmFsdWU="}`),
+							Tags: []*secretsmanager.Tag{
+								{Key: aws.String("a"), Value: aws.String("1")},
+								{Key: aws.String("b"), Value: aws.String("2")},
+							},
+						}
+						if diff := cmp.Diff(want, got); diff != "" {
+							t.Errorf("\nCreateSecret(...): -want, +got:\n%s", diff)
+						}
+						return &secretsmanager.CreateSecretOutput{}, nil
+					},
+				},
+				kmsKey: aws.String("alias/cool"),
+				s: &connection.Secret{
+					ScopedName: connection.ScopedName{Name: "cool"},
+					Metadata:   &v1alpha1.ConnectionSecretMetadata{Labels: map[string]string{"b": "2", "a": "1"}},
+					Data:       connection.KeyValues{"key": []byte("value")},
+				},
+			},
+			want: nil,
+		},
+		"DescribeSecretError": {
+			reason: "We should return any error encountered describing the secret.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return nil, errBoom
+					},
+				},
+				s: &connection.Secret{ScopedName: connection.ScopedName{Name: "cool"}},
+			},
+			want: errors.Wrap(errBoom, errDescribeSecret),
+		},
+		"WriteOptionError": {
+			reason: "We should not update a secret if a write option returns an error.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return &secretsmanager.DescribeSecretOutput{ARN: arn}, nil
+					},
+					MockGetSecretValue: func(_ *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+						return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{}`)}, nil
+					},
+				},
+				s: &connection.Secret{ScopedName: connection.ScopedName{Name: "cool", Scope: "cool-scope"}},
+				wo: []connection.WriteOption{func(_ context.Context, _, _ *connection.Secret) error {
+					return errBoom
+				}},
+			},
+			want: errBoom,
+		},
+		"UpdateSecret": {
+			reason: "An existing secret should have its value updated.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(got *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						if diff := cmp.Diff(aws.String("cool-scope/cool"), got.SecretId); diff != "" {
+							t.Errorf("\nDescribeSecret(...): -want, +got:\n%s", diff)
+						}
+						return &secretsmanager.DescribeSecretOutput{ARN: arn}, nil
+					},
+					MockGetSecretValue: func(_ *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+						return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"key":"b2xk"}`)}, nil
+					},
+					MockPutSecretValue: func(got *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error) {
+						want := &secretsmanager.PutSecretValueInput{SecretId: arn, SecretString: aws.String(`{"key":"bmV3"}`)}
+						if diff := cmp.Diff(want, got); diff != "" {
+							t.Errorf("\nPutSecretValue(...): -want, +got:\n%s", diff)
+						}
+						return &secretsmanager.PutSecretValueOutput{}, nil
+					},
+				},
+				s: &connection.Secret{
+					ScopedName: connection.ScopedName{Name: "cool", Scope: "cool-scope"},
+					Data:       connection.KeyValues{"key": []byte("new")},
+				},
+			},
+			want: nil,
+		},
+		"UnchangedSecret": {
+			reason: "An existing secret should not have its value updated if its connection details would not change.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return &secretsmanager.DescribeSecretOutput{ARN: arn}, nil
+					},
+					MockGetSecretValue: func(_ *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+						return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"key":"dmFsdWU=","other":"dmFsdWU="}`)}, nil
+					},
+					MockPutSecretValue: func(_ *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error) {
+						t.Errorf("\nPutSecretValue(...): should not be called when connection details are unchanged")
+						return &secretsmanager.PutSecretValueOutput{}, nil
+					},
+				},
+				s: &connection.Secret{
+					ScopedName: connection.ScopedName{Name: "cool", Scope: "cool-scope"},
+					Data:       connection.KeyValues{"key": []byte("value")},
+				},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := &Store{client: tc.args.client, kmsKeyID: tc.args.kmsKey, defaultScope: "crossplane-system"}
+			err := ss.WriteKeyValues(context.Background(), tc.args.s, tc.args.wo...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nss.WriteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWriteKeyValuesMerge(t *testing.T) {
+	arn := aws.String("arn:aws:secretsmanager:us-west-2:123456789012:secret:cool")
+
+	// value is the value of the secret as stored by Secrets Manager.
+	var value *string
+	client := &mockClient{
+		MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+			if value == nil {
+				return nil, errNotFound
+			}
+			return &secretsmanager.DescribeSecretOutput{ARN: arn}, nil
+		},
+		MockGetSecretValue: func(_ *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error) {
+			return &secretsmanager.GetSecretValueOutput{SecretString: value}, nil
+		},
+		MockCreateSecret: func(i *secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error) {
+			value = i.SecretString
+			return &secretsmanager.CreateSecretOutput{}, nil
+		},
+		MockPutSecretValue: func(i *secretsmanager.PutSecretValueInput) (*secretsmanager.PutSecretValueOutput, error) {
+			value = i.SecretString
+			return &secretsmanager.PutSecretValueOutput{}, nil
+		},
+	}
+	ss := &Store{client: client, defaultScope: "crossplane-system"}
+
+	// Write all connection details, as a managed resource would when it is
+	// created, then a subset of them, as it would when it is observed.
+	for _, kv := range []connection.KeyValues{
+		{"username": []byte("cool"), "password": []byte("verysecure"), "stale": []byte("stale")},
+		{"endpoint": []byte("example.org"), "stale": nil},
+	} {
+		s := &connection.Secret{ScopedName: connection.ScopedName{Name: "cool"}, Data: kv}
+		if err := ss.WriteKeyValues(context.Background(), s); err != nil {
+			t.Fatalf("ss.WriteKeyValues(...): %s", err)
+		}
+	}
+
+	want := connection.KeyValues{"endpoint": []byte("example.org"), "password": []byte("verysecure"), "username": []byte("cool")}
+	got, err := fromSecretString(value)
+	if err != nil {
+		t.Fatalf("fromSecretString(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ss.WriteKeyValues(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestSecretStringRoundTrip(t *testing.T) {
+	// Connection details may be binary data that is not valid UTF-8.
+	want := connection.KeyValues{
+		"certificate": {0x30, 0x82, 0xff, 0xfe, 0x00, 0xc3},
+		"username":    []byte("cool"),
+	}
+	s, err := toSecretString(want)
+	if err != nil {
+		t.Fatalf("toSecretString(...): %s", err)
+	}
+	got, err := fromSecretString(s)
+	if err != nil {
+		t.Fatalf("fromSecretString(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("fromSecretString(toSecretString(...)): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDeleteKeyValues(t *testing.T) {
+	errBoom := errors.New("boom")
+	arn := aws.String("arn:aws:secretsmanager:us-west-2:123456789012:secret:cool")
+
+	type args struct {
+		client secretsmanageriface.SecretsManagerAPI
+		s      *connection.Secret
+		do     []connection.DeleteOption
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotFound": {
+			reason: "Deleting a secret that does not exist should succeed.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return nil, errNotFound
+					},
+				},
+				s: &connection.Secret{ScopedName: connection.ScopedName{Name: "cool"}},
+			},
+			want: nil,
+		},
+		"DeleteOptionError": {
+			reason: "We should not delete a secret if a delete option returns an error.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return &secretsmanager.DescribeSecretOutput{ARN: arn}, nil
+					},
+				},
+				s: &connection.Secret{ScopedName: connection.ScopedName{Name: "cool"}},
+				do: []connection.DeleteOption{func(_ context.Context, _ *connection.Secret) error {
+					return errBoom
+				}},
+			},
+			want: errBoom,
+		},
+		"Success": {
+			reason: "An existing secret should be deleted without a recovery window.",
+			args: args{
+				client: &mockClient{
+					MockDescribeSecret: func(_ *secretsmanager.DescribeSecretInput) (*secretsmanager.DescribeSecretOutput, error) {
+						return &secretsmanager.DescribeSecretOutput{ARN: arn}, nil
+					},
+					MockDeleteSecret: func(got *secretsmanager.DeleteSecretInput) (*secretsmanager.DeleteSecretOutput, error) {
+						want := &secretsmanager.DeleteSecretInput{SecretId: arn, ForceDeleteWithoutRecovery: aws.Bool(true)}
+						if diff := cmp.Diff(want, got); diff != "" {
+							t.Errorf("\nDeleteSecret(...): -want, +got:\n%s", diff)
+						}
+						return &secretsmanager.DeleteSecretOutput{}, nil
+					},
+				},
+				s: &connection.Secret{ScopedName: connection.ScopedName{Name: "cool"}},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ss := &Store{client: tc.args.client, defaultScope: "crossplane-system"}
+			err := ss.DeleteKeyValues(context.Background(), tc.args.s, tc.args.do...)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nss.DeleteKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"bytes"
+	"io/ioutil"
+	"text/template"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtParseTemplate   = "cannot parse template for key %q"
+	errFmtExecuteTemplate = "cannot execute template for key %q"
+)
+
+// Derive additional connection details from the supplied connection details
+// using the supplied templates. Templates are executed against a map of the
+// supplied connection details' keys to their string values. Derived connection
+// details are added to a copy of the supplied connection details, overwriting
+// any existing details with the same key. A template that references a
+// connection detail that does not exist is skipped, so that any existing
+// detail with its key is retained when only some connection details are
+// supplied, for example when a managed resource is observed rather than
+// created.
+func Derive(kv KeyValues, templates ...v1alpha1.ConnectionDetailTemplate) (KeyValues, error) {
+	if len(templates) == 0 {
+		return kv, nil
+	}
+
+	data := make(map[string]string, len(kv))
+	out := make(KeyValues, len(kv)+len(templates))
+	for k, v := range kv {
+		out[k] = v
+		if v != nil {
+			data[k] = string(v)
+		}
+	}
+
+	for _, t := range templates {
+		tmpl, err := template.New(t.Key).Option("missingkey=error").Parse(t.Template)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseTemplate, t.Key)
+		}
+		b := &bytes.Buffer{}
+		if err := tmpl.Execute(b, data); err != nil {
+			if missingKeysOnly(tmpl, data) {
+				continue
+			}
+			return nil, errors.Wrapf(err, errFmtExecuteTemplate, t.Key)
+		}
+		out[t.Key] = b.Bytes()
+	}
+
+	return out, nil
+}
+
+// missingKeysOnly returns true if the supplied template, which failed to
+// execute, would execute successfully if missing keys were permitted; i.e. if
+// it failed only because it referenced connection details that do not exist.
+func missingKeysOnly(tmpl *template.Template, data map[string]string) bool {
+	return tmpl.Option("missingkey=zero").Execute(ioutil.Discard, data) == nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+func TestDerive(t *testing.T) {
+	kv := KeyValues{
+		"endpoint": []byte("example.org"),
+		"port":     []byte("5432"),
+		"username": []byte("admin"),
+	}
+
+	type want struct {
+		kv  KeyValues
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason    string
+		templates []v1alpha1.ConnectionDetailTemplate
+		want      want
+	}{
+		"NoTemplates": {
+			reason: "Connection details should be returned unchanged if there are no templates.",
+			want:   want{kv: kv},
+		},
+		"Derived": {
+			reason: "Derived connection details should be added to the supplied connection details.",
+			templates: []v1alpha1.ConnectionDetailTemplate{
+				{Key: "url", Template: "jdbc:postgresql://{{ .endpoint }}:{{ .port }}/postgres?user={{ .username }}"},
+			},
+			want: want{kv: KeyValues{
+				"endpoint": []byte("example.org"),
+				"port":     []byte("5432"),
+				"username": []byte("admin"),
+				"url":      []byte("jdbc:postgresql://example.org:5432/postgres?user=admin"),
+			}},
+		},
+		"MissingKey": {
+			reason: "A template that references a connection detail that does not exist should be skipped.",
+			templates: []v1alpha1.ConnectionDetailTemplate{
+				{Key: "url", Template: "{{ .endpoint }}/{{ .database }}"},
+				{Key: "host", Template: "{{ .endpoint }}"},
+			},
+			want: want{kv: KeyValues{
+				"endpoint": []byte("example.org"),
+				"port":     []byte("5432"),
+				"username": []byte("admin"),
+				"host":     []byte("example.org"),
+			}},
+		},
+		"ExecuteError": {
+			reason: "A template that cannot be executed for reasons other than a missing connection detail should return an error.",
+			templates: []v1alpha1.ConnectionDetailTemplate{
+				{Key: "url", Template: "{{ index .endpoint 100 }}"},
+			},
+			want: want{err: true},
+		},
+		"InvalidTemplate": {
+			reason: "An invalid template should return an error.",
+			templates: []v1alpha1.ConnectionDetailTemplate{
+				{Key: "url", Template: "{{ .endpoint "},
+			},
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Derive(kv, tc.templates...)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\nReason: %s\nDerive(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kv, got); diff != "" {
+				t.Errorf("\nReason: %s\nDerive(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
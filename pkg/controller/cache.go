@@ -0,0 +1,40 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// NewCacheFn returns a function that builds a cache restricted to the supplied
+// namespaces. The cache watches all namespaces if none are supplied, a single
+// namespace if one is supplied, and each of the supplied namespaces otherwise.
+// Objects that are not namespaced are always cached.
+func NewCacheFn(namespaces ...string) cache.NewCacheFunc {
+	switch len(namespaces) {
+	case 0:
+		return cache.New
+	case 1:
+		return func(cfg *rest.Config, o cache.Options) (cache.Cache, error) {
+			o.Namespace = namespaces[0]
+			return cache.New(cfg, o)
+		}
+	default:
+		return cache.MultiNamespacedCacheBuilder(namespaces)
+	}
+}
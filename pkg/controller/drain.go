@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// DefaultDrainTimeout is a reasonable amount of time to wait for in-flight
+// reconciles to finish during shutdown. It is shorter than the default
+// Kubernetes termination grace period of thirty seconds.
+const DefaultDrainTimeout = 25 * time.Second
+
+// Error strings.
+const (
+	errDrainTimeout = "timed out waiting for in-flight reconciles to finish"
+)
+
+// A Drainer tracks in-flight reconciles so that a provider may wait for them
+// to finish before it exits. Terminating a provider mid-reconcile can leak
+// external resources; for example if it exits after an external resource was
+// created but before its external name was recorded.
+type Drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+// NewDrainer returns a new Drainer.
+func NewDrainer() *Drainer {
+	return &Drainer{}
+}
+
+// Reconciler wraps the supplied Reconciler such that its reconciles are
+// tracked by the Drainer. Reconciles that would start after the Drainer began
+// draining are requeued rather than run.
+func (d *Drainer) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(req reconcile.Request) (reconcile.Result, error) {
+		if !d.track() {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		defer d.inflight.Done()
+		return r.Reconcile(req)
+	})
+}
+
+func (d *Drainer) track() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inflight.Add(1)
+	return true
+}
+
+// Drain stops new reconciles from starting, then waits for in-flight
+// reconciles to finish. It returns an error if they do not finish within the
+// supplied timeout. Drain is typically called once the controller manager has
+// stopped, before the provider exits.
+func (d *Drainer) Drain(timeout time.Duration) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return errors.New(errDrainTimeout)
+	}
+}
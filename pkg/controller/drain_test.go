@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDrainer(t *testing.T) {
+	d := NewDrainer()
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	r := d.Reconciler(reconcile.Func(func(reconcile.Request) (reconcile.Result, error) {
+		close(started)
+		<-finish
+		return reconcile.Result{}, nil
+	}))
+
+	go func() { _, _ = r.Reconcile(reconcile.Request{}) }()
+	<-started
+
+	// The in-flight reconcile has not finished, so draining should time out.
+	if diff := cmp.Diff(errors.New(errDrainTimeout), d.Drain(10*time.Millisecond), test.EquateErrors()); diff != "" {
+		t.Errorf("d.Drain(...): -want error, +got error:\n%s", diff)
+	}
+
+	// New reconciles should be requeued rather than run once draining starts.
+	got, err := r.Reconcile(reconcile.Request{})
+	if diff := cmp.Diff(reconcile.Result{Requeue: true}, got); diff != "" {
+		t.Errorf("r.Reconcile(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("r.Reconcile(...): -want error, +got error:\n%s", diff)
+	}
+
+	// Draining should succeed once the in-flight reconcile finishes.
+	close(finish)
+	if diff := cmp.Diff(nil, d.Drain(time.Second), test.EquateErrors()); diff != "" {
+		t.Errorf("d.Drain(...): -want error, +got error:\n%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// NewImpersonatingClientFn returns a function that builds a client that
+// impersonates the identity, if any, associated with the context of each
+// request; see resource.ImpersonatingClient. Requests without an identity are
+// made using a client built by the supplied function, or using the manager's
+// default cache-backed client if it is nil.
+func NewImpersonatingClientFn(fn manager.NewClientFunc) manager.NewClientFunc {
+	if fn == nil {
+		fn = newCachingClient
+	}
+	return func(ca cache.Cache, cfg *rest.Config, o client.Options) (client.Client, error) {
+		c, err := fn(ca, cfg, o)
+		if err != nil {
+			return nil, err
+		}
+		return resource.NewImpersonatingClient(c, cfg, o.Scheme, resource.WithImpersonatingClientFn(func(cfg *rest.Config) (client.Client, error) {
+			return client.New(cfg, o)
+		})), nil
+	}
+}
+
+// newCachingClient builds a client that reads from the supplied cache and
+// writes to the API server, like the manager's default client.
+func newCachingClient(ca cache.Cache, cfg *rest.Config, o client.Options) (client.Client, error) {
+	c, err := client.New(cfg, o)
+	if err != nil {
+		return nil, err
+	}
+	return &client.DelegatingClient{
+		Reader:       &client.DelegatingReader{CacheReader: ca, ClientReader: c},
+		Writer:       c,
+		StatusClient: c,
+	}, nil
+}
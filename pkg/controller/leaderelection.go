@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// EnvPodNamespace is the environment variable from which the namespace in
+// which a provider runs is detected, typically populated using the downward
+// API.
+const EnvPodNamespace = "POD_NAMESPACE"
+
+const serviceAccountNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// LeaderElectionID returns the name of the lock used to elect a leader among
+// the replicas of the named provider.
+func LeaderElectionID(provider string) string {
+	return fmt.Sprintf("crossplane-leader-election-%s", strings.ToLower(provider))
+}
+
+// ForLeaderElection returns the supplied controller-runtime manager options,
+// updated such that the replicas of the named provider elect a leader. Only
+// the leader runs controllers, while all replicas keep their caches warm so
+// that a new leader may take over quickly. The lock is created in the
+// namespace in which the provider runs unless a namespace was explicitly
+// specified.
+func ForLeaderElection(mo manager.Options, provider string) manager.Options {
+	mo.LeaderElection = true
+	mo.LeaderElectionID = LeaderElectionID(provider)
+	if mo.LeaderElectionNamespace == "" {
+		mo.LeaderElectionNamespace = detectNamespace(os.Getenv, serviceAccountNamespacePath)
+	}
+	return mo
+}
+
+// detectNamespace returns the namespace in which the process is running, if
+// it can be detected.
+func detectNamespace(getenv func(string) string, path string) string {
+	if ns := getenv(EnvPodNamespace); ns != "" {
+		return ns
+	}
+	ns, err := ioutil.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(ns))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectNamespace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "namespace")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(...): %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "namespace")
+	if err := ioutil.WriteFile(file, []byte("filens\n"), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile(...): %s", err)
+	}
+
+	type args struct {
+		env  map[string]string
+		path string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"FromEnvironment": {
+			reason: "The namespace should be read from the environment if it is set",
+			args: args{
+				env:  map[string]string{EnvPodNamespace: "envns"},
+				path: file,
+			},
+			want: "envns",
+		},
+		"FromFile": {
+			reason: "The namespace should be read from the service account file if the environment is not set",
+			args: args{
+				path: file,
+			},
+			want: "filens",
+		},
+		"Undetectable": {
+			reason: "No namespace should be returned if it cannot be detected",
+			args: args{
+				path: filepath.Join(dir, "nope"),
+			},
+			want: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := detectNamespace(func(k string) string { return tc.args.env[k] }, tc.args.path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndetectNamespace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
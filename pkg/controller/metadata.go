@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtSyncMetadata = "cannot sync metadata cache of %s"
+	errFmtNotMetadata  = "cached object is a %T, not *v1.PartialObjectMetadata"
+)
+
+// A MetadataCacheOption configures a MetadataCache.
+type MetadataCacheOption func(*metadataCacheOptions)
+
+type metadataCacheOptions struct {
+	namespace string
+	resync    time.Duration
+	selector  string
+}
+
+// WithMetadataNamespace restricts a MetadataCache to objects in the supplied
+// namespace. Objects in all namespaces are cached by default.
+func WithMetadataNamespace(ns string) MetadataCacheOption {
+	return func(o *metadataCacheOptions) {
+		o.namespace = ns
+	}
+}
+
+// WithMetadataResyncPeriod specifies how frequently a MetadataCache resyncs.
+func WithMetadataResyncPeriod(d time.Duration) MetadataCacheOption {
+	return func(o *metadataCacheOptions) {
+		o.resync = d
+	}
+}
+
+// WithMetadataLabelSelector restricts a MetadataCache to objects matching the
+// supplied label selector.
+func WithMetadataLabelSelector(s string) MetadataCacheOption {
+	return func(o *metadataCacheOptions) {
+		o.selector = s
+	}
+}
+
+// A MetadataCache caches only the metadata of a particular kind of object,
+// for example Secrets. Watching high-volume secondary kinds as metadata, and
+// reading the full object from the API server only when a reconcile needs its
+// data, can significantly reduce a provider's memory usage and the bandwidth
+// consumed by its watches.
+type MetadataCache struct {
+	gvr      schema.GroupVersionResource
+	factory  metadatainformer.SharedInformerFactory
+	informer informers.GenericInformer
+}
+
+// NewMetadataCache returns a MetadataCache that caches the metadata of the
+// supplied kind of resource. The MetadataCache must be started before it is
+// read from, typically by adding it to a controller manager.
+func NewMetadataCache(c metadata.Interface, gvr schema.GroupVersionResource, o ...MetadataCacheOption) *MetadataCache {
+	opts := &metadataCacheOptions{}
+	for _, fn := range o {
+		fn(opts)
+	}
+
+	f := metadatainformer.NewFilteredSharedInformerFactory(c, opts.resync, opts.namespace, func(lo *metav1.ListOptions) {
+		lo.LabelSelector = opts.selector
+	})
+
+	// Informers must be requested from the factory before it is started.
+	return &MetadataCache{gvr: gvr, factory: f, informer: f.ForResource(gvr)}
+}
+
+// Get the metadata of the object with the supplied namespace and name from the
+// cache. The namespace should be empty for objects that are not namespaced.
+// Read the full object from the API server if its data is needed.
+func (c *MetadataCache) Get(namespace, name string) (*metav1.PartialObjectMetadata, error) {
+	var (
+		o   interface{}
+		err error
+	)
+	if namespace == "" {
+		o, err = c.informer.Lister().Get(name)
+	} else {
+		o, err = c.informer.Lister().ByNamespace(namespace).Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pom, ok := o.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return nil, errors.Errorf(errFmtNotMetadata, o)
+	}
+	return pom.DeepCopy(), nil
+}
+
+// Source returns a source of events for the cached objects, suitable for use
+// in a controller watch. Events contain *v1.PartialObjectMetadata objects,
+// which are sufficient for most event handlers, including those that enqueue
+// an object's owner.
+func (c *MetadataCache) Source() source.Source {
+	return &source.Informer{Informer: c.informer.Informer()}
+}
+
+// WaitForCacheSync blocks until the cache is synced, returning false if it
+// could not be synced before the supplied stop channel was closed.
+func (c *MetadataCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return cache.WaitForCacheSync(stop, c.informer.Informer().HasSynced)
+}
+
+// NeedLeaderElection returns false; every replica of a provider keeps its
+// MetadataCache warm, not only the leader.
+func (c *MetadataCache) NeedLeaderElection() bool {
+	return false
+}
+
+// Start the MetadataCache, blocking until the supplied stop channel is
+// closed.
+func (c *MetadataCache) Start(stop <-chan struct{}) error {
+	c.factory.Start(stop)
+	for gvr, ok := range c.factory.WaitForCacheSync(stop) {
+		if !ok {
+			return errors.Errorf(errFmtSyncMetadata, gvr)
+		}
+	}
+	<-stop
+	return nil
+}
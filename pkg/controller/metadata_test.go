@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata/fake"
+)
+
+func TestMetadataCache(t *testing.T) {
+	wanted := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "coolns",
+			Name:      "wanted",
+			Labels:    map[string]string{"cool": "true"},
+		},
+	}
+	unwanted := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "coolns",
+			Name:      "unwanted",
+		},
+	}
+
+	s := runtime.NewScheme()
+	metav1.AddMetaToScheme(s)
+	mc := fake.NewSimpleMetadataClient(s, wanted, unwanted)
+	c := NewMetadataCache(mc, schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, WithMetadataLabelSelector("cool=true"))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() { _ = c.Start(stop) }()
+	if !c.WaitForCacheSync(stop) {
+		t.Fatal("c.WaitForCacheSync(...): cache did not sync")
+	}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   *metav1.PartialObjectMetadata
+		found  bool
+	}{
+		"Matching": {
+			reason: "The metadata of an object that matches the selector should be read from the cache",
+			name:   "wanted",
+			want:   wanted,
+			found:  true,
+		},
+		"NotMatching": {
+			reason: "An object that does not match the selector should not be found",
+			name:   "unwanted",
+			found:  false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := c.Get("coolns", tc.name)
+			if diff := cmp.Diff(tc.found, !kerrors.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want found, +got found:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
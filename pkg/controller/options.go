@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controller configures controllers that are built using this
+// runtime.
+package controller
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/otlp"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Defaults.
+const (
+	DefaultMaxConcurrentReconciles = 1
+	DefaultPollInterval            = 1 * time.Minute
+)
+
+// Options frequently passed to controllers, and the reconcilers they run. A
+// provider typically builds one set of Options and passes it to the setup
+// function of each of its controllers.
+type Options struct {
+	// Logger used by reconcilers.
+	Logger logging.Logger
+
+	// Recorder used by reconcilers to emit events.
+	Recorder event.Recorder
+
+	// GlobalRateLimiter limits the rate at which reconciles are run. It is
+	// typically shared by all controllers of a provider. Reconciles are not
+	// globally rate limited if it is nil.
+	GlobalRateLimiter workqueue.RateLimiter
+
+	// MaxConcurrentReconciles is the number of reconciles each controller may
+	// run concurrently.
+	MaxConcurrentReconciles int
+
+	// PollInterval is how frequently resources that are up to date are
+	// reconciled in order to detect drift.
+	PollInterval time.Duration
+
+	// Namespaces to which informer caches and reconcilers are restricted. All
+	// namespaces are watched and reconciled if it is empty. Connection secrets
+	// are read through the same caches, so the namespaces they are written to
+	// must be included.
+	Namespaces []string
+
+	// Drainer tracks in-flight reconciles so that shutdown may wait for them
+	// to finish. Reconciles are not tracked if it is nil.
+	Drainer *Drainer
+
+	// Features that are enabled for all controllers.
+	Features *feature.Flags
+
+	// ControllerFeatures are enabled only for particular controllers, keyed
+	// by controller name. This allows a feature to be rolled out to a subset
+	// of a provider's controllers.
+	ControllerFeatures map[string]*feature.Flags
+
+	// Impersonation causes the manager's client to impersonate the identity
+	// that a reconciler associates with each request, for example using
+	// managed.WithImpersonation, so that per-tenant RBAC is enforced on what
+	// the reconciler may read and write on a tenant's behalf.
+	Impersonation bool
+
+	// MetricsExporter pushes metrics to an OpenTelemetry collector, in
+	// addition to exposing them to Prometheus. Metrics are only exposed to
+	// Prometheus if it is nil.
+	MetricsExporter *otlp.Exporter
+}
+
+// DefaultOptions returns a functional set of Options that log and record
+// events nowhere, and do not globally rate limit reconciles.
+func DefaultOptions() Options {
+	return Options{
+		Logger:                  logging.NewNopLogger(),
+		Recorder:                event.NewNopRecorder(),
+		MaxConcurrentReconciles: DefaultMaxConcurrentReconciles,
+		PollInterval:            DefaultPollInterval,
+	}
+}
+
+// ForController returns a copy of these Options for the named controller. Its
+// Features are the union of the Features enabled for all controllers, and
+// those enabled for the named controller.
+func (o Options) ForController(name string) Options {
+	scoped, ok := o.ControllerFeatures[name]
+	if !ok {
+		return o
+	}
+	o.Features = feature.Union(o.Features, scoped)
+	return o
+}
+
+// ForControllerRuntime returns controller-runtime controller options derived
+// from these Options.
+func (o Options) ForControllerRuntime() controller.Options {
+	return controller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles}
+}
+
+// RateLimited returns the supplied Reconciler, wrapped such that it is limited
+// by the GlobalRateLimiter, if any.
+func (o Options) RateLimited(r reconcile.Reconciler) reconcile.Reconciler {
+	if o.GlobalRateLimiter == nil {
+		return r
+	}
+	return ratelimiter.NewReconciler(r, o.GlobalRateLimiter)
+}
+
+// Drained returns the supplied Reconciler, wrapped such that its reconciles
+// are tracked by the Drainer, if any.
+func (o Options) Drained(r reconcile.Reconciler) reconcile.Reconciler {
+	if o.Drainer == nil {
+		return r
+	}
+	return o.Drainer.Reconciler(r)
+}
+
+// ForManager returns the supplied controller-runtime manager options, updated
+// such that the manager's caches are restricted to the Namespaces of these
+// Options, if any, and such that its client impersonates if Impersonation is
+// enabled.
+func (o Options) ForManager(mo manager.Options) manager.Options {
+	if len(o.Namespaces) > 0 {
+		mo.Namespace = ""
+		mo.NewCache = NewCacheFn(o.Namespaces...)
+	}
+	if o.Impersonation {
+		mo.NewClient = NewImpersonatingClientFn(mo.NewClient)
+	}
+	return mo
+}
+
+// InNamespaces returns a PredicateFn that accepts only objects in the
+// Namespaces of these Options, if any.
+func (o Options) InNamespaces() resource.PredicateFn {
+	return resource.IsInNamespace(o.Namespaces...)
+}
+
+// ExportMetrics adds the MetricsExporter, if any, to the supplied manager, such
+// that metrics are pushed while the manager runs.
+func (o Options) ExportMetrics(mgr manager.Manager) error {
+	if o.MetricsExporter == nil {
+		return nil
+	}
+	return mgr.Add(o.MetricsExporter)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+)
+
+func TestForControllerRuntime(t *testing.T) {
+	o := Options{MaxConcurrentReconciles: 3}
+	want := controller.Options{MaxConcurrentReconciles: 3}
+	if diff := cmp.Diff(want, o.ForControllerRuntime()); diff != "" {
+		t.Errorf("\no.ForControllerRuntime(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	r := reconcile.Func(func(reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, nil })
+
+	cases := map[string]struct {
+		reason  string
+		o       Options
+		limited bool
+	}{
+		"NoGlobalRateLimiter": {
+			reason:  "The Reconciler should be returned as is when there is no GlobalRateLimiter",
+			o:       Options{},
+			limited: false,
+		},
+		"GlobalRateLimiter": {
+			reason:  "The Reconciler should be rate limited when there is a GlobalRateLimiter",
+			o:       Options{GlobalRateLimiter: ratelimiter.NewGlobal(1)},
+			limited: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, limited := tc.o.RateLimited(r).(*ratelimiter.Reconciler)
+			if diff := cmp.Diff(tc.limited, limited); diff != "" {
+				t.Errorf("\n%s\no.RateLimited(...): -want limited, +got limited:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestForManager(t *testing.T) {
+	cases := map[string]struct {
+		reason         string
+		o              Options
+		wantFunc       bool
+		wantClientFunc bool
+	}{
+		"AllNamespaces": {
+			reason:   "The manager's cache should not be restricted when there are no Namespaces",
+			o:        Options{},
+			wantFunc: false,
+		},
+		"SomeNamespaces": {
+			reason:   "The manager's cache should be restricted when there are Namespaces",
+			o:        Options{Namespaces: []string{"coolns", "otherns"}},
+			wantFunc: true,
+		},
+		"Impersonation": {
+			reason:         "The manager's client should impersonate when Impersonation is enabled",
+			o:              Options{Impersonation: true},
+			wantClientFunc: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.o.ForManager(manager.Options{})
+			if diff := cmp.Diff(tc.wantFunc, got.NewCache != nil); diff != "" {
+				t.Errorf("\n%s\no.ForManager(...): -want NewCache, +got NewCache:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantClientFunc, got.NewClient != nil); diff != "" {
+				t.Errorf("\n%s\no.ForManager(...): -want NewClient, +got NewClient:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestForController(t *testing.T) {
+	global := &feature.Flags{}
+	global.Enable("EnableAlphaGlobal")
+
+	scoped := &feature.Flags{}
+	scoped.Enable("EnableAlphaScoped")
+
+	o := Options{
+		Features:           global,
+		ControllerFeatures: map[string]*feature.Flags{"managed/cool": scoped},
+	}
+
+	cases := map[string]struct {
+		reason     string
+		controller string
+		want       map[feature.Flag]bool
+	}{
+		"Unscoped": {
+			reason:     "A controller without scoped features should have only the global features",
+			controller: "managed/lame",
+			want:       map[feature.Flag]bool{"EnableAlphaGlobal": true, "EnableAlphaScoped": false},
+		},
+		"Scoped": {
+			reason:     "A controller with scoped features should have both the global and scoped features",
+			controller: "managed/cool",
+			want:       map[feature.Flag]bool{"EnableAlphaGlobal": true, "EnableAlphaScoped": true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := o.ForController(tc.controller).Features
+			for f, want := range tc.want {
+				if diff := cmp.Diff(want, got.Enabled(f)); diff != "" {
+					t.Errorf("\n%s\no.ForController(...).Features.Enabled(%s): -want, +got:\n%s", tc.reason, f, diff)
+				}
+			}
+		})
+	}
+}
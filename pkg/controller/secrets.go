@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtSyncSecrets = "cannot sync cache of %s"
+)
+
+// A SecretCacheOption configures a SecretCache.
+type SecretCacheOption func(*secretCacheOptions)
+
+type secretCacheOptions struct {
+	namespace string
+	resync    time.Duration
+}
+
+// WithSecretNamespace restricts a SecretCache to Secrets in the supplied
+// namespace. Secrets in all namespaces are cached by default.
+func WithSecretNamespace(ns string) SecretCacheOption {
+	return func(o *secretCacheOptions) {
+		o.namespace = ns
+	}
+}
+
+// WithSecretResyncPeriod specifies how frequently a SecretCache resyncs.
+func WithSecretResyncPeriod(d time.Duration) SecretCacheOption {
+	return func(o *secretCacheOptions) {
+		o.resync = d
+	}
+}
+
+// A SecretCache is a client.Client that reads Secrets from an informer cache
+// that contains only the Secrets matching a label selector. All other reads,
+// and all writes, are delegated to the wrapped client. Caching only the
+// Secrets a provider is interested in (for example its connection secrets)
+// rather than every Secret in the cluster can dramatically reduce its memory
+// usage. Note that a Secret that does not match the selector is not found.
+type SecretCache struct {
+	client.Client
+
+	factory  informers.SharedInformerFactory
+	informer cache.SharedIndexInformer
+	secrets  corelisters.SecretLister
+}
+
+// NewSecretCache returns a SecretCache that caches the Secrets matching the
+// supplied label selector, and delegates all other operations to the supplied
+// client. The SecretCache must be started before it is read from, typically
+// by adding it to a controller manager.
+func NewSecretCache(c client.Client, cs kubernetes.Interface, s labels.Selector, o ...SecretCacheOption) *SecretCache {
+	opts := &secretCacheOptions{}
+	for _, fn := range o {
+		fn(opts)
+	}
+
+	f := informers.NewSharedInformerFactoryWithOptions(cs, opts.resync,
+		informers.WithNamespace(opts.namespace),
+		informers.WithTweakListOptions(func(lo *metav1.ListOptions) { lo.LabelSelector = s.String() }),
+	)
+
+	// Informers must be requested from the factory before it is started.
+	i := f.Core().V1().Secrets()
+	return &SecretCache{Client: c, factory: f, informer: i.Informer(), secrets: i.Lister()}
+}
+
+// Get the Secret identified by the supplied key from the cache, or any other
+// kind of object from the wrapped client.
+func (c *SecretCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	s, ok := obj.(*corev1.Secret)
+	if !ok {
+		return c.Client.Get(ctx, key, obj)
+	}
+	got, err := c.secrets.Secrets(key.Namespace).Get(key.Name)
+	if err != nil {
+		return err
+	}
+	got.DeepCopyInto(s)
+	return nil
+}
+
+// List Secrets from the cache, or any other kind of object from the wrapped
+// client.
+func (c *SecretCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	sl, ok := list.(*corev1.SecretList)
+	if !ok {
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	lo := (&client.ListOptions{}).ApplyOptions(opts)
+	sel := labels.Everything()
+	if lo.LabelSelector != nil {
+		sel = lo.LabelSelector
+	}
+
+	var items []*corev1.Secret
+	var err error
+	if lo.Namespace != "" {
+		items, err = c.secrets.Secrets(lo.Namespace).List(sel)
+	} else {
+		items, err = c.secrets.List(sel)
+	}
+	if err != nil {
+		return err
+	}
+
+	sl.Items = make([]corev1.Secret, len(items))
+	for i := range items {
+		items[i].DeepCopyInto(&sl.Items[i])
+	}
+	return nil
+}
+
+// Source returns a source of events for the cached Secrets, suitable for use
+// in a controller watch.
+func (c *SecretCache) Source() source.Source {
+	return &source.Informer{Informer: c.informer}
+}
+
+// WaitForCacheSync blocks until the cache is synced, returning false if it
+// could not be synced before the supplied stop channel was closed.
+func (c *SecretCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return cache.WaitForCacheSync(stop, c.informer.HasSynced)
+}
+
+// NeedLeaderElection returns false; every replica of a provider keeps its
+// SecretCache warm, not only the leader.
+func (c *SecretCache) NeedLeaderElection() bool {
+	return false
+}
+
+// Start the SecretCache, blocking until the supplied stop channel is closed.
+func (c *SecretCache) Start(stop <-chan struct{}) error {
+	c.factory.Start(stop)
+	for t, ok := range c.factory.WaitForCacheSync(stop) {
+		if !ok {
+			return errors.Errorf(errFmtSyncSecrets, t)
+		}
+	}
+	<-stop
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSecretCache(t *testing.T) {
+	wanted := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "coolns",
+		Name:      "wanted",
+		Labels:    map[string]string{"cool": "true"},
+	}}
+	unwanted := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "coolns",
+		Name:      "unwanted",
+	}}
+
+	cs := fake.NewSimpleClientset(wanted, unwanted)
+	c := NewSecretCache(nil, cs, labels.SelectorFromSet(labels.Set{"cool": "true"}))
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() { _ = c.Start(stop) }()
+	if !c.WaitForCacheSync(stop) {
+		t.Fatal("c.WaitForCacheSync(...): cache did not sync")
+	}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   *corev1.Secret
+		found  bool
+	}{
+		"Matching": {
+			reason: "A Secret that matches the selector should be read from the cache",
+			name:   "wanted",
+			want:   wanted,
+			found:  true,
+		},
+		"NotMatching": {
+			reason: "A Secret that does not match the selector should not be found",
+			name:   "unwanted",
+			want:   &corev1.Secret{},
+			found:  false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := &corev1.Secret{}
+			err := c.Get(context.Background(), types.NamespacedName{Namespace: "coolns", Name: tc.name}, got)
+			if diff := cmp.Diff(tc.found, !kerrors.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want found, +got found:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	l := &corev1.SecretList{}
+	if err := c.List(context.Background(), l, client.InNamespace("coolns")); err != nil {
+		t.Fatalf("c.List(...): %s", err)
+	}
+	if diff := cmp.Diff([]corev1.Secret{*wanted}, l.Items); diff != "" {
+		t.Errorf("c.List(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion contains utilities for converting managed resources
+// between API versions. Each kind of managed resource served at multiple API
+// versions has one hub version, to and from which all other versions convert.
+// Register converting kinds with the webhook/conversion package to serve
+// these conversions.
+package conversion
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+// Error strings.
+const (
+	errConvertToUnstructured   = "cannot convert source object to unstructured data"
+	errConvertFromUnstructured = "cannot convert unstructured data to destination object"
+	errConvertTo               = "cannot convert to hub version"
+	errConvertFrom             = "cannot convert from hub version"
+	errNotConvertible          = "object must be a pointer to a convertible type"
+
+	errFmtRename    = "cannot rename field %q to %q"
+	errFmtDrop      = "cannot drop field %q"
+	errFmtDefault   = "cannot default field %q"
+	errFmtTransform = "cannot transform field %q to %q"
+)
+
+// A Hub is the version of a kind to and from which all other versions of the
+// kind convert. A Hub need only implement a no-op Hub method.
+type Hub = conversion.Hub
+
+// A Convertible is a version of a kind that is not the Hub version. It must
+// be able to convert itself to and from the Hub version, typically using
+// Convert.
+type Convertible = conversion.Convertible
+
+// A FieldMapping maps a field of a source object to the destination object.
+// The destination object starts as a copy of the source object, so fields that
+// have the same path and schema in both versions need not be mapped. Mappings
+// always read from the unmodified source object, so the order in which they're
+// applied only matters when they write to the same field.
+type FieldMapping func(src, dst *fieldpath.Paved) error
+
+// Rename the field at the supplied 'from' path to the supplied 'to' path. The
+// field is not renamed if it does not exist in the source object.
+func Rename(from, to string) FieldMapping {
+	return func(src, dst *fieldpath.Paved) error {
+		v, err := src.GetValue(from)
+		if err != nil {
+			// The field doesn't exist, so there's nothing to rename.
+			return nil
+		}
+		if err := dst.DeleteField(from); err != nil {
+			return errors.Wrapf(err, errFmtRename, from, to)
+		}
+		return errors.Wrapf(dst.SetValue(to, v), errFmtRename, from, to)
+	}
+}
+
+// Drop the field at the supplied path, for example because it does not exist
+// in the destination version.
+func Drop(path string) FieldMapping {
+	return func(_, dst *fieldpath.Paved) error {
+		return errors.Wrapf(dst.DeleteField(path), errFmtDrop, path)
+	}
+}
+
+// Default the field at the supplied path to the supplied value, if it is not
+// set in the destination object. Use it for fields that are required in the
+// destination version but optional or nonexistent in the source version.
+func Default(path string, value interface{}) FieldMapping {
+	return func(_, dst *fieldpath.Paved) error {
+		if _, err := dst.GetValue(path); err == nil {
+			return nil
+		}
+		return errors.Wrapf(dst.SetValue(path, value), errFmtDefault, path)
+	}
+}
+
+// Transform the field at the supplied 'from' path using the supplied function,
+// writing the result to the supplied 'to' path. The field is not transformed
+// if it does not exist in the source object. The source field is removed from
+// the destination object if the paths differ.
+func Transform(from, to string, fn func(v interface{}) (interface{}, error)) FieldMapping {
+	return func(src, dst *fieldpath.Paved) error {
+		v, err := src.GetValue(from)
+		if err != nil {
+			// The field doesn't exist, so there's nothing to transform.
+			return nil
+		}
+		nv, err := fn(v)
+		if err != nil {
+			return errors.Wrapf(err, errFmtTransform, from, to)
+		}
+		if from != to {
+			if err := dst.DeleteField(from); err != nil {
+				return errors.Wrapf(err, errFmtTransform, from, to)
+			}
+		}
+		return errors.Wrapf(dst.SetValue(to, nv), errFmtTransform, from, to)
+	}
+}
+
+// Convert the supplied source object to the supplied destination object, which
+// is typically another version of the same kind. All fields of the source
+// object are copied to the destination object, except those that are changed
+// by the supplied FieldMappings. The destination object's apiVersion and kind
+// are not changed. Fields of the source object that do not exist in the
+// destination object's schema are silently dropped.
+func Convert(src, dst runtime.Object, m ...FieldMapping) error {
+	from, err := runtime.DefaultUnstructuredConverter.ToUnstructured(src)
+	if err != nil {
+		return errors.Wrap(err, errConvertToUnstructured)
+	}
+	to, err := runtime.DefaultUnstructuredConverter.ToUnstructured(src)
+	if err != nil {
+		return errors.Wrap(err, errConvertToUnstructured)
+	}
+	delete(to, "apiVersion")
+	delete(to, "kind")
+
+	sp, dp := fieldpath.Pave(from), fieldpath.Pave(to)
+	for _, fn := range m {
+		if err := fn(sp, dp); err != nil {
+			return err
+		}
+	}
+
+	gvk := dst.GetObjectKind().GroupVersionKind()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(dp.UnstructuredContent(), dst); err != nil {
+		return errors.Wrap(err, errConvertFromUnstructured)
+	}
+	dst.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+// RoundTrip converts the supplied Convertible to the supplied Hub, and then
+// back to a new Convertible of the same type. It returns the new Convertible,
+// which should be identical to the supplied one if no information was lost in
+// conversion. RoundTrip is intended for use in tests.
+func RoundTrip(c Convertible, h Hub) (Convertible, error) {
+	if err := c.ConvertTo(h); err != nil {
+		return nil, errors.Wrap(err, errConvertTo)
+	}
+	out, ok := newOf(c).(Convertible)
+	if !ok {
+		return nil, errors.New(errNotConvertible)
+	}
+	out.GetObjectKind().SetGroupVersionKind(c.GetObjectKind().GroupVersionKind())
+	return out, errors.Wrap(out.ConvertFrom(h), errConvertFrom)
+}
+
+// RoundTripHub converts the supplied Hub to the supplied Convertible, and then
+// back to a new Hub of the same type. It returns the new Hub, which should be
+// identical to the supplied one if no information was lost in conversion.
+// RoundTripHub is intended for use in tests.
+func RoundTripHub(h Hub, c Convertible) (Hub, error) {
+	if err := c.ConvertFrom(h); err != nil {
+		return nil, errors.Wrap(err, errConvertFrom)
+	}
+	out, ok := newOf(h).(Hub)
+	if !ok {
+		return nil, errors.New(errNotConvertible)
+	}
+	out.GetObjectKind().SetGroupVersionKind(h.GetObjectKind().GroupVersionKind())
+	return out, errors.Wrap(c.ConvertTo(out), errConvertTo)
+}
+
+// newOf returns a new, empty object of the same type as the supplied object,
+// which must be a pointer.
+func newOf(o runtime.Object) interface{} {
+	t := reflect.TypeOf(o)
+	if t.Kind() != reflect.Ptr {
+		return nil
+	}
+	return reflect.New(t.Elem()).Interface()
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	v1GVK = schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+	v2GVK = schema.GroupVersionKind{Group: "example.org", Version: "v2", Kind: "Cool"}
+)
+
+// CoolV1 is a spoke version of an example kind.
+type CoolV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CoolV1Spec `json:"spec"`
+}
+
+type CoolV1Spec struct {
+	Size   string `json:"size,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
+func (c *CoolV1) DeepCopyObject() runtime.Object {
+	out := *c
+	c.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+func (c *CoolV1) ConvertTo(h Hub) error {
+	return Convert(c, h,
+		Rename("spec.size", "spec.forProvider.instanceSize"),
+		Transform("spec.region", "spec.forProvider.location", upper),
+	)
+}
+
+func (c *CoolV1) ConvertFrom(h Hub) error {
+	return Convert(h, c,
+		Rename("spec.forProvider.instanceSize", "spec.size"),
+		Transform("spec.forProvider.location", "spec.region", lower),
+		Drop("spec.forProvider"),
+	)
+}
+
+// CoolV2 is the hub version of an example kind.
+type CoolV2 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CoolV2Spec `json:"spec"`
+}
+
+type CoolV2Spec struct {
+	ForProvider CoolV2Parameters `json:"forProvider"`
+}
+
+type CoolV2Parameters struct {
+	InstanceSize string `json:"instanceSize,omitempty"`
+	Location     string `json:"location,omitempty"`
+	Tier         string `json:"tier,omitempty"`
+}
+
+func (c *CoolV2) DeepCopyObject() runtime.Object {
+	out := *c
+	c.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+func (c *CoolV2) Hub() {}
+
+func upper(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	return strings.ToUpper(s), nil
+}
+
+func lower(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	return strings.ToLower(s), nil
+}
+
+func TestConvert(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		src runtime.Object
+		dst runtime.Object
+		m   []FieldMapping
+	}
+	type want struct {
+		dst runtime.Object
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ConvertTo": {
+			reason: "Fields should be copied or mapped, without changing the destination's apiVersion and kind.",
+			args: args{
+				src: &CoolV1{
+					TypeMeta:   metav1.TypeMeta{APIVersion: v1GVK.GroupVersion().String(), Kind: v1GVK.Kind},
+					ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: map[string]string{"cool": "true"}},
+					Spec:       CoolV1Spec{Size: "large", Region: "us-west"},
+				},
+				dst: &CoolV2{TypeMeta: metav1.TypeMeta{APIVersion: v2GVK.GroupVersion().String(), Kind: v2GVK.Kind}},
+				m: []FieldMapping{
+					Rename("spec.size", "spec.forProvider.instanceSize"),
+					Rename("spec.region", "spec.forProvider.location"),
+					Rename("spec.missing", "spec.forProvider.missing"),
+					Default("spec.forProvider.tier", "standard"),
+				},
+			},
+			want: want{
+				dst: &CoolV2{
+					TypeMeta:   metav1.TypeMeta{APIVersion: v2GVK.GroupVersion().String(), Kind: v2GVK.Kind},
+					ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: map[string]string{"cool": "true"}},
+					Spec:       CoolV2Spec{ForProvider: CoolV2Parameters{InstanceSize: "large", Location: "us-west", Tier: "standard"}},
+				},
+			},
+		},
+		"DefaultNotOverwritten": {
+			reason: "Default should not overwrite a field that is already set.",
+			args: args{
+				src: &CoolV2{Spec: CoolV2Spec{ForProvider: CoolV2Parameters{Tier: "premium"}}},
+				dst: &CoolV2{},
+				m:   []FieldMapping{Default("spec.forProvider.tier", "standard")},
+			},
+			want: want{
+				dst: &CoolV2{Spec: CoolV2Spec{ForProvider: CoolV2Parameters{Tier: "premium"}}},
+			},
+		},
+		"TransformError": {
+			reason: "Errors transforming a field should be returned.",
+			args: args{
+				src: &CoolV1{Spec: CoolV1Spec{Size: "large"}},
+				dst: &CoolV2{},
+				m: []FieldMapping{Transform("spec.size", "spec.forProvider.instanceSize", func(_ interface{}) (interface{}, error) {
+					return nil, errBoom
+				})},
+			},
+			want: want{
+				dst: &CoolV2{},
+				err: errors.Wrapf(errBoom, errFmtTransform, "spec.size", "spec.forProvider.instanceSize"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Convert(tc.args.src, tc.args.dst, tc.args.m...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dst, tc.args.dst); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	spoke := &CoolV1{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1GVK.GroupVersion().String(), Kind: v1GVK.Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec:       CoolV1Spec{Size: "large", Region: "us-west"},
+	}
+	hub := &CoolV2{TypeMeta: metav1.TypeMeta{APIVersion: v2GVK.GroupVersion().String(), Kind: v2GVK.Kind}}
+
+	got, err := RoundTrip(spoke, hub)
+	if err != nil {
+		t.Fatalf("RoundTrip(...): %s", err)
+	}
+	if diff := cmp.Diff(spoke, got); diff != "" {
+		t.Errorf("RoundTrip(...): -want, +got:\n%s", diff)
+	}
+
+	wantHub := &CoolV2{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v2GVK.GroupVersion().String(), Kind: v2GVK.Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec:       CoolV2Spec{ForProvider: CoolV2Parameters{InstanceSize: "large", Location: "US-WEST"}},
+	}
+	if diff := cmp.Diff(wantHub, hub); diff != "" {
+		t.Errorf("RoundTrip(...): -want hub, +got hub:\n%s", diff)
+	}
+}
+
+func TestRoundTripHub(t *testing.T) {
+	hub := &CoolV2{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v2GVK.GroupVersion().String(), Kind: v2GVK.Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec:       CoolV2Spec{ForProvider: CoolV2Parameters{InstanceSize: "large", Location: "US-WEST", Tier: "premium"}},
+	}
+
+	// The spoke version has no tier, so it is lost in conversion.
+	want := hub.DeepCopyObject().(*CoolV2)
+	want.Spec.ForProvider.Tier = ""
+
+	got, err := RoundTripHub(hub, &CoolV1{})
+	if err != nil {
+		t.Fatalf("RoundTripHub(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RoundTripHub(...): -want, +got:\n%s", diff)
+	}
+}
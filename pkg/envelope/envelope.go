@@ -0,0 +1,249 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope encrypts the data of connection secrets using envelope
+// encryption, for environments in which etcd encryption at rest is not
+// trusted. Each secret's data is encrypted using a unique data key, which is
+// itself encrypted by a key encryption key held by a KeyProvider such as a
+// KMS, and stored alongside the data it encrypts.
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// AnnotationKeyDataKey is the key in the annotations map of an encrypted
+// secret for its base64 encoded, encrypted data key.
+const AnnotationKeyDataKey = "secret.crossplane.io/encrypted-data-key"
+
+// dataKeySize is the size of data keys in bytes. Data keys are used for
+// AES-256-GCM.
+const dataKeySize = 32
+
+// Error strings.
+const (
+	errGenerateDataKey = "cannot generate data key"
+	errDecryptDataKey  = "cannot decrypt data key"
+	errDecodeDataKey   = "cannot decode data key"
+	errNewCipher       = "cannot create cipher"
+	errReadRandom      = "cannot read random bytes"
+	errShortCiphertext = "ciphertext is too short"
+
+	errFmtEncryptKey = "cannot encrypt secret key %q"
+	errFmtDecryptKey = "cannot decrypt secret key %q"
+)
+
+// A KeyProvider provides data keys that are encrypted by a key encryption key
+// that never leaves the KeyProvider, for example a key held by a cloud KMS or
+// a Kubernetes KMS plugin.
+type KeyProvider interface {
+	// GenerateDataKey returns a new data key in plaintext, and encrypted by
+	// the key encryption key.
+	GenerateDataKey(ctx context.Context) (plaintext, ciphertext []byte, err error)
+
+	// DecryptDataKey returns the plaintext of the supplied encrypted data
+	// key.
+	DecryptDataKey(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// KeyProviderFns are a series of functions that satisfy the KeyProvider
+// interface.
+type KeyProviderFns struct {
+	GenerateDataKeyFn func(ctx context.Context) (plaintext, ciphertext []byte, err error)
+	DecryptDataKeyFn  func(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// GenerateDataKey calls GenerateDataKeyFn.
+func (fns KeyProviderFns) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	return fns.GenerateDataKeyFn(ctx)
+}
+
+// DecryptDataKey calls DecryptDataKeyFn.
+func (fns KeyProviderFns) DecryptDataKey(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return fns.DecryptDataKeyFn(ctx, ciphertext)
+}
+
+// A LocalKeyProvider provides data keys encrypted by a key encryption key that
+// is held in memory, for example one read from a file mounted into the
+// provider's pod. It is intended for environments without a KMS.
+type LocalKeyProvider struct {
+	kek  []byte
+	rand io.Reader
+}
+
+// NewLocalKeyProvider returns a KeyProvider that encrypts data keys using the
+// supplied 16, 24, or 32 byte AES key encryption key.
+func NewLocalKeyProvider(kek []byte) *LocalKeyProvider {
+	return &LocalKeyProvider{kek: kek, rand: rand.Reader}
+}
+
+// GenerateDataKey returns a new random data key in plaintext, and encrypted by
+// the key encryption key.
+func (p *LocalKeyProvider) GenerateDataKey(_ context.Context) ([]byte, []byte, error) {
+	dk := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(p.rand, dk); err != nil {
+		return nil, nil, errors.Wrap(err, errReadRandom)
+	}
+	ct, err := seal(p.rand, p.kek, dk, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dk, ct, nil
+}
+
+// DecryptDataKey returns the plaintext of the supplied encrypted data key.
+func (p *LocalKeyProvider) DecryptDataKey(_ context.Context, ciphertext []byte) ([]byte, error) {
+	return open(p.kek, ciphertext, nil)
+}
+
+// An Encrypter encrypts and decrypts the data of secrets.
+type Encrypter struct {
+	keys KeyProvider
+	rand io.Reader
+}
+
+// NewEncrypter returns an Encrypter that encrypts secret data using data keys
+// provided by the supplied KeyProvider.
+func NewEncrypter(kp KeyProvider) *Encrypter {
+	return &Encrypter{keys: kp, rand: rand.Reader}
+}
+
+// IsEncrypted returns true if the supplied secret's data is encrypted.
+func IsEncrypted(s *corev1.Secret) bool {
+	return s.GetAnnotations()[AnnotationKeyDataKey] != ""
+}
+
+// EncryptSecret encrypts each value of the supplied secret's data, which must
+// be plaintext. Each value is authenticated along with its key, so that values
+// may not be moved between keys. If the secret already has an encrypted data
+// key annotation that data key is reused, so that the secret's data may be
+// merged with the data of an existing secret encrypted using the same key.
+// Otherwise a new data key is generated and stored, encrypted, in the secret's
+// annotations. Nil values are not encrypted, so that they still delete their
+// key when the secret is patched.
+func (e *Encrypter) EncryptSecret(ctx context.Context, s *corev1.Secret) error {
+	dk, edk, err := e.dataKey(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string][]byte, len(s.Data))
+	for k, v := range s.Data {
+		if v == nil {
+			data[k] = nil
+			continue
+		}
+		ct, err := seal(e.rand, dk, v, []byte(k))
+		if err != nil {
+			return errors.Wrapf(err, errFmtEncryptKey, k)
+		}
+		data[k] = ct
+	}
+	s.Data = data
+	meta.AddAnnotations(s, map[string]string{AnnotationKeyDataKey: base64.StdEncoding.EncodeToString(edk)})
+	return nil
+}
+
+// dataKey returns the plaintext and encrypted data key of the supplied secret,
+// generating a new data key if the secret does not yet have one.
+func (e *Encrypter) dataKey(ctx context.Context, s *corev1.Secret) ([]byte, []byte, error) {
+	if !IsEncrypted(s) {
+		dk, edk, err := e.keys.GenerateDataKey(ctx)
+		return dk, edk, errors.Wrap(err, errGenerateDataKey)
+	}
+	edk, err := base64.StdEncoding.DecodeString(s.GetAnnotations()[AnnotationKeyDataKey])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errDecodeDataKey)
+	}
+	dk, err := e.keys.DecryptDataKey(ctx, edk)
+	return dk, edk, errors.Wrap(err, errDecryptDataKey)
+}
+
+// DecryptSecret decrypts the data of the supplied secret, and removes its
+// encrypted data key annotation. Secrets that are not encrypted are returned
+// unchanged.
+func (e *Encrypter) DecryptSecret(ctx context.Context, s *corev1.Secret) error {
+	if !IsEncrypted(s) {
+		return nil
+	}
+	dk, _, err := e.dataKey(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string][]byte, len(s.Data))
+	for k, v := range s.Data {
+		if v == nil {
+			data[k] = nil
+			continue
+		}
+		pt, err := open(dk, v, []byte(k))
+		if err != nil {
+			return errors.Wrapf(err, errFmtDecryptKey, k)
+		}
+		data[k] = pt
+	}
+	s.Data = data
+	meta.RemoveAnnotations(s, AnnotationKeyDataKey)
+	return nil
+}
+
+// seal encrypts and authenticates the supplied plaintext and additional data
+// using AES-GCM. The random nonce is prepended to the returned ciphertext.
+func seal(r io.Reader, key, plaintext, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return nil, errors.Wrap(err, errReadRandom)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open decrypts and authenticates the supplied ciphertext, which must have
+// been produced by seal.
+func open(key, ciphertext, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New(errShortCiphertext)
+	}
+	nonce, ct := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ct, additional)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewCipher)
+	}
+	gcm, err := cipher.NewGCM(b)
+	return gcm, errors.Wrap(err, errNewCipher)
+}
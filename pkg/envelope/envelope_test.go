@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ KeyProvider = &LocalKeyProvider{}
+var _ KeyProvider = KeyProviderFns{}
+
+func TestRoundTrip(t *testing.T) {
+	kek := bytes.Repeat([]byte{1}, 32)
+	e := NewEncrypter(NewLocalKeyProvider(kek))
+
+	want := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"cool": "annotation"}},
+		Data:       map[string][]byte{"username": []byte("cool"), "password": []byte("verysecure"), "empty": {}},
+	}
+	s := want.DeepCopy()
+
+	if err := e.EncryptSecret(context.Background(), s); err != nil {
+		t.Fatalf("EncryptSecret(...): %s", err)
+	}
+	if !IsEncrypted(s) {
+		t.Errorf("EncryptSecret(...): secret should be encrypted")
+	}
+	for k, v := range s.Data {
+		if bytes.Contains(v, want.Data[k]) && len(want.Data[k]) > 0 {
+			t.Errorf("EncryptSecret(...): key %q contains plaintext", k)
+		}
+	}
+
+	if err := e.DecryptSecret(context.Background(), s); err != nil {
+		t.Fatalf("DecryptSecret(...): %s", err)
+	}
+	if diff := cmp.Diff(want, s, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("DecryptSecret(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestDecryptSecret(t *testing.T) {
+	errBoom := errors.New("boom")
+	kek := bytes.Repeat([]byte{1}, 32)
+
+	encrypted := func() *corev1.Secret {
+		s := &corev1.Secret{Data: map[string][]byte{"password": []byte("verysecure")}}
+		_ = NewEncrypter(NewLocalKeyProvider(kek)).EncryptSecret(context.Background(), s)
+		return s
+	}
+
+	cases := map[string]struct {
+		reason string
+		kp     KeyProvider
+		s      func() *corev1.Secret
+		want   error
+	}{
+		"NotEncrypted": {
+			reason: "Secrets that are not encrypted should be returned unchanged.",
+			kp:     NewLocalKeyProvider(kek),
+			s: func() *corev1.Secret {
+				return &corev1.Secret{Data: map[string][]byte{"password": []byte("verysecure")}}
+			},
+		},
+		"DecryptDataKeyError": {
+			reason: "Errors decrypting the data key should be returned.",
+			kp: KeyProviderFns{DecryptDataKeyFn: func(_ context.Context, _ []byte) ([]byte, error) {
+				return nil, errBoom
+			}},
+			s:    encrypted,
+			want: errors.Wrap(errBoom, errDecryptDataKey),
+		},
+		"WrongKeyEncryptionKey": {
+			reason: "Data keys encrypted by a different key encryption key should not be decrypted.",
+			kp:     NewLocalKeyProvider(bytes.Repeat([]byte{2}, 32)),
+			s:      encrypted,
+			want:   errors.Wrap(errors.New("cipher: message authentication failed"), errDecryptDataKey),
+		},
+		"MovedValue": {
+			reason: "Values that were moved to a different key should not be decrypted.",
+			kp:     NewLocalKeyProvider(kek),
+			s: func() *corev1.Secret {
+				s := encrypted()
+				s.Data["username"] = s.Data["password"]
+				delete(s.Data, "password")
+				return s
+			},
+			want: errors.Wrapf(errors.New("cipher: message authentication failed"), errFmtDecryptKey, "username"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewEncrypter(tc.kp).DecryptSecret(context.Background(), tc.s())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDecryptSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMergeEncrypted(t *testing.T) {
+	kek := bytes.Repeat([]byte{1}, 32)
+	e := NewEncrypter(NewLocalKeyProvider(kek))
+
+	existing := &corev1.Secret{Data: map[string][]byte{"username": []byte("cool"), "password": []byte("verysecure")}}
+	if err := e.EncryptSecret(context.Background(), existing); err != nil {
+		t.Fatalf("EncryptSecret(...): %s", err)
+	}
+
+	// Encrypt new data using the existing secret's data key, then merge it
+	// into the existing secret as a JSON merge patch would.
+	s := &corev1.Secret{Data: map[string][]byte{"endpoint": []byte("example.org"), "password": nil}}
+	meta.AddAnnotations(s, map[string]string{AnnotationKeyDataKey: existing.GetAnnotations()[AnnotationKeyDataKey]})
+	if err := e.EncryptSecret(context.Background(), s); err != nil {
+		t.Fatalf("EncryptSecret(...): %s", err)
+	}
+	if s.Data["password"] != nil {
+		t.Errorf("EncryptSecret(...): nil values should not be encrypted")
+	}
+	for k, v := range s.Data {
+		if v == nil {
+			delete(existing.Data, k)
+			continue
+		}
+		existing.Data[k] = v
+	}
+
+	if err := e.DecryptSecret(context.Background(), existing); err != nil {
+		t.Fatalf("DecryptSecret(...): %s", err)
+	}
+	want := map[string][]byte{"username": []byte("cool"), "endpoint": []byte("example.org")}
+	if diff := cmp.Diff(want, existing.Data); diff != "" {
+		t.Errorf("DecryptSecret(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestEncryptSecret(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		kp     KeyProvider
+		s      *corev1.Secret
+		want   error
+	}{
+		"GenerateDataKeyError": {
+			reason: "Errors generating a new data key should be returned.",
+			kp: KeyProviderFns{GenerateDataKeyFn: func(_ context.Context) ([]byte, []byte, error) {
+				return nil, nil, errBoom
+			}},
+			s:    &corev1.Secret{},
+			want: errors.Wrap(errBoom, errGenerateDataKey),
+		},
+		"DecryptDataKeyError": {
+			reason: "Errors decrypting an existing data key should be returned.",
+			kp: KeyProviderFns{DecryptDataKeyFn: func(_ context.Context, _ []byte) ([]byte, error) {
+				return nil, errBoom
+			}},
+			s: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationKeyDataKey: base64.StdEncoding.EncodeToString([]byte("key")),
+			}}},
+			want: errors.Wrap(errBoom, errDecryptDataKey),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewEncrypter(tc.kp).EncryptSecret(context.Background(), tc.s)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEncryptSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors is a github.com/pkg/errors compatible API for native errors.
+// Errors are wrapped using the standard library's %w verb, which allows
+// callers to use errors.Is and errors.As to inspect errors wrapped by this
+// runtime - for example to determine whether a Kubernetes API error is buried
+// beneath a wrapping message.
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// New returns an error that formats as the given text. Each call to New
+// returns a distinct error value even if the text is identical. This is a
+// passthrough to the standard library's errors.New.
+func New(text string) error { return errors.New(text) }
+
+// Is reports whether any error in err's chain matches target. This is a
+// passthrough to the standard library's errors.Is.
+func Is(err, target error) bool { return errors.Is(err, target) }
+
+// As finds the first error in err's chain that matches target, and if so, sets
+// target to that error value and returns true. Otherwise, it returns false.
+// This is a passthrough to the standard library's errors.As.
+func As(err error, target interface{}) bool { return errors.As(err, target) }
+
+// Unwrap returns the result of calling the Unwrap method on err, if err's type
+// contains an Unwrap method returning error. Otherwise, Unwrap returns nil.
+// This is a passthrough to the standard library's errors.Unwrap.
+func Unwrap(err error) error { return errors.Unwrap(err) }
+
+// Errorf formats according to a format specifier and returns the string as a
+// value that satisfies error. It may wrap an error using the %w verb. This is
+// a passthrough to the standard library's fmt.Errorf.
+func Errorf(format string, a ...interface{}) error { return fmt.Errorf(format, a...) }
+
+// WithMessage annotates err with a new message. If err is nil, WithMessage
+// returns nil.
+func WithMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// WithMessagef annotates err with the format specifier. If err is nil,
+// WithMessagef returns nil.
+func WithMessagef(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+}
+
+// Wrap is an alias for WithMessage.
+func Wrap(err error, message string) error {
+	return WithMessage(err, message)
+}
+
+// Wrapf is an alias for WithMessagef.
+func Wrapf(err error, format string, args ...interface{}) error {
+	return WithMessagef(err, format, args...)
+}
+
+// Cause calls Unwrap on each error it finds. It returns the first error it
+// finds that does not have an Unwrap method - i.e. the first error that was
+// not the result of a Wrap call, a Wrapf call, or an Errorf call with %w
+// wrapping. Errors wrapped by github.com/pkg/errors are also unwrapped.
+func Cause(err error) error {
+	type causer interface {
+		Cause() error
+	}
+
+	for {
+		var next error
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			next = e.Unwrap()
+		case causer:
+			next = e.Cause()
+		default:
+			return err
+		}
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWrap(t *testing.T) {
+	type args struct {
+		err     error
+		message string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"NilError": {
+			reason: "Wrapping a nil error should return nil",
+			args:   args{message: "very useful context"},
+			want:   "",
+		},
+		"NonNilError": {
+			reason: "Wrapping a non-nil error should prepend the message",
+			args:   args{err: New("boom"), message: "very useful context"},
+			want:   "very useful context: boom",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Wrap(tc.args.err, tc.args.message)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWrap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type causer struct{ cause error }
+
+func (c causer) Error() string { return "cause: " + c.cause.Error() }
+func (c causer) Cause() error  { return c.cause }
+
+func TestCause(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   error
+	}{
+		"NilError": {
+			reason: "The cause of a nil error should be nil",
+			err:    nil,
+			want:   nil,
+		},
+		"UnwrappedError": {
+			reason: "An error that was not wrapped should be its own cause",
+			err:    io.EOF,
+			want:   io.EOF,
+		},
+		"WrappedError": {
+			reason: "The cause of a wrapped error should be the innermost error",
+			err:    Wrap(Wrapf(io.EOF, "%s", "inner"), "outer"),
+			want:   io.EOF,
+		},
+		"Causer": {
+			reason: "Errors that implement Cause should be unwrapped",
+			err:    Wrap(causer{cause: io.EOF}, "outer"),
+			want:   io.EOF,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Cause(tc.err)
+			if got != tc.want {
+				t.Errorf("\n%s\nCause(...): want %v, got %v", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIs(t *testing.T) {
+	if !Is(Wrap(io.EOF, "outer"), io.EOF) {
+		t.Errorf("Is(...): wrapped error should match its cause")
+	}
+}
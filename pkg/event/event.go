@@ -20,6 +20,8 @@ package event
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 )
 
 // A Type of event.
@@ -55,12 +57,13 @@ func Normal(r Reason, message string, keysAndValues ...string) Event {
 	return e
 }
 
-// Warning returns a warning event, typically due to an error.
+// Warning returns a warning event, typically due to an error. The event's
+// message is derived from the error per v1alpha1.ErrorMessage.
 func Warning(r Reason, err error, keysAndValues ...string) Event {
 	e := Event{
 		Type:        TypeWarning,
 		Reason:      r,
-		Message:     err.Error(),
+		Message:     v1alpha1.ErrorMessage(err),
 		Annotations: map[string]string{},
 	}
 	sliceMap(keysAndValues, e.Annotations)
@@ -85,9 +88,10 @@ func NewAPIRecorder(r record.EventRecorder) *APIRecorder {
 	return &APIRecorder{kube: r, annotations: map[string]string{}}
 }
 
-// Event records the supplied event.
+// Event records the supplied event. The event's message is sanitized per
+// v1alpha1.SanitizeMessage.
 func (r *APIRecorder) Event(obj runtime.Object, e Event) {
-	r.kube.AnnotatedEventf(obj, r.annotations, string(e.Type), string(e.Reason), e.Message)
+	r.kube.AnnotatedEventf(obj, r.annotations, string(e.Type), string(e.Reason), "%s", v1alpha1.SanitizeMessage(e.Message))
 }
 
 // WithAnnotations returns a new *APIRecorder that includes the supplied
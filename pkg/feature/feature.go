@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package feature contains utilities for managing feature flags. Feature flags
+// allow large new behaviours to ship disabled, and to be enabled per provider
+// deployment.
+package feature
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFmtUnknownFlag = "unknown feature flag %q"
+)
+
+// A Flag enables a particular feature.
+type Flag string
+
+// The Maturity of a feature.
+type Maturity string
+
+// Feature maturities.
+const (
+	// Alpha features are disabled by default. They may change or be removed
+	// without notice.
+	Alpha Maturity = "Alpha"
+
+	// Beta features are well tested, and may be enabled by default.
+	Beta Maturity = "Beta"
+)
+
+// Flags that are enabled. The zero value - i.e. no enabled flags and no known
+// flags - is usable, and accepts any flag.
+type Flags struct {
+	m       sync.RWMutex
+	known   map[Flag]Maturity
+	enabled map[Flag]bool
+}
+
+// Register the supplied flag as a known flag of the supplied maturity. Flags
+// must be registered before they can be enabled by Parse or EnableMaturity.
+func (fs *Flags) Register(f Flag, m Maturity) {
+	fs.m.Lock()
+	if fs.known == nil {
+		fs.known = make(map[Flag]Maturity)
+	}
+	fs.known[f] = m
+	fs.m.Unlock()
+}
+
+// Maturity returns the maturity of the supplied flag, and whether it is known.
+func (fs *Flags) Maturity(f Flag) (Maturity, bool) {
+	if fs == nil {
+		return "", false
+	}
+	fs.m.RLock()
+	defer fs.m.RUnlock()
+	m, ok := fs.known[f]
+	return m, ok
+}
+
+// Known returns all known flags of the supplied maturity, sorted by name.
+func (fs *Flags) Known(m Maturity) []Flag {
+	if fs == nil {
+		return nil
+	}
+	fs.m.RLock()
+	defer fs.m.RUnlock()
+	known := make([]Flag, 0, len(fs.known))
+	for f, fm := range fs.known {
+		if fm == m {
+			known = append(known, f)
+		}
+	}
+	sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+	return known
+}
+
+// Enable a feature flag.
+func (fs *Flags) Enable(f Flag) {
+	fs.m.Lock()
+	if fs.enabled == nil {
+		fs.enabled = make(map[Flag]bool)
+	}
+	fs.enabled[f] = true
+	fs.m.Unlock()
+}
+
+// EnableMaturity enables all known flags of the supplied maturity.
+func (fs *Flags) EnableMaturity(m Maturity) {
+	for _, f := range fs.Known(m) {
+		fs.Enable(f)
+	}
+}
+
+// Enabled returns true if the supplied feature flag is enabled. A nil set of
+// Flags has no enabled flags.
+func (fs *Flags) Enabled(f Flag) bool {
+	if fs == nil {
+		return false
+	}
+	fs.m.RLock()
+	defer fs.m.RUnlock()
+	return fs.enabled[f]
+}
+
+// Union returns a new set of Flags in which every flag that is known or enabled
+// in any of the supplied Flags is known or enabled.
+func Union(fss ...*Flags) *Flags {
+	u := &Flags{}
+	for _, fs := range fss {
+		if fs == nil {
+			continue
+		}
+		fs.m.RLock()
+		for f, m := range fs.known {
+			u.Register(f, m)
+		}
+		for f, e := range fs.enabled {
+			if e {
+				u.Enable(f)
+			}
+		}
+		fs.m.RUnlock()
+	}
+	return u
+}
+
+// Parse enables the flags named in the supplied comma separated list, as would
+// typically be passed to a provider using a flag like --enable-alpha-features.
+// An error is returned if any flag is unknown and flags have been registered.
+func (fs *Flags) Parse(s string) error {
+	for _, name := range strings.Split(s, ",") {
+		f := Flag(strings.TrimSpace(name))
+		if f == "" {
+			continue
+		}
+		fs.m.RLock()
+		_, ok := fs.known[f]
+		unknown := len(fs.known) > 0 && !ok
+		fs.m.RUnlock()
+		if unknown {
+			return errors.Errorf(errFmtUnknownFlag, f)
+		}
+		fs.Enable(f)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const (
+	flagAlpha Flag = "EnableAlphaCool"
+	flagBeta  Flag = "EnableBetaCool"
+)
+
+func TestParse(t *testing.T) {
+	type want struct {
+		enabled map[Flag]bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason   string
+		register bool
+		s        string
+		want     want
+	}{
+		"Empty": {
+			reason:   "No flags should be enabled by an empty string",
+			register: true,
+			s:        "",
+			want: want{
+				enabled: map[Flag]bool{flagAlpha: false, flagBeta: false},
+			},
+		},
+		"KnownFlags": {
+			reason:   "Known flags should be enabled",
+			register: true,
+			s:        " EnableAlphaCool, EnableBetaCool ",
+			want: want{
+				enabled: map[Flag]bool{flagAlpha: true, flagBeta: true},
+			},
+		},
+		"UnknownFlag": {
+			reason:   "An error should be returned if a flag is unknown",
+			register: true,
+			s:        "EnableAlphaCool,EnableAlphaLame",
+			want: want{
+				enabled: map[Flag]bool{flagAlpha: true, flagBeta: false},
+				err:     errors.Errorf(errFmtUnknownFlag, "EnableAlphaLame"),
+			},
+		},
+		"NoKnownFlags": {
+			reason: "Any flag should be enabled if no flags are known",
+			s:      "EnableAlphaLame",
+			want: want{
+				enabled: map[Flag]bool{"EnableAlphaLame": true, flagAlpha: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := &Flags{}
+			if tc.register {
+				fs.Register(flagAlpha, Alpha)
+				fs.Register(flagBeta, Beta)
+			}
+			err := fs.Parse(tc.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfs.Parse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			for f, want := range tc.want.enabled {
+				if diff := cmp.Diff(want, fs.Enabled(f)); diff != "" {
+					t.Errorf("\n%s\nfs.Enabled(%s): -want, +got:\n%s", tc.reason, f, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestEnableMaturity(t *testing.T) {
+	fs := &Flags{}
+	fs.Register(flagAlpha, Alpha)
+	fs.Register(flagBeta, Beta)
+	fs.EnableMaturity(Beta)
+
+	want := map[Flag]bool{flagAlpha: false, flagBeta: true}
+	for f, w := range want {
+		if diff := cmp.Diff(w, fs.Enabled(f)); diff != "" {
+			t.Errorf("fs.Enabled(%s): -want, +got:\n%s", f, diff)
+		}
+	}
+}
+
+func TestNilFlags(t *testing.T) {
+	var fs *Flags
+	if diff := cmp.Diff(false, fs.Enabled(flagAlpha)); diff != "" {
+		t.Errorf("fs.Enabled(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	global := &Flags{}
+	global.Register(flagAlpha, Alpha)
+	global.Enable(flagAlpha)
+
+	scoped := &Flags{}
+	scoped.Register(flagBeta, Beta)
+	scoped.Enable(flagBeta)
+
+	u := Union(global, nil, scoped)
+
+	want := map[Flag]bool{flagAlpha: true, flagBeta: true, "EnableAlphaLame": false}
+	for f, w := range want {
+		if diff := cmp.Diff(w, u.Enabled(f)); diff != "" {
+			t.Errorf("u.Enabled(%s): -want, +got:\n%s", f, diff)
+		}
+	}
+	if diff := cmp.Diff([]Flag{flagBeta}, u.Known(Beta)); diff != "" {
+		t.Errorf("u.Known(...): -want, +got:\n%s", diff)
+	}
+}
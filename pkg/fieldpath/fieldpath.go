@@ -28,6 +28,8 @@ limitations under the License.
 // * data[.config.yml]
 // * metadata.annotations['crossplane.io/external-name']
 // * spec.items[0][8]
+// * spec.containers[*].image
+// * spec.rules[?name=admin].verbs
 // * apiVersion
 // * [42]
 //
@@ -48,11 +50,11 @@ import (
 	"strings"
 	"unicode/utf8"
 
-	"github.com/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
 
-// A SegmentType within a field path; either a field within an object, or an
-// index within an array.
+// A SegmentType within a field path; either a field within an object, an
+// index within an array, or a filter that selects an element of an array.
 type SegmentType int
 
 // Segment types.
@@ -60,6 +62,7 @@ const (
 	_ SegmentType = iota
 	SegmentField
 	SegmentIndex
+	SegmentFilter
 )
 
 // A Segment of a field path.
@@ -67,6 +70,20 @@ type Segment struct {
 	Type  SegmentType
 	Field string
 	Index uint
+
+	// Value is the value a filter segment's Field must have in order for an
+	// array element to be selected.
+	Value string
+}
+
+// Wildcard is a field path segment that matches every element of an array or
+// every field of an object. Wildcards are only meaningful to functions that
+// explicitly support them, for example Paved.ExpandWildcards.
+const Wildcard = "*"
+
+// IsWildcard returns true if the segment is a wildcard.
+func (s Segment) IsWildcard() bool {
+	return s.Type == SegmentField && s.Field == Wildcard
 }
 
 // Segments of a field path.
@@ -85,6 +102,8 @@ func (sg Segments) String() string {
 			b.WriteString(fmt.Sprintf(".%s", s.Field))
 		case SegmentIndex:
 			b.WriteString(fmt.Sprintf("[%d]", s.Index))
+		case SegmentFilter:
+			b.WriteString(fmt.Sprintf("[%c%s=%s]", question, s.Field, s.Value))
 		}
 	}
 
@@ -114,6 +133,25 @@ func Field(s string) Segment {
 	return Segment{Type: SegmentField, Field: strings.Trim(s, "'\"")}
 }
 
+// Filter produces a new segment that selects the first element of an array
+// that is an object with the supplied field set to the supplied value.
+func Filter(field, value string) Segment {
+	return Segment{Type: SegmentFilter, Field: field, Value: strings.Trim(value, "'\"")}
+}
+
+// FieldOrIndexOrFilter produces a new segment from the supplied bracketed
+// string. The segment is considered to be a filter if the string is of the
+// form ?field=value. Otherwise it is interpreted per FieldOrIndex, so a field
+// name may contain an equals sign.
+func FieldOrIndexOrFilter(s string) Segment {
+	if strings.HasPrefix(s, string(question)) {
+		if i := strings.IndexRune(s, equals); i > 1 {
+			return Filter(s[1:i], s[i+1:])
+		}
+	}
+	return FieldOrIndex(s)
+}
+
 // Parse the supplied path into a slice of Segments.
 func Parse(path string) (Segments, error) {
 	l := &lexer{input: path, items: make(chan item)}
@@ -125,7 +163,7 @@ func Parse(path string) (Segments, error) {
 		case itemField:
 			segments = append(segments, Field(i.val))
 		case itemFieldOrIndex:
-			segments = append(segments, FieldOrIndex(i.val))
+			segments = append(segments, FieldOrIndexOrFilter(i.val))
 		case itemError:
 			return nil, errors.Errorf("%s at position %d", i.val, i.pos)
 		}
@@ -137,6 +175,8 @@ const (
 	period       = '.'
 	leftBracket  = '['
 	rightBracket = ']'
+	equals       = '='
+	question     = '?'
 )
 
 type itemType int
@@ -250,8 +290,8 @@ func lexLeftBracket(l *lexer) stateFn {
 	return lexFieldOrIndex
 }
 
-// Strings between brackets may be either a field name or an array index.
-// Periods have no special meaning in this context.
+// Strings between brackets may be either a field name, an array index, or a
+// filter. Periods have no special meaning in this context.
 func lexFieldOrIndex(l *lexer) stateFn {
 	// We know a right bracket exists before EOL thanks to the preceding
 	// lexLeftBracket.
@@ -22,8 +22,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -56,6 +56,15 @@ func TestSegments(t *testing.T) {
 			},
 			want: "data[.config.yml]",
 		},
+		"Filter": {
+			s: Segments{
+				Field("spec"),
+				Field("rules"),
+				Filter("name", "admin"),
+				Field("verbs"),
+			},
+			want: "spec.rules[?name=admin].verbs",
+		},
 	}
 
 	for name, tc := range cases {
@@ -261,6 +270,49 @@ func TestParse(t *testing.T) {
 				err: errors.New("unterminated '[' at position 4"),
 			},
 		},
+		"Filter": {
+			reason: "A question mark followed by a field and value separated by an equals sign in brackets should be interpreted as a filter",
+			path:   "spec.rules[?name=admin].verbs",
+			want: want{
+				s: Segments{
+					Field("spec"),
+					Field("rules"),
+					Filter("name", "admin"),
+					Field("verbs"),
+				},
+			},
+		},
+		"QuotedFilterValue": {
+			reason: "A filter value may be quoted",
+			path:   "spec.rules[?name='cool.admin']",
+			want: want{
+				s: Segments{
+					Field("spec"),
+					Field("rules"),
+					Segment{Type: SegmentFilter, Field: "name", Value: "cool.admin"},
+				},
+			},
+		},
+		"QuotedFieldWithEquals": {
+			reason: "A quoted bracketed string containing an equals sign should be interpreted as a field",
+			path:   "data['a=b']",
+			want: want{
+				s: Segments{
+					Field("data"),
+					Field("a=b"),
+				},
+			},
+		},
+		"FieldWithEquals": {
+			reason: "An unquoted bracketed string containing an equals sign should be interpreted as a field",
+			path:   "data[a=b]",
+			want: want{
+				s: Segments{
+					Field("data"),
+					Field("a=b"),
+				},
+			},
+		},
 		"EmptyBracket": {
 			reason: "Brackets may not be empty",
 			path:   "spec[]",
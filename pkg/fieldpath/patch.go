@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errToUnstructured   = "cannot convert object to unstructured data"
+	errFromUnstructured = "cannot convert unstructured data to object"
+)
+
+// MergeOptions specify how a value is merged with any value that already
+// exists at a field path. The existing value is replaced when no MergeOptions
+// are supplied.
+type MergeOptions struct {
+	// KeepMapValues causes fields of an existing object that are not present
+	// in the new object to be preserved, recursively.
+	KeepMapValues bool
+
+	// AppendSlice causes the elements of a new array to be appended to those
+	// of an existing array, rather than replacing them.
+	AppendSlice bool
+}
+
+// MergeValue merges the supplied value with any existing value at the supplied
+// field path, per the supplied MergeOptions. It is equivalent to SetValue if the
+// MergeOptions are nil or no value exists at the field path.
+func (p *Paved) MergeValue(path string, value interface{}, mo *MergeOptions) error {
+	if mo == nil {
+		return p.SetValue(path, value)
+	}
+
+	segments, err := Parse(path)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse path %q", path)
+	}
+
+	current, err := p.getValue(segments)
+	if err != nil {
+		// There is nothing to merge with.
+		return p.setValue(segments, value)
+	}
+
+	v, err := toJSONValue(value)
+	if err != nil {
+		return err
+	}
+
+	return p.setValue(segments, merge(current, v, mo))
+}
+
+// merge the supplied JSON values. The new value takes precedence unless both
+// values are objects or arrays that the supplied MergeOptions allow to be
+// combined.
+func merge(current, new interface{}, mo *MergeOptions) interface{} {
+	switch n := new.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if !ok || !mo.KeepMapValues {
+			return n
+		}
+		merged := make(map[string]interface{}, len(c)+len(n))
+		for k, v := range c {
+			merged[k] = v
+		}
+		for k, v := range n {
+			if cv, ok := c[k]; ok {
+				merged[k] = merge(cv, v, mo)
+				continue
+			}
+			merged[k] = v
+		}
+		return merged
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if !ok || !mo.AppendSlice {
+			return n
+		}
+		merged := make([]interface{}, 0, len(c)+len(n))
+		merged = append(merged, c...)
+		return append(merged, n...)
+	}
+	return new
+}
+
+func toJSONValue(value interface{}) (interface{}, error) {
+	var v interface{}
+	j, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal value to JSON")
+	}
+	return v, errors.Wrap(json.Unmarshal(j, &v), "cannot unmarshal value from JSON")
+}
+
+// A Patch sets or merges a value at a field path.
+type Patch struct {
+	// Path at which to set the value.
+	Path string
+
+	// Value to set.
+	Value interface{}
+
+	// MergeOptions specify how the value is merged with any existing value.
+	// The existing value is replaced if MergeOptions are nil.
+	MergeOptions *MergeOptions
+}
+
+// A PatchError indicates that a particular Patch could not be applied.
+type PatchError struct {
+	// Index of the patch that could not be applied.
+	Index int
+
+	// Path of the patch that could not be applied.
+	Path string
+
+	// Err encountered applying the patch.
+	Err error
+}
+
+func (e PatchError) Error() string {
+	return fmt.Sprintf("patch %d (%s): %s", e.Index, e.Path, e.Err)
+}
+
+// PatchErrors are returned when one or more Patches could not be applied.
+type PatchErrors []PatchError
+
+func (e PatchErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// IsPatchErrors returns true if the supplied error indicates that one or more
+// patches could not be applied.
+func IsPatchErrors(err error) bool {
+	_, ok := errors.Cause(err).(PatchErrors)
+	return ok
+}
+
+// ApplyPatches applies the supplied patches in order. A patch that cannot be
+// applied does not prevent subsequent patches from being applied. PatchErrors
+// are returned if any patch could not be applied.
+func (p *Paved) ApplyPatches(patches ...Patch) error {
+	var errs PatchErrors
+	for i, pt := range patches {
+		if err := p.MergeValue(pt.Path, pt.Value, pt.MergeOptions); err != nil {
+			errs = append(errs, PatchError{Index: i, Path: pt.Path, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// PaveObject paves a runtime.Object, making it possible to get and set values
+// by field path. The supplied object must be convertible to unstructured data.
+func PaveObject(o runtime.Object) (*Paved, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	return Pave(u), errors.Wrap(err, errToUnstructured)
+}
+
+// PatchObject applies the supplied patches to the supplied object in order.
+// The object is updated with every patch that could be applied, even if
+// PatchErrors are returned because some patches could not be applied.
+func PatchObject(o runtime.Object, patches ...Patch) error {
+	p, err := PaveObject(o)
+	if err != nil {
+		return err
+	}
+
+	perr := p.ApplyPatches(patches...)
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(p.UnstructuredContent(), o); err != nil {
+		return errors.Wrap(err, errFromUnstructured)
+	}
+
+	return perr
+}
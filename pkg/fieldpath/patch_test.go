@@ -0,0 +1,280 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestMergeValue(t *testing.T) {
+	type args struct {
+		path  string
+		value interface{}
+		mo    *MergeOptions
+	}
+	type want struct {
+		object map[string]interface{}
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		data   []byte
+		args   args
+		want   want
+	}{
+		"NoMergeOptions": {
+			reason: "An existing value should be replaced if no merge options are supplied",
+			data:   []byte(`{"metadata":{"labels":{"a":"1"}}}`),
+			args: args{
+				path:  "metadata.labels",
+				value: map[string]string{"b": "2"},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{"b": "2"},
+					},
+				},
+			},
+		},
+		"KeepMapValues": {
+			reason: "Existing object fields should be preserved when KeepMapValues is set",
+			data:   []byte(`{"metadata":{"labels":{"a":"1","b":"1"}}}`),
+			args: args{
+				path:  "metadata.labels",
+				value: map[string]string{"b": "2"},
+				mo:    &MergeOptions{KeepMapValues: true},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{"a": "1", "b": "2"},
+					},
+				},
+			},
+		},
+		"KeepNestedMapValues": {
+			reason: "Existing object fields should be preserved recursively when KeepMapValues is set",
+			data:   []byte(`{"spec":{"a":{"b":"1","c":"1"}}}`),
+			args: args{
+				path:  "spec",
+				value: map[string]interface{}{"a": map[string]string{"c": "2"}},
+				mo:    &MergeOptions{KeepMapValues: true},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"a": map[string]interface{}{"b": "1", "c": "2"},
+					},
+				},
+			},
+		},
+		"AppendSlice": {
+			reason: "Array elements should be appended when AppendSlice is set",
+			data:   []byte(`{"spec":{"verbs":["get"]}}`),
+			args: args{
+				path:  "spec.verbs",
+				value: []string{"list"},
+				mo:    &MergeOptions{AppendSlice: true},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"verbs": []interface{}{"get", "list"},
+					},
+				},
+			},
+		},
+		"NonExistentValue": {
+			reason: "A value should be set if there is no existing value to merge with",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.verbs",
+				value: []string{"list"},
+				mo:    &MergeOptions{AppendSlice: true},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"verbs": []interface{}{"list"},
+					},
+				},
+			},
+		},
+		"MalformedPath": {
+			reason: "Merging at an invalid field path should fail",
+			data:   []byte(`{}`),
+			args: args{
+				path: "spec[]",
+				mo:   &MergeOptions{},
+			},
+			want: want{
+				object: map[string]interface{}{},
+				err:    errors.Wrap(errors.New("unexpected ']' at position 5"), "cannot parse path \"spec[]\""),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make(map[string]interface{})
+			_ = json.Unmarshal(tc.data, &in)
+			p := Pave(in)
+
+			err := p.MergeValue(tc.args.path, tc.args.value, tc.args.mo)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\np.MergeValue(%s): %s: -want error, +got error:\n%s", tc.args.path, tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.object, p.object); diff != "" {
+				t.Fatalf("\np.MergeValue(%s): %s: -want, +got:\n%s", tc.args.path, tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	type want struct {
+		object map[string]interface{}
+		err    error
+	}
+	cases := map[string]struct {
+		reason  string
+		data    []byte
+		patches []Patch
+		want    want
+	}{
+		"Success": {
+			reason: "All patches should be applied in order",
+			data:   []byte(`{}`),
+			patches: []Patch{
+				{Path: "metadata.name", Value: "lame"},
+				{Path: "metadata.name", Value: "cool"},
+				{Path: "metadata.labels", Value: map[string]string{"a": "1"}},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name":   "cool",
+						"labels": map[string]interface{}{"a": "1"},
+					},
+				},
+			},
+		},
+		"PartialFailure": {
+			reason: "Patches that can be applied should be applied, and errors returned for those that cannot",
+			data:   []byte(`{"items":["a"]}`),
+			patches: []Patch{
+				{Path: "items.name", Value: "nope"},
+				{Path: "metadata.name", Value: "cool"},
+				{Path: "spec[]", Value: "nope"},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"items": []interface{}{"a"},
+					"metadata": map[string]interface{}{
+						"name": "cool",
+					},
+				},
+				err: PatchErrors{
+					{Index: 0, Path: "items.name", Err: errors.New("items is not an object")},
+					{Index: 2, Path: "spec[]", Err: errors.Wrap(errors.New("unexpected ']' at position 5"), "cannot parse path \"spec[]\"")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make(map[string]interface{})
+			_ = json.Unmarshal(tc.data, &in)
+			p := Pave(in)
+
+			err := p.ApplyPatches(tc.patches...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\np.ApplyPatches(...): %s: -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.object, p.object); diff != "" {
+				t.Fatalf("\np.ApplyPatches(...): %s: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchObject(t *testing.T) {
+	type want struct {
+		o   *metav1.PartialObjectMetadata
+		err error
+	}
+	cases := map[string]struct {
+		reason  string
+		o       *metav1.PartialObjectMetadata
+		patches []Patch
+		want    want
+	}{
+		"Success": {
+			reason: "Patches should be applied to the supplied object",
+			o:      &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			patches: []Patch{
+				{Path: "metadata.labels", Value: map[string]string{"a": "1"}},
+				{Path: "metadata.annotations['crossplane.io/external-name']", Value: "cooler"},
+			},
+			want: want{
+				o: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+					Name:        "cool",
+					Labels:      map[string]string{"a": "1"},
+					Annotations: map[string]string{"crossplane.io/external-name": "cooler"},
+				}},
+			},
+		},
+		"PartialFailure": {
+			reason: "Patches that can be applied should be applied even if others fail",
+			o:      &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			patches: []Patch{
+				{Path: "metadata.name[0]", Value: "nope"},
+				{Path: "metadata.labels", Value: map[string]string{"a": "1"}},
+			},
+			want: want{
+				o: &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+					Name:   "cool",
+					Labels: map[string]string{"a": "1"},
+				}},
+				err: PatchErrors{
+					{Index: 0, Path: "metadata.name[0]", Err: errors.New("metadata.name is not an array")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PatchObject(tc.o, tc.patches...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\nPatchObject(...): %s: -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, tc.o); diff != "" {
+				t.Fatalf("\nPatchObject(...): %s: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,8 +18,11 @@ package fieldpath
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
 
-	"github.com/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
 
 // A Paved JSON object supports getting and setting values by their field path.
@@ -73,6 +76,20 @@ func (p *Paved) getValue(s Segments) (interface{}, error) {
 			}
 			it = array[current.Index]
 
+		case SegmentFilter:
+			array, ok := it.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("%s: not an array", s[:i])
+			}
+			ix := filterIndex(array, current)
+			if ix < 0 {
+				return nil, errors.Errorf("%s: no such element", s[:i+1])
+			}
+			if final {
+				return array[ix], nil
+			}
+			it = array[ix]
+
 		case SegmentField:
 			object, ok := it.(map[string]interface{})
 			if !ok {
@@ -93,6 +110,112 @@ func (p *Paved) getValue(s Segments) (interface{}, error) {
 	return nil, nil
 }
 
+// ExpandWildcards expands any wildcards in the supplied field path into the
+// concrete field paths that exist within the underlying object. A wildcard may
+// select all elements of an array (e.g. spec.containers[*].image) or all fields
+// of an object (e.g. metadata.labels[*]). Paths that traverse fields or array
+// elements that do not exist are omitted from the results. Fields of expanded
+// objects are returned in lexical order.
+func (p *Paved) ExpandWildcards(path string) ([]string, error) {
+	segments, err := Parse(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse path %q", path)
+	}
+
+	expanded, err := expandWildcards(p.object, segments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot expand wildcards for path %q", path)
+	}
+
+	paths := make([]string, len(expanded))
+	for i := range expanded {
+		paths[i] = expanded[i].String()
+	}
+	return paths, nil
+}
+
+func expandWildcards(data interface{}, s Segments) ([]Segments, error) {
+	var it = data
+	for i, current := range s {
+		if !current.IsWildcard() {
+			v, ok := childOf(it, current)
+			if !ok {
+				// Paths that don't exist expand to nothing.
+				return nil, nil
+			}
+			it = v
+			continue
+		}
+
+		var concrete []Segment
+		switch v := it.(type) {
+		case []interface{}:
+			for ix := range v {
+				concrete = append(concrete, Segment{Type: SegmentIndex, Index: uint(ix)})
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				concrete = append(concrete, Field(k))
+			}
+		default:
+			return nil, errors.Errorf("%s: cannot expand wildcard; not an array or object", s[:i])
+		}
+
+		var res []Segments
+		for _, c := range concrete {
+			e := make(Segments, 0, len(s))
+			e = append(e, s[:i]...)
+			e = append(e, c)
+			e = append(e, s[i+1:]...)
+
+			r, err := expandWildcards(data, e)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, r...)
+		}
+		return res, nil
+	}
+
+	return []Segments{s}, nil
+}
+
+// childOf returns the child of the supplied JSON value identified by the
+// supplied segment, if it exists.
+func childOf(it interface{}, s Segment) (interface{}, bool) {
+	switch s.Type {
+	case SegmentIndex:
+		array, ok := it.([]interface{})
+		if !ok || int(s.Index) >= len(array) {
+			return nil, false
+		}
+		return array[s.Index], true
+	case SegmentFilter:
+		array, ok := it.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		ix := filterIndex(array, s)
+		if ix < 0 {
+			return nil, false
+		}
+		return array[ix], true
+	case SegmentField:
+		object, ok := it.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok := object[s.Field]
+		return v, ok
+	}
+	return nil, false
+}
+
 // GetValue of the supplied field path.
 func (p *Paved) GetValue(path string) (interface{}, error) {
 	segments, err := Parse(path)
@@ -103,6 +226,20 @@ func (p *Paved) GetValue(path string) (interface{}, error) {
 	return p.getValue(segments)
 }
 
+// GetValueInto the supplied type. The value at the supplied field path is
+// marshalled to JSON, then unmarshalled into out.
+func (p *Paved) GetValueInto(path string, out interface{}) error {
+	v, err := p.GetValue(path)
+	if err != nil {
+		return err
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal value to JSON")
+	}
+	return errors.Wrap(json.Unmarshal(j, out), "cannot unmarshal value from JSON")
+}
+
 // GetString value of the supplied field path.
 func (p *Paved) GetString(path string) (string, error) {
 	v, err := p.GetValue(path)
@@ -194,18 +331,42 @@ func (p *Paved) GetNumber(path string) (float64, error) {
 	return f, nil
 }
 
+// GetInteger value of the supplied field path. JSON numbers are unmarshalled as
+// float64, so any number without a fractional part that fits in an int64 is
+// considered an integer.
+func (p *Paved) GetInteger(path string) (int64, error) {
+	v, err := p.GetValue(path)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, errors.Errorf("%s: not an integer", path)
+		}
+		// float64(math.MaxInt64) rounds up to 2^63, which overflows an int64.
+		if n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, errors.Errorf("%s: integer out of range", path)
+		}
+		return int64(n), nil
+	}
+
+	return 0, errors.Errorf("%s: not an integer", path)
+}
+
 func (p *Paved) setValue(s Segments, value interface{}) error {
 	// We expect p.object to look like JSON data that was unmarshalled into an
 	// interface{} per https://golang.org/pkg/encoding/json/#Unmarshal. We
 	// marshal our value to JSON and unmarshal it into an interface{} to ensure
 	// it meets these criteria before setting it within p.object.
-	var v interface{}
-	j, err := json.Marshal(value)
+	v, err := toJSONValue(value)
 	if err != nil {
-		return errors.Wrap(err, "cannot marshal value to JSON")
-	}
-	if err := json.Unmarshal(j, &v); err != nil {
-		return errors.Wrap(err, "cannot unmarshal value from JSON")
+		return err
 	}
 
 	var in interface{} = p.object
@@ -227,6 +388,27 @@ func (p *Paved) setValue(s Segments, value interface{}) error {
 			prepareElement(array, current, s[i+1])
 			in = array[current.Index]
 
+		case SegmentFilter:
+			array, ok := in.([]interface{})
+			if !ok {
+				return errors.Errorf("%s is not an array", s[:i])
+			}
+
+			// The preceding segment will have appended an element matching
+			// this filter if none existed, unless this is the first segment.
+			ix := filterIndex(array, current)
+			if ix < 0 {
+				return errors.Errorf("%s: no such element", s[:i+1])
+			}
+
+			if final {
+				array[ix] = v
+				return nil
+			}
+
+			prepareElement(array, Segment{Type: SegmentIndex, Index: uint(ix)}, s[i+1])
+			in = array[ix]
+
 		case SegmentField:
 			object, ok := in.(map[string]interface{})
 			if !ok {
@@ -250,18 +432,7 @@ func prepareElement(array []interface{}, current, next Segment) {
 	// If this segment is not the final one and doesn't exist we need to
 	// create it for our next segment.
 	if array[current.Index] == nil {
-		switch next.Type {
-		case SegmentIndex:
-			array[current.Index] = make([]interface{}, next.Index+1)
-		case SegmentField:
-			array[current.Index] = make(map[string]interface{})
-		}
-		return
-	}
-
-	// If our next segment indexes an array that exists in our current segment's
-	// element we must ensure the array is long enough to set the next segment.
-	if next.Type != SegmentIndex {
+		array[current.Index] = newContainer(next)
 		return
 	}
 
@@ -269,30 +440,14 @@ func prepareElement(array []interface{}, current, next Segment) {
 	if !ok {
 		return
 	}
-
-	if int(next.Index) < len(na) {
-		return
-	}
-
-	array[current.Index] = append(na, make([]interface{}, int(next.Index)-len(na)+1)...)
+	array[current.Index] = growArray(na, next)
 }
 
 func prepareField(object map[string]interface{}, current, next Segment) {
 	// If this segment is not the final one and doesn't exist we need to
 	// create it for our next segment.
 	if _, ok := object[current.Field]; !ok {
-		switch next.Type {
-		case SegmentIndex:
-			object[current.Field] = make([]interface{}, next.Index+1)
-		case SegmentField:
-			object[current.Field] = make(map[string]interface{})
-		}
-		return
-	}
-
-	// If our next segment indexes an array that exists in our current segment's
-	// field we must ensure the array is long enough to set the next segment.
-	if next.Type != SegmentIndex {
+		object[current.Field] = newContainer(next)
 		return
 	}
 
@@ -300,12 +455,84 @@ func prepareField(object map[string]interface{}, current, next Segment) {
 	if !ok {
 		return
 	}
+	object[current.Field] = growArray(na, next)
+}
 
-	if int(next.Index) < len(na) {
-		return
+// newContainer returns a new, empty container suitable for the supplied next
+// segment to be set within.
+func newContainer(next Segment) interface{} {
+	switch next.Type {
+	case SegmentIndex:
+		return make([]interface{}, next.Index+1)
+	case SegmentFilter:
+		return []interface{}{newFilterElement(next)}
+	case SegmentField:
+		return make(map[string]interface{})
+	}
+	return nil
+}
+
+// growArray ensures the supplied array, which already exists, contains an
+// element for the supplied next segment. If our next segment indexes the array
+// we must ensure it is long enough to set the next segment. If our next segment
+// filters the array we must ensure an element matching the filter exists.
+func growArray(na []interface{}, next Segment) []interface{} {
+	switch next.Type {
+	case SegmentIndex:
+		if int(next.Index) < len(na) {
+			return na
+		}
+		return append(na, make([]interface{}, int(next.Index)-len(na)+1)...)
+	case SegmentFilter:
+		if filterIndex(na, next) >= 0 {
+			return na
+		}
+		return append(na, newFilterElement(next))
+	}
+	return na
+}
+
+// newFilterElement returns an array element that matches the supplied filter
+// segment. Filter values are always set as strings.
+func newFilterElement(s Segment) map[string]interface{} {
+	return map[string]interface{}{s.Field: s.Value}
+}
+
+// filterIndex returns the index of the first element of the supplied array that
+// is an object whose filter field matches the supplied filter segment's value,
+// or -1 if no element matches.
+func filterIndex(array []interface{}, s Segment) int {
+	for i := range array {
+		o, ok := array[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v, ok := o[s.Field]
+		if !ok {
+			continue
+		}
+		if fv, ok := filterValue(v); ok && fv == s.Value {
+			return i
+		}
 	}
+	return -1
+}
 
-	object[current.Field] = append(na, make([]interface{}, int(next.Index)-len(na)+1)...)
+// filterValue returns the string representation of the supplied scalar JSON
+// value for comparison with a filter value. Objects and arrays have no string
+// representation, and thus never match a filter.
+func filterValue(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case bool:
+		return strconv.FormatBool(t), true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case int64:
+		return strconv.FormatInt(t, 10), true
+	}
+	return "", false
 }
 
 // SetValue at the supplied field path.
@@ -331,3 +558,98 @@ func (p *Paved) SetBool(path string, value bool) error {
 func (p *Paved) SetNumber(path string, value float64) error {
 	return p.SetValue(path, value)
 }
+
+// SetInteger value at the supplied field path.
+func (p *Paved) SetInteger(path string, value int64) error {
+	return p.SetValue(path, value)
+}
+
+// DeleteField deletes the field at the supplied field path. Deleting an array
+// element removes it from the array, shifting the indices of any subsequent
+// elements down by one. Deleting a field that does not exist is a no-op.
+func (p *Paved) DeleteField(path string) error {
+	segments, err := Parse(path)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse path %q", path)
+	}
+	return p.delete(segments)
+}
+
+func (p *Paved) delete(s Segments) error {
+	if p.object == nil || len(s) == 0 {
+		return nil
+	}
+
+	o, err := deleteField(p.object, s, 0)
+	if err != nil {
+		return err
+	}
+
+	p.object = o.(map[string]interface{})
+	return nil
+}
+
+// deleteField deletes the field identified by s[i:] from the supplied JSON
+// value, returning the (potentially new) value. Deleting an array element
+// produces a new, shorter array that callers must store in place of the old.
+func deleteField(in interface{}, s Segments, i int) (interface{}, error) { // nolint:gocyclo
+	// This function is slightly over our complexity goal, but is easier to
+	// follow as a single function.
+
+	current := s[i]
+	final := i == len(s)-1
+
+	switch current.Type {
+	case SegmentIndex, SegmentFilter:
+		array, ok := in.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s is not an array", s[:i])
+		}
+
+		ix := int(current.Index)
+		if current.Type == SegmentFilter {
+			ix = filterIndex(array, current)
+		}
+		if ix < 0 || ix >= len(array) {
+			return array, nil
+		}
+
+		if final {
+			compacted := make([]interface{}, 0, len(array)-1)
+			compacted = append(compacted, array[:ix]...)
+			return append(compacted, array[ix+1:]...), nil
+		}
+
+		v, err := deleteField(array[ix], s, i+1)
+		if err != nil {
+			return nil, err
+		}
+		array[ix] = v
+		return array, nil
+
+	case SegmentField:
+		object, ok := in.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s is not an object", s[:i])
+		}
+
+		child, ok := object[current.Field]
+		if !ok {
+			return object, nil
+		}
+
+		if final {
+			delete(object, current.Field)
+			return object, nil
+		}
+
+		v, err := deleteField(child, s, i+1)
+		if err != nil {
+			return nil, err
+		}
+		object[current.Field] = v
+		return object, nil
+	}
+
+	return in, nil
+}
@@ -18,13 +18,15 @@ package fieldpath
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -79,6 +81,30 @@ func TestGetValue(t *testing.T) {
 				value: float64(2),
 			},
 		},
+		"FilteredRuleVerbs": {
+			reason: "It should be possible to get a field from an array element selected by a filter",
+			path:   "spec.rules[?name=admin].verbs[0]",
+			data:   []byte(`{"spec":{"rules":[{"name":"user","verbs":["get"]},{"name":"admin","verbs":["*"]}]}}`),
+			want: want{
+				value: "*",
+			},
+		},
+		"FilteredNumber": {
+			reason: "It should be possible to filter array elements by a number field",
+			path:   "spec.ports[?port=443].name",
+			data:   []byte(`{"spec":{"ports":[{"port":80,"name":"http"},{"port":443,"name":"https"}]}}`),
+			want: want{
+				value: "https",
+			},
+		},
+		"NoFilterMatch": {
+			reason: "Requesting an array element that matches no filter should fail",
+			path:   "spec.rules[?name=admin].verbs",
+			data:   []byte(`{"spec":{"rules":[{"name":"user","verbs":["get"]}]}}`),
+			want: want{
+				err: errors.New("spec.rules[?name=admin]: no such element"),
+			},
+		},
 		"MetadataNope": {
 			reason: "Requesting a non-existent object field should fail",
 			path:   "metadata.name",
@@ -137,6 +163,81 @@ func TestGetValue(t *testing.T) {
 	}
 }
 
+func TestGetValueInto(t *testing.T) {
+	type Struct struct {
+		Slice       []string `json:"slice"`
+		StringField string   `json:"string"`
+		IntField    int      `json:"int"`
+	}
+
+	type args struct {
+		path string
+		out  interface{}
+	}
+	type want struct {
+		out interface{}
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		data   []byte
+		args   args
+		want   want
+	}{
+		"Struct": {
+			reason: "It should be possible to get a value into a struct.",
+			data:   []byte(`{"s":{"slice":["a"],"string":"b","int":1}}`),
+			args: args{
+				path: "s",
+				out:  &Struct{},
+			},
+			want: want{
+				out: &Struct{Slice: []string{"a"}, StringField: "b", IntField: 1},
+			},
+		},
+		"NotFound": {
+			reason: "Getting a value from a field path that doesn't exist should fail.",
+			data:   []byte(`{}`),
+			args: args{
+				path: "s",
+				out:  &Struct{},
+			},
+			want: want{
+				out: &Struct{},
+				err: errors.New("s: no such field"),
+			},
+		},
+		"TypeMismatch": {
+			reason: "Getting a value into a type it cannot be unmarshalled into should fail.",
+			data:   []byte(`{"s":"cool"}`),
+			args: args{
+				path: "s",
+				out:  &Struct{},
+			},
+			want: want{
+				out: &Struct{},
+				err: errors.Wrap(&json.UnmarshalTypeError{Value: "string", Type: reflect.TypeOf(Struct{}), Offset: 6}, "cannot unmarshal value from JSON"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make(map[string]interface{})
+			_ = json.Unmarshal(tc.data, &in)
+			p := Pave(in)
+
+			err := p.GetValueInto(tc.args.path, tc.args.out)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\np.GetValueInto(%s): %s: -want error, +got error:\n%s", tc.args.path, tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, tc.args.out); diff != "" {
+				t.Errorf("\np.GetValueInto(%s): %s: -want, +got:\n%s", tc.args.path, tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGetString(t *testing.T) {
 	type want struct {
 		value string
@@ -418,6 +519,91 @@ func TestGetNumber(t *testing.T) {
 	}
 }
 
+func TestGetInteger(t *testing.T) {
+	type want struct {
+		value int64
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		path   string
+		data   []byte
+		want   want
+	}{
+		"MetadataVersion": {
+			reason: "Requesting an integer field should work",
+			path:   "metadata.version",
+			data:   []byte(`{"metadata":{"version":2}}`),
+			want: want{
+				value: 2,
+			},
+		},
+		"MalformedPath": {
+			reason: "Requesting an invalid field path should fail",
+			path:   "spec[]",
+			want: want{
+				err: errors.Wrap(errors.New("unexpected ']' at position 5"), "cannot parse path \"spec[]\""),
+			},
+		},
+		"Fractional": {
+			reason: "Requesting a number with a fractional part should fail",
+			path:   "metadata.version",
+			data:   []byte(`{"metadata":{"version":2.5}}`),
+			want: want{
+				err: errors.New("metadata.version: not an integer"),
+			},
+		},
+		"NotANumber": {
+			reason: "Requesting an non-number field path should fail",
+			path:   "metadata.name",
+			data:   []byte(`{"metadata":{"name":"cool"}}`),
+			want: want{
+				err: errors.New("metadata.name: not an integer"),
+			},
+		},
+		"Negative": {
+			reason: "Requesting a negative integer field should work",
+			path:   "metadata.version",
+			data:   []byte(`{"metadata":{"version":-2}}`),
+			want: want{
+				value: -2,
+			},
+		},
+		"TooLarge": {
+			reason: "Requesting a number larger than the maximum int64 should fail",
+			path:   "metadata.version",
+			data:   []byte(`{"metadata":{"version":9223372036854775808}}`),
+			want: want{
+				err: errors.New("metadata.version: integer out of range"),
+			},
+		},
+		"TooSmall": {
+			reason: "Requesting a number smaller than the minimum int64 should fail",
+			path:   "metadata.version",
+			data:   []byte(`{"metadata":{"version":-1e19}}`),
+			want: want{
+				err: errors.New("metadata.version: integer out of range"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make(map[string]interface{})
+			_ = json.Unmarshal(tc.data, &in)
+			p := Pave(in)
+
+			got, err := p.GetInteger(tc.path)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\np.GetInteger(%s): %s: -want error, +got error:\n%s", tc.path, tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.value, got); diff != "" {
+				t.Errorf("\np.GetInteger(%s): %s: -want, +got:\n%s", tc.path, tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestSetValue(t *testing.T) {
 	type args struct {
 		path  string
@@ -482,6 +668,72 @@ func TestSetValue(t *testing.T) {
 				},
 			},
 		},
+		"FilteredRuleVerbs": {
+			reason: "Setting a field of an array element selected by a filter should work",
+			data:   []byte(`{"spec":{"rules":[{"name":"user"},{"name":"admin","verbs":["get"]}]}}`),
+			args: args{
+				path:  "spec.rules[?name=admin].verbs",
+				value: []string{"*"},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"rules": []interface{}{
+							map[string]interface{}{
+								"name": "user",
+							},
+							map[string]interface{}{
+								"name":  "admin",
+								"verbs": []interface{}{"*"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"NonExistentFilteredRuleVerbs": {
+			reason: "Setting a field of an array element that matches no filter should append a matching element",
+			data:   []byte(`{"spec":{"rules":[{"name":"user"}]}}`),
+			args: args{
+				path:  "spec.rules[?name=admin].verbs",
+				value: []string{"*"},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"rules": []interface{}{
+							map[string]interface{}{
+								"name": "user",
+							},
+							map[string]interface{}{
+								"name":  "admin",
+								"verbs": []interface{}{"*"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"NonExistentFilteredArray": {
+			reason: "Setting a field of an array element selected by a filter should create the array if necessary",
+			data:   []byte(`{}`),
+			args: args{
+				path:  "spec.rules[?name=admin].verbs",
+				value: []string{"*"},
+			},
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"rules": []interface{}{
+							map[string]interface{}{
+								"name":  "admin",
+								"verbs": []interface{}{"*"},
+							},
+						},
+					},
+				},
+			},
+		},
 		"NonExistentContainerName": {
 			reason: "Setting a field of a non-existent object that is an array element should work",
 			data:   []byte(`{}`),
@@ -658,3 +910,249 @@ func TestSetValue(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandWildcards(t *testing.T) {
+	type want struct {
+		expanded []string
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		path   string
+		data   []byte
+		want   want
+	}{
+		"NoWildcards": {
+			reason: "A path without wildcards should expand to itself",
+			path:   "spec.containers[0].image",
+			data:   []byte(`{"spec":{"containers":[{"image":"cool"}]}}`),
+			want: want{
+				expanded: []string{"spec.containers[0].image"},
+			},
+		},
+		"ArrayWildcard": {
+			reason: "A wildcard should expand to every element of an array",
+			path:   "spec.containers[*].image",
+			data:   []byte(`{"spec":{"containers":[{"image":"cool"},{"image":"cooler"}]}}`),
+			want: want{
+				expanded: []string{"spec.containers[0].image", "spec.containers[1].image"},
+			},
+		},
+		"ObjectWildcard": {
+			reason: "A wildcard should expand to every field of an object, in lexical order",
+			path:   "metadata.labels[*]",
+			data:   []byte(`{"metadata":{"labels":{"b":"2","a":"1"}}}`),
+			want: want{
+				expanded: []string{"metadata.labels.a", "metadata.labels.b"},
+			},
+		},
+		"NestedWildcards": {
+			reason: "Multiple wildcards should expand to every concrete path",
+			path:   "spec.containers[*].ports[*]",
+			data:   []byte(`{"spec":{"containers":[{"ports":[80,443]},{"ports":[8080]}]}}`),
+			want: want{
+				expanded: []string{"spec.containers[0].ports[0]", "spec.containers[0].ports[1]", "spec.containers[1].ports[0]"},
+			},
+		},
+		"MissingLeaf": {
+			reason: "Paths that do not exist should be omitted from the expansion",
+			path:   "spec.containers[*].image",
+			data:   []byte(`{"spec":{"containers":[{"name":"nope"},{"image":"cool"}]}}`),
+			want: want{
+				expanded: []string{"spec.containers[1].image"},
+			},
+		},
+		"MissingParent": {
+			reason: "A wildcard beneath a path that does not exist should expand to nothing",
+			path:   "spec.containers[*].image",
+			data:   []byte(`{"spec":{}}`),
+			want: want{
+				expanded: []string{},
+			},
+		},
+		"NotExpandable": {
+			reason: "Expanding a wildcard against a scalar should fail",
+			path:   "metadata.name[*]",
+			data:   []byte(`{"metadata":{"name":"cool"}}`),
+			want: want{
+				err: errors.Wrap(errors.New("metadata.name: cannot expand wildcard; not an array or object"), "cannot expand wildcards for path \"metadata.name[*]\""),
+			},
+		},
+		"MalformedPath": {
+			reason: "Expanding an invalid field path should fail",
+			path:   "spec[]",
+			want: want{
+				err: errors.Wrap(errors.New("unexpected ']' at position 5"), "cannot parse path \"spec[]\""),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make(map[string]interface{})
+			_ = json.Unmarshal(tc.data, &in)
+			p := Pave(in)
+
+			got, err := p.ExpandWildcards(tc.path)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\np.ExpandWildcards(%s): %s: -want error, +got error:\n%s", tc.path, tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.expanded, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\np.ExpandWildcards(%s): %s: -want, +got:\n%s", tc.path, tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeleteField(t *testing.T) {
+	type want struct {
+		object map[string]interface{}
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		data   []byte
+		path   string
+		want   want
+	}{
+		"MetadataName": {
+			reason: "Deleting an object field should work",
+			data:   []byte(`{"metadata":{"name":"cool","namespace":"default"}}`),
+			path:   "metadata.name",
+			want: want{
+				object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"namespace": "default",
+					},
+				},
+			},
+		},
+		"ArrayElement": {
+			reason: "Deleting an array element should remove it and compact the array",
+			data:   []byte(`{"spec":{"containers":[{"name":"a"},{"name":"b"},{"name":"c"}]}}`),
+			path:   "spec.containers[1]",
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "a"},
+							map[string]interface{}{"name": "c"},
+						},
+					},
+				},
+			},
+		},
+		"NestedArrayElement": {
+			reason: "Deleting an element of a nested array should compact only the nested array",
+			data:   []byte(`{"items":[["a","b"],["c"]]}`),
+			path:   "items[0][0]",
+			want: want{
+				object: map[string]interface{}{
+					"items": []interface{}{
+						[]interface{}{"b"},
+						[]interface{}{"c"},
+					},
+				},
+			},
+		},
+		"FilteredArrayElement": {
+			reason: "Deleting an array element selected by a filter should work",
+			data:   []byte(`{"spec":{"rules":[{"name":"user"},{"name":"admin"}]}}`),
+			path:   "spec.rules[?name=user]",
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"rules": []interface{}{
+							map[string]interface{}{"name": "admin"},
+						},
+					},
+				},
+			},
+		},
+		"FieldOfArrayElement": {
+			reason: "Deleting a field of an array element should work",
+			data:   []byte(`{"spec":{"containers":[{"name":"a","image":"cool"}]}}`),
+			path:   "spec.containers[0].image",
+			want: want{
+				object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "a"},
+						},
+					},
+				},
+			},
+		},
+		"NonExistentField": {
+			reason: "Deleting a field that does not exist should be a no-op",
+			data:   []byte(`{"metadata":{"name":"cool"}}`),
+			path:   "spec.containers[0].image",
+			want: want{
+				object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "cool",
+					},
+				},
+			},
+		},
+		"NonExistentElement": {
+			reason: "Deleting an array element that does not exist should be a no-op",
+			data:   []byte(`{"items":["a"]}`),
+			path:   "items[3]",
+			want: want{
+				object: map[string]interface{}{
+					"items": []interface{}{"a"},
+				},
+			},
+		},
+		"NotAnArray": {
+			reason: "Deleting an index of an object should fail",
+			data:   []byte(`{"metadata":{"name":"cool"}}`),
+			path:   "metadata[0]",
+			want: want{
+				object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "cool",
+					},
+				},
+				err: errors.New("metadata is not an array"),
+			},
+		},
+		"NotAnObject": {
+			reason: "Deleting a field of an array should fail",
+			data:   []byte(`{"items":["a"]}`),
+			path:   "items.name",
+			want: want{
+				object: map[string]interface{}{
+					"items": []interface{}{"a"},
+				},
+				err: errors.New("items is not an object"),
+			},
+		},
+		"MalformedPath": {
+			reason: "Deleting an invalid field path should fail",
+			data:   []byte(`{}`),
+			path:   "spec[]",
+			want: want{
+				object: map[string]interface{}{},
+				err:    errors.Wrap(errors.New("unexpected ']' at position 5"), "cannot parse path \"spec[]\""),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := make(map[string]interface{})
+			_ = json.Unmarshal(tc.data, &in)
+			p := Pave(in)
+
+			err := p.DeleteField(tc.path)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\np.DeleteField(%s): %s: -want error, +got error:\n%s", tc.path, tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.object, p.object); diff != "" {
+				t.Fatalf("\np.DeleteField(%s): %s: -want, +got:\n%s", tc.path, tc.reason, diff)
+			}
+		})
+	}
+}
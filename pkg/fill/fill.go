@@ -0,0 +1,312 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fill contains utilities for filling the fields of a managed
+// resource from the similarly shaped structs returned by provider SDKs, for
+// example to populate status.atProvider from an API response, or to
+// late-initialize spec.forProvider.
+package fill
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// TagKey is the key of the struct tag that may be used to specify the name of
+// the source field from which a field should be filled, for example:
+//
+//	type Parameters struct {
+//		Size string `json:"size" fill:"InstanceClass"`
+//		Zone string `json:"zone" fill:"-"`
+//	}
+//
+// Fields tagged "-" are never filled. Untagged fields are filled from the
+// source field of the same name.
+const TagKey = "fill"
+
+// Error strings.
+const (
+	errNotStructPtr = "cannot fill a value that is not a non-nil pointer to a struct"
+	errNotStruct    = "cannot fill from a value that is not a struct or a pointer to a struct"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	metaTimeType = reflect.TypeOf(metav1.Time{})
+)
+
+// AtProvider fills each field of the struct pointed to by to with the value of
+// the matching field of from, which is typically an API response from a
+// provider SDK. Fields are matched by name, ignoring case and underscores,
+// unless a field's TagKey tag specifies a different name. Nested structs,
+// slices, and maps are filled recursively even if their types differ. Values
+// are converted between pointer and non-pointer types, between types of the
+// same kind (e.g. int32 and int64, or string and a named string type), and
+// from time.Time to metav1.Time. Fields that cannot be converted, or that have
+// no matching field, are left unchanged.
+func AtProvider(to, from interface{}) error {
+	_, err := fill(to, from, false)
+	return err
+}
+
+// LateInitialize fills each unset field of the struct pointed to by to with
+// the value of the matching field of from, per AtProvider. A field is unset if
+// it is the zero value of its type. Fields that are set are assumed to have
+// been set by the author of the managed resource, and are never overwritten.
+// Set structs, including those referenced by pointers, are filled recursively.
+// LateInitialize returns true if any field was filled.
+func LateInitialize(to, from interface{}) (bool, error) {
+	return fill(to, from, true)
+}
+
+func fill(to, from interface{}, lateInit bool) (bool, error) {
+	tv := reflect.ValueOf(to)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() || tv.Elem().Kind() != reflect.Struct {
+		return false, errors.New(errNotStructPtr)
+	}
+	fv := deref(reflect.ValueOf(from))
+	if !fv.IsValid() {
+		// There's nothing to fill from.
+		return false, nil
+	}
+	if fv.Kind() != reflect.Struct {
+		return false, errors.New(errNotStruct)
+	}
+	f := &filler{lateInit: lateInit}
+	f.structs(tv.Elem(), fv)
+	return f.changed, nil
+}
+
+// deref dereferences the supplied pointer or interface value until it reaches
+// a concrete value. It returns an invalid value if it encounters a nil.
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// A filler fills values, recording whether it changed any.
+type filler struct {
+	lateInit bool
+	changed  bool
+}
+
+// structs fills each exported field of the supplied struct from the matching
+// field of the supplied source struct.
+func (f *filler) structs(to, from reflect.Value) {
+	src := fields(from)
+	t := to.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			// We can't set unexported fields.
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup(TagKey); ok {
+			name = tag
+		}
+		if name == "-" {
+			continue
+		}
+		if v, ok := src[normalize(name)]; ok {
+			f.value(to.Field(i), v)
+		}
+	}
+}
+
+// fields returns the exported fields of the supplied struct by normalized
+// name, including the fields promoted from any embedded structs.
+func fields(v reflect.Value) map[string]reflect.Value {
+	out := map[string]reflect.Value{}
+	embedded := []reflect.Value{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		// The exported fields of embedded structs are promoted even if the
+		// embedded struct's type is unexported.
+		if e := deref(v.Field(i)); sf.Anonymous && e.Kind() == reflect.Struct {
+			embedded = append(embedded, e)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		out[normalize(sf.Name)] = v.Field(i)
+	}
+
+	// Fields of the outer struct take precedence over promoted fields.
+	for _, e := range embedded {
+		for k, fv := range fields(e) {
+			if _, ok := out[k]; !ok {
+				out[k] = fv
+			}
+		}
+	}
+	return out
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
+
+// value fills the supplied value from the supplied source value.
+func (f *filler) value(to, from reflect.Value) {
+	from = deref(from)
+	if !from.IsValid() {
+		// There's nothing to fill from.
+		return
+	}
+
+	switch {
+	case to.Kind() == reflect.Ptr:
+		f.pointer(to, from)
+	case to.Kind() == reflect.Struct && from.Kind() == reflect.Struct && !opaque(to.Type()) && !opaque(from.Type()):
+		f.structs(to, from)
+	default:
+		if f.lateInit && !to.IsZero() {
+			return
+		}
+		nv, ok := convert(to.Type(), from)
+		if !ok || reflect.DeepEqual(to.Interface(), nv.Interface()) {
+			return
+		}
+		to.Set(nv)
+		f.changed = true
+	}
+}
+
+// pointer fills the supplied pointer from the supplied source value,
+// allocating the value it points to if necessary.
+func (f *filler) pointer(to, from reflect.Value) {
+	if !to.IsNil() {
+		// A set pointer to a non-struct is considered set in its entirety.
+		if f.lateInit && to.Elem().Kind() != reflect.Struct {
+			return
+		}
+		f.value(to.Elem(), from)
+		return
+	}
+	nv, ok := newValue(to.Type().Elem(), from)
+	if !ok {
+		return
+	}
+	p := reflect.New(to.Type().Elem())
+	p.Elem().Set(nv)
+	to.Set(p)
+	f.changed = true
+}
+
+// opaque types are treated as single values rather than structs of fields.
+func opaque(t reflect.Type) bool {
+	return t == timeType || t == metaTimeType
+}
+
+// newValue returns a new value of the supplied type filled from the supplied
+// source value. It returns false if the source value could not be converted.
+func newValue(t reflect.Type, from reflect.Value) (reflect.Value, bool) {
+	nv := reflect.New(t).Elem()
+	f := &filler{}
+	f.value(nv, from)
+	return nv, f.changed || deref(from).IsValid() && deref(from).IsZero()
+}
+
+// convert the supplied value to the supplied type.
+func convert(t reflect.Type, from reflect.Value) (reflect.Value, bool) {
+	ft := from.Type()
+	switch {
+	case t == metaTimeType && ft == timeType:
+		return reflect.ValueOf(metav1.NewTime(from.Interface().(time.Time))), true
+	case ft.AssignableTo(t):
+		return from, true
+	case t.Kind() == reflect.Slice && ft.Kind() == reflect.Slice:
+		return convertSlice(t, from)
+	case t.Kind() == reflect.Map && ft.Kind() == reflect.Map:
+		return convertMap(t, from)
+	case class(t.Kind()) != classOther && class(t.Kind()) == class(ft.Kind()):
+		return from.Convert(t), true
+	}
+	return reflect.Value{}, false
+}
+
+func convertSlice(t reflect.Type, from reflect.Value) (reflect.Value, bool) {
+	if from.IsNil() {
+		return reflect.Zero(t), true
+	}
+	out := reflect.MakeSlice(t, from.Len(), from.Len())
+	for i := 0; i < from.Len(); i++ {
+		nv, ok := newValue(t.Elem(), from.Index(i))
+		if !ok {
+			return reflect.Value{}, false
+		}
+		out.Index(i).Set(nv)
+	}
+	return out, true
+}
+
+func convertMap(t reflect.Type, from reflect.Value) (reflect.Value, bool) {
+	if from.IsNil() {
+		return reflect.Zero(t), true
+	}
+	out := reflect.MakeMapWithSize(t, from.Len())
+	iter := from.MapRange()
+	for iter.Next() {
+		k, ok := convert(t.Key(), iter.Key())
+		if !ok {
+			return reflect.Value{}, false
+		}
+		v, ok := newValue(t.Elem(), iter.Value())
+		if !ok {
+			return reflect.Value{}, false
+		}
+		out.SetMapIndex(k, v)
+	}
+	return out, true
+}
+
+type kindClass int
+
+const (
+	classOther kindClass = iota
+	classBool
+	classString
+	classNumber
+)
+
+// class returns the class of the supplied kind. Values may be converted
+// between kinds of the same class.
+func class(k reflect.Kind) kindClass {
+	switch k {
+	case reflect.Bool:
+		return classBool
+	case reflect.String:
+		return classString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return classNumber
+	}
+	return classOther
+}
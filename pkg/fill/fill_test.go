@@ -0,0 +1,319 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type sdkTags struct {
+	Key   *string
+	Value *string
+}
+
+type sdkEndpoint struct {
+	Address *string
+	Port    *int64
+}
+
+type sdkMeta struct {
+	ARN *string
+}
+
+type sdkInstance struct {
+	sdkMeta
+
+	DBInstanceIdentifier *string
+	DBInstanceStatus     *string
+	Endpoint             *sdkEndpoint
+	AllocatedStorage     *int64
+	MultiAZ              *bool
+	Engine               string
+	Tags                 []*sdkTags
+	Labels               map[string]*string
+	CreatedAt            *time.Time
+	Class                *string
+}
+
+type Tag struct {
+	Key   string
+	Value string
+}
+
+type Endpoint struct {
+	Address string
+	Port    int
+}
+
+type Observation struct {
+	ARN              string
+	Status           string `fill:"DBInstanceStatus"`
+	Endpoint         *Endpoint
+	AllocatedStorage int32
+	Tags             []Tag
+	Labels           map[string]string
+	CreatedAt        *metav1.Time
+	Class            int
+	Engine           string `fill:"-"`
+
+	unexported string
+}
+
+type Parameters struct {
+	DBInstanceIdentifier string
+	AllocatedStorage     *int
+	MultiAZ              *bool
+	Engine               string
+	Endpoint             *Endpoint
+	Tags                 []Tag
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+func int64Ptr(i int64) *int64 { return &i }
+func boolPtr(b bool) *bool    { return &b }
+
+func TestAtProvider(t *testing.T) {
+	created := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		to   interface{}
+		from interface{}
+	}
+	type want struct {
+		to  interface{}
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotStructPointer": {
+			reason: "We should return an error if we're asked to fill something other than a pointer to a struct.",
+			args: args{
+				to:   Observation{},
+				from: &sdkInstance{},
+			},
+			want: want{
+				to:  Observation{},
+				err: errors.New(errNotStructPtr),
+			},
+		},
+		"NotStruct": {
+			reason: "We should return an error if we're asked to fill from something other than a struct.",
+			args: args{
+				to:   &Observation{},
+				from: strPtr("nope"),
+			},
+			want: want{
+				to:  &Observation{},
+				err: errors.New(errNotStruct),
+			},
+		},
+		"NilSource": {
+			reason: "We should fill nothing from a nil source.",
+			args: args{
+				to:   &Observation{Status: "available"},
+				from: (*sdkInstance)(nil),
+			},
+			want: want{
+				to: &Observation{Status: "available"},
+			},
+		},
+		"Filled": {
+			reason: "We should fill matching fields, converting their types where necessary.",
+			args: args{
+				to: &Observation{Status: "creating", Class: 7, Engine: "postgres", unexported: "cool"},
+				from: &sdkInstance{
+					sdkMeta:          sdkMeta{ARN: strPtr("arn:cool")},
+					DBInstanceStatus: strPtr("available"),
+					Endpoint:         &sdkEndpoint{Address: strPtr("example.org"), Port: int64Ptr(5432)},
+					AllocatedStorage: int64Ptr(20),
+					Engine:           "mysql",
+					Tags:             []*sdkTags{{Key: strPtr("k"), Value: strPtr("v")}},
+					Labels:           map[string]*string{"a": strPtr("b")},
+					CreatedAt:        &created,
+					Class:            strPtr("db.t2.micro"),
+				},
+			},
+			want: want{
+				to: &Observation{
+					ARN:              "arn:cool",
+					Status:           "available",
+					Endpoint:         &Endpoint{Address: "example.org", Port: 5432},
+					AllocatedStorage: 20,
+					Tags:             []Tag{{Key: "k", Value: "v"}},
+					Labels:           map[string]string{"a": "b"},
+					CreatedAt:        &metav1.Time{Time: created},
+					Class:            7,
+					Engine:           "postgres",
+					unexported:       "cool",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := AtProvider(tc.args.to, tc.args.from)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAtProvider(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.to, tc.args.to, cmp.AllowUnexported(Observation{})); diff != "" {
+				t.Errorf("\n%s\nAtProvider(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	type args struct {
+		to   interface{}
+		from interface{}
+	}
+	type want struct {
+		to      interface{}
+		changed bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotStructPointer": {
+			reason: "We should return an error if we're asked to late initialize something other than a pointer to a struct.",
+			args: args{
+				to:   Parameters{},
+				from: &sdkInstance{},
+			},
+			want: want{
+				to:  Parameters{},
+				err: errors.New(errNotStructPtr),
+			},
+		},
+		"AllUnset": {
+			reason: "We should late initialize all unset fields.",
+			args: args{
+				to: &Parameters{},
+				from: &sdkInstance{
+					DBInstanceIdentifier: strPtr("cool"),
+					AllocatedStorage:     int64Ptr(20),
+					MultiAZ:              boolPtr(false),
+					Engine:               "mysql",
+					Endpoint:             &sdkEndpoint{Port: int64Ptr(3306)},
+					Tags:                 []*sdkTags{{Key: strPtr("k"), Value: strPtr("v")}},
+				},
+			},
+			want: want{
+				to: &Parameters{
+					DBInstanceIdentifier: "cool",
+					AllocatedStorage:     intPtr(20),
+					MultiAZ:              boolPtr(false),
+					Engine:               "mysql",
+					Endpoint:             &Endpoint{Port: 3306},
+					Tags:                 []Tag{{Key: "k", Value: "v"}},
+				},
+				changed: true,
+			},
+		},
+		"SomeSet": {
+			reason: "We should not overwrite set fields, but should late initialize the unset fields of set structs.",
+			args: args{
+				to: &Parameters{
+					DBInstanceIdentifier: "mine",
+					AllocatedStorage:     intPtr(10),
+					Endpoint:             &Endpoint{Address: "example.org"},
+					Tags:                 []Tag{{Key: "mine"}},
+				},
+				from: &sdkInstance{
+					DBInstanceIdentifier: strPtr("theirs"),
+					AllocatedStorage:     int64Ptr(20),
+					MultiAZ:              boolPtr(true),
+					Endpoint:             &sdkEndpoint{Address: strPtr("example.net"), Port: int64Ptr(3306)},
+					Tags:                 []*sdkTags{{Key: strPtr("k"), Value: strPtr("v")}},
+				},
+			},
+			want: want{
+				to: &Parameters{
+					DBInstanceIdentifier: "mine",
+					AllocatedStorage:     intPtr(10),
+					MultiAZ:              boolPtr(true),
+					Endpoint:             &Endpoint{Address: "example.org", Port: 3306},
+					Tags:                 []Tag{{Key: "mine"}},
+				},
+				changed: true,
+			},
+		},
+		"AllSet": {
+			reason: "We should report that nothing changed if every field was already set.",
+			args: args{
+				to: &Parameters{
+					DBInstanceIdentifier: "mine",
+					AllocatedStorage:     intPtr(10),
+					MultiAZ:              boolPtr(false),
+					Engine:               "postgres",
+					Endpoint:             &Endpoint{Address: "example.org", Port: 5432},
+					Tags:                 []Tag{{Key: "mine"}},
+				},
+				from: &sdkInstance{
+					DBInstanceIdentifier: strPtr("theirs"),
+					AllocatedStorage:     int64Ptr(20),
+					MultiAZ:              boolPtr(true),
+					Engine:               "mysql",
+					Endpoint:             &sdkEndpoint{Address: strPtr("example.net"), Port: int64Ptr(3306)},
+				},
+			},
+			want: want{
+				to: &Parameters{
+					DBInstanceIdentifier: "mine",
+					AllocatedStorage:     intPtr(10),
+					MultiAZ:              boolPtr(false),
+					Engine:               "postgres",
+					Endpoint:             &Endpoint{Address: "example.org", Port: 5432},
+					Tags:                 []Tag{{Key: "mine"}},
+				},
+				changed: false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed, err := LateInitialize(tc.args.to, tc.args.from)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.to, tc.args.to); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lateinit contains utilities for late-initializing managed resources,
+// i.e. for copying values the external system defaulted into fields of a
+// managed resource's spec that were not set by its author.
+package lateinit
+
+import (
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errNotStructPtr = "cannot late-initialize a value that is not a non-nil pointer to a struct"
+	errTypeMismatch = "cannot late-initialize from a value of a different type"
+)
+
+// A LateInitializer late-initializes fields, recording whether any were
+// changed so that callers know whether to persist the managed resource.
+type LateInitializer struct {
+	changed bool
+}
+
+// New returns a new LateInitializer.
+func New() *LateInitializer {
+	return &LateInitializer{}
+}
+
+// SetChanged marks the LateInitializer such that users can tell whether any
+// of the late-initialization calls returned the non-original argument.
+func (li *LateInitializer) SetChanged() {
+	li.changed = true
+}
+
+// IsChanged reports whether the second argument is ever used in late
+// initialization function calls.
+func (li *LateInitializer) IsChanged() bool {
+	return li.changed
+}
+
+// LateInitializeStringPtr returns org if it's non-nil, otherwise returns from,
+// which is the backup for the cases org is nil.
+func (li *LateInitializer) LateInitializeStringPtr(org *string, from *string) *string {
+	if org != nil || from == nil {
+		return org
+	}
+	li.SetChanged()
+	return from
+}
+
+// LateInitializeIntPtr returns org if it's non-nil, otherwise returns from,
+// which is the backup for the cases org is nil.
+func (li *LateInitializer) LateInitializeIntPtr(org *int, from *int) *int {
+	if org != nil || from == nil {
+		return org
+	}
+	li.SetChanged()
+	return from
+}
+
+// LateInitializeInt64Ptr returns org if it's non-nil, otherwise returns from,
+// which is the backup for the cases org is nil.
+func (li *LateInitializer) LateInitializeInt64Ptr(org *int64, from *int64) *int64 {
+	if org != nil || from == nil {
+		return org
+	}
+	li.SetChanged()
+	return from
+}
+
+// LateInitializeBoolPtr returns org if it's non-nil, otherwise returns from,
+// which is the backup for the cases org is nil.
+func (li *LateInitializer) LateInitializeBoolPtr(org *bool, from *bool) *bool {
+	if org != nil || from == nil {
+		return org
+	}
+	li.SetChanged()
+	return from
+}
+
+// LateInitialize walks the struct pointed to by spec, setting any of its
+// fields that are unset (i.e. the zero value of their type) to the value of
+// the corresponding field of the struct pointed to by from. Fields that are
+// set are assumed to have been set by the author of the managed resource, and
+// are never overwritten. Nested structs, including those referenced by
+// pointers that are set in both spec and from, are walked recursively. Both
+// arguments must be non-nil pointers to structs of the same type.
+func (li *LateInitializer) LateInitialize(spec, from interface{}) error {
+	sv, fv := reflect.ValueOf(spec), reflect.ValueOf(from)
+	if sv.Kind() != reflect.Ptr || sv.IsNil() || sv.Elem().Kind() != reflect.Struct {
+		return errors.New(errNotStructPtr)
+	}
+	if sv.Type() != fv.Type() {
+		return errors.New(errTypeMismatch)
+	}
+	if fv.IsNil() {
+		return nil
+	}
+	li.walk(sv.Elem(), fv.Elem())
+	return nil
+}
+
+func (li *LateInitializer) walk(spec, from reflect.Value) {
+	for i := 0; i < spec.NumField(); i++ {
+		sf, ff := spec.Field(i), from.Field(i)
+
+		// We can't set unexported fields.
+		if !sf.CanSet() {
+			continue
+		}
+
+		switch {
+		case ff.IsZero():
+			// There's nothing to late-initialize from.
+		case sf.IsZero():
+			sf.Set(ff)
+			li.SetChanged()
+		case sf.Kind() == reflect.Struct:
+			li.walk(sf, ff)
+		case sf.Kind() == reflect.Ptr && sf.Elem().Kind() == reflect.Struct:
+			li.walk(sf.Elem(), ff.Elem())
+		}
+	}
+}
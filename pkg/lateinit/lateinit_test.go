@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lateinit
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+
+func TestLateInitializeStringPtr(t *testing.T) {
+	type want struct {
+		s       *string
+		changed bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		org    *string
+		from   *string
+		want   want
+	}{
+		"OriginalSet": {
+			reason: "The original value should be preserved if it is set.",
+			org:    strPtr("author"),
+			from:   strPtr("default"),
+			want:   want{s: strPtr("author")},
+		},
+		"OriginalUnset": {
+			reason: "The backup value should be used if the original value is unset.",
+			from:   strPtr("default"),
+			want:   want{s: strPtr("default"), changed: true},
+		},
+		"BothUnset": {
+			reason: "Nothing should change if neither value is set.",
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li := New()
+			got := li.LateInitializeStringPtr(tc.org, tc.from)
+			if diff := cmp.Diff(tc.want.s, got); diff != "" {
+				t.Errorf("\n%s\nLateInitializeStringPtr(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, li.IsChanged()); diff != "" {
+				t.Errorf("\n%s\nIsChanged(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializeIntPtr(t *testing.T) {
+	li := New()
+	if diff := cmp.Diff(intPtr(1), li.LateInitializeIntPtr(intPtr(1), intPtr(2))); diff != "" {
+		t.Errorf("LateInitializeIntPtr(...): -want, +got:\n%s", diff)
+	}
+	if li.IsChanged() {
+		t.Errorf("IsChanged(): want false, got true")
+	}
+	if diff := cmp.Diff(intPtr(2), li.LateInitializeIntPtr(nil, intPtr(2))); diff != "" {
+		t.Errorf("LateInitializeIntPtr(...): -want, +got:\n%s", diff)
+	}
+	if !li.IsChanged() {
+		t.Errorf("IsChanged(): want true, got false")
+	}
+}
+
+type nested struct {
+	Zone *string
+	Tier string
+}
+
+type params struct {
+	Name     *string
+	Size     int
+	Tags     map[string]string
+	Location nested
+	Backup   *nested
+	internal string
+}
+
+func TestLateInitialize(t *testing.T) {
+	type args struct {
+		spec interface{}
+		from interface{}
+	}
+	type want struct {
+		spec    interface{}
+		changed bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotAStructPointer": {
+			reason: "We should return an error if spec is not a pointer to a struct.",
+			args:   args{spec: params{}, from: params{}},
+			want:   want{spec: params{}, err: errors.New(errNotStructPtr)},
+		},
+		"TypeMismatch": {
+			reason: "We should return an error if spec and from are of different types.",
+			args:   args{spec: &params{}, from: &nested{}},
+			want:   want{spec: &params{}, err: errors.New(errTypeMismatch)},
+		},
+		"NilFrom": {
+			reason: "Nothing should change if there is nothing to late-initialize from.",
+			args:   args{spec: &params{Size: 1}, from: (*params)(nil)},
+			want:   want{spec: &params{Size: 1}},
+		},
+		"UnsetFieldsInitialized": {
+			reason: "Unset fields should be late-initialized, while fields the author set are preserved.",
+			args: args{
+				spec: &params{
+					Name:     strPtr("author"),
+					Location: nested{Tier: "gold"},
+					Backup:   &nested{Tier: "silver"},
+				},
+				from: &params{
+					Name:     strPtr("default"),
+					Size:     10,
+					Tags:     map[string]string{"cool": "true"},
+					Location: nested{Zone: strPtr("us-west-1a"), Tier: "bronze"},
+					Backup:   &nested{Zone: strPtr("us-west-1b"), Tier: "bronze"},
+					internal: "ignored",
+				},
+			},
+			want: want{
+				spec: &params{
+					Name:     strPtr("author"),
+					Size:     10,
+					Tags:     map[string]string{"cool": "true"},
+					Location: nested{Zone: strPtr("us-west-1a"), Tier: "gold"},
+					Backup:   &nested{Zone: strPtr("us-west-1b"), Tier: "silver"},
+				},
+				changed: true,
+			},
+		},
+		"NothingToInitialize": {
+			reason: "Nothing should change if every field is already set.",
+			args: args{
+				spec: &params{Name: strPtr("author"), Size: 1},
+				from: &params{Name: strPtr("default"), Size: 10},
+			},
+			want: want{spec: &params{Name: strPtr("author"), Size: 1}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			li := New()
+			err := li.LateInitialize(tc.args.spec, tc.args.from)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.spec, tc.args.spec, cmp.AllowUnexported(params{})); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, li.IsChanged()); diff != "" {
+				t.Errorf("\n%s\nIsChanged(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
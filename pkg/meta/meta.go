@@ -22,11 +22,12 @@ import (
 	"hash/fnv"
 	"strings"
 
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
 
 /*
@@ -40,6 +41,41 @@ import (
 // the name of the resource as it appears on provider's systems.
 const AnnotationKeyExternalName = "crossplane.io/external-name"
 
+// AnnotationKeyDryRun is the key in the annotations map of a managed resource
+// that, when set to "true", causes it to be reconciled in dry-run mode. Its
+// external resource is observed, but never created, updated, or deleted.
+const AnnotationKeyDryRun = "crossplane.io/dry-run"
+
+// AnnotationKeyReconciliationPaused is the key in the annotations map of a
+// managed resource that, when set to "true", pauses its reconciliation. Its
+// external resource is neither observed nor changed until the annotation is
+// removed.
+const AnnotationKeyReconciliationPaused = "crossplane.io/paused"
+
+// AnnotationKeyLastExternalName is the key in the annotations map of a managed
+// resource for the external name it had when its external resource was last
+// known to exist. It is used to detect changes to the external name of a
+// managed resource whose external resource has already been created.
+const AnnotationKeyLastExternalName = "crossplane.io/last-external-name"
+
+// AnnotationKeyIdempotencyToken is the key in the annotations map of a managed
+// resource for a token that uniquely identifies the creation of its external
+// resource. Providers whose APIs support client tokens may pass it when they
+// create an external resource, so that retried creations are not duplicated.
+const AnnotationKeyIdempotencyToken = "crossplane.io/idempotency-token"
+
+// AnnotationKeyRetry is the key in the annotations map of a managed resource
+// that, when set to "true", causes a managed resource that exhausted its retry
+// budget to be reconciled again with a fresh budget. The annotation is removed
+// once it has been honored.
+const AnnotationKeyRetry = "crossplane.io/retry"
+
+// AnnotationKeyImpersonateServiceAccount is the key in the annotations map of
+// a resource for the name of a service account, in the resource's namespace,
+// that should be impersonated when reading and writing on behalf of the
+// resource.
+const AnnotationKeyImpersonateServiceAccount = "crossplane.io/impersonate-service-account"
+
 // Supported resources with all of these annotations will be fully or partially
 // propagated to the named resource of the same kind, assuming it exists and
 // consents to propagation.
@@ -50,6 +86,23 @@ const (
 	AnnotationKeyPropagateFromName      = "from.propagate.crossplane.io/name"
 )
 
+// IsDryRun returns true if the supplied object should be reconciled in dry-run
+// mode.
+func IsDryRun(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyDryRun] == "true"
+}
+
+// IsPaused returns true if the supplied object's reconciliation is paused.
+func IsPaused(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyReconciliationPaused] == "true"
+}
+
+// ShouldRetry returns true if the supplied object should be reconciled again
+// even if it exhausted its retry budget.
+func ShouldRetry(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKeyRetry] == "true"
+}
+
 // ReferenceTo returns an object reference to the supplied object, presumed to
 // be of the supplied group, version, and kind.
 func ReferenceTo(o metav1.Object, of schema.GroupVersionKind) *corev1.ObjectReference {
@@ -233,6 +286,42 @@ func SetExternalName(o metav1.Object, name string) {
 	AddAnnotations(o, map[string]string{AnnotationKeyExternalName: name})
 }
 
+// GetLastExternalName returns the last external name annotation value on the
+// resource.
+func GetLastExternalName(o metav1.Object) string {
+	return o.GetAnnotations()[AnnotationKeyLastExternalName]
+}
+
+// SetLastExternalName sets the last external name annotation of the resource.
+func SetLastExternalName(o metav1.Object, name string) {
+	AddAnnotations(o, map[string]string{AnnotationKeyLastExternalName: name})
+}
+
+// GetIdempotencyToken returns the idempotency token annotation value on the
+// resource.
+func GetIdempotencyToken(o metav1.Object) string {
+	return o.GetAnnotations()[AnnotationKeyIdempotencyToken]
+}
+
+// SetIdempotencyToken sets the idempotency token annotation of the resource.
+func SetIdempotencyToken(o metav1.Object, token string) {
+	AddAnnotations(o, map[string]string{AnnotationKeyIdempotencyToken: token})
+}
+
+// GetImpersonatedServiceAccount returns the impersonated service account
+// annotation value on the resource.
+func GetImpersonatedServiceAccount(o metav1.Object) string {
+	return o.GetAnnotations()[AnnotationKeyImpersonateServiceAccount]
+}
+
+// ExternalNameChanged returns true if the external name of the supplied
+// resource differs from the last external name recorded for it. It returns
+// false if no external name has been recorded.
+func ExternalNameChanged(o metav1.Object) bool {
+	last := GetLastExternalName(o)
+	return last != "" && last != GetExternalName(o)
+}
+
 // AllowPropagation from one object to another by adding consenting annotations
 // to both.
 func AllowPropagation(from, to metav1.Object) {
@@ -22,12 +22,12 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -859,6 +859,41 @@ func TestSetExternalName(t *testing.T) {
 	}
 }
 
+func TestGetIdempotencyToken(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want string
+	}{
+		"TokenExists": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyIdempotencyToken: "cool-token"}}},
+			want: "cool-token",
+		},
+		"NoToken": {
+			o:    &corev1.Pod{},
+			want: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetIdempotencyToken(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GetIdempotencyToken(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetIdempotencyToken(t *testing.T) {
+	o := &corev1.Pod{}
+	want := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyIdempotencyToken: "cool-token"}}}
+
+	SetIdempotencyToken(o, "cool-token")
+	if diff := cmp.Diff(want, o); diff != "" {
+		t.Errorf("SetIdempotencyToken(...): -want, +got:\n%s", diff)
+	}
+}
+
 func TestAllowPropagation(t *testing.T) {
 	fromns := "from-namespace"
 	from := "from-name"
@@ -999,3 +1034,150 @@ func TestAllowsPropagationTo(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDryRun(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want bool
+	}{
+		"DryRun": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyDryRun: "true"}}},
+			want: true,
+		},
+		"NotDryRun": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyDryRun: "false"}}},
+			want: false,
+		},
+		"NoAnnotation": {
+			o:    &corev1.Pod{},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsDryRun(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsDryRun(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsPaused(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want bool
+	}{
+		"Paused": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyReconciliationPaused: "true"}}},
+			want: true,
+		},
+		"NotPaused": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyReconciliationPaused: "false"}}},
+			want: false,
+		},
+		"NoAnnotation": {
+			o:    &corev1.Pod{},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsPaused(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsPaused(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want bool
+	}{
+		"Retry": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyRetry: "true"}}},
+			want: true,
+		},
+		"DoNotRetry": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyRetry: "false"}}},
+			want: false,
+		},
+		"NoAnnotation": {
+			o:    &corev1.Pod{},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ShouldRetry(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ShouldRetry(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExternalNameChanged(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want bool
+	}{
+		"Changed": {
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationKeyExternalName:     "new",
+				AnnotationKeyLastExternalName: "old",
+			}}},
+			want: true,
+		},
+		"Unchanged": {
+			o: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				AnnotationKeyExternalName:     "old",
+				AnnotationKeyLastExternalName: "old",
+			}}},
+			want: false,
+		},
+		"NotRecorded": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyExternalName: "new"}}},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ExternalNameChanged(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ExternalNameChanged(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetImpersonatedServiceAccount(t *testing.T) {
+	cases := map[string]struct {
+		o    metav1.Object
+		want string
+	}{
+		"ServiceAccountExists": {
+			o:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyImpersonateServiceAccount: "cool-sa"}}},
+			want: "cool-sa",
+		},
+		"NoServiceAccount": {
+			o:    &corev1.Pod{},
+			want: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetImpersonatedServiceAccount(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GetImpersonatedServiceAccount(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openapi validates unstructured values against OpenAPI v3 schemas,
+// such as those of CustomResourceDefinitions.
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// A Schema is an unstructured OpenAPI v3 schema, for example the
+// openAPIV3Schema of an unstructured CustomResourceDefinition.
+type Schema map[string]interface{}
+
+// Property returns the schema of the property at the supplied path of object
+// properties, or nil if the schema does not define it.
+func (s Schema) Property(path ...string) Schema {
+	for _, name := range path {
+		props, _ := s["properties"].(map[string]interface{})
+		p, ok := props[name].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		s = p
+	}
+	return s
+}
+
+// Validate the supplied unstructured value, which is found at the supplied
+// path, against the supplied schema. Validate supports the subset of OpenAPI v3
+// that is commonly used by structural CustomResourceDefinition schemas; type,
+// properties, required, additionalProperties, items, enum, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern, minItems,
+// maxItems, and x-kubernetes-int-or-string. Other keywords are ignored. Null
+// values are treated as though they were omitted.
+func Validate(s Schema, value interface{}, path *field.Path) field.ErrorList {
+	if s == nil || value == nil {
+		return nil
+	}
+	if err := validateType(s, value, path); err != nil {
+		return field.ErrorList{err}
+	}
+
+	errs := validateEnum(s, value, path)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		errs = append(errs, validateObject(s, v, path)...)
+	case []interface{}:
+		errs = append(errs, validateArray(s, v, path)...)
+	case string:
+		errs = append(errs, validateString(s, v, path)...)
+	default:
+		if n, ok := number(v); ok {
+			errs = append(errs, validateNumber(s, n, path)...)
+		}
+	}
+	return errs
+}
+
+func validateType(s Schema, value interface{}, path *field.Path) *field.Error {
+	t, _ := s["type"].(string)
+	if ios, _ := s["x-kubernetes-int-or-string"].(bool); ios {
+		if _, ok := value.(string); ok {
+			return nil
+		}
+		t = "integer"
+	}
+
+	if !isType(t, value) {
+		return field.Invalid(path, value, fmt.Sprintf("must be of type %s", t))
+	}
+	return nil
+}
+
+// isType returns true if the supplied unstructured value is of the supplied
+// OpenAPI type. Any value is of an unspecified type.
+func isType(t string, value interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		n, ok := number(value)
+		return ok && n == float64(int64(n))
+	case "number":
+		_, ok := number(value)
+		return ok
+	}
+	return true
+}
+
+func validateEnum(s Schema, value interface{}, path *field.Path) field.ErrorList {
+	enum, ok := s["enum"].([]interface{})
+	if !ok || len(enum) == 0 {
+		return nil
+	}
+	valid := make([]string, len(enum))
+	for i, e := range enum {
+		if equal(e, value) {
+			return nil
+		}
+		valid[i] = fmt.Sprintf("%v", e)
+	}
+	return field.ErrorList{field.NotSupported(path, value, valid)}
+}
+
+func validateObject(s Schema, o map[string]interface{}, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	required, _ := s["required"].([]interface{})
+	for _, r := range required {
+		name, _ := r.(string)
+		if v, ok := o[name]; !ok || v == nil {
+			errs = append(errs, field.Required(path.Child(name), ""))
+		}
+	}
+
+	// We sort keys so that errors are returned in a stable order.
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	additional, _ := s["additionalProperties"].(map[string]interface{})
+	for _, k := range keys {
+		ps := s.Property(k)
+		if ps == nil {
+			ps = additional
+		}
+		errs = append(errs, Validate(ps, o[k], path.Child(k))...)
+	}
+	return errs
+}
+
+func validateArray(s Schema, a []interface{}, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if min, ok := integer(s["minItems"]); ok && len(a) < min {
+		errs = append(errs, field.Invalid(path, len(a), fmt.Sprintf("must have at least %d items", min)))
+	}
+	if max, ok := integer(s["maxItems"]); ok && len(a) > max {
+		errs = append(errs, field.TooMany(path, len(a), max))
+	}
+	items, _ := s["items"].(map[string]interface{})
+	for i, v := range a {
+		errs = append(errs, Validate(items, v, path.Index(i))...)
+	}
+	return errs
+}
+
+func validateString(s Schema, str string, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	l := utf8.RuneCountInString(str)
+	if min, ok := integer(s["minLength"]); ok && l < min {
+		errs = append(errs, field.Invalid(path, str, fmt.Sprintf("must be at least %d characters long", min)))
+	}
+	if max, ok := integer(s["maxLength"]); ok && l > max {
+		errs = append(errs, field.TooLong(path, str, max))
+	}
+	if p, ok := s["pattern"].(string); ok {
+		// Patterns that don't compile can't be enforced; the API server would
+		// have rejected them anyway.
+		if re, err := regexp.Compile(p); err == nil && !re.MatchString(str) {
+			errs = append(errs, field.Invalid(path, str, fmt.Sprintf("must match pattern %q", p)))
+		}
+	}
+	return errs
+}
+
+func validateNumber(s Schema, n float64, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if min, ok := number(s["minimum"]); ok {
+		if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive && n <= min {
+			errs = append(errs, field.Invalid(path, n, fmt.Sprintf("must be greater than %v", min)))
+		} else if n < min {
+			errs = append(errs, field.Invalid(path, n, fmt.Sprintf("must be greater than or equal to %v", min)))
+		}
+	}
+	if max, ok := number(s["maximum"]); ok {
+		if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive && n >= max {
+			errs = append(errs, field.Invalid(path, n, fmt.Sprintf("must be less than %v", max)))
+		} else if n > max {
+			errs = append(errs, field.Invalid(path, n, fmt.Sprintf("must be less than or equal to %v", max)))
+		}
+	}
+	return errs
+}
+
+// number returns the supplied unstructured value as a float64, if it is a
+// number.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// integer returns the supplied unstructured value as an int, if it is a
+// number.
+func integer(v interface{}) (int, bool) {
+	n, ok := number(v)
+	return int(n), ok
+}
+
+// equal returns true if the supplied unstructured values are equal. Numbers
+// are equal if they have the same value, regardless of their type.
+func equal(a, b interface{}) bool {
+	an, aok := number(a)
+	bn, bok := number(b)
+	if aok && bok {
+		return an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
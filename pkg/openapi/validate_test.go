@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestProperty(t *testing.T) {
+	region := map[string]interface{}{"type": "string"}
+	s := Schema{"properties": map[string]interface{}{
+		"spec": map[string]interface{}{"properties": map[string]interface{}{
+			"forProvider": map[string]interface{}{"properties": map[string]interface{}{
+				"region": region,
+			}},
+		}},
+	}}
+
+	cases := map[string]struct {
+		reason string
+		path   []string
+		want   Schema
+	}{
+		"Exists": {
+			reason: "We should return the schema of a nested property.",
+			path:   []string{"spec", "forProvider", "region"},
+			want:   region,
+		},
+		"DoesNotExist": {
+			reason: "We should return nil if the property is not defined.",
+			path:   []string{"spec", "forProvider", "zone"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := s.Property(tc.path...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ns.Property(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := field.NewPath("spec", "forProvider")
+
+	s := Schema{
+		"type":     "object",
+		"required": []interface{}{"region", "size"},
+		"properties": map[string]interface{}{
+			"region": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"us-east-1", "us-west-2"},
+			},
+			"size": map[string]interface{}{
+				"type":             "integer",
+				"minimum":          int64(10),
+				"maximum":          int64(100),
+				"exclusiveMaximum": true,
+			},
+			"name": map[string]interface{}{
+				"type":      "string",
+				"minLength": int64(3),
+				"maxLength": int64(8),
+				"pattern":   "^[a-z]+$",
+			},
+			"ratio": map[string]interface{}{
+				"type":    "number",
+				"minimum": 0.5,
+			},
+			"port": map[string]interface{}{
+				"x-kubernetes-int-or-string": true,
+			},
+			"zones": map[string]interface{}{
+				"type":     "array",
+				"minItems": int64(1),
+				"maxItems": int64(2),
+				"items":    map[string]interface{}{"type": "string"},
+			},
+			"tags": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		value  interface{}
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A valid value should produce no errors.",
+			value: map[string]interface{}{
+				"region":  "us-east-1",
+				"size":    int64(20),
+				"name":    "cool",
+				"ratio":   float64(1),
+				"port":    "http",
+				"zones":   []interface{}{"a"},
+				"tags":    map[string]interface{}{"cool": "true"},
+				"unknown": "ignored",
+			},
+		},
+		"Null": {
+			reason: "A null value should be treated as though it were omitted.",
+			value:  nil,
+		},
+		"WrongType": {
+			reason: "A value of the wrong type should produce a single error.",
+			value:  "nope",
+			want:   field.ErrorList{field.Invalid(p, "nope", "must be of type object")},
+		},
+		"Invalid": {
+			reason: "Each violation of the schema should produce an error.",
+			value: map[string]interface{}{
+				"size":  int64(100),
+				"name":  "No",
+				"ratio": float64(0.1),
+				"port":  2.5,
+				"zones": []interface{}{"a", "b", int64(3)},
+				"tags":  map[string]interface{}{"cool": true},
+			},
+			want: field.ErrorList{
+				field.Required(p.Child("region"), ""),
+				field.Invalid(p.Child("name"), "No", "must be at least 3 characters long"),
+				field.Invalid(p.Child("name"), "No", `must match pattern "^[a-z]+$"`),
+				field.Invalid(p.Child("port"), 2.5, "must be of type integer"),
+				field.Invalid(p.Child("ratio"), 0.1, "must be greater than or equal to 0.5"),
+				field.Invalid(p.Child("size"), float64(100), "must be less than 100"),
+				field.Invalid(p.Child("tags", "cool"), true, "must be of type string"),
+				field.TooMany(p.Child("zones"), 3, 2),
+				field.Invalid(p.Child("zones").Index(2), int64(3), "must be of type string"),
+			},
+		},
+		"NotSupported": {
+			reason: "A value that is not in the enum should produce an error.",
+			value:  map[string]interface{}{"region": "eu-west-1", "size": int64(10)},
+			want: field.ErrorList{
+				field.NotSupported(p.Child("region"), "eu-west-1", []string{"us-east-1", "us-west-2"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Validate(s, tc.value, p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
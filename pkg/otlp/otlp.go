@@ -0,0 +1,432 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otlp pushes the runtime's Prometheus metrics to an OpenTelemetry
+// collector using the OTLP/HTTP protocol, for platforms that do not scrape
+// Prometheus metrics.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// Defaults.
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultTimeout  = 10 * time.Second
+)
+
+// ScopeName is the name of the instrumentation scope of exported metrics.
+const ScopeName = "github.com/crossplane/crossplane-runtime"
+
+// Error strings.
+const (
+	errGather     = "cannot gather metrics"
+	errEncode     = "cannot encode metrics"
+	errNewRequest = "cannot create OTLP request"
+	errPush       = "cannot push metrics"
+
+	errFmtStatus = "OTLP endpoint returned status %d: %s"
+)
+
+// OTLP aggregation temporality. Prometheus metrics are always cumulative.
+const aggregationTemporalityCumulative = 2
+
+// An Exporter periodically pushes the metrics gathered from a Prometheus
+// gatherer to an OTLP/HTTP endpoint, using the JSON encoding of OTLP.
+type Exporter struct {
+	endpoint string
+	gatherer prometheus.Gatherer
+	client   *http.Client
+	headers  map[string]string
+	resource map[string]string
+	interval time.Duration
+	log      logging.Logger
+
+	start time.Time
+	now   func() time.Time
+}
+
+// An ExporterOption configures an Exporter.
+type ExporterOption func(*Exporter)
+
+// WithGatherer specifies the Prometheus gatherer from which metrics should be
+// exported. controller-runtime's metrics.Registry is used by default.
+func WithGatherer(g prometheus.Gatherer) ExporterOption {
+	return func(e *Exporter) {
+		e.gatherer = g
+	}
+}
+
+// WithHTTPClient specifies the HTTP client used to push metrics.
+func WithHTTPClient(c *http.Client) ExporterOption {
+	return func(e *Exporter) {
+		e.client = c
+	}
+}
+
+// WithHeaders specifies HTTP headers, for example authorization headers, that
+// should be sent with each push.
+func WithHeaders(h map[string]string) ExporterOption {
+	return func(e *Exporter) {
+		e.headers = h
+	}
+}
+
+// WithResourceAttributes specifies OpenTelemetry resource attributes, for
+// example service.name, that identify the source of exported metrics.
+func WithResourceAttributes(a map[string]string) ExporterOption {
+	return func(e *Exporter) {
+		e.resource = a
+	}
+}
+
+// WithInterval specifies how frequently the Exporter should push metrics.
+func WithInterval(d time.Duration) ExporterOption {
+	return func(e *Exporter) {
+		e.interval = d
+	}
+}
+
+// WithLogger specifies how the Exporter should log messages.
+func WithLogger(l logging.Logger) ExporterOption {
+	return func(e *Exporter) {
+		e.log = l
+	}
+}
+
+// NewExporter returns an Exporter that pushes metrics to the supplied OTLP/HTTP
+// metrics endpoint, for example http://otel-collector:4318/v1/metrics.
+func NewExporter(endpoint string, o ...ExporterOption) *Exporter {
+	e := &Exporter{
+		endpoint: endpoint,
+		gatherer: metrics.Registry,
+		client:   &http.Client{Timeout: DefaultTimeout},
+		interval: DefaultInterval,
+		log:      logging.NewNopLogger(),
+		start:    time.Now(),
+		now:      time.Now,
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Export gathers metrics and pushes them to the Exporter's endpoint once.
+func (e *Exporter) Export(ctx context.Context) error {
+	mfs, err := e.gatherer.Gather()
+	if err != nil {
+		return errors.Wrap(err, errGather)
+	}
+
+	body, err := json.Marshal(e.request(mfs))
+	if err != nil {
+		return errors.Wrap(err, errEncode)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errPush)
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+
+	if rsp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, 512))
+		return errors.Errorf(errFmtStatus, rsp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// Start pushing metrics at the Exporter's interval until the supplied channel
+// is closed. Start satisfies controller-runtime's manager.Runnable interface,
+// so an Exporter may be added to a manager.
+func (e *Exporter) Start(stop <-chan struct{}) error {
+	t := time.NewTicker(e.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			// Push one last time so that we don't lose the metrics recorded
+			// since our last push.
+			e.export()
+			return nil
+		case <-t.C:
+			e.export()
+		}
+	}
+}
+
+// NeedLeaderElection returns false, because every replica of a controller
+// manager records its own metrics. NeedLeaderElection satisfies
+// controller-runtime's manager.LeaderElectionRunnable interface.
+func (e *Exporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *Exporter) export() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	if err := e.Export(ctx); err != nil {
+		e.log.Debug("Cannot export metrics", "error", err)
+	}
+}
+
+// The below types are the subset of the OTLP JSON encoding that we produce.
+// See https://github.com/open-telemetry/opentelemetry-proto. Per the OTLP JSON
+// encoding 64 bit integers are encoded as decimal strings.
+
+type exportRequest struct {
+	ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+}
+
+type resourceMetrics struct {
+	Resource     otlpResource   `json:"resource"`
+	ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []attribute `json:"attributes,omitempty"`
+}
+
+type scopeMetrics struct {
+	Scope   scope    `json:"scope"`
+	Metrics []metric `json:"metrics"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type attribute struct {
+	Key   string         `json:"key"`
+	Value attributeValue `json:"value"`
+}
+
+type attributeValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type metric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Gauge       *gauge     `json:"gauge,omitempty"`
+	Sum         *sum       `json:"sum,omitempty"`
+	Histogram   *histogram `json:"histogram,omitempty"`
+	Summary     *summary   `json:"summary,omitempty"`
+}
+
+type gauge struct {
+	DataPoints []numberDataPoint `json:"dataPoints"`
+}
+
+type sum struct {
+	DataPoints             []numberDataPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type histogram struct {
+	DataPoints             []histogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type summary struct {
+	DataPoints []summaryDataPoint `json:"dataPoints"`
+}
+
+type numberDataPoint struct {
+	Attributes        []attribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	TimeUnixNano      string      `json:"timeUnixNano"`
+	AsDouble          float64     `json:"asDouble"`
+}
+
+type histogramDataPoint struct {
+	Attributes        []attribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	TimeUnixNano      string      `json:"timeUnixNano"`
+	Count             string      `json:"count"`
+	Sum               float64     `json:"sum"`
+	BucketCounts      []string    `json:"bucketCounts"`
+	ExplicitBounds    []float64   `json:"explicitBounds"`
+}
+
+type summaryDataPoint struct {
+	Attributes        []attribute     `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	QuantileValues    []quantileValue `json:"quantileValues"`
+}
+
+type quantileValue struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+func (e *Exporter) request(mfs []*dto.MetricFamily) exportRequest {
+	sm := scopeMetrics{Scope: scope{Name: ScopeName}, Metrics: make([]metric, 0, len(mfs))}
+	start, now := unixNano(e.start), unixNano(e.now())
+	for _, mf := range mfs {
+		sm.Metrics = append(sm.Metrics, convert(mf, start, now))
+	}
+	return exportRequest{ResourceMetrics: []resourceMetrics{{
+		Resource:     otlpResource{Attributes: attributes(e.resource)},
+		ScopeMetrics: []scopeMetrics{sm},
+	}}}
+}
+
+// convert the supplied Prometheus metric family to an OTLP metric. Counters
+// become monotonic sums, and untyped metrics become gauges.
+func convert(mf *dto.MetricFamily, start, now string) metric {
+	m := metric{Name: mf.GetName(), Description: mf.GetHelp()}
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		m.Sum = &sum{AggregationTemporality: aggregationTemporalityCumulative, IsMonotonic: true}
+		for _, pm := range mf.GetMetric() {
+			m.Sum.DataPoints = append(m.Sum.DataPoints, numberPoint(pm, pm.GetCounter().GetValue(), start, now))
+		}
+	case dto.MetricType_HISTOGRAM:
+		m.Histogram = &histogram{AggregationTemporality: aggregationTemporalityCumulative}
+		for _, pm := range mf.GetMetric() {
+			m.Histogram.DataPoints = append(m.Histogram.DataPoints, histogramPoint(pm, start, now))
+		}
+	case dto.MetricType_SUMMARY:
+		m.Summary = &summary{}
+		for _, pm := range mf.GetMetric() {
+			m.Summary.DataPoints = append(m.Summary.DataPoints, summaryPoint(pm, start, now))
+		}
+	case dto.MetricType_UNTYPED:
+		m.Gauge = &gauge{}
+		for _, pm := range mf.GetMetric() {
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, numberPoint(pm, pm.GetUntyped().GetValue(), start, now))
+		}
+	default:
+		m.Gauge = &gauge{}
+		for _, pm := range mf.GetMetric() {
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, numberPoint(pm, pm.GetGauge().GetValue(), start, now))
+		}
+	}
+	return m
+}
+
+func numberPoint(pm *dto.Metric, v float64, start, now string) numberDataPoint {
+	return numberDataPoint{
+		Attributes:        labels(pm.GetLabel()),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		AsDouble:          v,
+	}
+}
+
+func summaryPoint(pm *dto.Metric, start, now string) summaryDataPoint {
+	s := pm.GetSummary()
+	dp := summaryDataPoint{
+		Attributes:        labels(pm.GetLabel()),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Count:             strconv.FormatUint(s.GetSampleCount(), 10),
+		Sum:               s.GetSampleSum(),
+		QuantileValues:    make([]quantileValue, 0, len(s.GetQuantile())),
+	}
+	for _, q := range s.GetQuantile() {
+		dp.QuantileValues = append(dp.QuantileValues, quantileValue{Quantile: q.GetQuantile(), Value: q.GetValue()})
+	}
+	return dp
+}
+
+// histogramPoint converts the supplied Prometheus histogram, whose buckets are
+// cumulative, to an OTLP histogram data point, whose buckets are not. OTLP
+// histograms have an implicit final bucket with no upper bound.
+func histogramPoint(pm *dto.Metric, start, now string) histogramDataPoint {
+	h := pm.GetHistogram()
+	dp := histogramDataPoint{
+		Attributes:        labels(pm.GetLabel()),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      now,
+		Count:             strconv.FormatUint(h.GetSampleCount(), 10),
+		Sum:               h.GetSampleSum(),
+		BucketCounts:      make([]string, 0, len(h.GetBucket())+1),
+		ExplicitBounds:    make([]float64, 0, len(h.GetBucket())),
+	}
+	prev := uint64(0)
+	for _, b := range h.GetBucket() {
+		dp.ExplicitBounds = append(dp.ExplicitBounds, b.GetUpperBound())
+		dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(b.GetCumulativeCount()-prev, 10))
+		prev = b.GetCumulativeCount()
+	}
+	dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(h.GetSampleCount()-prev, 10))
+	return dp
+}
+
+func labels(lps []*dto.LabelPair) []attribute {
+	if len(lps) == 0 {
+		return nil
+	}
+	a := make([]attribute, 0, len(lps))
+	for _, lp := range lps {
+		a = append(a, attribute{Key: lp.GetName(), Value: attributeValue{StringValue: lp.GetValue()}})
+	}
+	return a
+}
+
+func attributes(m map[string]string) []attribute {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	a := make([]attribute, 0, len(keys))
+	for _, k := range keys {
+		a = append(a, attribute{Key: k, Value: attributeValue{StringValue: m[k]}})
+	}
+	return a
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
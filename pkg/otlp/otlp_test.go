@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func str(s string) *string                 { return &s }
+func f64(f float64) *float64               { return &f }
+func u64(u uint64) *uint64                 { return &u }
+func typ(t dto.MetricType) *dto.MetricType { return &t }
+
+func TestExport(t *testing.T) {
+	errBoom := errors.New("boom")
+	start := time.Unix(0, 1)
+	now := time.Unix(0, 2)
+
+	mfs := []*dto.MetricFamily{
+		{
+			Name: str("reconciles_total"),
+			Help: str("Reconciles."),
+			Type: typ(dto.MetricType_COUNTER),
+			Metric: []*dto.Metric{{
+				Label:   []*dto.LabelPair{{Name: str("result"), Value: str("success")}},
+				Counter: &dto.Counter{Value: f64(3)},
+			}},
+		},
+		{
+			Name:   str("exists"),
+			Type:   typ(dto.MetricType_GAUGE),
+			Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: f64(2)}}},
+		},
+		{
+			Name: str("duration_seconds"),
+			Type: typ(dto.MetricType_HISTOGRAM),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: u64(5),
+					SampleSum:   f64(7.5),
+					Bucket: []*dto.Bucket{
+						{UpperBound: f64(1), CumulativeCount: u64(1)},
+						{UpperBound: f64(2), CumulativeCount: u64(3)},
+					},
+				},
+			}},
+		},
+		{
+			Name: str("latency_seconds"),
+			Type: typ(dto.MetricType_SUMMARY),
+			Metric: []*dto.Metric{{
+				Summary: &dto.Summary{
+					SampleCount: u64(2),
+					SampleSum:   f64(1),
+					Quantile:    []*dto.Quantile{{Quantile: f64(0.5), Value: f64(0.5)}},
+				},
+			}},
+		},
+	}
+
+	type args struct {
+		status   int
+		gatherer prometheus.Gatherer
+		o        []ExporterOption
+	}
+	type want struct {
+		err     error
+		req     *exportRequest
+		headers http.Header
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GatherError": {
+			reason: "Errors gathering metrics should be returned.",
+			args: args{
+				gatherer: prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return nil, errBoom }),
+			},
+			want: want{err: errors.Wrap(errBoom, errGather)},
+		},
+		"StatusError": {
+			reason: "Unsuccessful HTTP responses should be returned as errors.",
+			args: args{
+				status:   http.StatusBadRequest,
+				gatherer: prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return nil, nil }),
+			},
+			want: want{
+				err: errors.Errorf(errFmtStatus, http.StatusBadRequest, []byte("nope")),
+				req: &exportRequest{ResourceMetrics: []resourceMetrics{{
+					ScopeMetrics: []scopeMetrics{{Scope: scope{Name: ScopeName}, Metrics: []metric{}}},
+				}}},
+			},
+		},
+		"Success": {
+			reason: "Gathered metrics should be converted to OTLP and pushed.",
+			args: args{
+				status:   http.StatusOK,
+				gatherer: prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return mfs, nil }),
+				o: []ExporterOption{
+					WithHeaders(map[string]string{"Authorization": "Bearer cool"}),
+					WithResourceAttributes(map[string]string{"service.name": "provider-cool", "k8s.pod.name": "cool-pod"}),
+				},
+			},
+			want: want{
+				headers: http.Header{"Authorization": []string{"Bearer cool"}},
+				req: &exportRequest{ResourceMetrics: []resourceMetrics{{
+					Resource: otlpResource{Attributes: []attribute{
+						{Key: "k8s.pod.name", Value: attributeValue{StringValue: "cool-pod"}},
+						{Key: "service.name", Value: attributeValue{StringValue: "provider-cool"}},
+					}},
+					ScopeMetrics: []scopeMetrics{{Scope: scope{Name: ScopeName}, Metrics: []metric{
+						{
+							Name:        "reconciles_total",
+							Description: "Reconciles.",
+							Sum: &sum{
+								AggregationTemporality: aggregationTemporalityCumulative,
+								IsMonotonic:            true,
+								DataPoints: []numberDataPoint{{
+									Attributes:        []attribute{{Key: "result", Value: attributeValue{StringValue: "success"}}},
+									StartTimeUnixNano: "1",
+									TimeUnixNano:      "2",
+									AsDouble:          3,
+								}},
+							},
+						},
+						{
+							Name:  "exists",
+							Gauge: &gauge{DataPoints: []numberDataPoint{{StartTimeUnixNano: "1", TimeUnixNano: "2", AsDouble: 2}}},
+						},
+						{
+							Name: "duration_seconds",
+							Histogram: &histogram{
+								AggregationTemporality: aggregationTemporalityCumulative,
+								DataPoints: []histogramDataPoint{{
+									StartTimeUnixNano: "1",
+									TimeUnixNano:      "2",
+									Count:             "5",
+									Sum:               7.5,
+									BucketCounts:      []string{"1", "2", "2"},
+									ExplicitBounds:    []float64{1, 2},
+								}},
+							},
+						},
+						{
+							Name: "latency_seconds",
+							Summary: &summary{DataPoints: []summaryDataPoint{{
+								StartTimeUnixNano: "1",
+								TimeUnixNano:      "2",
+								Count:             "2",
+								Sum:               1,
+								QuantileValues:    []quantileValue{{Quantile: 0.5, Value: 0.5}},
+							}}},
+						},
+					}}},
+				}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *exportRequest
+			var headers http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("\n%s\nExport(...): unexpected Content-Type %q", tc.reason, ct)
+				}
+				got = &exportRequest{}
+				if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+					t.Errorf("\n%s\nExport(...): cannot decode request: %s", tc.reason, err)
+				}
+				if a := r.Header.Get("Authorization"); a != "" {
+					headers = http.Header{"Authorization": []string{a}}
+				}
+				w.WriteHeader(tc.args.status)
+				if tc.args.status != http.StatusOK {
+					_, _ = w.Write([]byte("nope\n"))
+				}
+			}))
+			defer srv.Close()
+
+			e := NewExporter(srv.URL, append(tc.args.o, WithGatherer(tc.args.gatherer))...)
+			e.start = start
+			e.now = func() time.Time { return now }
+
+			err := e.Export(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExport(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.req, got); diff != "" {
+				t.Errorf("\n%s\nExport(...): -want request, +got request:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.headers, headers); diff != "" {
+				t.Errorf("\n%s\nExport(...): -want headers, +got headers:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
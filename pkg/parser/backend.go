@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errNilFS     = "filesystem backend requires a filesystem"
+	errWalkFS    = "cannot walk filesystem"
+	errFmtOpen   = "cannot open file %q"
+	errFmtRead   = "cannot read file %q"
+	errFmtFilter = "cannot filter file %q"
+)
+
+// A Backend provides a YAML stream for a Parser to parse.
+type Backend interface {
+	Init(ctx context.Context) (io.ReadCloser, error)
+}
+
+// An FS is a read-only, hierarchical filesystem from which YAML streams may be
+// read.
+type FS interface {
+	// Open the named file for reading.
+	Open(name string) (io.ReadCloser, error)
+
+	// Walk the file tree rooted at root, calling fn for each file or
+	// directory, including root, in lexical order.
+	Walk(root string, fn filepath.WalkFunc) error
+}
+
+// An OSFS is an FS backed by the host's filesystem.
+type OSFS struct{}
+
+// Open the named file for reading.
+func (OSFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Clean(name))
+}
+
+// Walk the file tree rooted at root.
+func (OSFS) Walk(root string, fn filepath.WalkFunc) error {
+	return filepath.Walk(root, fn)
+}
+
+// A FilterFn determines whether the file at the supplied path should be
+// skipped when reading a YAML stream from an FS. Returning filepath.SkipDir
+// for a directory skips the directory and its contents.
+type FilterFn func(path string, info os.FileInfo) (skip bool, err error)
+
+// SkipDirs skips directories. Their contents are still read.
+func SkipDirs() FilterFn {
+	return func(_ string, info os.FileInfo) (bool, error) {
+		return info.IsDir(), nil
+	}
+}
+
+// SkipNotYAML skips files that do not have a .yaml or .yml extension.
+func SkipNotYAML() FilterFn {
+	return func(path string, info os.FileInfo) (bool, error) {
+		if info.IsDir() {
+			return false, nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		return ext != ".yaml" && ext != ".yml", nil
+	}
+}
+
+// SkipPath skips files and directories whose names match the supplied shell
+// file name pattern, per filepath.Match. Matching directories are skipped
+// along with their contents.
+func SkipPath(pattern string) FilterFn {
+	return func(path string, info os.FileInfo) (bool, error) {
+		match, err := filepath.Match(pattern, info.Name())
+		if err != nil || !match {
+			return false, err
+		}
+		if info.IsDir() {
+			return true, filepath.SkipDir
+		}
+		return true, nil
+	}
+}
+
+// An FSBackend reads a YAML stream from the files in an FS.
+type FSBackend struct {
+	fs      FS
+	dir     string
+	filters []FilterFn
+}
+
+// An FSBackendOption configures an FSBackend.
+type FSBackendOption func(*FSBackend)
+
+// FSDir sets the directory of the FS from which an FSBackend will read. The
+// root directory (".") is read by default.
+func FSDir(dir string) FSBackendOption {
+	return func(b *FSBackend) {
+		b.dir = dir
+	}
+}
+
+// FSFilters adds the supplied filters to an FSBackend. A file is skipped if
+// any filter skips it.
+func FSFilters(fns ...FilterFn) FSBackendOption {
+	return func(b *FSBackend) {
+		b.filters = append(b.filters, fns...)
+	}
+}
+
+// NewFSBackend returns a Backend that reads a YAML stream from the files in the
+// supplied FS. Directories are always skipped.
+func NewFSBackend(fs FS, o ...FSBackendOption) *FSBackend {
+	b := &FSBackend{fs: fs, dir: ".", filters: []FilterFn{SkipDirs()}}
+	for _, fn := range o {
+		fn(b)
+	}
+	return b
+}
+
+// Init returns a YAML stream consisting of the content of each file in the
+// backend's directory that was not filtered, in lexical order. Each file is
+// treated as one or more distinct YAML documents.
+func (b *FSBackend) Init(_ context.Context) (io.ReadCloser, error) {
+	if b.fs == nil {
+		return nil, errors.New(errNilFS)
+	}
+
+	buf := &bytes.Buffer{}
+	err := b.fs.Walk(b.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		skip := false
+		for _, filter := range b.filters {
+			s, err := filter(path, info)
+			if err == filepath.SkipDir {
+				return err
+			}
+			if err != nil {
+				return errors.Wrapf(err, errFmtFilter, path)
+			}
+			skip = skip || s
+		}
+		if skip {
+			return nil
+		}
+		return b.read(buf, path)
+	})
+	return ioutil.NopCloser(buf), errors.Wrap(err, errWalkFS)
+}
+
+func (b *FSBackend) read(buf *bytes.Buffer, path string) error {
+	f, err := b.fs.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, errFmtOpen, path)
+	}
+	defer f.Close() // nolint:errcheck
+
+	// Separate each file from the one before it so that the last document
+	// of one file is never merged with the first document of the next.
+	if buf.Len() > 0 {
+		buf.WriteString("\n---\n")
+	}
+	_, err = io.Copy(buf, f)
+	return errors.Wrapf(err, errFmtRead, path)
+}
+
+// An EchoBackend returns the YAML stream it was created with.
+type EchoBackend struct {
+	stream string
+}
+
+// NewEchoBackend returns a Backend that returns the supplied YAML stream.
+func NewEchoBackend(stream string) *EchoBackend {
+	return &EchoBackend{stream: stream}
+}
+
+// Init returns the backend's YAML stream.
+func (b *EchoBackend) Init(_ context.Context) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(b.stream)), nil
+}
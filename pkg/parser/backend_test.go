@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ Backend = &FSBackend{}
+	_ Backend = &EchoBackend{}
+	_ FS      = OSFS{}
+)
+
+type MockFS struct {
+	MockOpen func(name string) (io.ReadCloser, error)
+	MockWalk func(root string, fn filepath.WalkFunc) error
+}
+
+func (m *MockFS) Open(name string) (io.ReadCloser, error) { return m.MockOpen(name) }
+
+func (m *MockFS) Walk(root string, fn filepath.WalkFunc) error { return m.MockWalk(root, fn) }
+
+func TestFSBackend(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	dir, err := ioutil.TempDir("", "parser")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(...): %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.yaml":          "a: 1",
+		"b.txt":           "b: 2",
+		"c/c.yml":         "c: 3",
+		"d/d.yaml":        "d: 4",
+		"e.yaml":          "e: 5\n---\nf: 6\n",
+		"d/nested/g.yaml": "g: 7",
+	}
+	for name, data := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatalf("os.MkdirAll(...): %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(data), 0600); err != nil {
+			t.Fatalf("ioutil.WriteFile(...): %v", err)
+		}
+	}
+
+	type want struct {
+		stream string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		b      *FSBackend
+		want   want
+	}{
+		"NilFS": {
+			reason: "An FSBackend without a filesystem should return an error.",
+			b:      NewFSBackend(nil),
+			want: want{
+				err: errors.New(errNilFS),
+			},
+		},
+		"AllFiles": {
+			reason: "Every file should be read, in lexical order, separated by a document separator.",
+			b:      NewFSBackend(OSFS{}, FSDir(dir)),
+			want: want{
+				stream: "a: 1\n---\nb: 2\n---\nc: 3\n---\nd: 4\n---\ng: 7\n---\ne: 5\n---\nf: 6\n",
+			},
+		},
+		"SkipNotYAMLAndPath": {
+			reason: "Filtered files and directories should not be read.",
+			b:      NewFSBackend(OSFS{}, FSDir(dir), FSFilters(SkipNotYAML(), SkipPath("d"))),
+			want: want{
+				stream: "a: 1\n---\nc: 3\n---\ne: 5\n---\nf: 6\n",
+			},
+		},
+		"FilterError": {
+			reason: "Errors returned by a filter should be returned.",
+			b: NewFSBackend(OSFS{}, FSDir(dir), FSFilters(func(_ string, _ os.FileInfo) (bool, error) {
+				return false, errBoom
+			})),
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errBoom, errFmtFilter, dir), errWalkFS),
+			},
+		},
+		"OpenError": {
+			reason: "Errors opening a file should be returned.",
+			b: NewFSBackend(&MockFS{
+				MockOpen: func(_ string) (io.ReadCloser, error) { return nil, errBoom },
+				MockWalk: func(root string, fn filepath.WalkFunc) error {
+					return fn("cool.yaml", fileInfo(t, filepath.Join(dir, "a.yaml")), nil)
+				},
+			}),
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errBoom, errFmtOpen, "cool.yaml"), errWalkFS),
+			},
+		},
+		"WalkError": {
+			reason: "Errors walking the filesystem should be returned.",
+			b: NewFSBackend(&MockFS{
+				MockWalk: func(root string, fn filepath.WalkFunc) error { return fn(root, nil, errBoom) },
+			}),
+			want: want{
+				err: errors.Wrap(errBoom, errWalkFS),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := tc.b.Init(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInit(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := ioutil.ReadAll(r)
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nInit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func fileInfo(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("os.Stat(...): %v", err)
+	}
+	return fi
+}
+
+func TestEchoBackend(t *testing.T) {
+	want := "a: 1\n---\nb: 2"
+	r, err := NewEchoBackend(want).Init(context.Background())
+	if err != nil {
+		t.Fatalf("Init(...): %v", err)
+	}
+	got, _ := ioutil.ReadAll(r)
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Init(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errFmtConvert         = "cannot convert document %d (%s)"
+	errFmtNotUnstructured = "%s is neither typed nor unstructured"
+)
+
+// An ObjectFilterFn returns true if the supplied object passes the filter.
+type ObjectFilterFn func(o runtime.Object) bool
+
+// IsGroupKind accepts objects of the supplied kinds, regardless of version.
+func IsGroupKind(gks ...schema.GroupKind) ObjectFilterFn {
+	allowed := make(map[schema.GroupKind]bool, len(gks))
+	for _, gk := range gks {
+		allowed[gk] = true
+	}
+	return func(o runtime.Object) bool {
+		return allowed[o.GetObjectKind().GroupVersionKind().GroupKind()]
+	}
+}
+
+// IsGVK accepts objects of the supplied kinds.
+func IsGVK(gvks ...schema.GroupVersionKind) ObjectFilterFn {
+	allowed := make(map[schema.GroupVersionKind]bool, len(gvks))
+	for _, gvk := range gvks {
+		allowed[gvk] = true
+	}
+	return func(o runtime.Object) bool {
+		return allowed[o.GetObjectKind().GroupVersionKind()]
+	}
+}
+
+// IsCRD accepts CustomResourceDefinitions of any version.
+func IsCRD() ObjectFilterFn {
+	return IsGroupKind(CustomResourceDefinitionGroupKind)
+}
+
+// IsManaged accepts managed resources. Objects that were parsed as unstructured
+// are accepted if the supplied ObjectCreater creates a managed resource for
+// their kind.
+func IsManaged(oc runtime.ObjectCreater) ObjectFilterFn {
+	return func(o runtime.Object) bool {
+		if _, ok := o.(resource.Managed); ok {
+			return true
+		}
+		if oc == nil {
+			return false
+		}
+		n, err := oc.New(o.GetObjectKind().GroupVersionKind())
+		if err != nil {
+			return false
+		}
+		_, ok := n.(resource.Managed)
+		return ok
+	}
+}
+
+// Not accepts objects that the supplied filter does not accept.
+func Not(fn ObjectFilterFn) ObjectFilterFn {
+	return func(o runtime.Object) bool {
+		return !fn(o)
+	}
+}
+
+// Partition the supplied objects into those that the supplied filter accepts,
+// and those that it does not. Objects retain their relative order.
+func Partition(objs []runtime.Object, fn ObjectFilterFn) (accepted, rejected []runtime.Object) {
+	accepted = make([]runtime.Object, 0)
+	rejected = make([]runtime.Object, 0)
+	for _, o := range objs {
+		if fn(o) {
+			accepted = append(accepted, o)
+			continue
+		}
+		rejected = append(rejected, o)
+	}
+	return accepted, rejected
+}
+
+// CRDs returns the v1beta1 CustomResourceDefinitions among the supplied
+// objects, converting any that were parsed as unstructured.
+func CRDs(objs []runtime.Object) ([]*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	isV1beta1CRD := IsGVK(apiextensionsv1beta1.SchemeGroupVersion.WithKind(CustomResourceDefinitionGroupKind.Kind))
+	crds := make([]*apiextensionsv1beta1.CustomResourceDefinition, 0)
+	for i, o := range objs {
+		if !isV1beta1CRD(o) {
+			continue
+		}
+		if crd, ok := o.(*apiextensionsv1beta1.CustomResourceDefinition); ok {
+			crds = append(crds, crd)
+			continue
+		}
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+		if err := fromUnstructured(o, crd); err != nil {
+			return nil, errors.Wrapf(err, errFmtConvert, i, describe(o))
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// ManagedResources returns the managed resources among the supplied objects.
+// Objects that were parsed as unstructured are converted to the managed
+// resource the supplied ObjectCreater creates for their kind, if any.
+func ManagedResources(oc runtime.ObjectCreater, objs []runtime.Object) ([]resource.Managed, error) {
+	isManaged := IsManaged(oc)
+	mgs := make([]resource.Managed, 0)
+	for i, o := range objs {
+		if !isManaged(o) {
+			continue
+		}
+		if mg, ok := o.(resource.Managed); ok {
+			mgs = append(mgs, mg)
+			continue
+		}
+		n, err := oc.New(o.GetObjectKind().GroupVersionKind())
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtConvert, i, describe(o))
+		}
+		if err := fromUnstructured(o, n); err != nil {
+			return nil, errors.Wrapf(err, errFmtConvert, i, describe(o))
+		}
+		mgs = append(mgs, n.(resource.Managed))
+	}
+	return mgs, nil
+}
+
+// fromUnstructured converts the supplied unstructured object into the supplied
+// typed object.
+func fromUnstructured(from, into runtime.Object) error {
+	u, ok := from.(runtime.Unstructured)
+	if !ok {
+		return errors.Errorf(errFmtNotUnstructured, from.GetObjectKind().GroupVersionKind())
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), into); err != nil {
+		return err
+	}
+	into.GetObjectKind().SetGroupVersionKind(from.GetObjectKind().GroupVersionKind())
+	return nil
+}
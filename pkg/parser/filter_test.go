@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestPartition(t *testing.T) {
+	crdGVK := apiextensionsv1beta1.SchemeGroupVersion.WithKind("CustomResourceDefinition")
+	crd := object(crdGVK, "cool.example.org")
+	cm := &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}}
+	mg := &fake.Managed{}
+	umg := object(fake.GVK(mg), "cool")
+
+	type want struct {
+		accepted []runtime.Object
+		rejected []runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		fn     ObjectFilterFn
+		want   want
+	}{
+		"IsCRD": {
+			reason: "CustomResourceDefinitions should be accepted.",
+			fn:     IsCRD(),
+			want: want{
+				accepted: []runtime.Object{crd},
+				rejected: []runtime.Object{cm, mg, umg},
+			},
+		},
+		"IsGVK": {
+			reason: "Objects of the supplied kinds should be accepted.",
+			fn:     IsGVK(corev1.SchemeGroupVersion.WithKind("ConfigMap")),
+			want: want{
+				accepted: []runtime.Object{cm},
+				rejected: []runtime.Object{crd, mg, umg},
+			},
+		},
+		"IsManaged": {
+			reason: "Typed managed resources, and unstructured objects of managed resource kinds known to the scheme, should be accepted.",
+			fn:     IsManaged(fake.SchemeWith(&fake.Managed{})),
+			want: want{
+				accepted: []runtime.Object{mg, umg},
+				rejected: []runtime.Object{crd, cm},
+			},
+		},
+		"NotIsManaged": {
+			reason: "Objects that are not managed resources should be accepted.",
+			fn:     Not(IsManaged(nil)),
+			want: want{
+				accepted: []runtime.Object{crd, cm, umg},
+				rejected: []runtime.Object{mg},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			accepted, rejected := Partition([]runtime.Object{crd, cm, mg, umg}, tc.fn)
+			if diff := cmp.Diff(tc.want.accepted, accepted); diff != "" {
+				t.Errorf("\n%s\nPartition(...): -want accepted, +got accepted:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rejected, rejected); diff != "" {
+				t.Errorf("\n%s\nPartition(...): -want rejected, +got rejected:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDs(t *testing.T) {
+	typed := &apiextensionsv1beta1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "a.example.org"},
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "b.example.org"},
+		"spec":       map[string]interface{}{"group": "example.org"},
+	}}
+	converted := &apiextensionsv1beta1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "b.example.org"},
+		Spec:       apiextensionsv1beta1.CustomResourceDefinitionSpec{Group: "example.org"},
+	}
+	v1 := object(CustomResourceDefinitionGroupKind.WithVersion("v1"), "c.example.org")
+
+	got, err := CRDs([]runtime.Object{typed, v1, u})
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("CRDs(...): -want error, +got error:\n%s", diff)
+	}
+	want := []*apiextensionsv1beta1.CustomResourceDefinition{typed, converted}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CRDs(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestManagedResources(t *testing.T) {
+	mg := &fake.Managed{}
+	umg := object(fake.GVK(mg), "")
+	cm := &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}}
+
+	got, err := ManagedResources(fake.SchemeWith(&fake.Managed{}), []runtime.Object{mg, cm, umg})
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("ManagedResources(...): -want error, +got error:\n%s", diff)
+	}
+	want := []resource.Managed{mg, &fake.Managed{}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ManagedResources(...): -want, +got:\n%s", diff)
+	}
+}
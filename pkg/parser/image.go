@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errNilFetcher = "image backend requires an image fetcher"
+	errFetchImage = "cannot fetch image"
+	errNoYAML     = "image contains no YAML files"
+
+	errFmtOpenLayer = "cannot open image layer %d"
+	errFmtReadLayer = "cannot read image layer %d"
+)
+
+// A Layer of an OCI image.
+type Layer interface {
+	// Uncompressed returns the layer's uncompressed tar archive.
+	Uncompressed() (io.ReadCloser, error)
+}
+
+// An ImageFetcher fetches the layers of an OCI image.
+type ImageFetcher interface {
+	// Fetch the layers of the image with the supplied reference, using
+	// credentials from the supplied keychain. Layers are returned in the
+	// order they are applied, base layer first.
+	Fetch(ctx context.Context, ref string, k Keychain) ([]Layer, error)
+}
+
+// An ImageBackend reads a YAML stream from a layer of an OCI image.
+type ImageBackend struct {
+	fetcher  ImageFetcher
+	ref      string
+	keychain Keychain
+	filters  []FilterFn
+}
+
+// An ImageBackendOption configures an ImageBackend.
+type ImageBackendOption func(*ImageBackend)
+
+// WithKeychain specifies the keychain from which an ImageBackend will obtain
+// registry credentials. Images are fetched anonymously by default.
+func WithKeychain(k Keychain) ImageBackendOption {
+	return func(b *ImageBackend) {
+		b.keychain = k
+	}
+}
+
+// ImageFilters adds the supplied filters to an ImageBackend. A file is skipped
+// if any filter skips it.
+func ImageFilters(fns ...FilterFn) ImageBackendOption {
+	return func(b *ImageBackend) {
+		b.filters = append(b.filters, fns...)
+	}
+}
+
+// NewImageBackend returns a Backend that reads a YAML stream from the files in
+// a layer of the OCI image with the supplied reference. Directories, whiteout
+// files, and files that are not YAML are always skipped.
+func NewImageBackend(f ImageFetcher, ref string, o ...ImageBackendOption) *ImageBackend {
+	b := &ImageBackend{
+		fetcher:  f,
+		ref:      ref,
+		keychain: Anonymous,
+		filters:  []FilterFn{SkipDirs(), SkipNotYAML(), SkipPath(".wh.*")},
+	}
+	for _, fn := range o {
+		fn(b)
+	}
+	return b
+}
+
+// Init returns a YAML stream consisting of the content of each file in the
+// topmost layer of the backend's image that contains a file that was not
+// filtered. Files are read in the order they appear in the layer, and each is
+// treated as one or more distinct YAML documents.
+func (b *ImageBackend) Init(ctx context.Context) (io.ReadCloser, error) {
+	if b.fetcher == nil {
+		return nil, errors.New(errNilFetcher)
+	}
+
+	layers, err := b.fetcher.Fetch(ctx, b.ref, b.keychain)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchImage)
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		buf, err := b.read(layers[i], i)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 0 {
+			return ioutil.NopCloser(buf), nil
+		}
+	}
+
+	return nil, errors.New(errNoYAML)
+}
+
+func (b *ImageBackend) read(l Layer, i int) (*bytes.Buffer, error) {
+	rc, err := l.Uncompressed()
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtOpenLayer, i)
+	}
+	defer rc.Close() // nolint:errcheck
+
+	buf := &bytes.Buffer{}
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			// Drain the layer so that it can be verified, if the
+			// layer supports verification.
+			if _, err := io.Copy(ioutil.Discard, rc); err != nil {
+				return nil, errors.Wrapf(err, errFmtReadLayer, i)
+			}
+			return buf, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadLayer, i)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		skip, err := b.skip(filepath.Clean(hdr.Name), hdr)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtFilter, hdr.Name)
+		}
+		if skip {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n---\n")
+		}
+		if _, err := io.Copy(buf, tr); err != nil {
+			return nil, errors.Wrapf(err, errFmtReadLayer, i)
+		}
+	}
+}
+
+func (b *ImageBackend) skip(path string, hdr *tar.Header) (bool, error) {
+	for _, filter := range b.filters {
+		skip, err := filter(path, hdr.FileInfo())
+		if err == filepath.SkipDir {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if skip {
+			return true, nil
+		}
+	}
+	return false, nil
+}
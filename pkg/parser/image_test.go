@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Backend = &ImageBackend{}
+
+type MockLayer struct {
+	MockUncompressed func() (io.ReadCloser, error)
+}
+
+func (l *MockLayer) Uncompressed() (io.ReadCloser, error) { return l.MockUncompressed() }
+
+type ImageFetcherFn func(ctx context.Context, ref string, k Keychain) ([]Layer, error)
+
+func (fn ImageFetcherFn) Fetch(ctx context.Context, ref string, k Keychain) ([]Layer, error) {
+	return fn(ctx, ref, k)
+}
+
+// archive returns a tar archive containing the supplied files, in order.
+func archive(t *testing.T, files ...string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for i := 0; i < len(files); i += 2 {
+		hdr := &tar.Header{Name: files[i], Mode: 0600, Size: int64(len(files[i+1])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("tw.WriteHeader(...): %v", err)
+		}
+		if _, err := tw.Write([]byte(files[i+1])); err != nil {
+			t.Fatalf("tw.Write(...): %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tw.Close(): %v", err)
+	}
+	return buf.Bytes()
+}
+
+func layer(data []byte) Layer {
+	return &MockLayer{MockUncompressed: func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}}
+}
+
+func TestImageBackend(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := "example.org/cool/package:v1"
+
+	base := layer(archive(t, "package.yaml", "a: 1"))
+	top := layer(archive(t,
+		"README.md", "# Cool",
+		"crds/b.yaml", "b: 2",
+		".wh.package.yaml", "",
+		"crds/c.yml", "c: 3",
+	))
+	notYAML := layer(archive(t, "README.md", "# Cool"))
+
+	type want struct {
+		stream string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		b      *ImageBackend
+		want   want
+	}{
+		"NilFetcher": {
+			reason: "An ImageBackend without a fetcher should return an error.",
+			b:      NewImageBackend(nil, ref),
+			want: want{
+				err: errors.New(errNilFetcher),
+			},
+		},
+		"FetchError": {
+			reason: "Errors fetching the image should be returned.",
+			b: NewImageBackend(ImageFetcherFn(func(_ context.Context, _ string, _ Keychain) ([]Layer, error) {
+				return nil, errBoom
+			}), ref),
+			want: want{
+				err: errors.Wrap(errBoom, errFetchImage),
+			},
+		},
+		"OpenLayerError": {
+			reason: "Errors opening a layer should be returned.",
+			b: NewImageBackend(ImageFetcherFn(func(_ context.Context, _ string, _ Keychain) ([]Layer, error) {
+				return []Layer{&MockLayer{MockUncompressed: func() (io.ReadCloser, error) { return nil, errBoom }}}, nil
+			}), ref),
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtOpenLayer, 0),
+			},
+		},
+		"NoYAML": {
+			reason: "An error should be returned if no layer contains a YAML file.",
+			b: NewImageBackend(ImageFetcherFn(func(_ context.Context, _ string, _ Keychain) ([]Layer, error) {
+				return []Layer{notYAML}, nil
+			}), ref),
+			want: want{
+				err: errors.New(errNoYAML),
+			},
+		},
+		"TopmostLayer": {
+			reason: "The YAML files of the topmost layer that contains any should be read, in order.",
+			b: NewImageBackend(ImageFetcherFn(func(_ context.Context, gotRef string, k Keychain) ([]Layer, error) {
+				if gotRef != ref {
+					t.Errorf("Fetch(...): want ref %q, got %q", ref, gotRef)
+				}
+				if k == nil {
+					t.Errorf("Fetch(...): want non-nil keychain")
+				}
+				return []Layer{base, top, notYAML}, nil
+			}), ref),
+			want: want{
+				stream: "b: 2\n---\nc: 3",
+			},
+		},
+		"Filtered": {
+			reason: "Files skipped by the supplied filters should not be read.",
+			b: NewImageBackend(ImageFetcherFn(func(_ context.Context, _ string, _ Keychain) ([]Layer, error) {
+				return []Layer{base, top}, nil
+			}), ref, ImageFilters(SkipPath("b.yaml"))),
+			want: want{
+				stream: "c: 3",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := tc.b.Init(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInit(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := ioutil.ReadAll(r)
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nInit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
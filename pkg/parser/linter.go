@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errNoMeta     = "no meta object found"
+	errAccessMeta = "cannot access object metadata"
+	errNoName     = "object has no name"
+
+	errFmtDocument       = "document %d (%s)"
+	errFmtTooManyMeta    = "exactly one meta object is allowed, found %d in documents %s"
+	errFmtKindNotAllowed = "kind %s is not allowed"
+	errFmtDuplicateCRD   = "CustomResourceDefinition %q is defined by documents %s"
+)
+
+// CustomResourceDefinitionGroupKind is the GroupKind of a
+// CustomResourceDefinition, regardless of its API version.
+var CustomResourceDefinitionGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// A Linter lints a set of parsed objects.
+type Linter interface {
+	// Lint the supplied objects, in the order they were parsed.
+	Lint(objs []runtime.Object) error
+}
+
+// A PackageCheckFn checks the entire set of parsed objects. Errors should
+// identify the offending objects by their index in the set.
+type PackageCheckFn func(objs []runtime.Object) error
+
+// An ObjectCheckFn checks a single parsed object.
+type ObjectCheckFn func(o runtime.Object) error
+
+// A PackageLinter lints a set of parsed objects using package and object
+// checks.
+type PackageLinter struct {
+	pre       []PackageCheckFn
+	perObject []ObjectCheckFn
+	post      []PackageCheckFn
+}
+
+// NewPackageLinter returns a Linter that runs the supplied pre checks against
+// the entire set of parsed objects, then the supplied per object checks
+// against each object, then the supplied post checks against the entire set.
+func NewPackageLinter(pre []PackageCheckFn, perObject []ObjectCheckFn, post []PackageCheckFn) *PackageLinter {
+	return &PackageLinter{pre: pre, perObject: perObject, post: post}
+}
+
+// Lint the supplied objects. Every check is run, and all errors are returned
+// as a single aggregated error. Errors returned by per object checks identify
+// the offending object by its index, which is also the index of the document
+// from which it was parsed, ignoring empty documents.
+func (l *PackageLinter) Lint(objs []runtime.Object) error {
+	errs := make([]error, 0)
+	for _, fn := range l.pre {
+		errs = append(errs, fn(objs))
+	}
+	for i, o := range objs {
+		for _, fn := range l.perObject {
+			errs = append(errs, errors.Wrapf(fn(o), errFmtDocument, i, describe(o)))
+		}
+	}
+	for _, fn := range l.post {
+		errs = append(errs, fn(objs))
+	}
+	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
+}
+
+// ExactlyOneMeta checks that exactly one of the supplied objects is a meta
+// object, i.e. is of one of the supplied kinds.
+func ExactlyOneMeta(kinds ...schema.GroupKind) PackageCheckFn {
+	allowed := make(map[schema.GroupKind]bool, len(kinds))
+	for _, gk := range kinds {
+		allowed[gk] = true
+	}
+	return func(objs []runtime.Object) error {
+		found := make([]int, 0)
+		for i, o := range objs {
+			if allowed[o.GetObjectKind().GroupVersionKind().GroupKind()] {
+				found = append(found, i)
+			}
+		}
+		switch len(found) {
+		case 0:
+			return errors.New(errNoMeta)
+		case 1:
+			return nil
+		default:
+			return errors.Errorf(errFmtTooManyMeta, len(found), indexes(found))
+		}
+	}
+}
+
+// OnlyAllowedGVKs checks that an object is of one of the supplied kinds.
+func OnlyAllowedGVKs(gvks ...schema.GroupVersionKind) ObjectCheckFn {
+	allowed := make(map[schema.GroupVersionKind]bool, len(gvks))
+	for _, gvk := range gvks {
+		allowed[gvk] = true
+	}
+	return func(o runtime.Object) error {
+		gvk := o.GetObjectKind().GroupVersionKind()
+		if !allowed[gvk] {
+			return errors.Errorf(errFmtKindNotAllowed, gvk)
+		}
+		return nil
+	}
+}
+
+// NoDuplicateCRDs checks that no two of the supplied objects are
+// CustomResourceDefinitions with the same name, regardless of their API
+// version.
+func NoDuplicateCRDs() PackageCheckFn {
+	return func(objs []runtime.Object) error {
+		defined := make(map[string][]int)
+		errs := make([]error, 0)
+		for i, o := range objs {
+			if o.GetObjectKind().GroupVersionKind().GroupKind() != CustomResourceDefinitionGroupKind {
+				continue
+			}
+			a, err := meta.Accessor(o)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(errors.Wrap(err, errAccessMeta), errFmtDocument, i, describe(o)))
+				continue
+			}
+			if a.GetName() == "" {
+				errs = append(errs, errors.Wrapf(errors.New(errNoName), errFmtDocument, i, describe(o)))
+				continue
+			}
+			defined[a.GetName()] = append(defined[a.GetName()], i)
+		}
+
+		names := make([]string, 0, len(defined))
+		for name := range defined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if len(defined[name]) > 1 {
+				errs = append(errs, errors.Errorf(errFmtDuplicateCRD, name, indexes(defined[name])))
+			}
+		}
+		return utilerrors.NewAggregate(errs)
+	}
+}
+
+// describe returns a short description of the supplied object, for example
+// 'CustomResourceDefinition "cool.example.org"'.
+func describe(o runtime.Object) string {
+	kind := o.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = "unknown kind"
+	}
+	if a, err := meta.Accessor(o); err == nil && a.GetName() != "" {
+		return fmt.Sprintf("%s %q", kind, a.GetName())
+	}
+	return kind
+}
+
+// indexes returns a human readable list of the supplied document indexes.
+func indexes(idx []int) string {
+	s := make([]string, len(idx))
+	for i := range idx {
+		s[i] = fmt.Sprintf("%d", idx[i])
+	}
+	return strings.Join(s, ", ")
+}
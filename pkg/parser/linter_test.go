@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Linter = &PackageLinter{}
+
+func object(gvk schema.GroupVersionKind, name string) runtime.Object {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(name)
+	return u
+}
+
+func TestPackageLinter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	metaGK := schema.GroupKind{Group: "meta.example.org", Kind: "Provider"}
+	metaGVK := metaGK.WithVersion("v1")
+	crdV1beta1 := CustomResourceDefinitionGroupKind.WithVersion("v1beta1")
+	crdV1 := CustomResourceDefinitionGroupKind.WithVersion("v1")
+
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+	}
+
+	pass := func(_ runtime.Object) error { return nil }
+	fail := func(_ runtime.Object) error { return errBoom }
+
+	cases := map[string]struct {
+		reason string
+		l      Linter
+		objs   []runtime.Object
+		want   error
+	}{
+		"Success": {
+			reason: "Objects that pass every check should result in no error.",
+			l: NewPackageLinter(
+				[]PackageCheckFn{ExactlyOneMeta(metaGK)},
+				[]ObjectCheckFn{pass, OnlyAllowedGVKs(metaGVK, crdV1beta1)},
+				[]PackageCheckFn{NoDuplicateCRDs()},
+			),
+			objs: []runtime.Object{
+				object(metaGVK, "cool"),
+				object(crdV1beta1, "a.example.org"),
+				object(crdV1beta1, "b.example.org"),
+			},
+		},
+		"NoMeta": {
+			reason: "A set of objects with no meta object should fail ExactlyOneMeta.",
+			l:      NewPackageLinter([]PackageCheckFn{ExactlyOneMeta(metaGK)}, nil, nil),
+			objs:   []runtime.Object{cm},
+			want:   utilerrors.NewAggregate([]error{errors.New(errNoMeta)}),
+		},
+		"TooManyMeta": {
+			reason: "A set of objects with more than one meta object should fail ExactlyOneMeta.",
+			l:      NewPackageLinter([]PackageCheckFn{ExactlyOneMeta(metaGK)}, nil, nil),
+			objs:   []runtime.Object{object(metaGVK, "a"), cm, object(metaGVK, "b")},
+			want:   utilerrors.NewAggregate([]error{errors.Errorf(errFmtTooManyMeta, 2, "0, 2")}),
+		},
+		"AggregatedObjectErrors": {
+			reason: "Every object that fails a check should be reported, identified by its position.",
+			l:      NewPackageLinter(nil, []ObjectCheckFn{fail, OnlyAllowedGVKs(metaGVK)}, nil),
+			objs:   []runtime.Object{object(metaGVK, "cool"), cm},
+			want: utilerrors.NewAggregate([]error{
+				errors.Wrapf(errBoom, errFmtDocument, 0, `Provider "cool"`),
+				errors.Wrapf(errBoom, errFmtDocument, 1, `ConfigMap "cool"`),
+				errors.Wrapf(errors.Errorf(errFmtKindNotAllowed, cm.GroupVersionKind()), errFmtDocument, 1, `ConfigMap "cool"`),
+			}),
+		},
+		"DuplicateCRDs": {
+			reason: "CustomResourceDefinitions with the same name should be reported, regardless of API version.",
+			l:      NewPackageLinter(nil, nil, []PackageCheckFn{NoDuplicateCRDs()}),
+			objs: []runtime.Object{
+				object(crdV1beta1, "a.example.org"),
+				object(crdV1beta1, "b.example.org"),
+				object(crdV1, "a.example.org"),
+				object(crdV1beta1, ""),
+			},
+			want: utilerrors.NewAggregate([]error{
+				errors.Wrapf(errors.New(errNoName), errFmtDocument, 3, "CustomResourceDefinition"),
+				errors.Errorf(errFmtDuplicateCRD, "a.example.org", "0, 2"),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.l.Lint(tc.objs)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLint(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package parser parses multi-document YAML streams into Kubernetes objects.
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errReadStream = "cannot read YAML stream"
+
+	errFmtDecodeDocument = "cannot decode document %d at line %d"
+)
+
+const separator = "---"
+
+// An ObjectCreaterTyper can create and determine the kind of objects, for
+// example a runtime.Scheme.
+type ObjectCreaterTyper interface {
+	runtime.ObjectCreater
+	runtime.ObjectTyper
+}
+
+// An UnstructuredCreater creates an *unstructured.Unstructured of any kind.
+type UnstructuredCreater struct{}
+
+// New returns a new *unstructured.Unstructured of the supplied kind.
+func (UnstructuredCreater) New(kind schema.GroupVersionKind) (runtime.Object, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(kind)
+	return u, nil
+}
+
+// A Parser parses multi-document YAML streams into Kubernetes objects.
+type Parser struct {
+	scheme   runtime.Decoder
+	fallback runtime.Decoder
+}
+
+// A ParserOption configures a Parser.
+type ParserOption func(*Parser)
+
+// WithFallback specifies the ObjectCreater used to create objects of kinds
+// that are not known to the Parser's scheme. Objects of unknown kinds are
+// parsed as an *unstructured.Unstructured by default.
+func WithFallback(oc runtime.ObjectCreater) ParserOption {
+	return func(p *Parser) {
+		p.fallback = decoder(oc, nopTyper{})
+	}
+}
+
+// New returns a Parser that parses objects of the kinds known to the supplied
+// scheme into their typed representation.
+func New(s ObjectCreaterTyper, o ...ParserOption) *Parser {
+	p := &Parser{
+		scheme:   decoder(s, s),
+		fallback: decoder(UnstructuredCreater{}, nopTyper{}),
+	}
+	for _, fn := range o {
+		fn(p)
+	}
+	return p
+}
+
+func decoder(oc runtime.ObjectCreater, ot runtime.ObjectTyper) runtime.Decoder {
+	return json.NewSerializerWithOptions(json.DefaultMetaFactory, oc, ot, json.SerializerOptions{Yaml: true})
+}
+
+// Parse the supplied YAML stream into objects, in the order they appear in the
+// stream. Empty documents are ignored. The supplied stream is always closed.
+// Any error decoding a document identifies the document by its index (from
+// zero, ignoring empty documents) and the line (from one) at which it starts.
+func (p *Parser) Parse(ctx context.Context, stream io.ReadCloser) ([]runtime.Object, error) {
+	defer stream.Close() // nolint:errcheck
+
+	objs := make([]runtime.Object, 0)
+	r := newDocumentReader(stream)
+	i := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		doc, err := r.Read()
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadStream)
+		}
+		if doc.empty() {
+			continue
+		}
+
+		o, err := p.decode(doc.data)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeDocument, i, doc.line)
+		}
+		objs = append(objs, o)
+		i++
+	}
+}
+
+func (p *Parser) decode(data []byte) (runtime.Object, error) {
+	o, _, err := p.scheme.Decode(data, nil, nil)
+	if runtime.IsNotRegisteredError(err) {
+		o, _, err = p.fallback.Decode(data, nil, nil)
+	}
+	return o, err
+}
+
+// A nopTyper is used by our fallback decoder, which never decodes into an
+// existing object and thus never needs to determine an object's kind.
+type nopTyper struct{}
+
+func (nopTyper) ObjectKinds(o runtime.Object) ([]schema.GroupVersionKind, bool, error) {
+	return nil, false, runtime.NewNotRegisteredErrForType("parser", reflect.TypeOf(o))
+}
+
+func (nopTyper) Recognizes(_ schema.GroupVersionKind) bool {
+	return false
+}
+
+// A document read from a YAML stream.
+type document struct {
+	line int
+	data []byte
+}
+
+// empty returns true if the document contains nothing but whitespace and
+// comments.
+func (d document) empty() bool {
+	j, err := yaml.YAMLToJSON(d.data)
+	return err == nil && string(bytes.TrimSpace(j)) == "null"
+}
+
+// A documentReader splits a YAML stream into documents, keeping track of
+// where each document starts.
+type documentReader struct {
+	r    *bufio.Reader
+	line int
+	eof  bool
+}
+
+func newDocumentReader(r io.Reader) *documentReader {
+	return &documentReader{r: bufio.NewReader(r), line: 1}
+}
+
+// Read the next document from the stream. Read returns io.EOF when no
+// documents remain.
+func (r *documentReader) Read() (document, error) {
+	if r.eof {
+		return document{}, io.EOF
+	}
+
+	doc := document{line: r.line}
+	buf := &bytes.Buffer{}
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return document{}, err
+		}
+		if len(line) > 0 {
+			r.line++
+		}
+
+		if isSeparator(line) {
+			doc.data = buf.Bytes()
+			return doc, nil
+		}
+
+		buf.Write(line)
+		if err == io.EOF {
+			r.eof = true
+			doc.data = buf.Bytes()
+			return doc, nil
+		}
+	}
+}
+
+// isSeparator returns true if the supplied line is a YAML document separator.
+func isSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, []byte(separator)) {
+		return false
+	}
+	return len(bytes.TrimRightFunc(line[len(separator):], unicode.IsSpace)) == 0
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ runtime.ObjectCreater = UnstructuredCreater{}
+
+const (
+	configMap = `
+# A typed object.
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cool
+data:
+  cool: very`
+
+	composite = `apiVersion: example.org/v1
+kind: CoolComposite
+metadata:
+  name: cool
+spec:
+  cool: true`
+
+	noKind = `apiVersion: v1
+metadata:
+  name: cool`
+)
+
+type ErrorReader struct{ err error }
+
+func (r ErrorReader) Read(_ []byte) (int, error) { return 0, r.err }
+
+type ErrorCreater struct{ err error }
+
+func (c ErrorCreater) New(_ schema.GroupVersionKind) (runtime.Object, error) { return nil, c.err }
+
+func TestParse(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	s.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.ConfigMap{})
+
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Data:       map[string]string{"cool": "very"},
+	}
+
+	cp := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.org/v1",
+		"kind":       "CoolComposite",
+		"metadata":   map[string]interface{}{"name": "cool"},
+		"spec":       map[string]interface{}{"cool": true},
+	}}
+
+	type args struct {
+		ctx    context.Context
+		stream io.ReadCloser
+	}
+	type want struct {
+		objs []runtime.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      *Parser
+		args   args
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty stream should result in no objects.",
+			p:      New(s),
+			args: args{
+				ctx:    context.Background(),
+				stream: ioutil.NopCloser(strings.NewReader("")),
+			},
+			want: want{
+				objs: []runtime.Object{},
+			},
+		},
+		"ReadError": {
+			reason: "Errors reading the stream should be returned.",
+			p:      New(s),
+			args: args{
+				ctx:    context.Background(),
+				stream: ioutil.NopCloser(ErrorReader{err: errBoom}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errReadStream),
+			},
+		},
+		"TypedAndUnstructured": {
+			reason: "Kinds known to the scheme should be parsed as typed objects, and others as unstructured objects.",
+			p:      New(s),
+			args: args{
+				ctx:    context.Background(),
+				stream: ioutil.NopCloser(strings.NewReader("---\n" + configMap + "\n---\n# Empty.\n---\n" + composite + "\n---\n")),
+			},
+			want: want{
+				objs: []runtime.Object{cm, cp},
+			},
+		},
+		"FallbackError": {
+			reason: "Errors creating objects of kinds unknown to the scheme should be returned.",
+			p:      New(s, WithFallback(ErrorCreater{err: errBoom})),
+			args: args{
+				ctx:    context.Background(),
+				stream: ioutil.NopCloser(strings.NewReader(composite)),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtDecodeDocument, 0, 1),
+			},
+		},
+		"DecodeError": {
+			reason: "Errors decoding a document should identify the document and the line at which it starts.",
+			p:      New(s),
+			args: args{
+				ctx:    context.Background(),
+				stream: ioutil.NopCloser(strings.NewReader(configMap + "\n---\n" + noKind)),
+			},
+			want: want{
+				err: errors.Wrapf(runtime.NewMissingKindErr(noKind), errFmtDecodeDocument, 1, 10),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := tc.p.Parse(tc.args.ctx, tc.args.stream)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, objs); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
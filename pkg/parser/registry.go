@@ -0,0 +1,423 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errGetManifest    = "cannot get image manifest"
+	errDecodeManifest = "cannot decode image manifest"
+	errGetBlob        = "cannot get image layer"
+	errDecompress     = "cannot decompress image layer"
+	errResolveCreds   = "cannot resolve registry credentials"
+	errGetToken       = "cannot get registry token"
+	errDecodeToken    = "cannot decode registry token"
+
+	errFmtInvalidReference = "invalid image reference %q"
+	errFmtUnsupportedType  = "unsupported image manifest media type %q"
+	errFmtUnsupportedAlg   = "unsupported digest algorithm %q"
+	errFmtDigestMismatch   = "image layer digest %q does not match content digest %q"
+	errFmtStatus           = "unexpected status %q from %s"
+)
+
+// Manifest media types.
+const (
+	MediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+const (
+	defaultRegistry = "index.docker.io"
+	defaultTag      = "latest"
+)
+
+// Credentials used to authenticate to an OCI registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// A Keychain resolves the credentials used to authenticate to an OCI
+// registry.
+type Keychain interface {
+	Resolve(registry string) (Credentials, error)
+}
+
+// A KeychainFn is a function that satisfies the Keychain interface.
+type KeychainFn func(registry string) (Credentials, error)
+
+// Resolve the credentials for the supplied registry.
+func (fn KeychainFn) Resolve(registry string) (Credentials, error) {
+	return fn(registry)
+}
+
+// Anonymous is a Keychain that resolves empty credentials for every registry.
+var Anonymous Keychain = KeychainFn(func(_ string) (Credentials, error) { return Credentials{}, nil })
+
+// A RegistryFetcher fetches OCI images from registries that implement the OCI
+// distribution API.
+type RegistryFetcher struct {
+	client *http.Client
+	scheme string
+}
+
+// A RegistryFetcherOption configures a RegistryFetcher.
+type RegistryFetcherOption func(*RegistryFetcher)
+
+// WithHTTPClient specifies the HTTP client used by a RegistryFetcher.
+// http.DefaultClient is used by default.
+func WithHTTPClient(c *http.Client) RegistryFetcherOption {
+	return func(f *RegistryFetcher) {
+		f.client = c
+	}
+}
+
+// WithPlainHTTP causes a RegistryFetcher to connect to registries via HTTP
+// rather than HTTPS.
+func WithPlainHTTP() RegistryFetcherOption {
+	return func(f *RegistryFetcher) {
+		f.scheme = "http"
+	}
+}
+
+// NewRegistryFetcher returns an ImageFetcher that fetches OCI images from
+// registries that implement the OCI distribution API.
+func NewRegistryFetcher(o ...RegistryFetcherOption) *RegistryFetcher {
+	f := &RegistryFetcher{client: http.DefaultClient, scheme: "https"}
+	for _, fn := range o {
+		fn(f)
+	}
+	return f
+}
+
+// Fetch the layers of the image with the supplied reference. Only image
+// manifests are supported; image indexes and manifest lists are not.
+func (f *RegistryFetcher) Fetch(ctx context.Context, ref string, k Keychain) ([]Layer, error) {
+	r, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &registrySession{client: f.client, keychain: k, registry: r.registry, repository: r.repository}
+	base := fmt.Sprintf("%s://%s/v2/%s", f.scheme, r.registry, r.repository)
+
+	req, err := http.NewRequest(http.MethodGet, base+"/manifests/"+r.identifier, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetManifest)
+	}
+	req.Header.Set("Accept", strings.Join([]string{MediaTypeOCIManifest, MediaTypeDockerManifest}, ", "))
+	rsp, err := s.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, errGetManifest)
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+
+	m := &manifest{}
+	if err := json.NewDecoder(rsp.Body).Decode(m); err != nil {
+		return nil, errors.Wrap(err, errDecodeManifest)
+	}
+	if m.MediaType == "" {
+		m.MediaType = rsp.Header.Get("Content-Type")
+	}
+	if m.MediaType != MediaTypeOCIManifest && m.MediaType != MediaTypeDockerManifest {
+		return nil, errors.Errorf(errFmtUnsupportedType, m.MediaType)
+	}
+
+	layers := make([]Layer, len(m.Layers))
+	for i := range m.Layers {
+		layers[i] = &registryLayer{ctx: ctx, session: s, url: base + "/blobs/" + m.Layers[i].Digest, descriptor: m.Layers[i]}
+	}
+	return layers, nil
+}
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+}
+
+// A registryLayer is an image layer that is fetched from a registry when it is
+// opened.
+type registryLayer struct {
+	ctx        context.Context
+	session    *registrySession
+	url        string
+	descriptor descriptor
+}
+
+// Uncompressed returns the layer's uncompressed tar archive. Reading the
+// archive returns an error at EOF if the layer's content does not match its
+// digest.
+func (l *registryLayer) Uncompressed() (io.ReadCloser, error) {
+	v, err := newVerifier(l.descriptor.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, l.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetBlob)
+	}
+	rsp, err := l.session.do(req.WithContext(l.ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, errGetBlob)
+	}
+
+	v.r = rsp.Body
+	if !strings.Contains(l.descriptor.MediaType, "gzip") {
+		return &readCloser{Reader: v, closers: []io.Closer{rsp.Body}}, nil
+	}
+
+	gz, err := gzip.NewReader(v)
+	if err != nil {
+		_ = rsp.Body.Close()
+		return nil, errors.Wrap(err, errDecompress)
+	}
+	return &readCloser{Reader: gz, closers: []io.Closer{gz, rsp.Body}}, nil
+}
+
+// A verifier hashes the content read through it, and returns an error at EOF
+// if the content does not match the expected digest.
+type verifier struct {
+	r    io.Reader
+	h    hash.Hash
+	want string
+}
+
+func newVerifier(digest string) (*verifier, error) {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 || parts[0] != "sha256" {
+		return nil, errors.Errorf(errFmtUnsupportedAlg, digest)
+	}
+	return &verifier{h: sha256.New(), want: digest}, nil
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	_, _ = v.h.Write(p[:n])
+	if err == io.EOF {
+		if got := "sha256:" + hex.EncodeToString(v.h.Sum(nil)); got != v.want {
+			return n, errors.Errorf(errFmtDigestMismatch, v.want, got)
+		}
+	}
+	return n, err
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var err error
+	for _, c := range rc.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// A registrySession makes requests to a registry, authenticating as the
+// registry demands.
+type registrySession struct {
+	client     *http.Client
+	keychain   Keychain
+	registry   string
+	repository string
+
+	// authorization is the Authorization header that satisfied the last
+	// challenge presented by the registry.
+	authorization string
+}
+
+func (s *registrySession) do(req *http.Request) (*http.Response, error) {
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode == http.StatusUnauthorized && s.authorization == "" {
+		_ = rsp.Body.Close()
+		if err := s.authorize(req.Context(), rsp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", s.authorization)
+		if rsp, err = s.client.Do(req); err != nil {
+			return nil, err
+		}
+	}
+	if rsp.StatusCode != http.StatusOK {
+		_ = rsp.Body.Close()
+		return nil, errors.Errorf(errFmtStatus, rsp.Status, req.URL.Path)
+	}
+	return rsp, nil
+}
+
+// authorize determines the Authorization header that satisfies the supplied
+// WWW-Authenticate challenge.
+func (s *registrySession) authorize(ctx context.Context, challenge string) error {
+	creds := Credentials{}
+	if s.keychain != nil {
+		c, err := s.keychain.Resolve(s.registry)
+		if err != nil {
+			return errors.Wrap(err, errResolveCreds)
+		}
+		creds = c
+	}
+
+	scheme, params := parseChallenge(challenge)
+	if !strings.EqualFold(scheme, "bearer") {
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(creds.Username, creds.Password)
+		s.authorization = req.Header.Get("Authorization")
+		return nil
+	}
+
+	q := url.Values{}
+	if v := params["service"]; v != "" {
+		q.Set("service", v)
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull", s.repository))
+
+	req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+q.Encode(), nil)
+	if err != nil {
+		return errors.Wrap(err, errGetToken)
+	}
+	if creds.Username != "" || creds.Password != "" {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	rsp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, errGetToken)
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+	if rsp.StatusCode != http.StatusOK {
+		return errors.Wrap(errors.Errorf(errFmtStatus, rsp.Status, req.URL.Path), errGetToken)
+	}
+
+	t := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(rsp.Body).Decode(t); err != nil {
+		return errors.Wrap(err, errDecodeToken)
+	}
+	if t.Token == "" {
+		t.Token = t.AccessToken
+	}
+	s.authorization = "Bearer " + t.Token
+	return nil
+}
+
+// parseChallenge parses a WWW-Authenticate challenge, for example:
+// Bearer realm="https://auth.example.org/token",service="registry.example.org"
+func parseChallenge(challenge string) (string, map[string]string) {
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	params := map[string]string{}
+	if len(parts) < 2 {
+		return parts[0], params
+	}
+
+	rest := parts[1]
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			value, rest = rest[1:end+1], rest[end+1:]
+			rest = strings.TrimPrefix(rest, `"`)
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		params[key] = value
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return parts[0], params
+}
+
+type reference struct {
+	registry   string
+	repository string
+	identifier string
+}
+
+// parseReference parses an image reference of the form
+// [registry/]repository[:tag|@digest]. References without a registry are
+// assumed to be to Docker Hub, and references without a tag or digest are
+// assumed to be to the latest tag.
+func parseReference(ref string) (reference, error) {
+	r := reference{registry: defaultRegistry, identifier: defaultTag}
+
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, r.identifier = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, r.identifier = name[:i], name[i+1:]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		r.registry, name = parts[0], parts[1]
+	}
+	if r.registry == "docker.io" {
+		r.registry = defaultRegistry
+	}
+	if r.registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	r.repository = name
+
+	if r.repository == "" || r.identifier == "" || strings.HasSuffix(r.repository, "/") {
+		return reference{}, errors.Errorf(errFmtInvalidReference, ref)
+	}
+	return r, nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ ImageFetcher = &RegistryFetcher{}
+
+func TestParseReference(t *testing.T) {
+	type want struct {
+		r   reference
+		err error
+	}
+
+	cases := map[string]struct {
+		ref  string
+		want want
+	}{
+		"DockerHubOfficial": {
+			ref:  "cool",
+			want: want{r: reference{registry: "index.docker.io", repository: "library/cool", identifier: "latest"}},
+		},
+		"DockerHub": {
+			ref:  "docker.io/example/cool:v1",
+			want: want{r: reference{registry: "index.docker.io", repository: "example/cool", identifier: "v1"}},
+		},
+		"RegistryWithPort": {
+			ref:  "localhost:5000/example/cool",
+			want: want{r: reference{registry: "localhost:5000", repository: "example/cool", identifier: "latest"}},
+		},
+		"Digest": {
+			ref:  "example.org/cool@sha256:abc",
+			want: want{r: reference{registry: "example.org", repository: "cool", identifier: "sha256:abc"}},
+		},
+		"Invalid": {
+			ref:  "example.org/cool:",
+			want: want{err: errors.Errorf(errFmtInvalidReference, "example.org/cool:")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := parseReference(tc.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("parseReference(%q): -want error, +got error:\n%s", tc.ref, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, r, cmp.AllowUnexported(reference{})); diff != "" {
+				t.Errorf("parseReference(%q): -want, +got:\n%s", tc.ref, diff)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://example.org/token",service="example.org",scope="repository:cool:pull,push"`)
+	if scheme != "Bearer" {
+		t.Errorf("parseChallenge(...): want scheme Bearer, got %q", scheme)
+	}
+	want := map[string]string{
+		"realm":   "https://example.org/token",
+		"service": "example.org",
+		"scope":   "repository:cool:pull,push",
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("parseChallenge(...): -want, +got:\n%s", diff)
+	}
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// registry returns a registry that requires bearer token authentication and
+// serves a single image with a single layer.
+func registry(t *testing.T, mediaType string, blob []byte, blobDigest string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "cool" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("scope") != "repository:example/cool:pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "tkn"})
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tkn" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/example/cool/manifests/v1":
+			_ = json.NewEncoder(w).Encode(manifest{
+				MediaType: mediaType,
+				Layers:    []descriptor{{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: blobDigest}},
+			})
+		case "/v2/example/cool/blobs/" + blobDigest:
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return srv
+}
+
+func TestRegistryFetcher(t *testing.T) {
+	content := archive(t, "package.yaml", "a: 1")
+	compressed := &bytes.Buffer{}
+	gz := gzip.NewWriter(compressed)
+	_, _ = gz.Write(content)
+	_ = gz.Close()
+	blob := compressed.Bytes()
+
+	creds := KeychainFn(func(_ string) (Credentials, error) {
+		return Credentials{Username: "cool", Password: "secret"}, nil
+	})
+
+	type args struct {
+		mediaType string
+		digest    string
+		keychain  Keychain
+		ref       string
+	}
+	type want struct {
+		content []byte
+		err     error
+		readErr error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "An image layer should be fetched using a bearer token obtained with the keychain's credentials.",
+			args: args{
+				mediaType: MediaTypeOCIManifest,
+				digest:    digest(blob),
+				keychain:  creds,
+				ref:       "example/cool:v1",
+			},
+			want: want{
+				content: content,
+			},
+		},
+		"Unauthorized": {
+			reason: "An error should be returned if a token cannot be obtained.",
+			args: args{
+				mediaType: MediaTypeOCIManifest,
+				digest:    digest(blob),
+				keychain:  Anonymous,
+				ref:       "example/cool:v1",
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.Errorf(errFmtStatus, "401 Unauthorized", "/token"), errGetToken), errGetManifest),
+			},
+		},
+		"UnsupportedMediaType": {
+			reason: "An error should be returned if the manifest is not an image manifest.",
+			args: args{
+				mediaType: "application/vnd.oci.image.index.v1+json",
+				digest:    digest(blob),
+				keychain:  creds,
+				ref:       "example/cool:v1",
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnsupportedType, "application/vnd.oci.image.index.v1+json"),
+			},
+		},
+		"DigestMismatch": {
+			reason: "An error should be returned when a layer that does not match its digest is read.",
+			args: args{
+				mediaType: MediaTypeOCIManifest,
+				digest:    digest([]byte("wrong")),
+				keychain:  creds,
+				ref:       "example/cool:v1",
+			},
+			want: want{
+				readErr: errors.Errorf(errFmtDigestMismatch, digest([]byte("wrong")), digest(blob)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := registry(t, tc.args.mediaType, blob, tc.args.digest)
+			defer srv.Close()
+
+			ref := strings.TrimPrefix(srv.URL, "http://") + "/" + tc.args.ref
+			layers, err := NewRegistryFetcher(WithPlainHTTP()).Fetch(context.Background(), ref, tc.args.keychain)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if len(layers) != 1 {
+				t.Fatalf("\n%s\nFetch(...): want 1 layer, got %d", tc.reason, len(layers))
+			}
+
+			rc, err := layers[0].Uncompressed()
+			if err != nil {
+				t.Fatalf("\n%s\nUncompressed(): %v", tc.reason, err)
+			}
+			defer rc.Close()
+			got, err := ioutil.ReadAll(rc)
+			if diff := cmp.Diff(tc.want.readErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nReadAll(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.content, got); diff != "" {
+				t.Errorf("\n%s\nReadAll(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
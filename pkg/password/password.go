@@ -20,6 +20,24 @@ package password
 import (
 	"crypto/rand"
 	"math/big"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errEmptyCharacterSet = "cannot generate a password from an empty character set"
+	errEmptyClass        = "required character classes must not be empty"
+	errTooShort          = "password length must be at least the number of required character classes"
+)
+
+// Character classes.
+const (
+	Lowercase = "abcdefghijklmnopqrstuvwxyz"
+	Uppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Digits    = "0123456789"
+	Symbols   = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
 )
 
 // Settings for password generation.
@@ -27,13 +45,18 @@ type Settings struct {
 	// CharacterSet of allowed password characters.
 	CharacterSet string
 
+	// RequiredClasses of characters. Generated passwords contain at least one
+	// character from each required class. Characters from required classes
+	// are allowed even if they are not part of the CharacterSet.
+	RequiredClasses []string
+
 	// Length of generated passwords.
 	Length int
 }
 
 // Default password generation settings.
 var Default = Settings{
-	CharacterSet: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	CharacterSet: Lowercase + Uppercase + Digits,
 	Length:       27,
 }
 
@@ -45,13 +68,90 @@ func Generate() (string, error) {
 
 // Generate a password.
 func (s Settings) Generate() (string, error) {
+	if s.Length < len(s.RequiredClasses) {
+		return "", errors.New(errTooShort)
+	}
+
+	set := s.CharacterSet
+	for _, c := range s.RequiredClasses {
+		if c == "" {
+			return "", errors.New(errEmptyClass)
+		}
+		set += c
+	}
+	set = dedupe(set)
+	if set == "" && s.Length > 0 {
+		return "", errors.New(errEmptyCharacterSet)
+	}
+
 	pw := make([]byte, s.Length)
 	for i := 0; i < s.Length; i++ {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(s.CharacterSet))))
+		// The first characters are drawn from each required class in turn.
+		// We shuffle them into random positions below.
+		from := set
+		if i < len(s.RequiredClasses) {
+			from = s.RequiredClasses[i]
+		}
+		n, err := randInt(len(from))
 		if err != nil {
 			return "", err
 		}
-		pw[i] = s.CharacterSet[n.Int64()]
+		pw[i] = from[n]
 	}
+
+	// Fisher-Yates shuffle, so that required characters may appear anywhere.
+	for i := len(pw) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		pw[i], pw[j] = pw[j], pw[i]
+	}
+
 	return string(pw), nil
 }
+
+// ConnectionDetails returns a copy of the supplied connection details that
+// contains a password at the supplied key. A new password is generated only
+// if the supplied connection details do not already contain one. Providers
+// that must invent the initial credentials of an external resource should
+// publish them only as connection details, rather than persisting them in the
+// managed resource's spec.
+func (s Settings) ConnectionDetails(existing map[string][]byte, key string) (map[string][]byte, error) {
+	cd := make(map[string][]byte, len(existing)+1)
+	for k, v := range existing {
+		cd[k] = v
+	}
+	if len(cd[key]) > 0 {
+		return cd, nil
+	}
+	pw, err := s.Generate()
+	if err != nil {
+		return nil, err
+	}
+	cd[key] = []byte(pw)
+	return cd, nil
+}
+
+func randInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot generate random number")
+	}
+	return int(n.Int64()), nil
+}
+
+// dedupe removes duplicate characters from the supplied string, so that no
+// character is more likely to be chosen than any other.
+func dedupe(set string) string {
+	seen := make(map[byte]bool, len(set))
+	var b strings.Builder
+	for i := 0; i < len(set); i++ {
+		if seen[set[i]] {
+			continue
+		}
+		seen[set[i]] = true
+		b.WriteByte(set[i])
+	}
+	return b.String()
+}
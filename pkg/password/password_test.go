@@ -1,9 +1,13 @@
 package password
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
 func TestGenerate(t *testing.T) {
@@ -18,3 +22,94 @@ func TestGenerate(t *testing.T) {
 		t.Errorf("Generate: %s\n", err)
 	}
 }
+
+func TestGenerateErrors(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      Settings
+		want   error
+	}{
+		"TooShort": {
+			reason: "We should return an error if the password can't contain every required class.",
+			s:      Settings{RequiredClasses: []string{Lowercase, Digits}, Length: 1},
+			want:   errors.New(errTooShort),
+		},
+		"EmptyClass": {
+			reason: "We should return an error if a required class is empty.",
+			s:      Settings{RequiredClasses: []string{""}, Length: 1},
+			want:   errors.New(errEmptyClass),
+		},
+		"EmptyCharacterSet": {
+			reason: "We should return an error if there are no characters to choose from.",
+			s:      Settings{Length: 1},
+			want:   errors.New(errEmptyCharacterSet),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.s.Generate()
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGenerate(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateRequiredClasses(t *testing.T) {
+	s := Settings{CharacterSet: "a", RequiredClasses: []string{Digits, Symbols}, Length: 3}
+
+	// Passwords are random, so we generate a few to be more confident every
+	// one contains every required class.
+	for i := 0; i < 10; i++ {
+		got, err := s.Generate()
+		if err != nil {
+			t.Fatalf("Generate(): %s", err)
+		}
+		if len(got) != s.Length {
+			t.Errorf("Generate(): want length %d, got %q", s.Length, got)
+		}
+		for _, c := range s.RequiredClasses {
+			if !strings.ContainsAny(got, c) {
+				t.Errorf("Generate(): %q contains no characters from required class %q", got, c)
+			}
+		}
+	}
+}
+
+func TestConnectionDetails(t *testing.T) {
+	s := Settings{CharacterSet: "a", Length: 3}
+
+	cases := map[string]struct {
+		reason   string
+		existing map[string][]byte
+		want     map[string][]byte
+	}{
+		"NoExistingDetails": {
+			reason: "A password should be generated if there are no existing connection details.",
+			want:   map[string][]byte{"password": []byte("aaa")},
+		},
+		"NoExistingPassword": {
+			reason:   "A password should be added to existing connection details that don't contain one.",
+			existing: map[string][]byte{"username": []byte("admin")},
+			want:     map[string][]byte{"username": []byte("admin"), "password": []byte("aaa")},
+		},
+		"ExistingPassword": {
+			reason:   "An existing password should be preserved.",
+			existing: map[string][]byte{"password": []byte("hunter2")},
+			want:     map[string][]byte{"password": []byte("hunter2")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := s.ConnectionDetails(tc.existing, "password")
+			if err != nil {
+				t.Fatalf("\n%s\nConnectionDetails(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConnectionDetails(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
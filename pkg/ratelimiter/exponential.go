@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ workqueue.RateLimiter = &ItemExponential{}
+
+// An ExponentialOption configures an ItemExponential rate limiter.
+type ExponentialOption func(*ItemExponential)
+
+// WithBaseDelay specifies the delay before an item is first retried.
+func WithBaseDelay(d time.Duration) ExponentialOption {
+	return func(r *ItemExponential) {
+		r.base = d
+	}
+}
+
+// WithMaxDelay specifies the longest an item will wait to be retried.
+func WithMaxDelay(d time.Duration) ExponentialOption {
+	return func(r *ItemExponential) {
+		r.max = d
+	}
+}
+
+// WithResetAfter specifies how long an item must go without being rate
+// limited before its backoff is reset to the base delay, as if it had been
+// forgotten. Backoff is only reset when an item is forgotten by default.
+func WithResetAfter(d time.Duration) ExponentialOption {
+	return func(r *ItemExponential) {
+		r.reset = d
+	}
+}
+
+type failure struct {
+	count int
+	last  time.Time
+}
+
+// An ItemExponential rate limiter delays each item by base*2^n, where n is the
+// number of times the item has been rate limited since it was last forgotten
+// or reset. The delay never exceeds the configured maximum.
+type ItemExponential struct {
+	base  time.Duration
+	max   time.Duration
+	reset time.Duration
+
+	mx       sync.Mutex
+	failures map[interface{}]failure
+	now      func() time.Time
+}
+
+// NewItemExponential returns a per-item exponential backoff rate limiter. It
+// uses DefaultBaseDelay and DefaultMaxDelay unless configured otherwise.
+func NewItemExponential(o ...ExponentialOption) *ItemExponential {
+	r := &ItemExponential{
+		base:     DefaultBaseDelay,
+		max:      DefaultMaxDelay,
+		failures: map[interface{}]failure{},
+		now:      time.Now,
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// When returns how long the supplied item should wait before being retried.
+func (r *ItemExponential) When(item interface{}) time.Duration {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	now := r.now()
+	f := r.failures[item]
+	if r.reset > 0 && !f.last.IsZero() && now.Sub(f.last) > r.reset {
+		f = failure{}
+	}
+	exp := f.count
+	r.failures[item] = failure{count: f.count + 1, last: now}
+
+	// Cap the backoff such that the calculated value never overflows.
+	backoff := float64(r.base.Nanoseconds()) * math.Pow(2, float64(exp))
+	if backoff > math.MaxInt64 || time.Duration(backoff) > r.max {
+		return r.max
+	}
+	return time.Duration(backoff)
+}
+
+// NumRequeues returns how many times the supplied item has been rate limited
+// since it was last forgotten.
+func (r *ItemExponential) NumRequeues(item interface{}) int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.failures[item].count
+}
+
+// Forget the supplied item, resetting its backoff.
+func (r *ItemExponential) Forget(item interface{}) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	delete(r.failures, item)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestItemExponential(t *testing.T) {
+	type step struct {
+		elapsed time.Duration
+		forget  bool
+	}
+	cases := map[string]struct {
+		reason string
+		o      []ExponentialOption
+		steps  []step
+		want   []time.Duration
+	}{
+		"Defaults": {
+			reason: "The delay should double from the default base delay",
+			steps:  []step{{}, {}, {}},
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		"MaxDelay": {
+			reason: "The delay should not exceed the maximum delay",
+			o:      []ExponentialOption{WithBaseDelay(1 * time.Second), WithMaxDelay(3 * time.Second)},
+			steps:  []step{{}, {}, {}, {}},
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		"Forget": {
+			reason: "The delay should be reset when an item is forgotten",
+			o:      []ExponentialOption{WithBaseDelay(1 * time.Second)},
+			steps:  []step{{}, {}, {forget: true}},
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 1 * time.Second},
+		},
+		"ResetAfter": {
+			reason: "The delay should be reset when an item has not been rate limited for the reset duration",
+			o:      []ExponentialOption{WithBaseDelay(1 * time.Second), WithResetAfter(1 * time.Minute)},
+			steps:  []step{{}, {elapsed: 30 * time.Second}, {elapsed: 2 * time.Minute}},
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 1 * time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			r := NewItemExponential(tc.o...)
+			r.now = func() time.Time { return now }
+
+			got := make([]time.Duration, 0, len(tc.steps))
+			for _, s := range tc.steps {
+				now = now.Add(s.elapsed)
+				if s.forget {
+					r.Forget("item")
+				}
+				got = append(got, r.When("item"))
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nr.When(...): %s: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
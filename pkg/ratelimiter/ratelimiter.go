@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimiter contains suggested default workqueue rate limiters for
+// Crossplane providers.
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Defaults for the rate limiters returned by NewDefaultManagedRateLimiter.
+const (
+	// DefaultBaseDelay is the delay before a failed item is first retried.
+	DefaultBaseDelay = 1 * time.Second
+
+	// DefaultMaxDelay is the longest a failed item will wait to be retried.
+	DefaultMaxDelay = 60 * time.Second
+
+	// DefaultProviderRPS is the average number of requeues per second allowed
+	// across all controllers of a provider.
+	DefaultProviderRPS = 1
+
+	// DefaultProviderBurst is the number of requeues that may exceed the
+	// average rate in a short burst.
+	DefaultProviderBurst = 100
+)
+
+var (
+	providersMu sync.Mutex
+	providers   = map[string]*workqueue.BucketRateLimiter{}
+)
+
+// NewDefaultProviderRateLimiter returns a token bucket rate limiter that is
+// shared by every caller that supplies the same provider name. It limits the
+// average number of requeues per second across all controllers of a provider.
+func NewDefaultProviderRateLimiter(providerName string) *workqueue.BucketRateLimiter {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if rl, ok := providers[providerName]; ok {
+		return rl
+	}
+	rl := &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(DefaultProviderRPS), DefaultProviderBurst)}
+	providers[providerName] = rl
+	return rl
+}
+
+// NewDefaultManagedRateLimiter returns a rate limiter suitable for a managed
+// resource controller. It takes the maximum delay of a per-item exponential
+// backoff limiter and the supplied provider's token bucket limiter. Failed
+// items are retried after DefaultBaseDelay, doubling to at most
+// DefaultMaxDelay, while the provider's overall requeue rate stays bounded.
+// The per-item backoff may be tuned by supplying ExponentialOptions.
+func NewDefaultManagedRateLimiter(providerName string, o ...ExponentialOption) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		NewItemExponential(o...),
+		NewDefaultProviderRateLimiter(providerName),
+	)
+}
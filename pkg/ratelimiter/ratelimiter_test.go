@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewDefaultProviderRateLimiter(t *testing.T) {
+	a := NewDefaultProviderRateLimiter("a")
+	if a != NewDefaultProviderRateLimiter("a") {
+		t.Errorf("NewDefaultProviderRateLimiter(...): limiters for the same provider should be shared")
+	}
+	if a == NewDefaultProviderRateLimiter("b") {
+		t.Errorf("NewDefaultProviderRateLimiter(...): limiters for different providers should not be shared")
+	}
+}
+
+func TestNewDefaultManagedRateLimiter(t *testing.T) {
+	rl := NewDefaultManagedRateLimiter("cool")
+	item := "item"
+
+	want := []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i, w := range want {
+		if diff := cmp.Diff(w, rl.When(item)); diff != "" {
+			t.Errorf("rl.When(...) attempt %d: -want, +got:\n%s", i, diff)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		rl.When(item)
+	}
+	if diff := cmp.Diff(DefaultMaxDelay, rl.When(item)); diff != "" {
+		t.Errorf("rl.When(...): backoff should be capped: -want, +got:\n%s", diff)
+	}
+
+	rl.Forget(item)
+	if diff := cmp.Diff(0, rl.NumRequeues(item)); diff != "" {
+		t.Errorf("rl.NumRequeues(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewGlobal returns a token bucket rate limiter meant to be shared by every
+// controller in a provider binary. It allows the supplied average number of
+// reconciles per second, with bursts of up to ten times that number.
+func NewGlobal(rps int) *workqueue.BucketRateLimiter {
+	return &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(rps), rps*10)}
+}
+
+// A Reconciler rate limits an inner, wrapped Reconciler. Requests that are
+// rate limited are requeued without being passed to the inner Reconciler.
+type Reconciler struct {
+	inner reconcile.Reconciler
+	limit workqueue.RateLimiter
+	now   func() time.Time
+
+	mu           sync.Mutex
+	reservations map[reconcile.Request]time.Time
+}
+
+// NewReconciler wraps the supplied Reconciler, ensuring requests are passed
+// to it no more frequently than the supplied RateLimiter allows. Wrapping the
+// Reconciler of every controller in a provider binary using the same limiter,
+// e.g. one returned by NewGlobal, bounds the provider's total reconcile rate,
+// and thus the load it places on external APIs, regardless of how many kinds
+// of resource it reconciles.
+func NewReconciler(r reconcile.Reconciler, l workqueue.RateLimiter) *Reconciler {
+	return &Reconciler{inner: r, limit: l, now: time.Now, reservations: make(map[reconcile.Request]time.Time)}
+}
+
+// Reconcile the supplied request, subject to rate limiting. Asking the
+// RateLimiter when a request may be processed reserves a slot for it, so a
+// request that is rate limited is not rate limited again when it is requeued.
+// Instead it is passed to the inner Reconciler once its reservation expires.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	if d := r.reserve(req); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+	return r.inner.Reconcile(req)
+}
+
+// reserve returns how long the supplied request must wait before it may be
+// passed to the inner Reconciler.
+func (r *Reconciler) reserve(req reconcile.Request) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if at, ok := r.reservations[req]; ok {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+		delete(r.reservations, req)
+		return 0
+	}
+
+	d := r.limit.When(req)
+	if d > 0 {
+		r.reservations[req] = now.Add(d)
+	}
+	return d
+}
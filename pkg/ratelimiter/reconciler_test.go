@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+type predictableRateLimiter struct{ d time.Duration }
+
+func (r *predictableRateLimiter) When(_ interface{}) time.Duration { return r.d }
+func (r *predictableRateLimiter) Forget(_ interface{})             {}
+func (r *predictableRateLimiter) NumRequeues(_ interface{}) int    { return 0 }
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		req reconcile.Request
+	}
+	type want struct {
+		res reconcile.Result
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		r      reconcile.Reconciler
+		args   args
+		want   want
+	}{
+		"RateLimited": {
+			reason: "Requests that are rate limited should be requeued after the duration specified by the RateLimiter.",
+			r: NewReconciler(
+				reconcile.Func(func(_ reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, errBoom }),
+				&predictableRateLimiter{d: 8 * time.Second}),
+			want: want{res: reconcile.Result{RequeueAfter: 8 * time.Second}},
+		},
+		"NotRateLimited": {
+			reason: "Requests that are not rate limited should be passed to the inner Reconciler.",
+			r: NewReconciler(
+				reconcile.Func(func(_ reconcile.Request) (reconcile.Result, error) {
+					return reconcile.Result{Requeue: true}, errBoom
+				}),
+				&predictableRateLimiter{}),
+			want: want{res: reconcile.Result{Requeue: true}, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.r.Reconcile(tc.args.req)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.res, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type countingRateLimiter struct {
+	predictableRateLimiter
+	calls int
+}
+
+func (r *countingRateLimiter) When(item interface{}) time.Duration {
+	r.calls++
+	return r.predictableRateLimiter.When(item)
+}
+
+func TestReconcileRequeued(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}
+	now := time.Now()
+	reconciled := 0
+
+	rl := &countingRateLimiter{predictableRateLimiter: predictableRateLimiter{d: 8 * time.Second}}
+	r := NewReconciler(reconcile.Func(func(_ reconcile.Request) (reconcile.Result, error) {
+		reconciled++
+		return reconcile.Result{}, nil
+	}), rl)
+	r.now = func() time.Time { return now }
+
+	// A request that is requeued before its reservation expires should wait
+	// only for the remainder of its reservation, without reserving again.
+	for i, want := range []time.Duration{8 * time.Second, 5 * time.Second, 2 * time.Second} {
+		got, err := r.Reconcile(req)
+		if err != nil {
+			t.Fatalf("r.Reconcile(...) requeue %d: %s", i, err)
+		}
+		if diff := cmp.Diff(reconcile.Result{RequeueAfter: want}, got); diff != "" {
+			t.Errorf("r.Reconcile(...) requeue %d: -want, +got:\n%s", i, diff)
+		}
+		now = now.Add(3 * time.Second)
+	}
+	if rl.calls != 1 {
+		t.Errorf("r.Reconcile(...): want 1 reservation, got %d", rl.calls)
+	}
+
+	// A request that is requeued once its reservation expires should be
+	// passed to the inner Reconciler.
+	if _, err := r.Reconcile(req); err != nil {
+		t.Fatalf("r.Reconcile(...): %s", err)
+	}
+	if reconciled != 1 {
+		t.Errorf("r.Reconcile(...): want 1 inner reconcile, got %d", reconciled)
+	}
+	if rl.calls != 1 {
+		t.Errorf("r.Reconcile(...): want 1 reservation, got %d", rl.calls)
+	}
+}
+
+func TestNewGlobal(t *testing.T) {
+	var rl workqueue.RateLimiter = NewGlobal(1)
+
+	// The bucket should allow a burst of ten requests before rate limiting.
+	for i := 0; i < 10; i++ {
+		if d := rl.When("item"); d != 0 {
+			t.Fatalf("rl.When(...) request %d: want no delay, got %s", i, d)
+		}
+	}
+	if d := rl.When("item"); d == 0 {
+		t.Errorf("rl.When(...): want delay once the burst is exhausted, got none")
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rbac generates the RBAC rules required by a provider's controllers,
+// so that providers may ship accurate, minimal ClusterRoles rather than
+// granting wildcard permissions.
+package rbac
+
+import (
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errMapKindFmt = "cannot determine resource for kind %s"
+)
+
+// Verbs.
+const (
+	VerbGet    = "get"
+	VerbList   = "list"
+	VerbWatch  = "watch"
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbPatch  = "patch"
+	VerbDelete = "delete"
+)
+
+// Subresources.
+const (
+	SubresourceStatus     = "status"
+	SubresourceFinalizers = "finalizers"
+)
+
+var (
+	// VerbsRead are the verbs required to read and watch a kind of resource.
+	VerbsRead = []string{VerbGet, VerbList, VerbWatch}
+
+	// VerbsReconcile are the verbs required to reconcile a kind of resource
+	// that is created by something other than its controller, for example a
+	// managed resource.
+	VerbsReconcile = []string{VerbGet, VerbList, VerbWatch, VerbUpdate, VerbPatch}
+
+	// VerbsEdit are the verbs required to fully manage the lifecycle of a
+	// kind of resource.
+	VerbsEdit = []string{VerbGet, VerbList, VerbWatch, VerbCreate, VerbUpdate, VerbPatch, VerbDelete}
+)
+
+// A grant of verbs for either a kind, or an already known group and
+// resource.
+type grant struct {
+	kind        *schema.GroupVersionKind
+	resource    schema.GroupResource
+	subresource string
+	verbs       []string
+}
+
+// Rules accumulates the access required by a provider's controllers.
+type Rules struct {
+	mapper meta.RESTMapper
+	grants []grant
+}
+
+// An Option configures the access required by a provider's controllers.
+type Option func(r *Rules)
+
+// WithRESTMapper determines the resource corresponding to each kind using the
+// supplied RESTMapper. By default the resource is guessed by lowercasing and
+// pluralising the kind, which is correct for the vast majority of kinds.
+func WithRESTMapper(m meta.RESTMapper) Option {
+	return func(r *Rules) {
+		r.mapper = m
+	}
+}
+
+// Allow the supplied verbs on the supplied kinds of resource.
+func Allow(verbs []string, kinds ...schema.GroupVersionKind) Option {
+	return func(r *Rules) {
+		for i := range kinds {
+			r.grants = append(r.grants, grant{kind: &kinds[i], verbs: verbs})
+		}
+	}
+}
+
+// AllowSubresource allows the supplied verbs on the supplied subresource of
+// the supplied kinds of resource.
+func AllowSubresource(subresource string, verbs []string, kinds ...schema.GroupVersionKind) Option {
+	return func(r *Rules) {
+		for i := range kinds {
+			r.grants = append(r.grants, grant{kind: &kinds[i], subresource: subresource, verbs: verbs})
+		}
+	}
+}
+
+// AllowResource allows the supplied verbs on the supplied group and resource.
+func AllowResource(verbs []string, group, resource string) Option {
+	return func(r *Rules) {
+		r.grants = append(r.grants, grant{resource: schema.GroupResource{Group: group, Resource: resource}, verbs: verbs})
+	}
+}
+
+// Reads allows the supplied kinds of resource to be read and watched, for
+// example a provider's ProviderConfig.
+func Reads(kinds ...schema.GroupVersionKind) Option {
+	return Allow(VerbsRead, kinds...)
+}
+
+// Edits allows the full lifecycle of the supplied kinds of resource to be
+// managed.
+func Edits(kinds ...schema.GroupVersionKind) Option {
+	return Allow(VerbsEdit, kinds...)
+}
+
+// Manages allows the supplied kinds of managed resource to be reconciled. A
+// managed resource reconciler must be able to update a managed resource and
+// its status, and to update its finalizers subresource in order to set it as
+// the blocking owner of its connection secret.
+func Manages(kinds ...schema.GroupVersionKind) Option {
+	return func(r *Rules) {
+		Allow(VerbsReconcile, kinds...)(r)
+		AllowSubresource(SubresourceStatus, []string{VerbGet, VerbUpdate, VerbPatch}, kinds...)(r)
+		AllowSubresource(SubresourceFinalizers, []string{VerbUpdate}, kinds...)(r)
+	}
+}
+
+// Runtime allows the access required by the runtime itself, regardless of
+// which kinds of resource a provider manages. This includes publishing
+// connection secrets and non-sensitive connection details, recording events,
+// and acquiring a leader election lock, which may be either a ConfigMap or a
+// Lease.
+func Runtime() Option {
+	return func(r *Rules) {
+		AllowResource(VerbsEdit, "", "secrets")(r)
+		AllowResource(VerbsEdit, "", "configmaps")(r)
+		AllowResource([]string{VerbCreate, VerbUpdate, VerbPatch}, "", "events")(r)
+		AllowResource([]string{VerbGet, VerbCreate, VerbUpdate}, "coordination.k8s.io", "leases")(r)
+	}
+}
+
+// PolicyRules renders the minimal RBAC policy rules required by a provider's
+// controllers. Rules are deterministically ordered; resources in the same API
+// group that require the same verbs are combined into a single rule.
+func PolicyRules(o ...Option) ([]rbacv1.PolicyRule, error) {
+	r := &Rules{}
+	for _, fn := range o {
+		fn(r)
+	}
+
+	// Union the verbs required for each resource.
+	verbs := map[schema.GroupResource]map[string]bool{}
+	for _, g := range r.grants {
+		gr, err := r.groupResource(g)
+		if err != nil {
+			return nil, err
+		}
+		if verbs[gr] == nil {
+			verbs[gr] = map[string]bool{}
+		}
+		for _, v := range g.verbs {
+			verbs[gr][v] = true
+		}
+	}
+
+	// Combine resources in the same API group that require the same verbs.
+	type key struct {
+		group string
+		verbs string
+	}
+	resources := map[key][]string{}
+	for gr, vs := range verbs {
+		k := key{group: gr.Group, verbs: strings.Join(sorted(vs), ",")}
+		resources[k] = append(resources[k], gr.Resource)
+	}
+
+	keys := make([]key, 0, len(resources))
+	for k := range resources {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].verbs < keys[j].verbs
+	})
+
+	rules := make([]rbacv1.PolicyRule, 0, len(keys))
+	for _, k := range keys {
+		res := resources[k]
+		sort.Strings(res)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{k.group},
+			Resources: res,
+			Verbs:     strings.Split(k.verbs, ","),
+		})
+	}
+	return rules, nil
+}
+
+// ClusterRole renders a ClusterRole with the supplied name that grants the
+// minimal RBAC policy rules required by a provider's controllers.
+func ClusterRole(name string, o ...Option) (*rbacv1.ClusterRole, error) {
+	rules, err := PolicyRules(o...)
+	if err != nil {
+		return nil, err
+	}
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules:      rules,
+	}, nil
+}
+
+func (r *Rules) groupResource(g grant) (schema.GroupResource, error) {
+	gr := g.resource
+	if g.kind != nil {
+		var err error
+		if gr, err = r.resourceFor(*g.kind); err != nil {
+			return schema.GroupResource{}, errors.Wrapf(err, errMapKindFmt, g.kind)
+		}
+	}
+	if g.subresource != "" {
+		gr.Resource = gr.Resource + "/" + g.subresource
+	}
+	return gr, nil
+}
+
+func (r *Rules) resourceFor(gvk schema.GroupVersionKind) (schema.GroupResource, error) {
+	if r.mapper == nil {
+		plural, _ := meta.UnsafeGuessKindToResource(gvk)
+		return plural.GroupResource(), nil
+	}
+	m, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupResource{}, err
+	}
+	return m.Resource.GroupResource(), nil
+}
+
+func sorted(set map[string]bool) []string {
+	s := make([]string, 0, len(set))
+	for v := range set {
+		s = append(s, v)
+	}
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	bucket  = schema.GroupVersionKind{Group: "storage.example.org", Version: "v1alpha1", Kind: "Bucket"}
+	policy  = schema.GroupVersionKind{Group: "storage.example.org", Version: "v1alpha1", Kind: "BucketPolicy"}
+	pc      = schema.GroupVersionKind{Group: "example.org", Version: "v1alpha1", Kind: "ProviderConfig"}
+	network = schema.GroupVersionKind{Group: "network.example.org", Version: "v1alpha1", Kind: "Network"}
+)
+
+func TestPolicyRules(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.AddSpecific(network,
+		schema.GroupVersionResource{Group: network.Group, Version: network.Version, Resource: "networkz"},
+		schema.GroupVersionResource{Group: network.Group, Version: network.Version, Resource: "network"},
+		meta.RESTScopeRoot)
+
+	type want struct {
+		rules []rbacv1.PolicyRule
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []Option
+		want   want
+	}{
+		"NoOptions": {
+			reason: "No rules should be rendered when no access is required.",
+			want:   want{rules: []rbacv1.PolicyRule{}},
+		},
+		"Runtime": {
+			reason: "The runtime's own rules should be rendered, combining resources that require the same verbs.",
+			o:      []Option{Runtime()},
+			want: want{rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps", "secrets"}, Verbs: []string{"create", "delete", "get", "list", "patch", "update", "watch"}},
+				{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"create", "patch", "update"}},
+				{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{"leases"}, Verbs: []string{"create", "get", "update"}},
+			}},
+		},
+		"Provider": {
+			reason: "Managed kinds should be reconcilable, and other kinds should be granted only the access they require.",
+			o:      []Option{Manages(bucket, policy), Reads(pc)},
+			want: want{rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"example.org"}, Resources: []string{"providerconfigs"}, Verbs: []string{"get", "list", "watch"}},
+				{APIGroups: []string{"storage.example.org"}, Resources: []string{"bucketpolicies", "buckets"}, Verbs: []string{"get", "list", "patch", "update", "watch"}},
+				{APIGroups: []string{"storage.example.org"}, Resources: []string{"bucketpolicies/status", "buckets/status"}, Verbs: []string{"get", "patch", "update"}},
+				{APIGroups: []string{"storage.example.org"}, Resources: []string{"bucketpolicies/finalizers", "buckets/finalizers"}, Verbs: []string{"update"}},
+			}},
+		},
+		"UnionVerbs": {
+			reason: "The verbs required by multiple grants on the same resource should be combined.",
+			o:      []Option{Reads(bucket), Allow([]string{VerbCreate}, bucket), AllowResource([]string{VerbDelete}, bucket.Group, "buckets")},
+			want: want{rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"storage.example.org"}, Resources: []string{"buckets"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+			}},
+		},
+		"RESTMapper": {
+			reason: "Resources should be determined using the supplied RESTMapper, regardless of the order of options.",
+			o:      []Option{Edits(network), WithRESTMapper(mapper)},
+			want: want{rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"network.example.org"}, Resources: []string{"networkz"}, Verbs: []string{"create", "delete", "get", "list", "patch", "update", "watch"}},
+			}},
+		},
+		"RESTMapperError": {
+			reason: "Errors determining the resource for a kind should be returned.",
+			o:      []Option{WithRESTMapper(mapper), Reads(bucket)},
+			want:   want{err: errors.Wrapf(&meta.NoKindMatchError{GroupKind: bucket.GroupKind(), SearchedVersions: []string{bucket.Version}}, errMapKindFmt, &bucket)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := PolicyRules(tc.o...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPolicyRules(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rules, got); diff != "" {
+				t.Errorf("\n%s\nPolicyRules(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClusterRole(t *testing.T) {
+	want := &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-provider"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"example.org"}, Resources: []string{"providerconfigs"}, Verbs: []string{"get", "list", "watch"}},
+		},
+	}
+	got, err := ClusterRole("cool-provider", Reads(pc))
+	if err != nil {
+		t.Fatalf("ClusterRole(...): %s", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ClusterRole(...): -want, +got:\n%s", diff)
+	}
+}
@@ -19,11 +19,11 @@ package claimbinding
 import (
 	"context"
 
-	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
@@ -51,19 +51,28 @@ func NewAPIManagedCreator(c client.Client, t runtime.ObjectTyper) *APIManagedCre
 
 // Create the supplied resource using the supplied class and claim.
 func (a *APIManagedCreator) Create(ctx context.Context, cm resource.Claim, cs resource.Class, mg resource.Managed) error {
-	cmr := meta.ReferenceTo(cm, resource.MustGetKind(cm, a.typer))
-	csr := meta.ReferenceTo(cs, resource.MustGetKind(cs, a.typer))
+	cmk, err := resource.GetKind(cm, a.typer)
+	if err != nil {
+		return err
+	}
+	csk, err := resource.GetKind(cs, a.typer)
+	if err != nil {
+		return err
+	}
+	mgk, err := resource.GetKind(mg, a.typer)
+	if err != nil {
+		return err
+	}
 
-	mg.SetClaimReference(cmr)
-	mg.SetClassReference(csr)
+	mg.SetClaimReference(meta.ReferenceTo(cm, cmk))
+	mg.SetClassReference(meta.ReferenceTo(cs, csk))
 	if err := a.client.Create(ctx, mg); err != nil {
 		return errors.Wrap(err, errCreateManaged)
 	}
 	// Since we use GenerateName feature of ObjectMeta, final name of the
 	// resource is calculated during the creation of the resource. So, we
 	// can generate a complete reference only after the creation.
-	mgr := meta.ReferenceTo(mg, resource.MustGetKind(mg, a.typer))
-	cm.SetResourceReference(mgr)
+	cm.SetResourceReference(meta.ReferenceTo(mg, mgk))
 
 	return errors.Wrap(a.client.Update(ctx, cm), errUpdateClaim)
 }
@@ -88,8 +97,11 @@ func (a *APIBinder) Bind(ctx context.Context, cm resource.Claim, mg resource.Man
 	// This claim reference will already be set for dynamically provisioned
 	// managed resources, but we need to make sure it's set for statically
 	// provisioned resources too.
-	cmr := meta.ReferenceTo(cm, resource.MustGetKind(cm, a.typer))
-	mg.SetClaimReference(cmr)
+	cmk, err := resource.GetKind(cm, a.typer)
+	if err != nil {
+		return err
+	}
+	mg.SetClaimReference(meta.ReferenceTo(cm, cmk))
 	mg.SetBindingPhase(v1alpha1.BindingPhaseBound)
 	if err := a.client.Update(ctx, mg); err != nil {
 		return errors.Wrap(err, errUpdateManaged)
@@ -146,8 +158,11 @@ func (a *APIStatusBinder) Bind(ctx context.Context, cm resource.Claim, mg resour
 	// This claim reference will already be set for dynamically provisioned
 	// managed resources, but we need to make sure it's set for statically
 	// provisioned resources too.
-	cmr := meta.ReferenceTo(cm, resource.MustGetKind(cm, a.typer))
-	mg.SetClaimReference(cmr)
+	cmk, err := resource.GetKind(cm, a.typer)
+	if err != nil {
+		return err
+	}
+	mg.SetClaimReference(meta.ReferenceTo(cm, cmk))
 	if err := a.client.Update(ctx, mg); err != nil {
 		return errors.Wrap(err, errUpdateManaged)
 	}
@@ -21,13 +21,13 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -58,12 +58,26 @@ func TestCreate(t *testing.T) {
 	csname := "coolclass"
 	mgname := "coolmanaged"
 	errBoom := errors.New("boom")
+	_, errGetKind := resource.GetKind(&fake.Claim{}, runtime.NewScheme())
 
 	cases := map[string]struct {
 		fields fields
 		args   args
 		want   error
 	}{
+		"GetKindError": {
+			fields: fields{
+				client: &test.MockClient{},
+				typer:  runtime.NewScheme(),
+			},
+			args: args{
+				ctx: context.Background(),
+				cm:  &fake.Claim{},
+				cs:  &fake.Class{},
+				mg:  &fake.Managed{},
+			},
+			want: errGetKind,
+		},
 		"CreateManagedError": {
 			fields: fields{
 				client: &test.MockClient{
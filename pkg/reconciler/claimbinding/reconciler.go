@@ -21,7 +21,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +30,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -231,7 +232,10 @@ func WithManagedCreator(c ManagedCreator) ReconcilerOption {
 }
 
 // WithManagedConnectionPropagator specifies which ManagedConnectionPropagator
-// should be used to propagate resource connection details to their claim.
+// should be used to propagate resource connection details to their claim. An
+// APIManagedConnectionPropagator is used by default. Alternative strategies,
+// for example propagating to an external secret store, may be plugged in, and
+// combined using a resource.ManagedConnectionPropagatorChain.
 func WithManagedConnectionPropagator(p resource.ManagedConnectionPropagator) ReconcilerOption {
 	return func(r *Reconciler) {
 		r.managed.ManagedConnectionPropagator = p
@@ -268,6 +272,19 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithOptions configures the Reconciler using the supplied controller Options.
+// Its Logger and Recorder are used unless they are nil.
+func WithOptions(o controller.Options) ReconcilerOption {
+	return func(r *Reconciler) {
+		if o.Logger != nil {
+			r.log = o.Logger
+		}
+		if o.Recorder != nil {
+			r.record = o.Recorder
+		}
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles resource claims
 // of the supplied ClaimKind with resources of the supplied ManagedKind. It
 // panics if asked to reconcile a claim or resource kind that is not registered
@@ -17,21 +17,44 @@ limitations under the License.
 package managed
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 
-	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/envelope"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
 
+// AnnotationKeyConnectionDetailsChecksum is the key of the connection secret
+// annotation that records a checksum of the ConnectionDetails most recently
+// published to it. The checksum is used only to detect whether the details
+// being published have changed since they were last published. It is an
+// unkeyed hash, so the secret's data is also compared to the details being
+// published before a write is skipped.
+const AnnotationKeyConnectionDetailsChecksum = "crossplane.io/connection-details-checksum"
+
 // Error strings.
 const (
-	errCreateOrUpdateSecret = "cannot create or update connection secret"
-	errUpdateManaged        = "cannot update managed resource"
-	errUpdateManagedStatus  = "cannot update managed resource status"
+	errCreateOrUpdateSecret      = "cannot create or update connection secret"
+	errCreateOrUpdateConfigMap   = "cannot create or update connection config map"
+	errChecksumConnectionDetails = "cannot compute checksum of connection details"
+	errEncryptSecret             = "cannot encrypt connection secret"
+	errGetSecret                 = "cannot get connection secret"
+	errUpdateManaged             = "cannot update managed resource"
+	errPatchManaged              = "cannot patch managed resource"
+	errUpdateManagedStatus       = "cannot update managed resource status"
 )
 
 // An APIFinalizer adds and removes finalizers to and from a resource.
@@ -60,6 +83,83 @@ func (a *APIFinalizer) RemoveFinalizer(ctx context.Context, mg resource.Managed)
 	return errors.Wrap(resource.IgnoreNotFound(a.client.Update(ctx, mg)), errUpdateManaged)
 }
 
+// An APIPatchingFinalizer adds and removes finalizers to and from a resource
+// using JSON patches that target only its finalizers. Unlike an APIFinalizer
+// it neither conflicts with nor overwrites concurrent changes to the rest of
+// the resource.
+type APIPatchingFinalizer struct {
+	client    client.Client
+	finalizer string
+}
+
+// NewAPIPatchingFinalizer returns a new APIPatchingFinalizer.
+func NewAPIPatchingFinalizer(c client.Client, finalizer string) *APIPatchingFinalizer {
+	return &APIPatchingFinalizer{client: c, finalizer: finalizer}
+}
+
+// A jsonPatchOperation is a single RFC 6902 JSON patch operation.
+type jsonPatchOperation struct {
+	Operation string      `json:"op"`
+	Path      string      `json:"path"`
+	Value     interface{} `json:"value,omitempty"`
+}
+
+// AddFinalizer to the supplied Managed resource.
+func (a *APIPatchingFinalizer) AddFinalizer(ctx context.Context, mg resource.Managed) error {
+	if meta.FinalizerExists(mg, a.finalizer) {
+		return nil
+	}
+
+	// We can append to existing finalizers without disturbing them, but we
+	// must create the finalizers array if there are none. We only do so if
+	// the resource is unchanged, lest we overwrite finalizers that were
+	// added since we read it.
+	ops := []jsonPatchOperation{{Operation: "add", Path: "/metadata/finalizers/-", Value: a.finalizer}}
+	if len(mg.GetFinalizers()) == 0 {
+		ops = []jsonPatchOperation{
+			{Operation: "test", Path: "/metadata/resourceVersion", Value: mg.GetResourceVersion()},
+			{Operation: "add", Path: "/metadata/finalizers", Value: []string{a.finalizer}},
+		}
+	}
+
+	meta.AddFinalizer(mg, a.finalizer)
+	return errors.Wrap(a.patch(ctx, mg, ops), errPatchManaged)
+}
+
+// RemoveFinalizer from the supplied Managed resource.
+func (a *APIPatchingFinalizer) RemoveFinalizer(ctx context.Context, mg resource.Managed) error {
+	// We test that the finalizer is still where we expect it to be before
+	// removing it, in case finalizers were added or removed since we read
+	// the resource.
+	ops := make([]jsonPatchOperation, 0)
+	keep := make([]string, 0)
+	for _, f := range mg.GetFinalizers() {
+		if f != a.finalizer {
+			keep = append(keep, f)
+			continue
+		}
+		path := fmt.Sprintf("/metadata/finalizers/%d", len(keep))
+		ops = append(ops,
+			jsonPatchOperation{Operation: "test", Path: path, Value: f},
+			jsonPatchOperation{Operation: "remove", Path: path},
+		)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	mg.SetFinalizers(keep)
+	return errors.Wrap(resource.IgnoreNotFound(a.patch(ctx, mg, ops)), errPatchManaged)
+}
+
+func (a *APIPatchingFinalizer) patch(ctx context.Context, mg resource.Managed, ops []jsonPatchOperation) error {
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	return a.client.Patch(ctx, mg, client.ConstantPatch(types.JSONPatchType, data))
+}
+
 // NameAsExternalName writes the name of the managed resource to
 // the external name annotation field in order to be used as name of
 // the external resource in provider.
@@ -79,34 +179,311 @@ func (a *NameAsExternalName) Initialize(ctx context.Context, mg resource.Managed
 	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
 }
 
+// An APIExternalNameRecorder records the external name of a managed resource
+// by annotating it in the Kubernetes API server.
+type APIExternalNameRecorder struct{ client client.Client }
+
+// NewAPIExternalNameRecorder returns a new APIExternalNameRecorder.
+func NewAPIExternalNameRecorder(c client.Client) *APIExternalNameRecorder {
+	return &APIExternalNameRecorder{client: c}
+}
+
+// RecordExternalName of the supplied managed resource, unless it has no
+// external name or its external name is already recorded.
+func (a *APIExternalNameRecorder) RecordExternalName(ctx context.Context, mg resource.Managed) error {
+	n := meta.GetExternalName(mg)
+	if n == "" || n == meta.GetLastExternalName(mg) {
+		return nil
+	}
+	meta.SetLastExternalName(mg, n)
+	return errors.Wrap(a.client.Update(ctx, mg), errUpdateManaged)
+}
+
 // An APISecretPublisher publishes ConnectionDetails by submitting a Secret to a
 // Kubernetes API server.
 type APISecretPublisher struct {
-	secret resource.Applicator
-	typer  runtime.ObjectTyper
+	client    client.Reader
+	secret    resource.Applicator
+	configMap resource.Applicator
+	typer     runtime.ObjectTyper
+
+	nonSensitive map[string]bool
+	options      []resource.ConnectionSecretOption
+	encrypter    SecretEncrypter
+}
+
+// A SecretEncrypter encrypts the data of a connection secret before it is
+// published, for example using an envelope.Encrypter.
+type SecretEncrypter interface {
+	// EncryptSecret replaces the data of the supplied secret with its
+	// encrypted data. The secret's data may be shared with the connection
+	// details being published, so it must be replaced rather than modified.
+	// The secret carries the envelope.AnnotationKeyDataKey annotation of the
+	// existing secret it will be merged with, if any, and must be encrypted
+	// using that data key.
+	EncryptSecret(ctx context.Context, s *corev1.Secret) error
+}
+
+// A SecretDecrypter decrypts the data of a connection secret that was
+// encrypted by a SecretEncrypter. A SecretEncrypter that is also a
+// SecretDecrypter, such as an envelope.Encrypter, allows the publisher to
+// determine whether an encrypted secret still contains the connection details
+// it published.
+type SecretDecrypter interface {
+	// DecryptSecret replaces the data of the supplied secret with its
+	// decrypted data.
+	DecryptSecret(ctx context.Context, s *corev1.Secret) error
+}
+
+// A SecretEncrypterFn is a function that satisfies the SecretEncrypter
+// interface.
+type SecretEncrypterFn func(ctx context.Context, s *corev1.Secret) error
+
+// EncryptSecret replaces the data of the supplied secret with its encrypted
+// data.
+func (fn SecretEncrypterFn) EncryptSecret(ctx context.Context, s *corev1.Secret) error {
+	return fn(ctx, s)
+}
+
+// An APISecretPublisherOption configures an APISecretPublisher.
+type APISecretPublisherOption func(*APISecretPublisher)
+
+// WithSecretReader specifies the reader used to determine whether connection
+// details have already been published to a secret. Secrets are read using the
+// publisher's client by default. Supply a resource.CachedReader to read
+// secrets from a cache rather than from the API server.
+func WithSecretReader(r client.Reader) APISecretPublisherOption {
+	return func(a *APISecretPublisher) {
+		a.client = r
+	}
+}
+
+// WithNonSensitiveKeys specifies connection details that are not sensitive,
+// for example hosts, ports, and endpoints. These details are published to a
+// ConfigMap with the same namespace and name as the connection secret, rather
+// than to the secret, so that consumers that need only these details do not
+// need permission to read secrets.
+func WithNonSensitiveKeys(keys ...string) APISecretPublisherOption {
+	return func(a *APISecretPublisher) {
+		for _, k := range keys {
+			a.nonSensitive[k] = true
+		}
+	}
+}
+
+// WithConnectionSecretOptions specifies options used to configure published
+// connection secrets, for example to add labels or set their type.
+func WithConnectionSecretOptions(so ...resource.ConnectionSecretOption) APISecretPublisherOption {
+	return func(a *APISecretPublisher) {
+		a.options = append(a.options, so...)
+	}
+}
+
+// WithSecretEncrypter specifies how connection secrets should be encrypted
+// before they are published, for environments in which etcd encryption at rest
+// is not trusted. Connection secrets are not encrypted by default. Details
+// published to a ConfigMap by WithNonSensitiveKeys are never encrypted.
+func WithSecretEncrypter(e SecretEncrypter) APISecretPublisherOption {
+	return func(a *APISecretPublisher) {
+		a.encrypter = e
+	}
 }
 
 // NewAPISecretPublisher returns a new APISecretPublisher.
-func NewAPISecretPublisher(c client.Client, ot runtime.ObjectTyper) *APISecretPublisher {
+func NewAPISecretPublisher(c client.Client, ot runtime.ObjectTyper, o ...APISecretPublisherOption) *APISecretPublisher {
 	// NOTE(negz): We transparently inject an APIPatchingApplicator in order to maintain
 	// backward compatibility with the original API of this function.
-	return &APISecretPublisher{secret: resource.NewAPIPatchingApplicator(c), typer: ot}
+	a := &APISecretPublisher{
+		client:       c,
+		secret:       resource.NewAPIPatchingApplicator(c),
+		configMap:    resource.NewAPIPatchingApplicator(c),
+		typer:        ot,
+		nonSensitive: map[string]bool{},
+	}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // PublishConnection publishes the supplied ConnectionDetails to a Secret in the
-// same namespace as the supplied Managed resource. It is a no-op if the secret
-// already exists with the supplied ConnectionDetails.
+// same namespace as the supplied Managed resource. Any non-sensitive details
+// are published to a ConfigMap instead. It is a no-op if the supplied
+// ConnectionDetails have not changed since they were last published to the
+// secret, according to its checksum annotation, and the secret still contains
+// them.
 func (a *APISecretPublisher) PublishConnection(ctx context.Context, mg resource.Managed, c ConnectionDetails) error {
 	// This resource does not want to expose a connection secret.
 	if mg.GetWriteConnectionSecretToReference() == nil {
 		return nil
 	}
 
-	s := resource.ConnectionSecretFor(mg, resource.MustGetKind(mg, a.typer))
+	kind, err := resource.GetKind(mg, a.typer)
+	if err != nil {
+		return err
+	}
+	s := resource.ConnectionSecretFor(mg, kind, a.options...)
 	s.Data = c
+
+	sum, err := checksum(c)
+	if err != nil {
+		return errors.Wrap(err, errChecksumConnectionDetails)
+	}
+	meta.AddAnnotations(s, map[string]string{AnnotationKeyConnectionDetailsChecksum: sum})
+
+	// Connection details rarely change once a managed resource is ready, so
+	// we avoid writing the secret on every reconcile if we have already
+	// published these exact details to it. Our client is typically backed by
+	// a cache, so this read is cheap.
+	if a.published(ctx, s, mg.GetUID(), sum) {
+		return nil
+	}
+
+	// We publish our non-sensitive details before our secret, which records
+	// the checksum of all of our details, so that we'll try again if we
+	// cannot publish them.
+	if err := a.publishNonSensitive(ctx, mg, kind, s, c); err != nil {
+		return err
+	}
+
+	if a.encrypter != nil {
+		if err := a.encrypt(ctx, s); err != nil {
+			return err
+		}
+	}
+
 	return errors.Wrap(a.secret.Apply(ctx, s, resource.ConnectionSecretMustBeControllableBy(mg.GetUID())), errCreateOrUpdateSecret)
 }
 
+// publishNonSensitive publishes any non-sensitive details to a ConfigMap, and
+// removes them from the supplied secret.
+func (a *APISecretPublisher) publishNonSensitive(ctx context.Context, mg resource.Managed, kind schema.GroupVersionKind, s *corev1.Secret, c ConnectionDetails) error {
+	if len(a.nonSensitive) == 0 {
+		return nil
+	}
+	cm := resource.ConnectionConfigMapFor(mg, kind)
+	s.Data = make(map[string][]byte, len(c))
+	for k, v := range c {
+		if !a.nonSensitive[k] {
+			s.Data[k] = v
+			continue
+		}
+		if v != nil {
+			cm.Data[k] = string(v)
+		}
+	}
+	return errors.Wrap(a.configMap.Apply(ctx, cm, resource.MustBeControllableBy(mg.GetUID())), errCreateOrUpdateConfigMap)
+}
+
+// encrypt encrypts the data of the supplied secret. Our secret is merged with
+// any existing secret when it is applied, so we reuse the existing secret's
+// data key in order that its existing values remain readable. If the existing
+// secret is not yet encrypted we encrypt its existing values along with ours.
+func (a *APISecretPublisher) encrypt(ctx context.Context, s *corev1.Secret) error {
+	current := &corev1.Secret{}
+	if a.client != nil {
+		err := a.client.Get(ctx, types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}, current)
+		if resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errGetSecret)
+		}
+	}
+
+	// Our secret's data may be shared with the connection details being
+	// published, so we must replace rather than modify it.
+	data := make(map[string][]byte, len(s.Data)+len(current.Data))
+	if !envelope.IsEncrypted(current) {
+		for k, v := range current.Data {
+			data[k] = v
+		}
+	}
+	for k, v := range s.Data {
+		data[k] = v
+	}
+	s.Data = data
+
+	if envelope.IsEncrypted(current) {
+		meta.AddAnnotations(s, map[string]string{envelope.AnnotationKeyDataKey: current.GetAnnotations()[envelope.AnnotationKeyDataKey]})
+	}
+	return errors.Wrap(a.encrypter.EncryptSecret(ctx, s), errEncryptSecret)
+}
+
+// published returns true if the supplied connection secret already exists,
+// is controlled by the supplied UID, has the supplied checksum annotation, has
+// the type, labels, and annotations of the supplied secret, and contains its
+// sensitive data. Secret data that was edited since it was published is thus
+// restored, even if its checksum annotation was not edited.
+func (a *APISecretPublisher) published(ctx context.Context, s *corev1.Secret, u types.UID, sum string) bool {
+	if a.client == nil {
+		return false
+	}
+	current := &corev1.Secret{}
+	if err := a.client.Get(ctx, types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}, current); err != nil {
+		return false
+	}
+	if c := metav1.GetControllerOf(current); c == nil || c.UID != u {
+		return false
+	}
+	return current.GetAnnotations()[AnnotationKeyConnectionDetailsChecksum] == sum &&
+		current.Type == s.Type &&
+		contains(current.GetLabels(), s.GetLabels()) &&
+		contains(current.GetAnnotations(), s.GetAnnotations()) &&
+		a.containsData(ctx, current, s.Data)
+}
+
+// containsData returns true if the supplied current secret contains the
+// supplied data, ignoring any non-sensitive keys that are published to a
+// ConfigMap. Nil values must not exist in the current secret. Encrypted
+// secrets can only be compared if our SecretEncrypter is also a
+// SecretDecrypter.
+func (a *APISecretPublisher) containsData(ctx context.Context, current *corev1.Secret, data map[string][]byte) bool {
+	if envelope.IsEncrypted(current) {
+		d, ok := a.encrypter.(SecretDecrypter)
+		if !ok {
+			return false
+		}
+		current = current.DeepCopy()
+		if err := d.DecryptSecret(ctx, current); err != nil {
+			return false
+		}
+	}
+	for k, v := range data {
+		if a.nonSensitive[k] {
+			continue
+		}
+		cv, ok := current.Data[k]
+		if v == nil && ok {
+			return false
+		}
+		if v != nil && (!ok || !bytes.Equal(cv, v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains returns true if all of the key value pairs in sub exist in m.
+func contains(m, sub map[string]string) bool {
+	for k, v := range sub {
+		if cv, ok := m[k]; !ok || cv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// checksum returns a SHA-256 checksum of the supplied ConnectionDetails. JSON
+// objects are marshalled with sorted keys, so the checksum is stable. Nil
+// values, which delete a key from a connection secret, are distinct from empty
+// values.
+func checksum(c ConnectionDetails) (string, error) {
+	j, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(j)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // UnpublishConnection is no-op since PublishConnection only creates resources
 // that will be garbage collected by Kubernetes when the managed resource is
 // deleted.
@@ -17,16 +17,22 @@ limitations under the License.
 package managed
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/envelope"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -35,6 +41,7 @@ import (
 
 var (
 	_ Finalizer   = &APIFinalizer{}
+	_ Finalizer   = &APIPatchingFinalizer{}
 	_ Initializer = &NameAsExternalName{}
 )
 
@@ -154,6 +161,146 @@ func TestAPIFinalizerAdder(t *testing.T) {
 	}
 }
 
+func TestAPIPatchingFinalizer(t *testing.T) {
+	finalizer := "veryfinal"
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		err   error
+		mg    resource.Managed
+		patch string
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		remove bool
+		args   args
+		want   want
+	}{
+		"AddExists": {
+			reason: "We should not patch the managed resource if the finalizer already exists.",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+			},
+		},
+		"AddFirst": {
+			reason: "We should create the finalizers array, but only if the resource has not changed.",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"}},
+			},
+			want: want{
+				mg:    &fake.Managed{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1", Finalizers: []string{finalizer}}},
+				patch: `[{"op":"test","path":"/metadata/resourceVersion","value":"1"},{"op":"add","path":"/metadata/finalizers","value":["veryfinal"]}]`,
+			},
+		},
+		"AddAppend": {
+			reason: "We should append to existing finalizers.",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+			want: want{
+				mg:    &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other", finalizer}}},
+				patch: `[{"op":"add","path":"/metadata/finalizers/-","value":"veryfinal"}]`,
+			},
+		},
+		"AddPatchError": {
+			reason: "Errors patching the managed resource should be returned.",
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+			want: want{
+				err:   errors.Wrap(errBoom, errPatchManaged),
+				mg:    &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other", finalizer}}},
+				patch: `[{"op":"add","path":"/metadata/finalizers/-","value":"veryfinal"}]`,
+			},
+		},
+		"RemoveNotExists": {
+			reason: "We should not patch the managed resource if the finalizer does not exist.",
+			remove: true,
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+			},
+		},
+		"Remove": {
+			reason: "We should remove every instance of the finalizer, testing that each is where we expect.",
+			remove: true,
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer, "other", finalizer}}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"other"}}},
+				patch: `[{"op":"test","path":"/metadata/finalizers/0","value":"veryfinal"},{"op":"remove","path":"/metadata/finalizers/0"},` +
+					`{"op":"test","path":"/metadata/finalizers/1","value":"veryfinal"},{"op":"remove","path":"/metadata/finalizers/1"}]`,
+			},
+		},
+		"RemovePatchError": {
+			reason: "Errors patching the managed resource should be returned.",
+			remove: true,
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{finalizer}}},
+			},
+			want: want{
+				err:   errors.Wrap(errBoom, errPatchManaged),
+				mg:    &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{}}},
+				patch: `[{"op":"test","path":"/metadata/finalizers/0","value":"veryfinal"},{"op":"remove","path":"/metadata/finalizers/0"}]`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			patched := ""
+			c := &test.MockClient{MockPatch: func(_ context.Context, obj runtime.Object, p client.Patch, _ ...client.PatchOption) error {
+				if p.Type() != types.JSONPatchType {
+					t.Errorf("\n%s\nPatch(...): want patch type %s, got %s", tc.reason, types.JSONPatchType, p.Type())
+				}
+				data, _ := p.Data(obj)
+				patched = string(data)
+				if tc.want.err != nil {
+					return errBoom
+				}
+				return nil
+			}}
+
+			api := NewAPIPatchingFinalizer(c, finalizer)
+			var err error
+			if tc.remove {
+				err = api.RemoveFinalizer(tc.args.ctx, tc.args.mg)
+			} else {
+				err = api.AddFinalizer(tc.args.ctx, tc.args.mg)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFinalizer(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("\n%s\nFinalizer(...) Managed: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patch, patched); diff != "" {
+				t.Errorf("\n%s\nFinalizer(...) patch: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestNameAsExternalName(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -233,10 +380,111 @@ func TestNameAsExternalName(t *testing.T) {
 	}
 }
 
+func TestAPIExternalNameRecorder(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		err error
+		mg  resource.Managed
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		client client.Client
+		args   args
+		want   want
+	}{
+		"UpdateManagedError": {
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+			args: args{
+				ctx: context.Background(),
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{meta.AnnotationKeyExternalName: "new"},
+				}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateManaged),
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName:     "new",
+						meta.AnnotationKeyLastExternalName: "new",
+					},
+				}},
+			},
+		},
+		"UpdateSuccessful": {
+			client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			args: args{
+				ctx: context.Background(),
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName:     "new",
+						meta.AnnotationKeyLastExternalName: "old",
+					},
+				}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName:     "new",
+						meta.AnnotationKeyLastExternalName: "new",
+					},
+				}},
+			},
+		},
+		"AlreadyRecorded": {
+			args: args{
+				ctx: context.Background(),
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName:     "old",
+						meta.AnnotationKeyLastExternalName: "old",
+					},
+				}},
+			},
+			want: want{
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						meta.AnnotationKeyExternalName:     "old",
+						meta.AnnotationKeyLastExternalName: "old",
+					},
+				}},
+			},
+		},
+		"NoExternalName": {
+			args: args{
+				ctx: context.Background(),
+				mg:  &fake.Managed{},
+			},
+			want: want{
+				mg: &fake.Managed{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := NewAPIExternalNameRecorder(tc.client)
+			err := api.RecordExternalName(tc.args.ctx, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("api.RecordExternalName(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("api.RecordExternalName(...) Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestAPISecretPublisher(t *testing.T) {
 	errBoom := errors.New("boom")
 
 	mg := &fake.Managed{
+		ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"},
 		ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{Ref: &v1alpha1.SecretReference{
 			Namespace: "coolnamespace",
 			Name:      "coolsecret",
@@ -244,10 +492,29 @@ func TestAPISecretPublisher(t *testing.T) {
 	}
 
 	cd := ConnectionDetails{"cool": {42}}
+	sum, _ := checksum(cd)
+
+	published := func(sum string, data map[string][]byte, annotations ...string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(o runtime.Object) error {
+			s := resource.ConnectionSecretFor(mg, fake.GVK(mg))
+			s.SetAnnotations(map[string]string{AnnotationKeyConnectionDetailsChecksum: sum})
+			for i := 0; i+1 < len(annotations); i += 2 {
+				meta.AddAnnotations(s, map[string]string{annotations[i]: annotations[i+1]})
+			}
+			s.Data = data
+			*o.(*corev1.Secret) = *s
+			return nil
+		})
+	}
 
 	type fields struct {
-		secret resource.Applicator
-		typer  runtime.ObjectTyper
+		client       client.Reader
+		secret       resource.Applicator
+		configMap    resource.Applicator
+		typer        runtime.ObjectTyper
+		nonSensitive map[string]bool
+		options      []resource.ConnectionSecretOption
+		encrypter    SecretEncrypter
 	}
 
 	type args struct {
@@ -256,12 +523,26 @@ func TestAPISecretPublisher(t *testing.T) {
 		c   ConnectionDetails
 	}
 
+	_, errGetKind := resource.GetKind(mg, runtime.NewScheme())
+
 	cases := map[string]struct {
 		reason string
 		fields fields
 		args   args
 		want   error
 	}{
+		"GetKindError": {
+			reason: "An error getting the kind of the managed resource should be returned rather than causing a panic",
+			fields: fields{
+				typer: runtime.NewScheme(),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+			want: errGetKind,
+		},
 		"ResourceDoesNotPublishSecret": {
 			reason: "A managed resource with a nil GetWriteConnectionSecretToReference should not publish a secret",
 			args: args{
@@ -272,6 +553,7 @@ func TestAPISecretPublisher(t *testing.T) {
 		"ApplyError": {
 			reason: "An error applying the connection secret should be returned",
 			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
 				secret: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error { return errBoom }),
 				typer:  fake.SchemeWith(&fake.Managed{}),
 			},
@@ -281,11 +563,100 @@ func TestAPISecretPublisher(t *testing.T) {
 			},
 			want: errors.Wrap(errBoom, errCreateOrUpdateSecret),
 		},
+		"AlreadyPublished": {
+			reason: "The connection secret should not be applied if the supplied connection details were already published",
+			fields: fields{
+				client: &test.MockClient{MockGet: published(sum, cd)},
+				secret: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+					t.Errorf("Apply should not be called when the connection details are unchanged")
+					return nil
+				}),
+				typer: fake.SchemeWith(&fake.Managed{}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+		},
+		"EditedData": {
+			reason: "The connection secret should be applied if its data was edited since the connection details were published",
+			fields: fields{
+				client: &test.MockClient{MockGet: published(sum, map[string][]byte{"cool": []byte("edited")})},
+				secret: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+					if diff := cmp.Diff(map[string][]byte(cd), o.(*corev1.Secret).Data); diff != "" {
+						t.Errorf("-want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				typer: fake.SchemeWith(&fake.Managed{}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+		},
+		"EncryptedWithoutDecrypter": {
+			reason: "An encrypted connection secret should be applied if the encrypter cannot decrypt it to compare its data",
+			fields: fields{
+				client: &test.MockClient{MockGet: published(sum, cd, envelope.AnnotationKeyDataKey, "key")},
+				secret: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error { return errBoom }),
+				typer:  fake.SchemeWith(&fake.Managed{}),
+				encrypter: SecretEncrypterFn(func(_ context.Context, _ *corev1.Secret) error {
+					return nil
+				}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+			want: errors.Wrap(errBoom, errCreateOrUpdateSecret),
+		},
+		"ChangedMetadata": {
+			reason: "The connection secret should be applied if it does not have the desired metadata",
+			fields: fields{
+				client: &test.MockClient{MockGet: published(sum, cd)},
+				secret: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+					want := resource.ConnectionSecretFor(mg, fake.GVK(mg), resource.WithSecretLabels(map[string]string{"cool": "very"}))
+					meta.AddAnnotations(want, map[string]string{AnnotationKeyConnectionDetailsChecksum: sum})
+					want.Data = cd
+					if diff := cmp.Diff(want, o); diff != "" {
+						t.Errorf("-want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				typer:   fake.SchemeWith(&fake.Managed{}),
+				options: []resource.ConnectionSecretOption{resource.WithSecretLabels(map[string]string{"cool": "very"})},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+		},
+		"ChangedDetails": {
+			reason: "The connection secret should be applied if different connection details were previously published",
+			fields: fields{
+				client: &test.MockClient{MockGet: published("stale", cd)},
+				secret: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error { return errBoom }),
+				typer:  fake.SchemeWith(&fake.Managed{}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+			want: errors.Wrap(errBoom, errCreateOrUpdateSecret),
+		},
 		"Success": {
 			reason: "A successful application of the connection secret should result in no error",
 			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
 				secret: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
 					want := resource.ConnectionSecretFor(mg, fake.GVK(mg))
+					want.SetAnnotations(map[string]string{AnnotationKeyConnectionDetailsChecksum: sum})
 					want.Data = cd
 					if diff := cmp.Diff(want, o); diff != "" {
 						t.Errorf("-want, +got:\n%s", diff)
@@ -300,11 +671,117 @@ func TestAPISecretPublisher(t *testing.T) {
 				c:   cd,
 			},
 		},
+		"ApplyConfigMapError": {
+			reason: "An error applying the connection config map should be returned",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				secret: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+					t.Errorf("Apply should not be called when the connection config map could not be applied")
+					return nil
+				}),
+				configMap:    resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error { return errBoom }),
+				typer:        fake.SchemeWith(&fake.Managed{}),
+				nonSensitive: map[string]bool{"endpoint": true},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   ConnectionDetails{"endpoint": []byte("example.org"), "password": []byte("secret")},
+			},
+			want: errors.Wrap(errBoom, errCreateOrUpdateConfigMap),
+		},
+		"SuccessWithNonSensitiveKeys": {
+			reason: "Non-sensitive connection details should be published to a config map, and sensitive details to a secret",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				secret: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+					cd := ConnectionDetails{"endpoint": []byte("example.org"), "password": []byte("secret")}
+					sum, _ := checksum(cd)
+					want := resource.ConnectionSecretFor(mg, fake.GVK(mg))
+					want.SetAnnotations(map[string]string{AnnotationKeyConnectionDetailsChecksum: sum})
+					want.Data = map[string][]byte{"password": []byte("secret")}
+					if diff := cmp.Diff(want, o); diff != "" {
+						t.Errorf("-want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				configMap: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+					want := resource.ConnectionConfigMapFor(mg, fake.GVK(mg))
+					want.Data = map[string]string{"endpoint": "example.org"}
+					if diff := cmp.Diff(want, o); diff != "" {
+						t.Errorf("-want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				typer:        fake.SchemeWith(&fake.Managed{}),
+				nonSensitive: map[string]bool{"endpoint": true},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   ConnectionDetails{"endpoint": []byte("example.org"), "password": []byte("secret")},
+			},
+		},
+		"EncryptError": {
+			reason: "An error encrypting the connection secret should be returned",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				secret: resource.ApplyFn(func(_ context.Context, _ runtime.Object, _ ...resource.ApplyOption) error {
+					t.Errorf("Apply should not be called when the connection secret could not be encrypted")
+					return nil
+				}),
+				typer:     fake.SchemeWith(&fake.Managed{}),
+				encrypter: SecretEncrypterFn(func(_ context.Context, _ *corev1.Secret) error { return errBoom }),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   cd,
+			},
+			want: errors.Wrap(errBoom, errEncryptSecret),
+		},
+		"SuccessWithEncryption": {
+			reason: "The connection secret should be encrypted before it is applied",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				secret: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+					want := resource.ConnectionSecretFor(mg, fake.GVK(mg))
+					want.SetAnnotations(map[string]string{AnnotationKeyConnectionDetailsChecksum: sum})
+					want.Data = map[string][]byte{"cool": []byte("encrypted")}
+					if diff := cmp.Diff(want, o); diff != "" {
+						t.Errorf("-want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				typer: fake.SchemeWith(&fake.Managed{}),
+				encrypter: SecretEncrypterFn(func(_ context.Context, s *corev1.Secret) error {
+					encrypted := make(map[string][]byte, len(s.Data))
+					for k := range s.Data {
+						encrypted[k] = []byte("encrypted")
+					}
+					s.Data = encrypted
+					return nil
+				}),
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  mg,
+				c:   ConnectionDetails{"cool": {42}},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			a := &APISecretPublisher{tc.fields.secret, tc.fields.typer}
+			a := &APISecretPublisher{
+				client:       tc.fields.client,
+				secret:       tc.fields.secret,
+				configMap:    tc.fields.configMap,
+				typer:        tc.fields.typer,
+				nonSensitive: tc.fields.nonSensitive,
+				options:      tc.fields.options,
+				encrypter:    tc.fields.encrypter,
+			}
 			got := a.PublishConnection(tc.args.ctx, tc.args.mg, tc.args.c)
 			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nPublish(...): -want, +got:\n%s", tc.reason, diff)
@@ -312,3 +789,153 @@ func TestAPISecretPublisher(t *testing.T) {
 		})
 	}
 }
+
+func TestAPISecretPublisherEncryptedMerge(t *testing.T) {
+	mg := &fake.Managed{
+		ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"},
+		ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{Ref: &v1alpha1.SecretReference{
+			Namespace: "coolnamespace",
+			Name:      "coolsecret",
+		}},
+	}
+
+	// stored is the connection secret as stored by the API server. Secrets
+	// are applied to it as a JSON merge patch would be.
+	var stored *corev1.Secret
+	get := func(_ context.Context, _ client.ObjectKey, o runtime.Object) error {
+		if stored == nil {
+			return kerrors.NewNotFound(schema.GroupResource{}, "")
+		}
+		*o.(*corev1.Secret) = *stored.DeepCopy()
+		return nil
+	}
+	apply := resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+		s := o.(*corev1.Secret).DeepCopy()
+		if stored == nil {
+			stored = s
+			return nil
+		}
+		meta.AddAnnotations(stored, s.GetAnnotations())
+		for k, v := range s.Data {
+			if v == nil {
+				delete(stored.Data, k)
+				continue
+			}
+			stored.Data[k] = v
+		}
+		return nil
+	})
+
+	e := envelope.NewEncrypter(envelope.NewLocalKeyProvider(bytes.Repeat([]byte{1}, 32)))
+	a := &APISecretPublisher{
+		client:       &test.MockClient{MockGet: get},
+		secret:       apply,
+		typer:        fake.SchemeWith(&fake.Managed{}),
+		nonSensitive: map[string]bool{},
+		encrypter:    e,
+	}
+
+	// Publish all of our details at create time, then a subset of them at
+	// observe time.
+	for _, cd := range []ConnectionDetails{
+		{"username": []byte("cool"), "password": []byte("verysecure"), "stale": []byte("stale")},
+		{"endpoint": []byte("example.org"), "stale": nil},
+	} {
+		if err := a.PublishConnection(context.Background(), mg, cd); err != nil {
+			t.Fatalf("PublishConnection(...): %s", err)
+		}
+	}
+
+	if err := e.DecryptSecret(context.Background(), stored); err != nil {
+		t.Fatalf("DecryptSecret(...): %s", err)
+	}
+	want := map[string][]byte{
+		"username": []byte("cool"),
+		"password": []byte("verysecure"),
+		"endpoint": []byte("example.org"),
+	}
+	if diff := cmp.Diff(want, stored.Data); diff != "" {
+		t.Errorf("DecryptSecret(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestAPISecretPublisherRestoresEditedData(t *testing.T) {
+	mg := &fake.Managed{
+		ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"},
+		ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{Ref: &v1alpha1.SecretReference{
+			Namespace: "coolnamespace",
+			Name:      "coolsecret",
+		}},
+	}
+	cd := ConnectionDetails{"username": []byte("cool"), "password": []byte("verysecure")}
+
+	cases := map[string]struct {
+		reason    string
+		encrypter *envelope.Encrypter
+	}{
+		"Plaintext": {
+			reason: "Edits to the data of a connection secret should be reverted when it is next published.",
+		},
+		"Encrypted": {
+			reason:    "Edits to the data of an encrypted connection secret should be reverted when it is next published.",
+			encrypter: envelope.NewEncrypter(envelope.NewLocalKeyProvider(bytes.Repeat([]byte{1}, 32))),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// stored is the connection secret as stored by the API server.
+			var stored *corev1.Secret
+			applied := 0
+			get := func(_ context.Context, _ client.ObjectKey, o runtime.Object) error {
+				if stored == nil {
+					return kerrors.NewNotFound(schema.GroupResource{}, "")
+				}
+				*o.(*corev1.Secret) = *stored.DeepCopy()
+				return nil
+			}
+			apply := resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				applied++
+				stored = o.(*corev1.Secret).DeepCopy()
+				return nil
+			})
+
+			a := &APISecretPublisher{
+				client:       &test.MockClient{MockGet: get},
+				secret:       apply,
+				typer:        fake.SchemeWith(&fake.Managed{}),
+				nonSensitive: map[string]bool{},
+			}
+			if tc.encrypter != nil {
+				a.encrypter = tc.encrypter
+			}
+
+			publish := func() {
+				if err := a.PublishConnection(context.Background(), mg, cd); err != nil {
+					t.Fatalf("PublishConnection(...): %s", err)
+				}
+			}
+
+			// Publishing the same details twice should write the secret
+			// once. Editing its data, but not its checksum annotation,
+			// should cause it to be written again.
+			publish()
+			publish()
+			stored.Data["password"] = []byte("edited")
+			publish()
+
+			if diff := cmp.Diff(2, applied); diff != "" {
+				t.Errorf("\n%s\nApply(...) calls: -want, +got:\n%s", tc.reason, diff)
+			}
+			got := stored.DeepCopy()
+			if tc.encrypter != nil {
+				if err := tc.encrypter.DecryptSecret(context.Background(), got); err != nil {
+					t.Fatalf("DecryptSecret(...): %s", err)
+				}
+			}
+			if diff := cmp.Diff(map[string][]byte(cd), got.Data); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want data, +got data:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An auditStatusSetter is a managed resource whose status records the
+// operations most recently performed on its external resource. Managed
+// resources that embed v1alpha1.ResourceStatus satisfy this interface by
+// delegating to it, for example:
+//
+//	func (mg *Example) SetAuditStatus(s v1alpha1.AuditStatus) {
+//		mg.Status.SetAuditStatus(s)
+//	}
+type auditStatusSetter interface {
+	SetAuditStatus(s v1alpha1.AuditStatus)
+}
+
+// An operationAuditor records the operations the Reconciler performs on
+// external resources to the status of their managed resources, if they
+// support it.
+type operationAuditor struct {
+	enabled bool
+	now     func() time.Time
+}
+
+// record that the supplied operation was performed on the external resource
+// of the supplied managed resource, returning the supplied connection details
+// and error. The audit is persisted along with the managed resource's status.
+func (a operationAuditor) record(mg resource.Managed, op v1alpha1.ExternalOperationType, cd ConnectionDetails, err error) {
+	s, ok := mg.(auditStatusSetter)
+	if !a.enabled || !ok {
+		return
+	}
+	o := &v1alpha1.ExternalOperation{
+		Type:   op,
+		Time:   metav1.NewTime(a.now()),
+		Result: v1alpha1.ExternalOperationSucceeded,
+		Digest: digestConnectionDetails(cd),
+	}
+	if err != nil {
+		o.Result = v1alpha1.ExternalOperationFailed
+		o.Digest = digest([]byte(err.Error()))
+	}
+	s.SetAuditStatus(v1alpha1.AuditStatus{LastExternalOperation: o})
+}
+
+// digestConnectionDetails returns a digest of the supplied connection details,
+// or an empty string if there are none.
+func digestConnectionDetails(cd ConnectionDetails) string {
+	if len(cd) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(cd))
+	for k := range cd {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		// Length prefixes ensure distinct details have distinct digests.
+		fmt.Fprintf(h, "%d:%s%d:", len(k), k, len(cd[k]))
+		h.Write(cd[k]) // nolint:errcheck
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+func digest(b []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+type auditedManaged struct {
+	fake.Managed
+	v1alpha1.AuditStatus
+}
+
+func TestOperationAuditorRecord(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	type args struct {
+		enabled bool
+		op      v1alpha1.ExternalOperationType
+		cd      ConnectionDetails
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   v1alpha1.AuditStatus
+	}{
+		"Disabled": {
+			reason: "Nothing should be recorded when auditing is disabled.",
+			args: args{
+				op: v1alpha1.ExternalOperationCreate,
+			},
+		},
+		"SucceededWithoutDetails": {
+			reason: "A successful operation that returned no connection details should be recorded without a digest.",
+			args: args{
+				enabled: true,
+				op:      v1alpha1.ExternalOperationDelete,
+			},
+			want: v1alpha1.AuditStatus{LastExternalOperation: &v1alpha1.ExternalOperation{
+				Type:   v1alpha1.ExternalOperationDelete,
+				Time:   metav1.NewTime(now),
+				Result: v1alpha1.ExternalOperationSucceeded,
+			}},
+		},
+		"SucceededWithDetails": {
+			reason: "A successful operation should be recorded with a digest of the connection details it returned.",
+			args: args{
+				enabled: true,
+				op:      v1alpha1.ExternalOperationCreate,
+				cd:      ConnectionDetails{"password": []byte("secret")},
+			},
+			want: v1alpha1.AuditStatus{LastExternalOperation: &v1alpha1.ExternalOperation{
+				Type:   v1alpha1.ExternalOperationCreate,
+				Time:   metav1.NewTime(now),
+				Result: v1alpha1.ExternalOperationSucceeded,
+				Digest: digestConnectionDetails(ConnectionDetails{"password": []byte("secret")}),
+			}},
+		},
+		"Failed": {
+			reason: "A failed operation should be recorded with a digest of the error it returned.",
+			args: args{
+				enabled: true,
+				op:      v1alpha1.ExternalOperationUpdate,
+				cd:      ConnectionDetails{"password": []byte("secret")},
+				err:     errBoom,
+			},
+			want: v1alpha1.AuditStatus{LastExternalOperation: &v1alpha1.ExternalOperation{
+				Type:   v1alpha1.ExternalOperationUpdate,
+				Time:   metav1.NewTime(now),
+				Result: v1alpha1.ExternalOperationFailed,
+				Digest: "sha256:81f52337ebb4cb1669bb802c708807dde0519d15cb102a6313d26ad5cd821713",
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := operationAuditor{enabled: tc.args.enabled, now: func() time.Time { return now }}
+			mg := &auditedManaged{}
+			a.record(mg, tc.args.op, tc.args.cd, tc.args.err)
+			if diff := cmp.Diff(tc.want, mg.GetAuditStatus()); diff != "" {
+				t.Errorf("\n%s\na.record(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDigestConnectionDetails(t *testing.T) {
+	a := digestConnectionDetails(ConnectionDetails{"username": []byte("admin"), "password": []byte("secret")})
+	b := digestConnectionDetails(ConnectionDetails{"password": []byte("secret"), "username": []byte("admin")})
+	if a != b {
+		t.Errorf("digestConnectionDetails(...): want identical digests of identical details, got %q and %q", a, b)
+	}
+
+	c := digestConnectionDetails(ConnectionDetails{"username": []byte("admi"), "password": []byte("nsecret")})
+	if a == c {
+		t.Errorf("digestConnectionDetails(...): want distinct digests of distinct details, got %q twice", a)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// BackoffGauges are gauges of the backoff state of managed resources that are
+// failing to reconcile, by kind and name. A managed resource's gauges are
+// removed once it reconciles successfully or is deleted, so only managed
+// resources that are currently failing to reconcile are represented.
+type BackoffGauges struct {
+	Step          *prometheus.GaugeVec
+	NextReconcile *prometheus.GaugeVec
+}
+
+// NewBackoffGauges returns a new BackoffGauges.
+func NewBackoffGauges() *BackoffGauges {
+	return &BackoffGauges{
+		Step: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "backoff_step",
+			Help:      "The number of consecutive times a managed resource has failed to reconcile.",
+		}, []string{"gvk", "name"}),
+		NextReconcile: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "next_reconcile_timestamp_seconds",
+			Help:      "The Unix time at which a managed resource that failed to reconcile will next be reconciled.",
+		}, []string{"gvk", "name"}),
+	}
+}
+
+// Describe sends the descriptors of each gauge to the supplied channel.
+func (g *BackoffGauges) Describe(ch chan<- *prometheus.Desc) {
+	g.Step.Describe(ch)
+	g.NextReconcile.Describe(ch)
+}
+
+// Collect sends each gauge to the supplied channel.
+func (g *BackoffGauges) Collect(ch chan<- prometheus.Metric) {
+	g.Step.Collect(ch)
+	g.NextReconcile.Collect(ch)
+}
+
+var (
+	registerBackoff sync.Once
+	backoff         = NewBackoffGauges()
+)
+
+// registeredBackoffGauges returns BackoffGauges that are registered with
+// controller-runtime's metrics registry. The gauges are shared by all
+// Reconcilers, which are distinguished by the kind of managed resource they
+// reconcile, and are registered only once.
+func registeredBackoffGauges() *BackoffGauges {
+	registerBackoff.Do(func() { metrics.Registry.MustRegister(backoff) })
+	return backoff
+}
+
+// A backoffStatusSetter is a managed resource whose status records its
+// reconcile backoff state. Managed resources that embed v1alpha1.ResourceStatus
+// satisfy this interface by delegating to it, for example:
+//
+//	func (mg *Example) SetBackoffStatus(s v1alpha1.BackoffStatus) {
+//		mg.Status.SetBackoffStatus(s)
+//	}
+type backoffStatusSetter interface {
+	SetBackoffStatus(s v1alpha1.BackoffStatus)
+}
+
+// A backoffRecorder records the backoff state of managed resources to their
+// status, if they support it, and to BackoffGauges, if any.
+type backoffRecorder struct {
+	gvk    string
+	gauges *BackoffGauges
+	now    func() time.Time
+}
+
+// failed records that the supplied managed resource failed to reconcile for
+// the supplied consecutive number of times, and will next be reconciled after
+// the supplied wait.
+func (b backoffRecorder) failed(mg resource.Managed, step int, wait time.Duration) {
+	next := b.now().Add(wait)
+	if s, ok := mg.(backoffStatusSetter); ok {
+		t := metav1.NewTime(next)
+		s.SetBackoffStatus(v1alpha1.BackoffStatus{NextReconcileTime: &t, BackoffStep: int32(step)})
+	}
+	if b.gauges == nil {
+		return
+	}
+	b.gauges.Step.WithLabelValues(b.gvk, mg.GetName()).Set(float64(step))
+	b.gauges.NextReconcile.WithLabelValues(b.gvk, mg.GetName()).Set(float64(next.Unix()))
+}
+
+// succeeded clears any backoff state recorded for the supplied managed
+// resource.
+func (b backoffRecorder) succeeded(mg resource.Managed) {
+	if s, ok := mg.(backoffStatusSetter); ok {
+		s.SetBackoffStatus(v1alpha1.BackoffStatus{})
+	}
+	if b.gauges == nil {
+		return
+	}
+	b.deleted(mg.GetName())
+}
+
+// deleted removes any backoff state recorded to BackoffGauges for the managed
+// resource with the supplied name, which no longer exists.
+func (b backoffRecorder) deleted(name string) {
+	if b.gauges == nil {
+		return
+	}
+	b.gauges.Step.DeleteLabelValues(b.gvk, name)
+	b.gauges.NextReconcile.DeleteLabelValues(b.gvk, name)
+}
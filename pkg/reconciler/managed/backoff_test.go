@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+type backoffManaged struct {
+	fake.Managed
+	v1alpha1.BackoffStatus
+}
+
+func TestBackoffRecorder(t *testing.T) {
+	now := time.Unix(1000, 0)
+	next := metav1.NewTime(now.Add(10 * time.Second))
+
+	type want struct {
+		status v1alpha1.BackoffStatus
+		step   float64
+		next   float64
+	}
+
+	cases := map[string]struct {
+		reason    string
+		gauges    *BackoffGauges
+		succeeded bool
+		deleted   bool
+		want      want
+	}{
+		"FailedNoGauges": {
+			reason: "Backoff state should be recorded to status when no gauges are configured",
+			want: want{
+				status: v1alpha1.BackoffStatus{NextReconcileTime: &next, BackoffStep: 3},
+			},
+		},
+		"Failed": {
+			reason: "Backoff state should be recorded to status and gauges",
+			gauges: NewBackoffGauges(),
+			want: want{
+				status: v1alpha1.BackoffStatus{NextReconcileTime: &next, BackoffStep: 3},
+				step:   3,
+				next:   1010,
+			},
+		},
+		"Succeeded": {
+			reason:    "Backoff state should be cleared from status and gauges after a successful reconcile",
+			gauges:    NewBackoffGauges(),
+			succeeded: true,
+			want: want{
+				status: v1alpha1.BackoffStatus{},
+			},
+		},
+		"Deleted": {
+			reason:  "Backoff state should be cleared from gauges after a managed resource is deleted",
+			gauges:  NewBackoffGauges(),
+			deleted: true,
+			want: want{
+				status: v1alpha1.BackoffStatus{NextReconcileTime: &next, BackoffStep: 3},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := backoffRecorder{gvk: "Cool.v1.example.org", gauges: tc.gauges, now: func() time.Time { return now }}
+			mg := &backoffManaged{Managed: fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}}
+
+			b.failed(mg, 3, 10*time.Second)
+			if tc.succeeded {
+				b.succeeded(mg)
+			}
+			if tc.deleted {
+				b.deleted(mg.GetName())
+			}
+
+			if diff := cmp.Diff(tc.want.status, mg.GetBackoffStatus()); diff != "" {
+				t.Errorf("\n%s\nGetBackoffStatus(): -want, +got:\n%s", tc.reason, diff)
+			}
+			if tc.gauges == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.step, testutil.ToFloat64(tc.gauges.Step.WithLabelValues(b.gvk, mg.GetName()))); diff != "" {
+				t.Errorf("\n%s\nStep: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.next, testutil.ToFloat64(tc.gauges.NextReconcile.WithLabelValues(b.gvk, mg.GetName()))); diff != "" {
+				t.Errorf("\n%s\nNextReconcile: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBackoffRecorderUnsupported(t *testing.T) {
+	b := backoffRecorder{gvk: "Cool.v1.example.org", gauges: NewBackoffGauges(), now: time.Now}
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+
+	// Managed resources that don't record backoff state in their status
+	// should still have their backoff state recorded to the gauges.
+	b.failed(mg, 2, time.Second)
+	if diff := cmp.Diff(float64(2), testutil.ToFloat64(b.gauges.Step.WithLabelValues(b.gvk, mg.GetName()))); diff != "" {
+		t.Errorf("Step: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errRemoveRetry = "cannot remove retry annotation"
+
+	errFmtMaxFailures = "retry budget exhausted after %d consecutive failures"
+	errFmtMaxDuration = "retry budget exhausted after failing for %s"
+)
+
+// A retryBudget limits how many consecutive times, or for how long, a managed
+// resource may fail to reconcile before the Reconciler parks it. Failures are
+// tracked in memory, and are counted per generation of a managed resource; a
+// change to its spec resets its budget.
+type retryBudget struct {
+	maxFailures int
+	maxDuration time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	failures map[types.NamespacedName]failures
+}
+
+// failures of a particular generation of a managed resource.
+type failures struct {
+	generation int64
+	count      int
+	since      time.Time
+	last       error
+}
+
+func newRetryBudget(maxFailures int, maxDuration time.Duration) *retryBudget {
+	return &retryBudget{
+		maxFailures: maxFailures,
+		maxDuration: maxDuration,
+		now:         time.Now,
+		failures:    map[types.NamespacedName]failures{},
+	}
+}
+
+func budgetKey(mg resource.Managed) types.NamespacedName {
+	return types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()}
+}
+
+// failed records that the supplied managed resource failed to reconcile with
+// the supplied error.
+func (b *retryBudget) failed(mg resource.Managed, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, ok := b.failures[budgetKey(mg)]
+	if !ok || f.generation != mg.GetGeneration() {
+		f = failures{generation: mg.GetGeneration(), since: b.now()}
+	}
+	f.count++
+	f.last = err
+	b.failures[budgetKey(mg)] = f
+}
+
+// reset the budget of the supplied managed resource.
+func (b *retryBudget) reset(mg resource.Managed) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.failures, budgetKey(mg))
+}
+
+// exhausted returns an error explaining why the supplied managed resource has
+// exhausted its retry budget, if it has.
+func (b *retryBudget) exhausted(mg resource.Managed) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, ok := b.failures[budgetKey(mg)]
+	if !ok || f.generation != mg.GetGeneration() {
+		return nil
+	}
+	if b.maxFailures > 0 && f.count >= b.maxFailures {
+		return errors.Wrapf(f.last, errFmtMaxFailures, f.count)
+	}
+	if d := b.now().Sub(f.since); b.maxDuration > 0 && d >= b.maxDuration {
+		return errors.Wrapf(f.last, errFmtMaxDuration, d.Round(time.Second))
+	}
+	return nil
+}
+
+// isParked returns true if the current generation of the supplied managed
+// resource was parked after exhausting its retry budget.
+func isParked(mg resource.Managed) bool {
+	c := mg.GetCondition(v1alpha1.TypeSynced)
+	return c.Reason == v1alpha1.ReasonRetryBudgetExhausted && c.ObservedGeneration == mg.GetGeneration()
+}
+
+// park the supplied managed resource if it has exhausted its retry budget.
+// Parked managed resources are not reconciled until their spec changes, or
+// until they are annotated to be retried. park returns true if the supplied
+// managed resource is parked and should not be reconciled. Managed resources
+// that have been deleted are never parked, so that they may be finalized.
+func (r *Reconciler) park(ctx context.Context, mg resource.Managed, log logging.Logger, record event.Recorder) (bool, error) {
+	if r.budget == nil || meta.WasDeleted(mg) {
+		return false, nil
+	}
+
+	if meta.ShouldRetry(mg) {
+		log.Debug("Retrying managed resource with a fresh retry budget")
+		r.budget.reset(mg)
+		meta.RemoveAnnotations(mg, meta.AnnotationKeyRetry)
+		return false, errors.Wrap(r.client.Update(ctx, mg), errRemoveRetry)
+	}
+
+	if isParked(mg) {
+		// We'll be requeued when the managed resource's spec or annotations
+		// change.
+		log.Debug("Managed resource is parked after exhausting its retry budget")
+		return true, nil
+	}
+
+	err := r.budget.exhausted(mg)
+	if err == nil {
+		return false, nil
+	}
+	log.Debug("Parking managed resource", "error", err)
+	record.Event(mg, event.Warning(reasonRetryBudgetExhausted, err))
+	mg.SetConditions(v1alpha1.RetryBudgetExhausted(err).WithObservedGeneration(mg.GetGeneration()))
+	return true, errors.Wrap(r.client.Status().Update(ctx, mg), errUpdateManagedStatus)
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRetryBudget(t *testing.T) {
+	errBoom := errors.New("boom")
+	start := time.Now()
+
+	type args struct {
+		maxFailures int
+		maxDuration time.Duration
+		failures    int
+		failedGen   int64
+		reset       bool
+		elapsed     time.Duration
+		mg          *fake.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoFailures": {
+			reason: "A managed resource that has not failed has not exhausted its budget.",
+			args: args{
+				maxFailures: 3,
+				mg:          &fake.Managed{},
+			},
+		},
+		"UnderMaxFailures": {
+			reason: "A managed resource that has failed fewer than the maximum times has not exhausted its budget.",
+			args: args{
+				maxFailures: 3,
+				failures:    2,
+				mg:          &fake.Managed{},
+			},
+		},
+		"MaxFailures": {
+			reason: "A managed resource that has failed the maximum times has exhausted its budget.",
+			args: args{
+				maxFailures: 3,
+				failures:    3,
+				mg:          &fake.Managed{},
+			},
+			want: errors.Wrapf(errBoom, errFmtMaxFailures, 3),
+		},
+		"UnderMaxDuration": {
+			reason: "A managed resource that has been failing for less than the maximum duration has not exhausted its budget.",
+			args: args{
+				maxDuration: time.Hour,
+				failures:    10,
+				elapsed:     time.Minute,
+				mg:          &fake.Managed{},
+			},
+		},
+		"MaxDuration": {
+			reason: "A managed resource that has been failing for the maximum duration has exhausted its budget.",
+			args: args{
+				maxDuration: time.Hour,
+				failures:    1,
+				elapsed:     2 * time.Hour,
+				mg:          &fake.Managed{},
+			},
+			want: errors.Wrapf(errBoom, errFmtMaxDuration, 2*time.Hour),
+		},
+		"SpecChanged": {
+			reason: "Failures of a previous generation of a managed resource should not count against its budget.",
+			args: args{
+				maxFailures: 3,
+				failures:    3,
+				failedGen:   1,
+				mg:          &fake.Managed{ObjectMeta: metav1.ObjectMeta{Generation: 2}},
+			},
+		},
+		"Reset": {
+			reason: "A managed resource whose budget was reset has not exhausted its budget.",
+			args: args{
+				maxFailures: 3,
+				failures:    3,
+				reset:       true,
+				mg:          &fake.Managed{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := newRetryBudget(tc.args.maxFailures, tc.args.maxDuration)
+			b.now = func() time.Time { return start }
+
+			failed := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Generation: tc.args.failedGen}}
+			for i := 0; i < tc.args.failures; i++ {
+				b.failed(failed, errBoom)
+			}
+			if tc.args.reset {
+				b.reset(tc.args.mg)
+			}
+
+			b.now = func() time.Time { return start.Add(tc.args.elapsed) }
+			got := b.exhausted(tc.args.mg)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.exhausted(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPark(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+	unknown := v1alpha1.Condition{Type: v1alpha1.TypeSynced, Status: corev1.ConditionUnknown}
+
+	exhausted := func() *retryBudget {
+		b := newRetryBudget(1, 0)
+		b.failed(&fake.Managed{}, errBoom)
+		return b
+	}
+
+	type args struct {
+		client client.Client
+		budget *retryBudget
+		mg     *fake.Managed
+	}
+	type want struct {
+		parked bool
+		err    error
+		synced v1alpha1.Condition
+		retry  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "Managed resources should never be parked if there is no retry budget.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+			want: want{
+				synced: unknown,
+			},
+		},
+		"Deleted": {
+			reason: "Managed resources that have been deleted should never be parked.",
+			args: args{
+				budget: exhausted(),
+				mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			},
+			want: want{
+				synced: unknown,
+			},
+		},
+		"RetryError": {
+			reason: "Errors removing the retry annotation should be returned.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				budget: exhausted(),
+				mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyRetry: "true"}}},
+			},
+			want: want{
+				err:    errors.Wrap(errBoom, errRemoveRetry),
+				synced: unknown,
+			},
+		},
+		"Retry": {
+			reason: "Managed resources annotated to be retried should not be parked, even if they are already parked.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				budget: exhausted(),
+				mg: &fake.Managed{
+					ObjectMeta:        metav1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyRetry: "true"}},
+					ConditionedStatus: *v1alpha1.NewConditionedStatus(v1alpha1.RetryBudgetExhausted(errBoom)),
+				},
+			},
+			want: want{
+				synced: v1alpha1.RetryBudgetExhausted(errBoom),
+			},
+		},
+		"AlreadyParked": {
+			reason: "Managed resources that were parked at their current generation should remain parked.",
+			args: args{
+				budget: newRetryBudget(1, 0),
+				mg: &fake.Managed{
+					ObjectMeta:        metav1.ObjectMeta{Generation: 1},
+					ConditionedStatus: *v1alpha1.NewConditionedStatus(v1alpha1.RetryBudgetExhausted(errBoom).WithObservedGeneration(1)),
+				},
+			},
+			want: want{
+				parked: true,
+				synced: v1alpha1.RetryBudgetExhausted(errBoom).WithObservedGeneration(1),
+			},
+		},
+		"ParkedAtPreviousGeneration": {
+			reason: "Managed resources that were parked at a previous generation should be unparked.",
+			args: args{
+				budget: newRetryBudget(1, 0),
+				mg: &fake.Managed{
+					ObjectMeta:        metav1.ObjectMeta{Generation: 2},
+					ConditionedStatus: *v1alpha1.NewConditionedStatus(v1alpha1.RetryBudgetExhausted(errBoom).WithObservedGeneration(1)),
+				},
+			},
+			want: want{
+				synced: v1alpha1.RetryBudgetExhausted(errBoom).WithObservedGeneration(1),
+			},
+		},
+		"ParkError": {
+			reason: "Errors updating the status of a newly parked managed resource should be returned.",
+			args: args{
+				client: &test.MockClient{MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom)},
+				budget: exhausted(),
+				mg:     &fake.Managed{},
+			},
+			want: want{
+				parked: true,
+				err:    errors.Wrap(errBoom, errUpdateManagedStatus),
+				synced: v1alpha1.RetryBudgetExhausted(errors.Wrapf(errBoom, errFmtMaxFailures, 1)),
+			},
+		},
+		"Park": {
+			reason: "Managed resources that have exhausted their retry budget should be parked.",
+			args: args{
+				client: &test.MockClient{MockStatusUpdate: test.NewMockStatusUpdateFn(nil)},
+				budget: exhausted(),
+				mg:     &fake.Managed{},
+			},
+			want: want{
+				parked: true,
+				synced: v1alpha1.RetryBudgetExhausted(errors.Wrapf(errBoom, errFmtMaxFailures, 1)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{client: tc.args.client, budget: tc.args.budget}
+			parked, err := r.park(context.Background(), tc.args.mg, logging.NewNopLogger(), event.NewNopRecorder())
+			if diff := cmp.Diff(tc.want.parked, parked); diff != "" {
+				t.Errorf("\n%s\nr.park(...): -want parked, +got parked:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.park(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.synced, tc.args.mg.GetCondition(v1alpha1.TypeSynced)); diff != "" {
+				t.Errorf("\n%s\nr.park(...): -want Synced condition, +got Synced condition:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.retry, meta.ShouldRetry(tc.args.mg)); diff != "" {
+				t.Errorf("\n%s\nr.park(...): -want retry, +got retry:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
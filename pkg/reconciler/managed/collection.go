@@ -0,0 +1,350 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errGetDesiredItems  = "cannot determine desired external items"
+	errGetExistingItems = "cannot determine existing external items"
+
+	errFmtItems         = "cannot %s %d of %d external items: %s"
+	errFmtItemsNotReady = "%d of %d external items are not ready: %s"
+)
+
+// Operations performed on external items.
+const (
+	itemOperationObserve = "observe"
+	itemOperationCreate  = "create"
+	itemOperationUpdate  = "update"
+	itemOperationDelete  = "delete"
+)
+
+// An ExternalItemClient manages the lifecycle of each of the set of external
+// objects represented by a managed resource, for example the rules of a
+// firewall policy. Each item is identified by a key that is unique within the
+// set. An ExternalItemClient should not set the Ready condition of the managed
+// resource; an ExternalCollectionClient derives it from all items.
+type ExternalItemClient interface {
+	// Desired returns the keys of the items the supplied managed resource
+	// specifies should exist.
+	Desired(ctx context.Context, mg resource.Managed) ([]string, error)
+
+	// Existing returns the keys of the items that currently exist for the
+	// supplied managed resource, whether or not they are desired.
+	Existing(ctx context.Context, mg resource.Managed) ([]string, error)
+
+	// Observe the item with the supplied key. An item whose observation
+	// includes a Ready condition that is not true is considered not ready.
+	Observe(ctx context.Context, mg resource.Managed, key string) (ExternalObservation, error)
+
+	// Create the item with the supplied key.
+	Create(ctx context.Context, mg resource.Managed, key string) (ExternalCreation, error)
+
+	// Update the item with the supplied key.
+	Update(ctx context.Context, mg resource.Managed, key string) (ExternalUpdate, error)
+
+	// Delete the item with the supplied key.
+	Delete(ctx context.Context, mg resource.Managed, key string) error
+}
+
+// ExternalItemClientFns are a series of functions that satisfy the
+// ExternalItemClient interface.
+type ExternalItemClientFns struct {
+	DesiredFn  func(ctx context.Context, mg resource.Managed) ([]string, error)
+	ExistingFn func(ctx context.Context, mg resource.Managed) ([]string, error)
+	ObserveFn  func(ctx context.Context, mg resource.Managed, key string) (ExternalObservation, error)
+	CreateFn   func(ctx context.Context, mg resource.Managed, key string) (ExternalCreation, error)
+	UpdateFn   func(ctx context.Context, mg resource.Managed, key string) (ExternalUpdate, error)
+	DeleteFn   func(ctx context.Context, mg resource.Managed, key string) error
+}
+
+// Desired returns the keys of the items the supplied managed resource desires.
+func (e ExternalItemClientFns) Desired(ctx context.Context, mg resource.Managed) ([]string, error) {
+	return e.DesiredFn(ctx, mg)
+}
+
+// Existing returns the keys of the items that currently exist.
+func (e ExternalItemClientFns) Existing(ctx context.Context, mg resource.Managed) ([]string, error) {
+	return e.ExistingFn(ctx, mg)
+}
+
+// Observe the item with the supplied key.
+func (e ExternalItemClientFns) Observe(ctx context.Context, mg resource.Managed, key string) (ExternalObservation, error) {
+	return e.ObserveFn(ctx, mg, key)
+}
+
+// Create the item with the supplied key.
+func (e ExternalItemClientFns) Create(ctx context.Context, mg resource.Managed, key string) (ExternalCreation, error) {
+	return e.CreateFn(ctx, mg, key)
+}
+
+// Update the item with the supplied key.
+func (e ExternalItemClientFns) Update(ctx context.Context, mg resource.Managed, key string) (ExternalUpdate, error) {
+	return e.UpdateFn(ctx, mg, key)
+}
+
+// Delete the item with the supplied key.
+func (e ExternalItemClientFns) Delete(ctx context.Context, mg resource.Managed, key string) error {
+	return e.DeleteFn(ctx, mg, key)
+}
+
+// An itemFailures accumulates the errors encountered while operating on
+// external items.
+type itemFailures struct {
+	operation string
+	total     int
+	msgs      []string
+}
+
+func (f *itemFailures) add(key string, err error) {
+	f.msgs = append(f.msgs, key+": "+err.Error())
+}
+
+// err returns an error describing every failure, or nil if there were none.
+func (f *itemFailures) err() error {
+	if len(f.msgs) == 0 {
+		return nil
+	}
+	return errors.Errorf(errFmtItems, f.operation, len(f.msgs), f.total, strings.Join(f.msgs, "; "))
+}
+
+// An itemState is the observed state of a set of external items.
+type itemState struct {
+	missing    []string
+	stale      []string
+	unwanted   []string
+	existing   []string
+	notReady   []string
+	desired    int
+	connection ConnectionDetails
+}
+
+// An ExternalCollectionClient is an ExternalClient for managed resources that
+// represent a set of external objects rather than a single external resource.
+// It fans each operation out to every item using an ExternalItemClient,
+// attempting all items even if some fail, and reports which items failed. It
+// sets the managed resource's Ready condition to reflect all desired items.
+//
+// An ExternalCollectionClient remembers what it observed so that it can create,
+// update, and delete only the items that require it. A new client should be
+// returned each time an ExternalConnecter connects.
+type ExternalCollectionClient struct {
+	items ExternalItemClient
+	state *itemState
+}
+
+// NewExternalCollectionClient returns an ExternalClient that manages the set of
+// external items represented by a managed resource using the supplied
+// ExternalItemClient.
+func NewExternalCollectionClient(c ExternalItemClient) *ExternalCollectionClient {
+	return &ExternalCollectionClient{items: c}
+}
+
+// Observe every desired item, and determine which existing items are not
+// desired. The external resource is considered to exist only if every desired
+// item exists, and to be up to date only if every desired item is up to date
+// and no undesired items exist. A managed resource that has been deleted is
+// considered to exist while any of its items exist.
+func (c *ExternalCollectionClient) Observe(ctx context.Context, mg resource.Managed) (ExternalObservation, error) {
+	s, err := c.observe(ctx, mg)
+	if err != nil {
+		return ExternalObservation{}, err
+	}
+	c.state = s
+
+	if meta.WasDeleted(mg) {
+		mg.SetConditions(v1alpha1.Deleting())
+		return ExternalObservation{ResourceExists: len(s.existing) > 0}, nil
+	}
+
+	switch {
+	case len(s.missing) > 0:
+		mg.SetConditions(v1alpha1.Creating())
+	case len(s.notReady) > 0:
+		mg.SetConditions(v1alpha1.UnavailableError(errors.Errorf(errFmtItemsNotReady, len(s.notReady), s.desired, strings.Join(s.notReady, ", "))))
+	default:
+		mg.SetConditions(v1alpha1.Available())
+	}
+
+	return ExternalObservation{
+		ResourceExists:    len(s.missing) == 0,
+		ResourceUpToDate:  len(s.missing) == 0 && len(s.stale) == 0 && len(s.unwanted) == 0,
+		ConnectionDetails: s.connection,
+	}, nil
+}
+
+func (c *ExternalCollectionClient) observe(ctx context.Context, mg resource.Managed) (*itemState, error) {
+	desired, err := c.items.Desired(ctx, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetDesiredItems)
+	}
+	existing, err := c.items.Existing(ctx, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetExistingItems)
+	}
+
+	s := &itemState{existing: existing, desired: len(desired), connection: ConnectionDetails{}}
+	if err := c.observeDesired(ctx, mg, desired, s); err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]bool, len(desired))
+	for _, key := range desired {
+		want[key] = true
+	}
+	for _, key := range existing {
+		if !want[key] {
+			s.unwanted = append(s.unwanted, key)
+		}
+	}
+	sort.Strings(s.unwanted)
+	return s, nil
+}
+
+// observeDesired observes each of the supplied desired items, recording their
+// state to the supplied itemState.
+func (c *ExternalCollectionClient) observeDesired(ctx context.Context, mg resource.Managed, desired []string, s *itemState) error {
+	f := &itemFailures{operation: itemOperationObserve, total: len(desired)}
+	for _, key := range desired {
+		o, err := c.items.Observe(ctx, mg, key)
+		if err != nil {
+			f.add(key, err)
+			continue
+		}
+		switch {
+		case !o.ResourceExists:
+			s.missing = append(s.missing, key)
+			continue
+		case !o.ResourceUpToDate:
+			s.stale = append(s.stale, key)
+		}
+		if !itemReady(o.Conditions) {
+			s.notReady = append(s.notReady, key)
+		}
+		for k, v := range o.ConnectionDetails {
+			s.connection[k] = v
+		}
+	}
+	return f.err()
+}
+
+// itemReady returns false if the supplied conditions include a Ready condition
+// that is not true.
+func itemReady(cs []v1alpha1.Condition) bool {
+	for _, c := range cs {
+		if c.Type == v1alpha1.TypeReady && c.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// Create every desired item that does not yet exist.
+func (c *ExternalCollectionClient) Create(ctx context.Context, mg resource.Managed) (ExternalCreation, error) {
+	s, err := c.observed(ctx, mg)
+	if err != nil {
+		return ExternalCreation{}, err
+	}
+
+	cd := ConnectionDetails{}
+	f := &itemFailures{operation: itemOperationCreate, total: len(s.missing)}
+	for _, key := range s.missing {
+		cr, err := c.items.Create(ctx, mg, key)
+		if err != nil {
+			f.add(key, err)
+			continue
+		}
+		for k, v := range cr.ConnectionDetails {
+			cd[k] = v
+		}
+	}
+	return ExternalCreation{ConnectionDetails: cd}, f.err()
+}
+
+// Update every desired item that is not up to date, create any that do not
+// exist, and delete any existing items that are not desired. Every item is
+// attempted even if some fail.
+func (c *ExternalCollectionClient) Update(ctx context.Context, mg resource.Managed) (ExternalUpdate, error) {
+	s, err := c.observed(ctx, mg)
+	if err != nil {
+		return ExternalUpdate{}, err
+	}
+
+	cr, createErr := c.Create(ctx, mg)
+
+	cd := cr.ConnectionDetails
+	f := &itemFailures{operation: itemOperationUpdate, total: len(s.stale)}
+	for _, key := range s.stale {
+		u, err := c.items.Update(ctx, mg, key)
+		if err != nil {
+			f.add(key, err)
+			continue
+		}
+		for k, v := range u.ConnectionDetails {
+			cd[k] = v
+		}
+	}
+
+	return ExternalUpdate{ConnectionDetails: cd}, utilerrors.NewAggregate([]error{createErr, f.err(), c.delete(ctx, mg, s.unwanted)})
+}
+
+// Delete every existing item.
+func (c *ExternalCollectionClient) Delete(ctx context.Context, mg resource.Managed) error {
+	s, err := c.observed(ctx, mg)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, mg, s.existing)
+}
+
+func (c *ExternalCollectionClient) delete(ctx context.Context, mg resource.Managed, keys []string) error {
+	f := &itemFailures{operation: itemOperationDelete, total: len(keys)}
+	for _, key := range keys {
+		if err := c.items.Delete(ctx, mg, key); err != nil {
+			f.add(key, err)
+		}
+	}
+	return f.err()
+}
+
+// observed returns the state recorded by the most recent call to Observe, or
+// observes the items if Observe has not been called.
+func (c *ExternalCollectionClient) observed(ctx context.Context, mg resource.Managed) (*itemState, error) {
+	if c.state != nil {
+		return c.state, nil
+	}
+	s, err := c.observe(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	c.state = s
+	return s, nil
+}
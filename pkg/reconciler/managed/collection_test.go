@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// itemClient returns an ExternalItemClient that desires the supplied keys, and
+// observes the supplied items. Items that are not observed do not exist. The
+// operation each item in fail is mapped to returns errBoom for that item.
+func itemClient(desired []string, observed map[string]ExternalObservation, fail map[string]string, calls *[]string) ExternalItemClientFns {
+	errBoom := errors.New("boom")
+	record := func(op, key string) error {
+		*calls = append(*calls, op+" "+key)
+		if fail[key] == op {
+			return errBoom
+		}
+		return nil
+	}
+	return ExternalItemClientFns{
+		DesiredFn: func(_ context.Context, _ resource.Managed) ([]string, error) { return desired, nil },
+		ExistingFn: func(_ context.Context, _ resource.Managed) ([]string, error) {
+			existing := make([]string, 0, len(observed))
+			for key := range observed {
+				existing = append(existing, key)
+			}
+			return existing, nil
+		},
+		ObserveFn: func(_ context.Context, _ resource.Managed, key string) (ExternalObservation, error) {
+			if fail[key] == itemOperationObserve {
+				return ExternalObservation{}, errBoom
+			}
+			return observed[key], nil
+		},
+		CreateFn: func(_ context.Context, _ resource.Managed, key string) (ExternalCreation, error) {
+			return ExternalCreation{ConnectionDetails: ConnectionDetails{key: []byte("created")}}, record(itemOperationCreate, key)
+		},
+		UpdateFn: func(_ context.Context, _ resource.Managed, key string) (ExternalUpdate, error) {
+			return ExternalUpdate{}, record(itemOperationUpdate, key)
+		},
+		DeleteFn: func(_ context.Context, _ resource.Managed, key string) error {
+			return record(itemOperationDelete, key)
+		},
+	}
+}
+
+func TestExternalCollectionClientObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+	exists := ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+
+	type args struct {
+		mg       resource.Managed
+		items    ExternalItemClient
+		desired  []string
+		observed map[string]ExternalObservation
+		fail     map[string]string
+	}
+	type want struct {
+		o     ExternalObservation
+		ready v1alpha1.Condition
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DesiredError": {
+			reason: "Errors determining the desired items should be returned",
+			args: args{
+				mg: &fake.Managed{},
+				items: ExternalItemClientFns{
+					DesiredFn: func(_ context.Context, _ resource.Managed) ([]string, error) { return nil, errBoom },
+				},
+			},
+			want: want{
+				ready: v1alpha1.Condition{Type: v1alpha1.TypeReady, Status: corev1.ConditionUnknown},
+				err:   errors.Wrap(errBoom, errGetDesiredItems),
+			},
+		},
+		"ObserveError": {
+			reason: "Items that cannot be observed should be reported",
+			args: args{
+				mg:       &fake.Managed{},
+				desired:  []string{"a", "b"},
+				observed: map[string]ExternalObservation{"a": exists},
+				fail:     map[string]string{"b": itemOperationObserve},
+			},
+			want: want{
+				ready: v1alpha1.Condition{Type: v1alpha1.TypeReady, Status: corev1.ConditionUnknown},
+				err:   errors.Errorf(errFmtItems, itemOperationObserve, 1, 2, "b: boom"),
+			},
+		},
+		"Missing": {
+			reason: "The external resource should not exist while any desired item is missing",
+			args: args{
+				mg:       &fake.Managed{},
+				desired:  []string{"a", "b"},
+				observed: map[string]ExternalObservation{"a": exists},
+			},
+			want: want{
+				o:     ExternalObservation{ResourceExists: false, ConnectionDetails: ConnectionDetails{}},
+				ready: v1alpha1.Creating(),
+			},
+		},
+		"NotReady": {
+			reason: "The managed resource should be unavailable while any desired item is not ready",
+			args: args{
+				mg:      &fake.Managed{},
+				desired: []string{"a", "b"},
+				observed: map[string]ExternalObservation{
+					"a": exists,
+					"b": {ResourceExists: true, ResourceUpToDate: true, Conditions: []v1alpha1.Condition{v1alpha1.Unavailable()}},
+				},
+			},
+			want: want{
+				o:     ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: ConnectionDetails{}},
+				ready: v1alpha1.UnavailableError(errors.Errorf(errFmtItemsNotReady, 1, 2, "b")),
+			},
+		},
+		"Unwanted": {
+			reason: "The external resource should not be up to date while undesired items exist",
+			args: args{
+				mg:       &fake.Managed{},
+				desired:  []string{"a"},
+				observed: map[string]ExternalObservation{"a": exists, "b": exists},
+			},
+			want: want{
+				o:     ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ConnectionDetails: ConnectionDetails{}},
+				ready: v1alpha1.Available(),
+			},
+		},
+		"Deleted": {
+			reason: "A deleted managed resource's external resource should exist while any item exists",
+			args: args{
+				mg:       &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+				desired:  []string{"a"},
+				observed: map[string]ExternalObservation{"b": exists},
+			},
+			want: want{
+				o:     ExternalObservation{ResourceExists: true},
+				ready: v1alpha1.Deleting(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			items := tc.args.items
+			if items == nil {
+				items = itemClient(tc.args.desired, tc.args.observed, tc.args.fail, &[]string{})
+			}
+			c := NewExternalCollectionClient(items)
+			o, err := c.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Observe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\nc.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ready, tc.args.mg.GetCondition(v1alpha1.TypeReady)); diff != "" {
+				t.Errorf("\n%s\nGetCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExternalCollectionClientUpdate(t *testing.T) {
+	exists := ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	stale := ExternalObservation{ResourceExists: true}
+
+	type want struct {
+		u     ExternalUpdate
+		calls []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		desired  []string
+		observed map[string]ExternalObservation
+		fail     map[string]string
+		want     want
+	}{
+		"Success": {
+			reason:   "Missing items should be created, stale items updated, and undesired items deleted",
+			desired:  []string{"a", "b", "c"},
+			observed: map[string]ExternalObservation{"b": stale, "c": exists, "d": exists},
+			want: want{
+				u:     ExternalUpdate{ConnectionDetails: ConnectionDetails{"a": []byte("created")}},
+				calls: []string{"create a", "update b", "delete d"},
+			},
+		},
+		"PartialFailure": {
+			reason:   "Every item should be attempted, and those that failed should be reported",
+			desired:  []string{"a", "b", "c"},
+			observed: map[string]ExternalObservation{"b": stale, "c": stale, "d": exists},
+			fail:     map[string]string{"b": itemOperationUpdate},
+			want: want{
+				u:     ExternalUpdate{ConnectionDetails: ConnectionDetails{"a": []byte("created")}},
+				calls: []string{"create a", "update b", "update c", "delete d"},
+				err:   utilerrors.NewAggregate([]error{errors.Errorf(errFmtItems, itemOperationUpdate, 1, 2, "b: boom")}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := []string{}
+			c := NewExternalCollectionClient(itemClient(tc.desired, tc.observed, tc.fail, &calls))
+			if _, err := c.Observe(context.Background(), &fake.Managed{}); err != nil {
+				t.Fatalf("c.Observe(...): %s", err)
+			}
+			u, err := c.Update(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Update(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.u, u); diff != "" {
+				t.Errorf("\n%s\nc.Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("\n%s\nc.Update(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
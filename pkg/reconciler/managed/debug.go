@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// An ExternalCall summarises a single request an ExternalClient made to an
+// external API, and the response it received. Summaries are logged verbatim,
+// so they must be sanitized; they must not include credentials, connection
+// details, or other sensitive data.
+type ExternalCall struct {
+	// Operation is the name of the external API operation that was called,
+	// for example CreateBucket.
+	Operation string
+
+	// Request summarises the request that was sent.
+	Request string
+
+	// Response summarises the response that was received, if any.
+	Response string
+
+	// Duration of the call.
+	Duration time.Duration
+
+	// Error returned by the call, if any.
+	Error error
+}
+
+// An ExternalCallReporter is an ExternalClient that reports the calls it makes
+// to an external API. The Reconciler logs reported calls at debug level after
+// each ExternalClient operation, along with the ID of the reconcile during
+// which they were made.
+type ExternalCallReporter interface {
+	// ExternalCalls returns the calls made since ExternalCalls was last
+	// called.
+	ExternalCalls() []ExternalCall
+}
+
+// An ExternalCallRecorder records external API calls. It may be embedded in an
+// ExternalClient in order to satisfy ExternalCallReporter. An ExternalCallRecorder
+// is safe for concurrent use.
+type ExternalCallRecorder struct {
+	mu    sync.Mutex
+	calls []ExternalCall
+}
+
+// RecordExternalCall records the supplied external API call.
+func (r *ExternalCallRecorder) RecordExternalCall(c ExternalCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, c)
+}
+
+// ExternalCalls returns and forgets the external API calls recorded since
+// ExternalCalls was last called.
+func (r *ExternalCallRecorder) ExternalCalls() []ExternalCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+// newReconcileID returns an ID that correlates the log lines emitted during a
+// single reconcile.
+func newReconcileID() string {
+	id, err := newIdempotencyToken()
+	if err != nil {
+		return "unknown"
+	}
+	return id
+}
+
+// logExternalCalls logs the external API calls reported by the supplied
+// ExternalClient, if it is an ExternalCallReporter.
+func logExternalCalls(log logging.Logger, ec ExternalClient) {
+	r, ok := ec.(ExternalCallReporter)
+	if !ok {
+		return
+	}
+	for _, c := range r.ExternalCalls() {
+		kv := []interface{}{"operation", c.Operation, "request", c.Request, "response", c.Response, "duration", c.Duration}
+		if c.Error != nil {
+			kv = append(kv, "error", c.Error)
+		}
+		log.Debug("External API call", kv...)
+	}
+}
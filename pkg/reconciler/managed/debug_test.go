@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ ExternalCallReporter = &ExternalCallRecorder{}
+
+type debugLogger struct {
+	logging.Logger
+	lines [][]interface{}
+}
+
+func (l *debugLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.lines = append(l.lines, append([]interface{}{msg}, keysAndValues...))
+}
+
+type reportingClient struct {
+	ExternalClientFns
+	ExternalCallRecorder
+}
+
+func TestExternalCallRecorder(t *testing.T) {
+	r := &ExternalCallRecorder{}
+	r.RecordExternalCall(ExternalCall{Operation: "Describe"})
+	r.RecordExternalCall(ExternalCall{Operation: "Create"})
+
+	want := []ExternalCall{{Operation: "Describe"}, {Operation: "Create"}}
+	if diff := cmp.Diff(want, r.ExternalCalls()); diff != "" {
+		t.Errorf("\nExternalCalls(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]ExternalCall(nil), r.ExternalCalls()); diff != "" {
+		t.Errorf("\nExternalCalls() should forget returned calls: -want, +got:\n%s", diff)
+	}
+}
+
+func TestLogExternalCalls(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		ec     ExternalClient
+		calls  []ExternalCall
+		want   [][]interface{}
+	}{
+		"NotAReporter": {
+			reason: "Nothing should be logged if the ExternalClient is not an ExternalCallReporter.",
+			ec:     &ExternalClientFns{},
+			want:   nil,
+		},
+		"Reporter": {
+			reason: "Each reported call should be logged, including its error if any.",
+			ec:     &reportingClient{},
+			calls: []ExternalCall{
+				{Operation: "Describe", Request: "id=cool", Response: "404", Duration: time.Second, Error: errBoom},
+				{Operation: "Create", Request: "id=cool", Response: "200", Duration: time.Second},
+			},
+			want: [][]interface{}{
+				{"External API call", "operation", "Describe", "request", "id=cool", "response", "404", "duration", time.Second, "error", errBoom},
+				{"External API call", "operation", "Create", "request", "id=cool", "response", "200", "duration", time.Second},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if r, ok := tc.ec.(*reportingClient); ok {
+				for _, c := range tc.calls {
+					r.RecordExternalCall(c)
+				}
+			}
+			l := &debugLogger{}
+			logExternalCalls(l, tc.ec)
+			if diff := cmp.Diff(tc.want, l.lines, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nlogExternalCalls(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
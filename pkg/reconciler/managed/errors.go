@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// An ErrorClass classifies an error returned by an ExternalClient.
+type ErrorClass string
+
+// Error classes.
+const (
+	// ClassNotFound indicates the external resource does not exist.
+	ClassNotFound ErrorClass = "NotFound"
+
+	// ClassAlreadyExists indicates the external resource already exists.
+	ClassAlreadyExists ErrorClass = "AlreadyExists"
+
+	// ClassThrottled indicates the external API is rate limiting requests.
+	ClassThrottled ErrorClass = "Throttled"
+
+	// ClassUnauthorized indicates the provider's credentials are invalid, or
+	// are not permitted to make the request.
+	ClassUnauthorized ErrorClass = "Unauthorized"
+
+	// ClassInvalidSpec indicates the external API rejected the managed
+	// resource's spec.
+	ClassInvalidSpec ErrorClass = "InvalidSpec"
+
+	// ClassTransient indicates an error that is likely to resolve itself if
+	// the request is retried.
+	ClassTransient ErrorClass = "Transient"
+)
+
+var reasons = map[ErrorClass]v1alpha1.ConditionReason{
+	ClassNotFound:      v1alpha1.ReasonExternalNotFound,
+	ClassAlreadyExists: v1alpha1.ReasonExternalAlreadyExists,
+	ClassThrottled:     v1alpha1.ReasonExternalThrottled,
+	ClassUnauthorized:  v1alpha1.ReasonExternalUnauthorized,
+	ClassInvalidSpec:   v1alpha1.ReasonExternalInvalidSpec,
+	ClassTransient:     v1alpha1.ReasonExternalTransient,
+}
+
+// A ClassifiedError is an error returned by an ExternalClient that has been
+// classified. The managed resource reconciler uses an error's class to
+// determine when to requeue, and how to report the error in conditions.
+type ClassifiedError struct {
+	Class ErrorClass
+	Err   error
+}
+
+func (e *ClassifiedError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the classified error.
+func (e *ClassifiedError) Unwrap() error { return e.Err }
+
+// ConditionReason returns the reason a condition should report for this
+// class of error.
+func (e *ClassifiedError) ConditionReason() v1alpha1.ConditionReason {
+	if r, ok := reasons[e.Class]; ok {
+		return r
+	}
+	return v1alpha1.ReasonReconcileError
+}
+
+// Classify the supplied error. Classify returns nil if the error is nil.
+func Classify(err error, c ErrorClass) error {
+	if err == nil {
+		return nil
+	}
+	return &ClassifiedError{Class: c, Err: err}
+}
+
+// NewNotFound classifies the supplied error as ClassNotFound.
+func NewNotFound(err error) error { return Classify(err, ClassNotFound) }
+
+// NewAlreadyExists classifies the supplied error as ClassAlreadyExists.
+func NewAlreadyExists(err error) error { return Classify(err, ClassAlreadyExists) }
+
+// NewThrottled classifies the supplied error as ClassThrottled.
+func NewThrottled(err error) error { return Classify(err, ClassThrottled) }
+
+// NewUnauthorized classifies the supplied error as ClassUnauthorized.
+func NewUnauthorized(err error) error { return Classify(err, ClassUnauthorized) }
+
+// NewInvalidSpec classifies the supplied error as ClassInvalidSpec.
+func NewInvalidSpec(err error) error { return Classify(err, ClassInvalidSpec) }
+
+// NewTransient classifies the supplied error as ClassTransient.
+func NewTransient(err error) error { return Classify(err, ClassTransient) }
+
+// ClassOf returns the class of the first ClassifiedError in the supplied
+// error's chain of wrapped errors, if any.
+func ClassOf(err error) (ErrorClass, bool) {
+	var ce *ClassifiedError
+	if !errors.As(err, &ce) {
+		return "", false
+	}
+	return ce.Class, true
+}
+
+// IsClass returns true if the supplied error is of the supplied class.
+func IsClass(err error, c ErrorClass) bool {
+	got, ok := ClassOf(err)
+	return ok && got == c
+}
+
+// IsNotFound returns true if the supplied error is of ClassNotFound.
+func IsNotFound(err error) bool { return IsClass(err, ClassNotFound) }
+
+// IsAlreadyExists returns true if the supplied error is of ClassAlreadyExists.
+func IsAlreadyExists(err error) bool { return IsClass(err, ClassAlreadyExists) }
+
+// IsThrottled returns true if the supplied error is of ClassThrottled.
+func IsThrottled(err error) bool { return IsClass(err, ClassThrottled) }
+
+// IsUnauthorized returns true if the supplied error is of ClassUnauthorized.
+func IsUnauthorized(err error) bool { return IsClass(err, ClassUnauthorized) }
+
+// IsInvalidSpec returns true if the supplied error is of ClassInvalidSpec.
+func IsInvalidSpec(err error) bool { return IsClass(err, ClassInvalidSpec) }
+
+// IsTransient returns true if the supplied error is of ClassTransient.
+func IsTransient(err error) bool { return IsClass(err, ClassTransient) }
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestClassOf(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		class  ErrorClass
+		ok     bool
+		reason v1alpha1.ConditionReason
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Unclassified": {
+			reason: "An unclassified error should have no class",
+			err:    errBoom,
+			want: want{
+				reason: v1alpha1.ReasonReconcileError,
+			},
+		},
+		"Classified": {
+			reason: "A classified error should have its class",
+			err:    NewThrottled(errBoom),
+			want: want{
+				class:  ClassThrottled,
+				ok:     true,
+				reason: v1alpha1.ReasonExternalThrottled,
+			},
+		},
+		"WrappedClassified": {
+			reason: "A wrapped classified error should have its class",
+			err:    errors.Wrap(NewUnauthorized(errBoom), "cannot observe"),
+			want: want{
+				class:  ClassUnauthorized,
+				ok:     true,
+				reason: v1alpha1.ReasonExternalUnauthorized,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			class, ok := ClassOf(tc.err)
+			if diff := cmp.Diff(tc.want.class, class); diff != "" {
+				t.Errorf("\n%s\nClassOf(...): -want class, +got class:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nClassOf(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, v1alpha1.ErrorReason(tc.err, v1alpha1.ReasonReconcileError)); diff != "" {
+				t.Errorf("\n%s\nErrorReason(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClassifyNil(t *testing.T) {
+	if err := NewTransient(nil); err != nil {
+		t.Errorf("NewTransient(nil): want nil, got %v", err)
+	}
+}
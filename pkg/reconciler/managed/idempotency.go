@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errGenerateIdempotencyToken = "cannot generate idempotency token"
+	errPersistIdempotencyToken  = "cannot persist idempotency token"
+	errClearIdempotencyToken    = "cannot clear idempotency token"
+)
+
+type idempotencyTokens struct {
+	enabled  bool
+	generate func() (string, error)
+}
+
+// newIdempotencyToken returns a random (version 4) UUID, which is accepted as
+// a client token by most APIs that support them.
+func newIdempotencyToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// ensureIdempotencyToken ensures the supplied managed resource has an
+// idempotency token before its external resource is created. A new token is
+// persisted to the API server before it is used, so that the same token is
+// used to retry the creation even if the Reconciler crashes after the external
+// resource was created but before it could be observed.
+func (r *Reconciler) ensureIdempotencyToken(ctx context.Context, mg resource.Managed) error {
+	if !r.idempotency.enabled || meta.GetIdempotencyToken(mg) != "" {
+		return nil
+	}
+	t, err := r.idempotency.generate()
+	if err != nil {
+		return errors.Wrap(err, errGenerateIdempotencyToken)
+	}
+	meta.SetIdempotencyToken(mg, t)
+	return errors.Wrap(r.client.Update(ctx, mg), errPersistIdempotencyToken)
+}
+
+// clearIdempotencyToken removes the idempotency token of the supplied managed
+// resource once its external resource is observed to exist, so that a new
+// token is used if the external resource must be created again.
+func (r *Reconciler) clearIdempotencyToken(ctx context.Context, mg resource.Managed) error {
+	if !r.idempotency.enabled || meta.GetIdempotencyToken(mg) == "" {
+		return nil
+	}
+	meta.RemoveAnnotations(mg, meta.AnnotationKeyIdempotencyToken)
+	return errors.Wrap(r.client.Update(ctx, mg), errClearIdempotencyToken)
+}
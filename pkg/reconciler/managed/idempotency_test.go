@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestNewIdempotencyToken(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	a, err := newIdempotencyToken()
+	if err != nil {
+		t.Fatalf("newIdempotencyToken(): %s", err)
+	}
+	if !uuid.MatchString(a) {
+		t.Errorf("newIdempotencyToken(): %q is not a version 4 UUID", a)
+	}
+	b, _ := newIdempotencyToken()
+	if a == b {
+		t.Errorf("newIdempotencyToken(): want unique tokens, got %q twice", a)
+	}
+}
+
+func TestEnsureIdempotencyToken(t *testing.T) {
+	errBoom := errors.New("boom")
+	token := func() (string, error) { return "cool-token", nil }
+
+	type args struct {
+		enabled  bool
+		generate func() (string, error)
+		client   client.Client
+		mg       *fake.Managed
+	}
+	type want struct {
+		err   error
+		token string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "No token should be generated when idempotency tokens are disabled.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+		},
+		"AlreadyHasToken": {
+			reason: "An existing token should be reused.",
+			args: args{
+				enabled: true,
+				mg:      &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyIdempotencyToken: "old-token"}}},
+			},
+			want: want{
+				token: "old-token",
+			},
+		},
+		"GenerateError": {
+			reason: "Errors generating a token should be returned.",
+			args: args{
+				enabled:  true,
+				generate: func() (string, error) { return "", errBoom },
+				mg:       &fake.Managed{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGenerateIdempotencyToken),
+			},
+		},
+		"PersistError": {
+			reason: "Errors persisting a new token should be returned.",
+			args: args{
+				enabled:  true,
+				generate: token,
+				client:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:       &fake.Managed{},
+			},
+			want: want{
+				err:   errors.Wrap(errBoom, errPersistIdempotencyToken),
+				token: "cool-token",
+			},
+		},
+		"Success": {
+			reason: "A new token should be generated and persisted.",
+			args: args{
+				enabled:  true,
+				generate: token,
+				client:   &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				mg:       &fake.Managed{},
+			},
+			want: want{
+				token: "cool-token",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				client:      tc.args.client,
+				idempotency: idempotencyTokens{enabled: tc.args.enabled, generate: tc.args.generate},
+			}
+			err := r.ensureIdempotencyToken(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ensureIdempotencyToken(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, meta.GetIdempotencyToken(tc.args.mg)); diff != "" {
+				t.Errorf("\n%s\nr.ensureIdempotencyToken(...): -want token, +got token:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClearIdempotencyToken(t *testing.T) {
+	errBoom := errors.New("boom")
+	withToken := func() *fake.Managed {
+		return &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyIdempotencyToken: "cool-token"}}}
+	}
+
+	type args struct {
+		enabled bool
+		client  client.Client
+		mg      *fake.Managed
+	}
+	type want struct {
+		err   error
+		token string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Disabled": {
+			reason: "Tokens should not be cleared when idempotency tokens are disabled.",
+			args: args{
+				mg: withToken(),
+			},
+			want: want{
+				token: "cool-token",
+			},
+		},
+		"NoToken": {
+			reason: "Nothing should be persisted if there is no token to clear.",
+			args: args{
+				enabled: true,
+				mg:      &fake.Managed{},
+			},
+		},
+		"UpdateError": {
+			reason: "Errors persisting the cleared token should be returned.",
+			args: args{
+				enabled: true,
+				client:  &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:      withToken(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errClearIdempotencyToken),
+			},
+		},
+		"Success": {
+			reason: "The token should be removed and persisted.",
+			args: args{
+				enabled: true,
+				client:  &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				mg:      withToken(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{client: tc.args.client, idempotency: idempotencyTokens{enabled: tc.args.enabled}}
+			err := r.clearIdempotencyToken(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.clearIdempotencyToken(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, meta.GetIdempotencyToken(tc.args.mg)); diff != "" {
+				t.Errorf("\n%s\nr.clearIdempotencyToken(...): -want token, +got token:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// External operations whose durations are recorded.
+const (
+	OperationConnect = "connect"
+	OperationObserve = "observe"
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+)
+
+// DurationHistograms are histograms of the duration of managed resource
+// reconciles by kind, and of the external operations made while reconciling by
+// kind and operation. They also record how long managed resources take to
+// first become ready after they are created, and to be deleted after their
+// deletion is requested, by kind.
+type DurationHistograms struct {
+	Reconcile  *prometheus.HistogramVec
+	External   *prometheus.HistogramVec
+	FirstReady *prometheus.HistogramVec
+	Deletion   *prometheus.HistogramVec
+}
+
+// NewDurationHistograms returns a new DurationHistograms.
+func NewDurationHistograms() *DurationHistograms {
+	return &DurationHistograms{
+		Reconcile: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "reconcile_duration_seconds",
+			Help:      "The time taken to reconcile a managed resource.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"gvk"}),
+		External: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "external_operation_duration_seconds",
+			Help:      "The time taken by an external operation made while reconciling a managed resource.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"gvk", "operation"}),
+		FirstReady: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "first_time_to_readiness_seconds",
+			Help:      "The time taken for a managed resource to first become ready after it was created.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+		}, []string{"gvk"}),
+		Deletion: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "deletion_seconds",
+			Help:      "The time taken for a managed resource's finalizer to be removed after its deletion was requested.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+		}, []string{"gvk"}),
+	}
+}
+
+// Describe sends the descriptors of each histogram to the supplied channel.
+func (h *DurationHistograms) Describe(ch chan<- *prometheus.Desc) {
+	h.Reconcile.Describe(ch)
+	h.External.Describe(ch)
+	h.FirstReady.Describe(ch)
+	h.Deletion.Describe(ch)
+}
+
+// Collect sends each histogram to the supplied channel.
+func (h *DurationHistograms) Collect(ch chan<- prometheus.Metric) {
+	h.Reconcile.Collect(ch)
+	h.External.Collect(ch)
+	h.FirstReady.Collect(ch)
+	h.Deletion.Collect(ch)
+}
+
+var (
+	registerDurations sync.Once
+	durations         = NewDurationHistograms()
+)
+
+// registeredDurationHistograms returns DurationHistograms that are registered
+// with controller-runtime's metrics registry. The histograms are shared by all
+// Reconcilers, which are distinguished by the kind of managed resource they
+// reconcile, and are registered only once.
+func registeredDurationHistograms() *DurationHistograms {
+	registerDurations.Do(func() { metrics.Registry.MustRegister(durations) })
+	return durations
+}
+
+// A durationObserver records durations to DurationHistograms, if any.
+type durationObserver struct {
+	gvk        string
+	histograms *DurationHistograms
+}
+
+// reconcile starts timing a reconcile. The returned function records the time
+// elapsed since reconcile was called.
+func (o durationObserver) reconcile() func() {
+	if o.histograms == nil {
+		return func() {}
+	}
+	t := time.Now()
+	return func() { o.histograms.Reconcile.WithLabelValues(o.gvk).Observe(time.Since(t).Seconds()) }
+}
+
+// external starts timing the supplied external operation. The returned
+// function records the time elapsed since external was called.
+func (o durationObserver) external(operation string) func() {
+	if o.histograms == nil {
+		return func() {}
+	}
+	t := time.Now()
+	return func() { o.histograms.External.WithLabelValues(o.gvk, operation).Observe(time.Since(t).Seconds()) }
+}
+
+// ready tracks whether the supplied managed resource becomes ready for the
+// first time. The returned function records the time elapsed since the managed
+// resource was created if it became ready since ready was called; it is
+// typically deferred until the end of a reconcile. A managed resource is
+// considered to be becoming ready for the first time if it had no Ready
+// condition, or was not ready because it was being created.
+func (o durationObserver) ready(mg resource.Managed) func() {
+	if o.histograms == nil {
+		return func() {}
+	}
+	c := mg.GetCondition(v1alpha1.TypeReady)
+	if c.Status != corev1.ConditionUnknown && c.Reason != v1alpha1.ReasonCreating {
+		return func() {}
+	}
+	return func() {
+		if mg.GetCondition(v1alpha1.TypeReady).Status != corev1.ConditionTrue {
+			return
+		}
+		o.histograms.FirstReady.WithLabelValues(o.gvk).Observe(time.Since(mg.GetCreationTimestamp().Time).Seconds())
+	}
+}
+
+// deleted records the time elapsed since deletion of the supplied managed
+// resource was requested. It should be called once its finalizer is removed.
+func (o durationObserver) deleted(mg resource.Managed) {
+	if o.histograms == nil || mg.GetDeletionTimestamp() == nil {
+		return
+	}
+	o.histograms.Deletion.WithLabelValues(o.gvk).Observe(time.Since(mg.GetDeletionTimestamp().Time).Seconds())
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+// samples returns the total number of samples observed by the histograms of
+// the supplied collector.
+func samples(t *testing.T, c prometheus.Collector) uint64 {
+	t.Helper()
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("reg.Gather(): %v", err)
+	}
+	var n uint64
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			n += m.GetHistogram().GetSampleCount()
+		}
+	}
+	return n
+}
+
+func TestDurationObserver(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		histograms *DurationHistograms
+		want       uint64
+	}{
+		"NoHistograms": {
+			reason: "Durations should not be recorded when no histograms are configured",
+		},
+		"Histograms": {
+			reason:     "The reconcile and each external operation should be recorded",
+			histograms: NewDurationHistograms(),
+			want:       3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := durationObserver{gvk: "Cool.v1.example.org", histograms: tc.histograms}
+
+			stop := o.reconcile()
+			o.external(OperationObserve)()
+			o.external(OperationUpdate)()
+			stop()
+
+			if tc.histograms == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, samples(t, tc.histograms)); diff != "" {
+				t.Errorf("\n%s\nsamples: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDurationObserverReady(t *testing.T) {
+	type args struct {
+		from []v1alpha1.Condition
+		to   []v1alpha1.Condition
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   uint64
+	}{
+		"FirstReady": {
+			reason: "A managed resource that becomes ready after being created should be recorded",
+			args: args{
+				from: []v1alpha1.Condition{v1alpha1.Creating()},
+				to:   []v1alpha1.Condition{v1alpha1.Available()},
+			},
+			want: 1,
+		},
+		"NoReadyCondition": {
+			reason: "A managed resource that becomes ready before having any Ready condition should be recorded",
+			args: args{
+				to: []v1alpha1.Condition{v1alpha1.Available()},
+			},
+			want: 1,
+		},
+		"StillCreating": {
+			reason: "A managed resource that has not become ready should not be recorded",
+			args: args{
+				from: []v1alpha1.Condition{v1alpha1.Creating()},
+				to:   []v1alpha1.Condition{v1alpha1.Creating()},
+			},
+		},
+		"ReadyAgain": {
+			reason: "A managed resource that becomes ready after being unavailable should not be recorded",
+			args: args{
+				from: []v1alpha1.Condition{v1alpha1.Unavailable()},
+				to:   []v1alpha1.Condition{v1alpha1.Available()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewDurationHistograms()
+			o := durationObserver{gvk: "Cool.v1.example.org", histograms: h}
+
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-1 * time.Minute))}}
+			mg.SetConditions(tc.args.from...)
+			done := o.ready(mg)
+			mg.SetConditions(tc.args.to...)
+			done()
+
+			if diff := cmp.Diff(tc.want, samples(t, h.FirstReady)); diff != "" {
+				t.Errorf("\n%s\nsamples: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDurationObserverDeleted(t *testing.T) {
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason string
+		mg     *fake.Managed
+		want   uint64
+	}{
+		"Deleted": {
+			reason: "The deletion of a managed resource should be recorded",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			want:   1,
+		},
+		"NotDeleted": {
+			reason: "A managed resource without a deletion timestamp should not be recorded",
+			mg:     &fake.Managed{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewDurationHistograms()
+			o := durationObserver{gvk: "Cool.v1.example.org", histograms: h}
+			o.deleted(tc.mg)
+
+			if diff := cmp.Diff(tc.want, samples(t, h.Deletion)); diff != "" {
+				t.Errorf("\n%s\nsamples: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
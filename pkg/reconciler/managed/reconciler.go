@@ -18,19 +18,25 @@ package managed
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
 
@@ -51,23 +57,40 @@ const (
 	errReconcileCreate  = "create failed"
 	errReconcileUpdate  = "update failed"
 	errReconcileDelete  = "delete failed"
+	errSetupController  = "cannot set up managed resource controller"
+
+	errFmtExternalNameChanged = "external name was changed from %q to %q after the external resource was created"
 )
 
 // Event reasons.
 const (
-	reasonCannotConnect     event.Reason = "CannotConnectToProvider"
-	reasonCannotInitialize  event.Reason = "CannotInitializeManagedResource"
-	reasonCannotResolveRefs event.Reason = "CannotResolveResourceReferences"
-	reasonCannotObserve     event.Reason = "CannotObserveExternalResource"
-	reasonCannotCreate      event.Reason = "CannotCreateExternalResource"
-	reasonCannotDelete      event.Reason = "CannotDeleteExternalResource"
-	reasonCannotPublish     event.Reason = "CannotPublishConnectionDetails"
-	reasonCannotUnpublish   event.Reason = "CannotUnpublishConnectionDetails"
-	reasonCannotUpdate      event.Reason = "CannotUpdateExternalResource"
+	reasonCannotTrack        event.Reason = "CannotTrackProviderUsage"
+	reasonCannotConnect      event.Reason = "CannotConnectToProvider"
+	reasonCannotInitialize   event.Reason = "CannotInitializeManagedResource"
+	reasonCannotResolveRefs  event.Reason = "CannotResolveResourceReferences"
+	reasonCannotValidateSpec event.Reason = "CannotValidateManagedResourceSpec"
+	reasonReferencesNotReady event.Reason = "ReferencesNotReady"
+	reasonCannotObserve      event.Reason = "CannotObserveExternalResource"
+	reasonCannotCreate       event.Reason = "CannotCreateExternalResource"
+	reasonCannotDelete       event.Reason = "CannotDeleteExternalResource"
+	reasonCannotPublish      event.Reason = "CannotPublishConnectionDetails"
+	reasonCannotUnpublish    event.Reason = "CannotUnpublishConnectionDetails"
+	reasonCannotUpdate       event.Reason = "CannotUpdateExternalResource"
+
+	reasonExternalNameChanged event.Reason = "ExternalNameChanged"
 
 	reasonDeleted event.Reason = "DeletedExternalResource"
 	reasonCreated event.Reason = "CreatedExternalResource"
 	reasonUpdated event.Reason = "UpdatedExternalResource"
+
+	reasonConditionChanged event.Reason = "ConditionChanged"
+
+	reasonPlanned event.Reason = "PlannedExternalResourceChange"
+
+	reasonRotated      event.Reason = "RotatedCredentials"
+	reasonCannotRotate event.Reason = "CannotRotateCredentials"
+
+	reasonRetryBudgetExhausted event.Reason = "RetryBudgetExhausted"
 )
 
 // ControllerName returns the recommended name for controllers that use this
@@ -76,6 +99,31 @@ func ControllerName(kind string) string {
 	return "managed/" + strings.ToLower(kind)
 }
 
+// ControllerNameForKind returns the recommended name for controllers that use
+// this package to reconcile the supplied kind of managed resource, for example
+// managed/bucket.storage.example.org. Unlike ControllerName it includes the
+// kind's API group, but not its version, so that names remain stable across
+// API versions.
+func ControllerNameForKind(of resource.ManagedKind) string {
+	return "managed/" + strings.ToLower(of.Kind+"."+of.Group)
+}
+
+// Setup adds a controller that reconciles the supplied kind of managed
+// resource to the supplied manager. The controller is named per
+// ControllerNameForKind, so the workqueue and reconcile metrics recorded by
+// controller-runtime are labelled by the kind of managed resource.
+func Setup(mgr manager.Manager, of resource.ManagedKind, o controller.Options, ro ...ReconcilerOption) error {
+	name := ControllerNameForKind(of)
+	o = o.ForController(name)
+
+	r := NewReconciler(mgr, of, append([]ReconcilerOption{WithOptions(o)}, ro...)...)
+	return errors.Wrap(builder.ControllerManagedBy(mgr).
+		Named(name).
+		For(resource.MustCreateObject(schema.GroupVersionKind(of), mgr.GetScheme())).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(o.Drained(o.RateLimited(r))), errSetupController)
+}
+
 // ConnectionDetails created or updated during an operation on an external
 // resource, for example usernames, passwords, endpoints, ports, etc.
 type ConnectionDetails map[string][]byte
@@ -130,6 +178,43 @@ func (cc InitializerChain) Initialize(ctx context.Context, mg resource.Managed)
 	return nil
 }
 
+// An ExternalNameRecorder records the external name of a managed resource
+// whose external resource is known to exist, so that subsequent changes to
+// its external name may be detected.
+type ExternalNameRecorder interface {
+	RecordExternalName(ctx context.Context, mg resource.Managed) error
+}
+
+// An ExternalNameRecorderFn is a function that satisfies the
+// ExternalNameRecorder interface.
+type ExternalNameRecorderFn func(ctx context.Context, mg resource.Managed) error
+
+// RecordExternalName calls ExternalNameRecorderFn function.
+func (fn ExternalNameRecorderFn) RecordExternalName(ctx context.Context, mg resource.Managed) error {
+	return fn(ctx, mg)
+}
+
+// An ExternalNamePolicy determines how the Reconciler handles a change to the
+// external name of a managed resource whose external resource was already
+// created.
+type ExternalNamePolicy string
+
+// External name policies.
+const (
+	// ExternalNameChangeBlock causes the Reconciler to refuse to reconcile a
+	// managed resource whose external name was changed, until the change is
+	// reverted. This prevents the old external resource from being silently
+	// abandoned. A deleted managed resource is not blocked; the change is
+	// ignored and the old external resource is finalized.
+	ExternalNameChangeBlock ExternalNamePolicy = "Block"
+
+	// ExternalNameChangeRetarget causes the Reconciler to treat a change to
+	// the external name of a managed resource as an explicit request to
+	// manage a different external resource. The old external resource is
+	// neither updated nor deleted.
+	ExternalNameChangeRetarget ExternalNamePolicy = "Retarget"
+)
+
 // A Finalizer finalizes the deletion of a resource claim.
 type Finalizer interface {
 	// AddFinalizer to the supplied Managed resource.
@@ -206,6 +291,8 @@ func (ec ExternalConnectorFn) Connect(ctx context.Context, mg resource.Managed)
 // idempotent. For example, Create call should not return AlreadyExists error
 // if it's called again with the same parameters or Delete call should not
 // return error if there is an ongoing deletion or resource does not exist.
+// Errors may be classified, for example using NewThrottled, to influence how
+// the managed resource reconciler requeues and reports them.
 type ExternalClient interface {
 	// Observe the external resource the supplied Managed resource represents,
 	// if any. Observe implementations must not modify the external resource,
@@ -296,6 +383,17 @@ type ExternalObservation struct {
 	ResourceExists    bool
 	ResourceUpToDate  bool
 	ConnectionDetails ConnectionDetails
+
+	// Conditions observed of the external resource, typically of types
+	// declared by a provider using a v1alpha1.ConditionDefinition. They are
+	// set on the managed resource, and an event is recorded each time one of
+	// them changes status.
+	Conditions []v1alpha1.Condition
+
+	// Diff is an optional, human-readable description of how the external
+	// resource differs from the desired state described by the managed
+	// resource. It is reported when reconciling in dry-run mode.
+	Diff string
 }
 
 // An ExternalCreation is the result of the creation of an external resource.
@@ -319,6 +417,20 @@ type Reconciler struct {
 	shortWait time.Duration
 	longWait  time.Duration
 	timeout   time.Duration
+	limiter   workqueue.RateLimiter
+
+	dryRun             bool
+	externalNamePolicy ExternalNamePolicy
+
+	impersonation resource.ImpersonationFn
+
+	transitions transitionObserver
+	durations   durationObserver
+	backoff     backoffRecorder
+	budget      *retryBudget
+	rotation    credentialRotation
+	idempotency idempotencyTokens
+	audit       operationAuditor
 
 	// The below structs embed the set of interfaces used to implement the
 	// managed resource reconciler. We do this primarily for readability, so
@@ -333,17 +445,23 @@ type Reconciler struct {
 
 type mrManaged struct {
 	ConnectionPublisher
+	ExternalNameRecorder
 	Finalizer
 	Initializer
 	ReferenceResolver
+	SpecValidator
+	resource.Tracker
 }
 
 func defaultMRManaged(m manager.Manager) mrManaged {
 	return mrManaged{
-		ConnectionPublisher: NewAPISecretPublisher(m.GetClient(), m.GetScheme()),
-		Finalizer:           NewAPIFinalizer(m.GetClient(), managedFinalizerName),
-		Initializer:         NewNameAsExternalName(m.GetClient()),
-		ReferenceResolver:   NewAPIReferenceResolver(m.GetClient()),
+		ConnectionPublisher:  NewAPISecretPublisher(m.GetClient(), m.GetScheme()),
+		ExternalNameRecorder: NewAPIExternalNameRecorder(m.GetClient()),
+		Finalizer:            NewAPIFinalizer(m.GetClient(), managedFinalizerName),
+		Initializer:          NewNameAsExternalName(m.GetClient()),
+		ReferenceResolver:    NewAPIReferenceResolver(m.GetClient()),
+		SpecValidator:        SpecValidatorFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+		Tracker:              resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
 	}
 }
 
@@ -357,6 +475,14 @@ func defaultMRExternal() mrExternal {
 	}
 }
 
+// A nopRateLimiter never delays a request, causing the Reconciler to fall back
+// to its short wait.
+type nopRateLimiter struct{}
+
+func (nopRateLimiter) When(_ interface{}) time.Duration { return 0 }
+func (nopRateLimiter) NumRequeues(_ interface{}) int    { return 0 }
+func (nopRateLimiter) Forget(_ interface{})             {}
+
 // A ReconcilerOption configures a Reconciler.
 type ReconcilerOption func(*Reconciler)
 
@@ -391,6 +517,129 @@ func WithLongWait(after time.Duration) ReconcilerOption {
 	}
 }
 
+// WithRateLimiter specifies how long the Reconciler should wait before
+// requeueing a managed resource when it encounters an error. The Reconciler
+// requeues after a short wait by default. A managed resource is forgotten by
+// the supplied RateLimiter when it is successfully reconciled. Because the
+// Reconciler reports most errors via status conditions rather than by
+// returning them, this RateLimiter rather than that of the controller's
+// workqueue determines how quickly failing managed resources are retried.
+// Package ratelimiter provides suitable RateLimiters, for example:
+//
+//	// Back off exponentially from 1s to 60s, per managed resource.
+//	WithRateLimiter(ratelimiter.NewItemExponential())
+//
+//	// As above, bounded by a token bucket shared by a provider's controllers.
+//	WithRateLimiter(ratelimiter.NewDefaultManagedRateLimiter("aws"))
+func WithRateLimiter(l workqueue.RateLimiter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.limiter = l
+	}
+}
+
+// WithBackoff specifies that the Reconciler should back off exponentially,
+// per the supplied options, when it encounters an error. It is shorthand for
+// WithRateLimiter(ratelimiter.NewItemExponential(o...)). Supplying the same
+// options to the controller's workqueue rate limiter, e.g. via
+// ratelimiter.NewDefaultManagedRateLimiter, allows one set of options to
+// control both requeue and retry pacing.
+func WithBackoff(o ...ratelimiter.ExponentialOption) ReconcilerOption {
+	return WithRateLimiter(ratelimiter.NewItemExponential(o...))
+}
+
+// WithConditionTransitionHook specifies a hook that the Reconciler should
+// notify when a condition of the supplied types changes status while a managed
+// resource is reconciled. Ready and Synced conditions are tracked if no types
+// are supplied. Use a ConditionTransitionCounter to chart condition flapping,
+// for example:
+//
+//	c := NewConditionTransitionCounter()
+//	metrics.Registry.MustRegister(c)
+//	WithConditionTransitionHook(c)
+func WithConditionTransitionHook(h ConditionTransitionHook, types ...v1alpha1.ConditionType) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.transitions.hook = h
+		if len(types) > 0 {
+			r.transitions.types = types
+		}
+	}
+}
+
+// WithDurationMetrics specifies that the Reconciler should record histograms of
+// the duration of each reconcile, and of each external operation it makes, as
+// well as how long managed resources take to first become ready and to be
+// deleted. The histograms are registered with controller-runtime's metrics
+// registry, and are labelled with the kind of managed resource being reconciled.
+func WithDurationMetrics() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.durations.histograms = registeredDurationHistograms()
+	}
+}
+
+// WithBackoffMetrics specifies that the Reconciler should record gauges of the
+// backoff state of each managed resource that fails to reconcile; how many
+// consecutive times it has failed, and when it will next be reconciled. The
+// gauges are registered with controller-runtime's metrics registry, and are
+// labelled with the kind and name of the managed resource. A managed resource's
+// gauges are removed once it reconciles successfully or is deleted. The backoff
+// state is recorded to the status of managed resources that support it
+// regardless.
+func WithBackoffMetrics() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.backoff.gauges = registeredBackoffGauges()
+	}
+}
+
+// WithRetryBudget specifies that the Reconciler should park a managed resource
+// that fails to reconcile the supplied number of consecutive times, or that
+// fails to reconcile for the supplied duration. Either limit may be zero to
+// disable it. Parked managed resources have a RetryBudgetExhausted Synced
+// condition, and are not reconciled again until their spec changes or they
+// are annotated with meta.AnnotationKeyRetry. Managed resources are retried
+// indefinitely by default.
+func WithRetryBudget(maxFailures int, maxDuration time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.budget = newRetryBudget(maxFailures, maxDuration)
+	}
+}
+
+// WithCredentialRotation specifies that the Reconciler should rotate the
+// credentials of managed resources whose ExternalClient is a CredentialRotator
+// once per the supplied period. Previous credentials are published alongside
+// new credentials until the supplied grace window has passed, allowing
+// consumers of the connection secret time to pick up the new credentials.
+// Credentials are not rotated by default.
+func WithCredentialRotation(period, grace time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.rotation.period = period
+		r.rotation.grace = grace
+	}
+}
+
+// WithIdempotencyTokens specifies that the Reconciler should annotate each
+// managed resource with an idempotency token before creating its external
+// resource. The token is persisted before the ExternalClient's Create method is
+// called, and is removed once the external resource is observed to exist.
+// ExternalClients may read the token using meta.GetIdempotencyToken and pass
+// it to APIs that support client tokens, so that creations retried after a
+// failure or a controller crash do not create duplicate external resources.
+func WithIdempotencyTokens() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.idempotency.enabled = true
+	}
+}
+
+// WithOperationAudit specifies that the Reconciler should record the last
+// operation it performed on the external resource of each managed resource;
+// its type, when it was performed, whether it succeeded, and a digest of its
+// outcome. The audit is recorded to the status of managed resources that
+// support it.
+func WithOperationAudit() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.audit.enabled = true
+	}
+}
+
 // WithExternalConnecter specifies how the Reconciler should connect to the API
 // used to sync and delete external resources.
 func WithExternalConnecter(c ExternalConnecter) ReconcilerOption {
@@ -415,6 +664,17 @@ func WithInitializers(i ...Initializer) ReconcilerOption {
 	}
 }
 
+// WithSpecValidators specifies how the Reconciler should validate the spec of
+// a managed resource before it makes any external API calls, for example
+// using a CRDSchemaValidator and any provider specific validators. Managed
+// resources whose spec is invalid are not observed, created, updated, or
+// deleted. Specs are not validated by default.
+func WithSpecValidators(v ...SpecValidator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.managed.SpecValidator = SpecValidatorChain(v)
+	}
+}
+
 // WithFinalizer specifies how the Reconciler should add and remove
 // finalizers to and from the managed resource.
 func WithFinalizer(f Finalizer) ReconcilerOption {
@@ -423,6 +683,24 @@ func WithFinalizer(f Finalizer) ReconcilerOption {
 	}
 }
 
+// WithExternalNameRecorder specifies how the Reconciler should record the
+// external name of a managed resource whose external resource is known to
+// exist.
+func WithExternalNameRecorder(er ExternalNameRecorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.managed.ExternalNameRecorder = er
+	}
+}
+
+// WithExternalNameChangePolicy specifies how the Reconciler should handle a
+// change to the external name of a managed resource whose external resource
+// was already created. Such changes are blocked by default.
+func WithExternalNameChangePolicy(p ExternalNamePolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.externalNamePolicy = p
+	}
+}
+
 // WithReferenceResolver specifies how the Reconciler should resolve any
 // inter-resource references it encounters while reconciling managed resources.
 func WithReferenceResolver(rr ReferenceResolver) ReconcilerOption {
@@ -431,6 +709,15 @@ func WithReferenceResolver(rr ReferenceResolver) ReconcilerOption {
 	}
 }
 
+// WithTracker specifies how the Reconciler should track managed resources,
+// for example by recording their usage of a provider. Managed resources are
+// tracked before the Reconciler connects to their provider.
+func WithTracker(t resource.Tracker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.managed.Tracker = t
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(l logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -445,6 +732,57 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithDryRun causes the Reconciler to reconcile every managed resource in
+// dry-run mode, as if it were annotated with meta.AnnotationKeyDryRun. In
+// dry-run mode external resources are observed, but never created, updated,
+// or deleted. Instead the change the Reconciler would have made is recorded as
+// a Planned condition and an event.
+func WithDryRun() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.dryRun = true
+	}
+}
+
+// WithStatusWriter specifies how the Reconciler should write the status of
+// the managed resources it reconciles, for example using a
+// resource.DebouncedStatusWriter. Status is written using the manager's client
+// by default.
+func WithStatusWriter(w client.StatusWriter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = &resource.StatusClient{Client: r.client, StatusWriter: w}
+	}
+}
+
+// WithImpersonation causes the Reconciler to act on behalf of each managed
+// resource using the identity, if any, that the supplied ImpersonationFn
+// derives from it. The identity is associated with the context of each
+// request the Reconciler and its components make after reading the managed
+// resource. It is impersonated only by clients that honor it, for example a
+// resource.ImpersonatingClient; see controller.Options.Impersonation.
+func WithImpersonation(fn resource.ImpersonationFn) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.impersonation = fn
+	}
+}
+
+// WithOptions configures the Reconciler using the supplied controller Options.
+// Its Logger and Recorder are used unless they are nil. Its PollInterval
+// determines how long the Reconciler waits before observing an up-to-date
+// managed resource again; see WithLongWait.
+func WithOptions(o controller.Options) ReconcilerOption {
+	return func(r *Reconciler) {
+		if o.Logger != nil {
+			r.log = o.Logger
+		}
+		if o.Recorder != nil {
+			r.record = o.Recorder
+		}
+		if o.PollInterval > 0 {
+			r.longWait = o.PollInterval
+		}
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles managed resources of the
 // supplied ManagedKind with resources in an external system such as a cloud
 // provider API. It panics if asked to reconcile a managed resource kind that is
@@ -462,15 +800,25 @@ func NewReconciler(m manager.Manager, of resource.ManagedKind, o ...ReconcilerOp
 	_ = nm()
 
 	r := &Reconciler{
-		client:     m.GetClient(),
-		newManaged: nm,
-		shortWait:  defaultManagedShortWait,
-		longWait:   defaultManagedLongWait,
-		timeout:    reconcileTimeout,
-		managed:    defaultMRManaged(m),
-		external:   defaultMRExternal(),
-		log:        logging.NewNopLogger(),
-		record:     event.NewNopRecorder(),
+		client:      m.GetClient(),
+		newManaged:  nm,
+		shortWait:   defaultManagedShortWait,
+		longWait:    defaultManagedLongWait,
+		timeout:     reconcileTimeout,
+		limiter:     nopRateLimiter{},
+		rotation:    credentialRotation{now: time.Now},
+		idempotency: idempotencyTokens{generate: newIdempotencyToken},
+		audit:       operationAuditor{now: time.Now},
+		transitions: transitionObserver{
+			gvk:   schema.GroupVersionKind(of),
+			types: []v1alpha1.ConditionType{v1alpha1.TypeReady, v1alpha1.TypeSynced},
+		},
+		durations: durationObserver{gvk: schema.GroupVersionKind(of).String()},
+		backoff:   backoffRecorder{gvk: schema.GroupVersionKind(of).String(), now: time.Now},
+		managed:   defaultMRManaged(m),
+		external:  defaultMRExternal(),
+		log:       logging.NewNopLogger(),
+		record:    event.NewNopRecorder(),
 	}
 
 	for _, ro := range o {
@@ -480,30 +828,140 @@ func NewReconciler(m manager.Manager, of resource.ManagedKind, o ...ReconcilerOp
 	return r
 }
 
+func conditionChangedMessage(c v1alpha1.Condition) string {
+	msg := fmt.Sprintf("Condition %s is now %s", c.Type, c.Status)
+	if c.Reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, c.Reason)
+	}
+	return msg
+}
+
+// plan records the change the Reconciler would make to the external resource
+// represented by the supplied managed resource, without making it. A managed
+// resource that is deleted in dry-run mode is not finalized, so that its plan
+// remains visible; it will be deleted once dry-run mode is disabled.
+func (r *Reconciler) plan(ctx context.Context, req reconcile.Request, mg resource.Managed, o ExternalObservation, log logging.Logger, record event.Recorder) (reconcile.Result, error) {
+	var c v1alpha1.Condition
+	switch {
+	case meta.WasDeleted(mg) && o.ResourceExists && mg.GetReclaimPolicy() == v1alpha1.ReclaimDelete:
+		c = v1alpha1.PlannedDelete()
+	case meta.WasDeleted(mg):
+		c = v1alpha1.PlannedNone()
+	case !o.ResourceExists:
+		c = v1alpha1.PlannedCreate(o.Diff)
+	case !o.ResourceUpToDate:
+		c = v1alpha1.PlannedUpdate(o.Diff)
+	default:
+		c = v1alpha1.PlannedNone()
+	}
+
+	// We only emit an event when the plan changes, to avoid emitting the
+	// same event every time we poll the external resource.
+	if !mg.GetCondition(v1alpha1.TypePlanned).Equal(c) {
+		msg := string(c.Reason)
+		if c.Message != "" {
+			msg = fmt.Sprintf("%s: %s", msg, c.Message)
+		}
+		record.Event(mg, event.Normal(reasonPlanned, msg))
+	}
+
+	log.Debug("Planned change to external resource", "plan", c.Reason, "requeue-after", time.Now().Add(r.longWait))
+	r.forget(req, mg)
+	mg.SetConditions(c, v1alpha1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: r.longWait}, errors.Wrap(r.client.Status().Update(ctx, mg), errUpdateManagedStatus)
+}
+
+// removePlan removes any Planned condition left behind by a managed resource
+// that was previously reconciled in dry-run mode.
+func removePlan(mg resource.Managed) {
+	if c, ok := mg.(interface {
+		RemoveConditions(ct ...v1alpha1.ConditionType)
+	}); ok {
+		c.RemoveConditions(v1alpha1.TypePlanned)
+	}
+}
+
+// forget resets the backoff of the supplied request, and clears any backoff
+// state recorded for the supplied managed resource.
+func (r *Reconciler) forget(req reconcile.Request, mg resource.Managed) {
+	r.limiter.Forget(req)
+	r.backoff.succeeded(mg)
+	r.budget.reset(mg)
+}
+
+// errorWait returns how long the Reconciler should wait before requeueing the
+// supplied request after encountering the supplied error, and records the
+// resulting backoff state for the supplied managed resource.
+func (r *Reconciler) errorWait(req reconcile.Request, mg resource.Managed, err error) time.Duration {
+	d := r.backoffWait(req, err)
+	r.backoff.failed(mg, r.limiter.NumRequeues(req), d)
+	r.budget.failed(mg, err)
+	return d
+}
+
+func (r *Reconciler) backoffWait(req reconcile.Request, err error) time.Duration {
+	if IsUnauthorized(err) || IsInvalidSpec(err) {
+		// Retrying is unlikely to succeed until the managed resource or the
+		// provider's credentials change, so there's no point backing off.
+		return r.longWait
+	}
+	d := r.limiter.When(req)
+	if d <= 0 || (IsThrottled(err) && d < r.shortWait) {
+		return r.shortWait
+	}
+	return d
+}
+
+// impersonate returns a copy of the supplied context associated with the
+// identity, if any, that should be impersonated on behalf of the supplied
+// managed resource.
+func (r *Reconciler) impersonate(ctx context.Context, mg resource.Managed) context.Context {
+	if r.impersonation == nil {
+		return ctx
+	}
+	ic, ok := r.impersonation(mg)
+	if !ok {
+		return ctx
+	}
+	return resource.WithImpersonation(ctx, ic)
+}
+
 // Reconcile a managed resource with an external resource.
 func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) { // nolint:gocyclo
 	// NOTE(negz): This method is a well over our cyclomatic complexity goal.
 	// Be wary of adding additional complexity.
 
-	log := r.log.WithValues("request", req)
+	defer r.durations.reconcile()()
+
+	log := r.log.WithValues("request", req, "reconcile-id", newReconcileID())
 	log.Debug("Reconciling")
 
 	ctx, cancel := context.WithTimeout(context.Background(), r.timeout+reconcileGracePeriod)
 	defer cancel()
 
-	// Govet linter has a check for lost cancel funcs but it's a false positive
-	// for child contexts as because parent's cancel is called, so we skip it
-	// for this line.
-	externalCtx, _ := context.WithTimeout(ctx, r.timeout) // nolint:govet
-
 	managed := r.newManaged()
 	if err := r.client.Get(ctx, req.NamespacedName, managed); err != nil {
 		// There's no need to requeue if we no longer exist. Otherwise we'll be
 		// requeued implicitly because we return an error.
 		log.Debug("Cannot get managed resource", "error", err)
+		if resource.IgnoreNotFound(err) == nil {
+			r.backoff.deleted(req.Name)
+		}
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetManaged)
 	}
 
+	// We read the managed resource using our own identity, but may act on its
+	// behalf using an identity derived from it from here on.
+	ctx = r.impersonate(ctx, managed)
+
+	// Govet linter has a check for lost cancel funcs but it's a false positive
+	// for child contexts as because parent's cancel is called, so we skip it
+	// for this line.
+	externalCtx, _ := context.WithTimeout(ctx, r.timeout) // nolint:govet
+
+	defer r.transitions.track(managed)()
+	defer r.durations.ready(managed)()
+
 	record := r.record.WithAnnotations("external-name", meta.GetExternalName(managed))
 	log = log.WithValues(
 		"uid", managed.GetUID(),
@@ -511,26 +969,78 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 		"external-name", meta.GetExternalName(managed),
 	)
 
+	if meta.IsPaused(managed) {
+		// We don't observe or change anything while reconciliation is paused.
+		// We'll be requeued when the paused annotation is removed.
+		log.Debug("Reconciliation is paused")
+		return reconcile.Result{}, nil
+	}
+
+	if parked, err := r.park(ctx, managed, log, record); parked || err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.managed.Track(ctx, managed); err != nil {
+		// We track managed resources before connecting so that, for example,
+		// a provider cannot be deleted while it's in use. If this is the
+		// first time we encounter this issue we'll be requeued implicitly
+		// when we update our status with the new error condition. If not, we
+		// want to try again after a short wait.
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot track managed resource", "error", err, "requeue-after", time.Now().Add(wait))
+		record.Event(managed, event.Warning(reasonCannotTrack, err))
+		managed.SetConditions(v1alpha1.ReconcileError(err))
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+	}
+
+	stop := r.durations.external(OperationConnect)
 	external, err := r.external.Connect(externalCtx, managed)
+	stop()
 	if err != nil {
 		// We'll usually hit this case if our Provider or its secret are missing
 		// or invalid. If this is first time we encounter this issue we'll be
 		// requeued implicitly when we update our status with the new error
 		// condition. If not, we want to try again after a short wait.
-		log.Debug("Cannot connect to provider", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot connect to provider", "error", err, "requeue-after", time.Now().Add(wait))
 		record.Event(managed, event.Warning(reasonCannotConnect, err))
 		managed.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errReconcileConnect)))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
 	if err := r.managed.Initialize(ctx, managed); err != nil {
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition.
 		// If not, we want to try again after a short wait.
-		log.Debug("Cannot initialize managed resource", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot initialize managed resource", "error", err, "requeue-after", time.Now().Add(wait))
 		record.Event(managed, event.Warning(reasonCannotInitialize, err))
 		managed.SetConditions(v1alpha1.ReconcileError(err))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+	}
+
+	if meta.ExternalNameChanged(managed) {
+		// Reconciling a managed resource whose external name was changed
+		// would cause us to abandon (or worse, delete) the wrong external
+		// resource, so unless we've been told to treat such changes as an
+		// explicit re-target we refuse to proceed until the change is
+		// reverted. We'll be requeued when the annotation changes.
+		err := errors.Errorf(errFmtExternalNameChanged, meta.GetLastExternalName(managed), meta.GetExternalName(managed))
+		switch {
+		case r.externalNamePolicy == ExternalNameChangeRetarget:
+			log.Debug("Retargeting managed resource", "reason", err)
+		case meta.WasDeleted(managed):
+			// Refusing to reconcile a deleted managed resource would leave it
+			// stuck terminating. We instead ignore the change and finalize
+			// the external resource we actually created.
+			log.Debug("Ignoring external name change of deleted managed resource", "reason", err)
+			meta.SetExternalName(managed, meta.GetLastExternalName(managed))
+		default:
+			log.Debug("Refusing to reconcile managed resource", "error", err, "requeue-after", time.Now().Add(r.longWait))
+			record.Event(managed, event.Warning(reasonExternalNameChanged, err))
+			managed.SetConditions(v1alpha1.ExternalNameChangeBlocked(err))
+			return reconcile.Result{RequeueAfter: r.longWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		}
 	}
 
 	// We resolve any references before observing our external resource because
@@ -544,51 +1054,91 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 	// delete, and that reference is stale at delete time.
 	if !meta.WasDeleted(managed) {
 		if err := r.managed.ResolveReferences(ctx, managed); err != nil {
-			condition := v1alpha1.ReconcileError(err)
-			if IsReferencesAccessError(err) {
-				condition = v1alpha1.ReferenceResolutionBlocked(err)
-			}
-
 			// If any of our referenced resources are not yet ready (or if we
 			// encountered an error resolving them) we want to try again after a
 			// short wait. If this is the first time we encounter this situation
 			// we'll be requeued implicitly due to the status update.
-			log.Debug("Cannot resolve managed resource references", "error", err, "requeue-after", time.Now().Add(r.shortWait))
-			record.Event(managed, event.Warning(reasonCannotResolveRefs, err))
-			managed.SetConditions(condition)
-			return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot resolve managed resource references", "error", err, "requeue-after", time.Now().Add(wait))
+			if IsReferencesAccessError(err) || reference.IsUnresolved(err) {
+				// The error names the referenced resources that are not yet
+				// ready, which tells users what they're waiting on.
+				record.Event(managed, event.Warning(reasonReferencesNotReady, err))
+				managed.SetConditions(v1alpha1.ReferenceResolutionBlocked(err), v1alpha1.ReferencesNotReady(err))
+			} else {
+				record.Event(managed, event.Warning(reasonCannotResolveRefs, err))
+				managed.SetConditions(v1alpha1.ReconcileError(err))
+			}
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 		}
 		managed.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+
+		if err := r.managed.ValidateSpec(ctx, managed); err != nil {
+			// There's no point calling the external API with a spec that we
+			// know it will reject. An invalid spec is unlikely to become
+			// valid until it changes, which will cause us to be requeued, so
+			// we'll usually wait a long time before trying again. If this is
+			// the first time we encounter this issue we'll be requeued
+			// implicitly due to the status update.
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot validate managed resource spec", "error", err, "requeue-after", time.Now().Add(wait))
+			record.Event(managed, event.Warning(reasonCannotValidateSpec, err))
+			managed.SetConditions(v1alpha1.ReconcileError(err))
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		}
 	}
 
+	stop = r.durations.external(OperationObserve)
 	observation, err := external.Observe(externalCtx, managed)
+	stop()
+	logExternalCalls(log, external)
 	if err != nil {
 		// We'll usually hit this case if our Provider credentials are invalid
 		// or insufficient for observing the external resource type we're
 		// concerned with. If this is the first time we encounter this issue
 		// we'll be requeued implicitly when we update our status with the new
 		// error condition. If not, we want to try again after a short wait.
-		log.Debug("Cannot observe external resource", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot observe external resource", "error", err, "requeue-after", time.Now().Add(wait))
 		record.Event(managed, event.Warning(reasonCannotObserve, err))
 		managed.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errReconcileObserve)))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
+	for _, c := range observation.Conditions {
+		if managed.GetCondition(c.Type).Status == c.Status {
+			continue
+		}
+		record.Event(managed, event.Normal(reasonConditionChanged, conditionChangedMessage(c), "condition-type", string(c.Type)))
+	}
+	managed.SetConditions(observation.Conditions...)
+
+	if r.dryRun || meta.IsDryRun(managed) {
+		return r.plan(ctx, req, managed, observation, log, record)
+	}
+	removePlan(managed)
+
 	if meta.WasDeleted(managed) {
 		log = log.WithValues("deletion-timestamp", managed.GetDeletionTimestamp())
 
 		if observation.ResourceExists && managed.GetReclaimPolicy() == v1alpha1.ReclaimDelete {
-			if err := external.Delete(externalCtx, managed); err != nil {
+			stop = r.durations.external(OperationDelete)
+			err = external.Delete(externalCtx, managed)
+			stop()
+			logExternalCalls(log, external)
+			r.audit.record(managed, v1alpha1.ExternalOperationDelete, nil, resource.Ignore(IsNotFound, err))
+			if err != nil && !IsNotFound(err) {
 				// We'll hit this condition if we can't delete our external
 				// resource, for example if our provider credentials don't have
 				// access to delete it. If this is the first time we encounter this
 				// issue we'll be requeued implicitly when we update our status with
 				// the new error condition. If not, we want to try again after a
 				// short wait.
-				log.Debug("Cannot delete external resource", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+				wait := r.errorWait(req, managed, err)
+				log.Debug("Cannot delete external resource", "error", err, "requeue-after", time.Now().Add(wait))
 				record.Event(managed, event.Warning(reasonCannotDelete, err))
 				managed.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errReconcileDelete)))
-				return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+				return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 			}
 
 			// We've successfully requested deletion of our external resource.
@@ -597,35 +1147,42 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 			// external resource was actually deleted. If it no longer exists
 			// we'll skip this block on the next reconcile and proceed to
 			// unpublish and finalize. If it still exists we'll re-enter this
-			// block and try again.
-			log.Debug("Successfully requested deletion of external resource", "requeue-after", time.Now().Add(r.shortWait))
-			record.Event(managed, event.Normal(reasonDeleted, "Successfully requested deletion of external resource"))
-			managed.SetConditions(v1alpha1.ReconcileSuccess())
-			return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			// block and try again. If the external client told us it was
+			// already gone we proceed to unpublish and finalize immediately.
+			if err == nil {
+				log.Debug("Successfully requested deletion of external resource", "requeue-after", time.Now().Add(r.shortWait))
+				record.Event(managed, event.Normal(reasonDeleted, "Successfully requested deletion of external resource"))
+				managed.SetConditions(v1alpha1.ReconcileSuccess())
+				return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			}
 		}
 		if err := r.managed.UnpublishConnection(ctx, managed, observation.ConnectionDetails); err != nil {
 			// If this is the first time we encounter this issue we'll be
 			// requeued implicitly when we update our status with the new error
 			// condition. If not, we want to try again after a short wait.
-			log.Debug("Cannot unpublish connection details", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot unpublish connection details", "error", err, "requeue-after", time.Now().Add(wait))
 			record.Event(managed, event.Warning(reasonCannotUnpublish, err))
 			managed.SetConditions(v1alpha1.ReconcileError(err))
-			return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(resource.IgnoreNotFound(r.client.Status().Update(ctx, managed)), errUpdateManagedStatus)
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(resource.IgnoreNotFound(r.client.Status().Update(ctx, managed)), errUpdateManagedStatus)
 		}
 		if err := r.managed.RemoveFinalizer(ctx, managed); err != nil {
 			// If this is the first time we encounter this issue we'll be
 			// requeued implicitly when we update our status with the new error
 			// condition. If not, we want to try again after a short wait.
-			log.Debug("Cannot remove managed resource finalizer", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot remove managed resource finalizer", "error", err, "requeue-after", time.Now().Add(wait))
 			managed.SetConditions(v1alpha1.ReconcileError(err))
-			return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(resource.IgnoreNotFound(r.client.Status().Update(ctx, managed)), errUpdateManagedStatus)
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(resource.IgnoreNotFound(r.client.Status().Update(ctx, managed)), errUpdateManagedStatus)
 		}
+		r.durations.deleted(managed)
 
 		// We've successfully deleted our external resource (if necessary) and
 		// removed our finalizer. If we assume we were the only controller that
 		// added a finalizer to this resource then it should no longer exist and
 		// thus there is no point trying to update its status.
 		log.Debug("Successfully deleted managed resource")
+		r.forget(req, managed)
 		return reconcile.Result{Requeue: false}, nil
 	}
 
@@ -633,23 +1190,63 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition. If
 		// not, we want to try again after a short wait.
-		log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(wait))
 		record.Event(managed, event.Warning(reasonCannotPublish, err))
 		managed.SetConditions(v1alpha1.ReconcileError(err))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
 	if err := r.managed.AddFinalizer(ctx, managed); err != nil {
 		// If this is the first time we encounter this issue we'll be
 		// requeued implicitly when we update our status with the new error
 		// condition. If not, we want to try again after a short wait.
-		log.Debug("Cannot add finalizer", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot add finalizer", "error", err, "requeue-after", time.Now().Add(wait))
 		managed.SetConditions(v1alpha1.ReconcileError(err))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(resource.IgnoreNotFound(r.client.Status().Update(ctx, managed)), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(resource.IgnoreNotFound(r.client.Status().Update(ctx, managed)), errUpdateManagedStatus)
+	}
+
+	if observation.ResourceExists {
+		if err := r.managed.RecordExternalName(ctx, managed); err != nil {
+			// If this is the first time we encounter this issue we'll be
+			// requeued implicitly when we update our status with the new error
+			// condition. If not, we want to try again after a short wait.
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot record external name", "error", err, "requeue-after", time.Now().Add(wait))
+			managed.SetConditions(v1alpha1.ReconcileError(err))
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		}
+
+		if err := r.clearIdempotencyToken(ctx, managed); err != nil {
+			// If this is the first time we encounter this issue we'll be
+			// requeued implicitly when we update our status with the new error
+			// condition. If not, we want to try again after a short wait.
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot clear idempotency token", "error", err, "requeue-after", time.Now().Add(wait))
+			managed.SetConditions(v1alpha1.ReconcileError(err))
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		}
 	}
 
 	if !observation.ResourceExists {
+		if err := r.ensureIdempotencyToken(ctx, managed); err != nil {
+			// We must persist our idempotency token before we create our
+			// external resource, or we risk creating it twice with different
+			// tokens. If this is the first time we encounter this issue we'll
+			// be requeued implicitly when we update our status with the new
+			// error condition. If not, we want to try again after a short wait.
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot persist idempotency token", "error", err, "requeue-after", time.Now().Add(wait))
+			managed.SetConditions(v1alpha1.ReconcileError(err))
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		}
+
+		stop = r.durations.external(OperationCreate)
 		creation, err := external.Create(externalCtx, managed)
+		stop()
+		logExternalCalls(log, external)
+		r.audit.record(managed, v1alpha1.ExternalOperationCreate, creation.ConnectionDetails, err)
 		if err != nil {
 			// We'll hit this condition if we can't create our external
 			// resource, for example if our provider credentials don't have
@@ -657,20 +1254,22 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 			// issue we'll be requeued implicitly when we update our status with
 			// the new error condition. If not, we want to try again after a
 			// short wait.
-			log.Debug("Cannot create external resource", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot create external resource", "error", err, "requeue-after", time.Now().Add(wait))
 			record.Event(managed, event.Warning(reasonCannotCreate, err))
 			managed.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errReconcileCreate)))
-			return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 		}
 
 		if err := r.managed.PublishConnection(ctx, managed, creation.ConnectionDetails); err != nil {
 			// If this is the first time we encounter this issue we'll be
 			// requeued implicitly when we update our status with the new error
 			// condition. If not, we want to try again after a short wait.
-			log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(wait))
 			record.Event(managed, event.Warning(reasonCannotPublish, err))
 			managed.SetConditions(v1alpha1.ReconcileError(err))
-			return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 		}
 
 		// We've successfully created our external resource. In many cases the
@@ -679,42 +1278,66 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 		// it's ready for use.
 		log.Debug("Successfully requested creation of external resource", "requeue-after", time.Now().Add(r.shortWait))
 		record.Event(managed, event.Normal(reasonCreated, "Successfully requested creation of external resource"))
+		r.forget(req, managed)
 		managed.SetConditions(v1alpha1.ReconcileSuccess())
 		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
 	if observation.ResourceUpToDate {
+		rotated, err := r.rotateCredentials(ctx, managed, external)
+		if err != nil {
+			// If this is the first time we encounter this issue we'll be
+			// requeued implicitly when we update our status with the new error
+			// condition. If not, we want to try again after a short wait.
+			wait := r.errorWait(req, managed, err)
+			log.Debug("Cannot rotate credentials", "error", err, "requeue-after", time.Now().Add(wait))
+			record.Event(managed, event.Warning(reasonCannotRotate, err))
+			managed.SetConditions(v1alpha1.CredentialsRotationError(err), v1alpha1.ReconcileError(err))
+			return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		}
+		if rotated {
+			record.Event(managed, event.Normal(reasonRotated, "Successfully rotated credentials"))
+			managed.SetConditions(v1alpha1.CredentialsRotationSuccess())
+		}
+
 		// We did not need to create, update, or delete our external resource.
 		// Per the below issue nothing will notify us if and when the external
 		// resource we manage changes, so we requeue a speculative reconcile
 		// after a long wait in order to observe it and react accordingly.
 		// https://github.com/crossplane/crossplane/issues/289
 		log.Debug("External resource is up to date", "requeue-after", time.Now().Add(r.longWait))
+		r.forget(req, managed)
 		managed.SetConditions(v1alpha1.ReconcileSuccess())
 		return reconcile.Result{RequeueAfter: r.longWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
+	stop = r.durations.external(OperationUpdate)
 	update, err := external.Update(externalCtx, managed)
+	stop()
+	logExternalCalls(log, external)
+	r.audit.record(managed, v1alpha1.ExternalOperationUpdate, update.ConnectionDetails, err)
 	if err != nil {
 		// We'll hit this condition if we can't update our external resource,
 		// for example if our provider credentials don't have access to update
 		// it. If this is the first time we encounter this issue we'll be
 		// requeued implicitly when we update our status with the new error
 		// condition. If not, we want to try again after a short wait.
-		log.Debug("Cannot update external resource", "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot update external resource", "requeue-after", time.Now().Add(wait))
 		record.Event(managed, event.Warning(reasonCannotUpdate, err))
 		managed.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errReconcileUpdate)))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
 	if err := r.managed.PublishConnection(ctx, managed, update.ConnectionDetails); err != nil {
 		// If this is the first time we encounter this issue we'll be requeued
 		// implicitly when we update our status with the new error condition. If
 		// not, we want to try again after a short wait.
-		log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(r.shortWait))
+		wait := r.errorWait(req, managed, err)
+		log.Debug("Cannot publish connection details", "error", err, "requeue-after", time.Now().Add(wait))
 		record.Event(managed, event.Warning(reasonCannotPublish, err))
 		managed.SetConditions(v1alpha1.ReconcileError(err))
-		return reconcile.Result{RequeueAfter: r.shortWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
+		return reconcile.Result{RequeueAfter: wait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
 	}
 
 	// We've successfully updated our external resource. Per the below issue
@@ -723,6 +1346,7 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 	// to observe it and react accordingly.
 	// https://github.com/crossplane/crossplane/issues/289
 	log.Debug("Successfully requested update of external resource", "requeue-after", time.Now().Add(r.longWait))
+	r.forget(req, managed)
 	record.Event(managed, event.Normal(reasonUpdated, "Successfully requested update of external resource"))
 	managed.SetConditions(v1alpha1.ReconcileSuccess())
 	return reconcile.Result{RequeueAfter: r.longWait}, errors.Wrap(r.client.Status().Update(ctx, managed), errUpdateManagedStatus)
@@ -19,20 +19,33 @@ package managed
 import (
 	"context"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -40,6 +53,118 @@ import (
 
 var _ reconcile.Reconciler = &Reconciler{}
 
+var encrypted = v1alpha1.ConditionDefinition{
+	Type:        "Encrypted",
+	TrueReason:  "Encrypted at rest",
+	FalseReason: "Not encrypted at rest",
+}
+
+func TestControllerNameForKind(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		of     resource.ManagedKind
+		want   string
+	}{
+		"KindAndGroup": {
+			reason: "The controller name should include the lowercased kind and group.",
+			of:     resource.ManagedKind{Group: "example.org", Version: "v1", Kind: "CoolResource"},
+			want:   "managed/coolresource.example.org",
+		},
+		"VersionOmitted": {
+			reason: "The controller name should not change when a kind is promoted to a new API version.",
+			of:     resource.ManagedKind{Group: "example.org", Version: "v1beta1", Kind: "CoolResource"},
+			want:   "managed/coolresource.example.org",
+		},
+		"DistinctGroups": {
+			reason: "Kinds with the same name in different groups should not share a controller name.",
+			of:     resource.ManagedKind{Group: "other.example.org", Version: "v1", Kind: "CoolResource"},
+			want:   "managed/coolresource.other.example.org",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ControllerNameForKind(tc.of)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nControllerNameForKind(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type setupManager struct {
+	fake.Manager
+
+	cache     cache.Cache
+	runnables []manager.Runnable
+}
+
+func (m *setupManager) GetConfig() *rest.Config { return &rest.Config{} }
+func (m *setupManager) GetCache() cache.Cache   { return m.cache }
+
+func (m *setupManager) GetEventRecorderFor(_ string) record.EventRecorder {
+	return record.NewFakeRecorder(0)
+}
+
+func (m *setupManager) SetFields(i interface{}) error {
+	if _, err := inject.CacheInto(m.cache, i); err != nil {
+		return err
+	}
+	_, err := inject.InjectorInto(m.SetFields, i)
+	return err
+}
+
+func (m *setupManager) Add(r manager.Runnable) error {
+	m.runnables = append(m.runnables, r)
+	return m.SetFields(r)
+}
+
+func TestSetup(t *testing.T) {
+	s := fake.SchemeWith(&fake.Managed{})
+	m := &setupManager{
+		Manager: fake.Manager{Scheme: s},
+		cache:   &informertest.FakeInformers{Scheme: s},
+	}
+	of := resource.ManagedKind(fake.GVK(&fake.Managed{}))
+
+	if err := Setup(m, of, controller.DefaultOptions()); err != nil {
+		t.Fatalf("Setup(...): %s", err)
+	}
+	if len(m.runnables) != 1 {
+		t.Fatalf("Setup(...): want 1 controller added to manager, got %d", len(m.runnables))
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() { _ = m.runnables[0].Start(stop) }()
+
+	// The controller's workqueue, and thus its metrics, are created when the
+	// controller starts.
+	want := ControllerNameForKind(of)
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		mfs, err := metrics.Registry.Gather()
+		if err != nil {
+			return false, err
+		}
+		for _, mf := range mfs {
+			if mf.GetName() != "workqueue_depth" {
+				continue
+			}
+			for _, mt := range mf.GetMetric() {
+				for _, l := range mt.GetLabel() {
+					if l.GetName() == "name" && l.GetValue() == want {
+						return true, nil
+					}
+				}
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		t.Errorf("Setup(...): want workqueue metrics labelled %q: %s", want, err)
+	}
+}
+
 func TestReconciler(t *testing.T) {
 	type args struct {
 		m  manager.Manager
@@ -54,6 +179,7 @@ func TestReconciler(t *testing.T) {
 
 	errBoom := errors.New("boom")
 	errNotReady := &referencesAccessErr{[]resource.ReferenceStatus{{Name: "cool-res", Status: resource.ReferenceNotReady}}}
+	errUnresolved := errors.Wrap(&reference.UnresolvedError{Name: "cool-res", Reason: "not ready"}, errResolveReferences)
 	now := metav1.Now()
 
 	cases := map[string]struct {
@@ -83,6 +209,61 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{}},
 		},
+		"Paused": {
+			reason: "Managed resources whose reconciliation is paused should not be reconciled.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							meta.AddAnnotations(obj.(*fake.Managed), map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+							return nil
+						}),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, _ runtime.Object, _ ...client.UpdateOption) error {
+							t.Errorf("Status().Update(...) called unexpectedly")
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						t.Errorf("Connect(...) called unexpectedly")
+						return nil, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"TrackError": {
+			reason: "Errors tracking the managed resource should trigger a requeue after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, got runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReconcileError(errBoom))
+							if diff := cmp.Diff(want, got, test.EquateConditions()); diff != "" {
+								reason := "Errors tracking the managed resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithTracker(resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return errBoom })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						t.Errorf("Connect(...) called unexpectedly")
+						return nil, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+		},
 		"ExternalConnectError": {
 			reason: "Errors connecting to the provider should trigger a requeue after a short wait.",
 			args: args{
@@ -110,6 +291,43 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
+		"Impersonation": {
+			reason: "Requests made after reading the managed resource should be associated with the identity to impersonate.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.MockGetFn(func(ctx context.Context, _ client.ObjectKey, _ runtime.Object) error {
+							if _, ok := resource.GetImpersonation(ctx); ok {
+								t.Errorf("\nReason: %s", "The managed resource should be read without impersonation.")
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(ctx context.Context, _ runtime.Object, _ ...client.UpdateOption) error {
+							want := rest.ImpersonationConfig{UserName: "cool-tenant"}
+							got, _ := resource.GetImpersonation(ctx)
+							if diff := cmp.Diff(want, got); diff != "" {
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", "Status should be updated using the impersonated identity.", diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithImpersonation(func(_ metav1.Object) (rest.ImpersonationConfig, bool) {
+						return rest.ImpersonationConfig{UserName: "cool-tenant"}, true
+					}),
+					WithExternalConnecter(ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (ExternalClient, error) {
+						if _, ok := resource.GetImpersonation(ctx); !ok {
+							t.Errorf("\nReason: %s", "The provider should be connected to using the impersonated identity.")
+						}
+						return nil, errBoom
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+		},
 		"InitializeError": {
 			reason: "Errors initializing the managed resource should trigger a requeue after a short wait.",
 			args: args{
@@ -146,7 +364,7 @@ func TestReconciler(t *testing.T) {
 						MockGet: test.NewMockGetFn(nil),
 						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
 							want := &fake.Managed{}
-							want.SetConditions(v1alpha1.ReferenceResolutionBlocked(errNotReady))
+							want.SetConditions(v1alpha1.ReferenceResolutionBlocked(errNotReady), v1alpha1.ReferencesNotReady(errNotReady))
 							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
 								reason := "Dependencies on unready references should be reported as a conditioned status."
 								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
@@ -167,6 +385,35 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
+		"ResolveReferencesUnresolvedError": {
+			reason: "Unresolved references should be reported as references not ready.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionBlocked(errUnresolved), v1alpha1.ReferencesNotReady(errUnresolved))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Unresolved references should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalConnecter(&NopConnecter{}),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, res resource.CanReference) error {
+						return errUnresolved
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+		},
 		"ResolveReferencesError": {
 			reason: "Errors during reference resolution references should trigger a requeue after a short wait.",
 			args: args{
@@ -196,6 +443,36 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
+		"ValidateSpecError": {
+			reason: "An invalid managed resource spec should trigger a requeue after a long wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.ReconcileError(NewInvalidSpec(errBoom)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "An invalid managed resource spec should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalConnecter(&NopConnecter{}),
+					WithSpecValidators(SpecValidatorFn(func(_ context.Context, _ resource.Managed) error {
+						return NewInvalidSpec(errBoom)
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
 		"ExternalObserveError": {
 			reason: "Errors observing the external resource should trigger a requeue after a short wait.",
 			args: args{
@@ -230,6 +507,58 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
+		"ExternalObserveErrorWithBackoff": {
+			reason: "Errors observing the external resource should trigger a requeue after the configured backoff.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithBackoff(ratelimiter.WithBaseDelay(5 * time.Second)),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{}, errBoom
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: 5 * time.Second}},
+		},
+		"ExternalObserveThrottledErrorWithBackoff": {
+			reason: "Throttled errors observing the external resource should trigger a requeue after at least a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil),
+						MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithBackoff(ratelimiter.WithBaseDelay(5 * time.Second)),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{}, NewThrottled(errBoom)
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+		},
 		"ExternalDeleteError": {
 			reason: "Errors deleting the external resource should trigger a requeue after a short wait.",
 			args: args{
@@ -318,6 +647,41 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
+		"ExternalDeleteNotFound": {
+			reason: "A deleted managed resource whose external resource is already gone should be finalized without a requeue.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							mg := obj.(*fake.Managed)
+							mg.SetDeletionTimestamp(&now)
+							mg.SetReclaimPolicy(v1alpha1.ReclaimDelete)
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true}, nil
+							},
+							DeleteFn: func(_ context.Context, _ resource.Managed) error {
+								return NewNotFound(errBoom)
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: false}},
+		},
 		"UnpublishConnectionDetailsError": {
 			reason: "Errors unpublishing connection details should trigger a requeue after a short wait.",
 			args: args{
@@ -493,18 +857,23 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
-		"CreateExternalError": {
-			reason: "Errors while creating an external resource should trigger a requeue after a short wait.",
+		"ExternalNameChangeBlocked": {
+			reason: "Changes to the external name of a managed resource whose external resource exists should be blocked by default.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{
-						MockGet: test.NewMockGetFn(nil),
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							meta.SetExternalName(obj.(*fake.Managed), "new")
+							meta.SetLastExternalName(obj.(*fake.Managed), "old")
+							return nil
+						}),
 						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
 							want := &fake.Managed{}
-							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
-							want.SetConditions(v1alpha1.ReconcileError(errors.Wrap(errBoom, errReconcileCreate)))
+							meta.SetExternalName(want, "new")
+							meta.SetLastExternalName(want, "old")
+							want.SetConditions(v1alpha1.ExternalNameChangeBlocked(errors.Errorf(errFmtExternalNameChanged, "old", "new")))
 							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
-								reason := "Errors while creating an external resource should be reported as a conditioned status."
+								reason := "A blocked external name change should be reported as a conditioned status."
 								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
 							}
 							return nil
@@ -515,35 +884,188 @@ func TestReconciler(t *testing.T) {
 				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
 				o: []ReconcilerOption{
 					WithInitializers(),
-					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
-					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
-						c := &ExternalClientFns{
-							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
-								return ExternalObservation{ResourceExists: false}, nil
-							},
-							CreateFn: func(_ context.Context, _ resource.Managed) (ExternalCreation, error) {
-								return ExternalCreation{}, errBoom
-							},
-						}
-						return c, nil
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error {
+						t.Errorf("ResolveReferences(...) called unexpectedly")
+						return nil
 					})),
-					WithConnectionPublishers(),
-					WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+					WithExternalConnecter(&NopConnecter{}),
 				},
 			},
-			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
 		},
-		"PublishCreationConnectionDetailsError": {
-			reason: "Errors publishing connection details after creation should trigger a requeue after a short wait.",
+		"ExternalNameChangeIgnoredWhenDeleted": {
+			reason: "Changes to the external name of a deleted managed resource should not block its deletion.",
 			args: args{
 				m: &fake.Manager{
 					Client: &test.MockClient{
-						MockGet: test.NewMockGetFn(nil),
-						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
-							want := &fake.Managed{}
-							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
-							want.SetConditions(v1alpha1.ReconcileError(errBoom))
-							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							mg := obj.(*fake.Managed)
+							mg.SetDeletionTimestamp(&now)
+							meta.SetExternalName(mg, "new")
+							meta.SetLastExternalName(mg, "old")
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, mg resource.Managed) (ExternalObservation, error) {
+								if diff := cmp.Diff("old", meta.GetExternalName(mg)); diff != "" {
+									reason := "A deleted managed resource should observe the external resource it created."
+									t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+								}
+								return ExternalObservation{ResourceExists: false}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: false}},
+		},
+		"ExternalNameChangeRetargeted": {
+			reason: "Changes to the external name of a managed resource should be treated as a re-target when so configured.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							meta.SetExternalName(obj.(*fake.Managed), "new")
+							meta.SetLastExternalName(obj.(*fake.Managed), "old")
+							return nil
+						}),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							meta.SetExternalName(want, "new")
+							meta.SetLastExternalName(want, "new")
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.ReconcileSuccess())
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "A re-targeted managed resource should record its new external name."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalNameChangePolicy(ExternalNameChangeRetarget),
+					WithExternalNameRecorder(ExternalNameRecorderFn(func(_ context.Context, mg resource.Managed) error {
+						meta.SetLastExternalName(mg, meta.GetExternalName(mg))
+						return nil
+					})),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
+		"RecordExternalNameError": {
+			reason: "Errors recording the external name of a managed resource should trigger a requeue after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.ReconcileError(errBoom))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Errors recording the external name should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithExternalNameRecorder(ExternalNameRecorderFn(func(_ context.Context, _ resource.Managed) error { return errBoom })),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+		},
+		"CreateExternalError": {
+			reason: "Errors while creating an external resource should trigger a requeue after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.ReconcileError(errors.Wrap(errBoom, errReconcileCreate)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Errors while creating an external resource should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: false}, nil
+							},
+							CreateFn: func(_ context.Context, _ resource.Managed) (ExternalCreation, error) {
+								return ExternalCreation{}, errBoom
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
+		},
+		"PublishCreationConnectionDetailsError": {
+			reason: "Errors publishing connection details after creation should trigger a requeue after a short wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.ReconcileError(errBoom))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
 								reason := "Errors publishing connection details after creation should be reported as a conditioned status."
 								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
 							}
@@ -651,6 +1173,44 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
 		},
+		"ExternalResourceConditions": {
+			reason: "Conditions observed of the external resource should be set on the managed resource.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(encrypted.True())
+							want.SetConditions(v1alpha1.ReconcileSuccess())
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Observed conditions should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: true, Conditions: []v1alpha1.Condition{encrypted.True()}}, nil
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
 		"UpdateExternalError": {
 			reason: "Errors while updating an external resource should trigger a requeue after a short wait.",
 			args: args{
@@ -691,6 +1251,46 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedShortWait}},
 		},
+		"UpdateExternalInvalidSpecError": {
+			reason: "Errors classified as an invalid spec should trigger a requeue after a long wait.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.ReconcileError(errors.Wrap(NewInvalidSpec(errBoom), errReconcileUpdate)))
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "Classified errors should be reported as a conditioned status with the class's reason."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+							},
+							UpdateFn: func(_ context.Context, _ resource.Managed) (ExternalUpdate, error) {
+								return ExternalUpdate{}, NewInvalidSpec(errBoom)
+							},
+						}
+						return c, nil
+					})),
+					WithConnectionPublishers(),
+					WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
 		"PublishUpdateConnectionDetailsError": {
 			reason: "Errors publishing connection details after an update should trigger a requeue after a short wait.",
 			args: args{
@@ -782,6 +1382,138 @@ func TestReconciler(t *testing.T) {
 			},
 			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
 		},
+		"DryRunCreate": {
+			reason: "A managed resource annotated for dry-run whose external resource does not exist should report a planned creation rather than creating it.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							meta.AddAnnotations(obj.(*fake.Managed), map[string]string{meta.AnnotationKeyDryRun: "true"})
+							return nil
+						}),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							meta.AddAnnotations(want, map[string]string{meta.AnnotationKeyDryRun: "true"})
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.PlannedCreate("+ cool"), v1alpha1.ReconcileSuccess())
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "A planned creation should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: false, Diff: "+ cool"}, nil
+							},
+							CreateFn: func(_ context.Context, _ resource.Managed) (ExternalCreation, error) {
+								t.Errorf("\nReason: %s", "Create should not be called in dry-run mode.")
+								return ExternalCreation{}, nil
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
+		"DryRunUpdate": {
+			reason: "A managed resource reconciled in dry-run mode whose external resource is not up to date should report a planned update rather than updating it.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetConditions(v1alpha1.ReferenceResolutionSuccess())
+							want.SetConditions(v1alpha1.PlannedUpdate("~ cool"), v1alpha1.ReconcileSuccess())
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "A planned update should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithDryRun(),
+					WithInitializers(),
+					WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true, ResourceUpToDate: false, Diff: "~ cool"}, nil
+							},
+							UpdateFn: func(_ context.Context, _ resource.Managed) (ExternalUpdate, error) {
+								t.Errorf("\nReason: %s", "Update should not be called in dry-run mode.")
+								return ExternalUpdate{}, nil
+							},
+						}
+						return c, nil
+					})),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
+		"DryRunDelete": {
+			reason: "A deleted managed resource reconciled in dry-run mode should report a planned deletion rather than deleting its external resource or being finalized.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							mg := obj.(*fake.Managed)
+							mg.SetDeletionTimestamp(&now)
+							mg.SetReclaimPolicy(v1alpha1.ReclaimDelete)
+							return nil
+						}),
+						MockStatusUpdate: test.MockStatusUpdateFn(func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+							want := &fake.Managed{}
+							want.SetDeletionTimestamp(&now)
+							want.SetReclaimPolicy(v1alpha1.ReclaimDelete)
+							want.SetConditions(v1alpha1.PlannedDelete(), v1alpha1.ReconcileSuccess())
+							if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+								reason := "A planned deletion should be reported as a conditioned status."
+								t.Errorf("\nReason: %s\n-want, +got:\n%s", reason, diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				mg: resource.ManagedKind(fake.GVK(&fake.Managed{})),
+				o: []ReconcilerOption{
+					WithDryRun(),
+					WithInitializers(),
+					WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+						c := &ExternalClientFns{
+							ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+								return ExternalObservation{ResourceExists: true}, nil
+							},
+							DeleteFn: func(_ context.Context, _ resource.Managed) error {
+								t.Errorf("\nReason: %s", "Delete should not be called in dry-run mode.")
+								return nil
+							},
+						}
+						return c, nil
+					})),
+					WithFinalizer(FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Managed) error {
+						t.Errorf("\nReason: %s", "The managed resource should not be finalized in dry-run mode.")
+						return nil
+					}}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultManagedLongWait}},
+		},
 	}
 
 	for name, tc := range cases {
@@ -799,3 +1531,105 @@ func TestReconciler(t *testing.T) {
 		})
 	}
 }
+
+type mockRateLimiter struct {
+	when      time.Duration
+	forgotten []interface{}
+}
+
+func (l *mockRateLimiter) When(_ interface{}) time.Duration { return l.when }
+func (l *mockRateLimiter) NumRequeues(_ interface{}) int    { return 0 }
+func (l *mockRateLimiter) Forget(item interface{})          { l.forgotten = append(l.forgotten, item) }
+
+func TestReconcilerRateLimiter(t *testing.T) {
+	type want struct {
+		result    reconcile.Result
+		forgotten []interface{}
+	}
+
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool-managed"}}
+
+	cases := map[string]struct {
+		reason string
+		when   time.Duration
+		o      []ReconcilerOption
+		want   want
+	}{
+		"ErrorRequeuesAfterWhen": {
+			reason: "Errors should trigger a requeue after the duration returned by the rate limiter, without forgetting the request.",
+			when:   42 * time.Second,
+			o: []ReconcilerOption{
+				WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+					c := &ExternalClientFns{
+						ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+							return ExternalObservation{}, errBoom
+						},
+					}
+					return c, nil
+				})),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: 42 * time.Second}},
+		},
+		"UpToDateForgets": {
+			reason: "A successful reconcile should cause the rate limiter to forget the request.",
+			when:   42 * time.Second,
+			o: []ReconcilerOption{
+				WithExternalConnecter(ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (ExternalClient, error) {
+					c := &ExternalClientFns{
+						ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+							return ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+						},
+					}
+					return c, nil
+				})),
+			},
+			want: want{
+				result:    reconcile.Result{RequeueAfter: defaultManagedLongWait},
+				forgotten: []interface{}{req},
+			},
+		},
+		"CreateSuccessfulForgets": {
+			reason: "Successfully creating an external resource should cause the rate limiter to forget the request.",
+			when:   42 * time.Second,
+			o:      []ReconcilerOption{WithExternalConnecter(&NopConnecter{})},
+			want: want{
+				result:    reconcile.Result{RequeueAfter: defaultManagedShortWait},
+				forgotten: []interface{}{req},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &fake.Manager{
+				Client: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				Scheme: fake.SchemeWith(&fake.Managed{}),
+			}
+			l := &mockRateLimiter{when: tc.when}
+			o := append([]ReconcilerOption{
+				WithInitializers(),
+				WithReferenceResolver(ReferenceResolverFn(func(_ context.Context, _ resource.CanReference) error { return nil })),
+				WithConnectionPublishers(),
+				WithFinalizer(FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Managed) error { return nil }}),
+				WithRateLimiter(l),
+			}, tc.o...)
+
+			r := NewReconciler(m, resource.ManagedKind(fake.GVK(&fake.Managed{})), o...)
+			got, err := r.Reconcile(req)
+
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.forgotten, l.forgotten); diff != "" {
+				t.Errorf("\nReason: %s\nRateLimiter.Forget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
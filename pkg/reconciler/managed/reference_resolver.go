@@ -22,9 +22,9 @@ import (
 	"reflect"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
 
@@ -34,6 +34,7 @@ const (
 	errUpdateReferencer    = "could not update resource referencer"
 	errBuildAttribute      = "could not build the attribute"
 	errAssignAttribute     = "could not assign the attribute"
+	errResolveReferences   = "cannot resolve references"
 )
 
 // referencesAccessErr is used to indicate that one or more references can not
@@ -165,6 +166,49 @@ func (r *APIReferenceResolver) ResolveReferences(ctx context.Context, res resour
 	return errors.Wrap(r.client.Update(ctx, res), errUpdateReferencer)
 }
 
+// A ReferenceResolvable is a managed resource that knows how to resolve its
+// own references, typically using a reference.APIResolver. Implementations
+// are expected to write resolved values into their referencing fields.
+type ReferenceResolvable interface {
+	ResolveReferences(ctx context.Context, r client.Reader) error
+}
+
+// An APISimpleReferenceResolver resolves references from one managed resource
+// to others by calling the referencing resource's ResolveReferences method,
+// then updates it in the Kubernetes API if any references were resolved.
+type APISimpleReferenceResolver struct {
+	client client.Client
+}
+
+// NewAPISimpleReferenceResolver returns a ReferenceResolver that resolves
+// references from one managed resource to others by calling the referencing
+// resource's ResolveReferences method, if any.
+func NewAPISimpleReferenceResolver(c client.Client) *APISimpleReferenceResolver {
+	return &APISimpleReferenceResolver{client: c}
+}
+
+// ResolveReferences of the supplied resource by calling its ResolveReferences
+// method, if any.
+func (a *APISimpleReferenceResolver) ResolveReferences(ctx context.Context, res resource.CanReference) error {
+	rr, ok := res.(ReferenceResolvable)
+	if !ok {
+		// This managed resource doesn't have any references to resolve.
+		return nil
+	}
+
+	existing := res.DeepCopyObject()
+	if err := rr.ResolveReferences(ctx, a.client); err != nil {
+		return errors.Wrap(err, errResolveReferences)
+	}
+
+	// Don't update if nothing changed during reference resolution.
+	if cmp.Equal(existing, res) {
+		return nil
+	}
+
+	return errors.Wrap(a.client.Update(ctx, res), errUpdateReferencer)
+}
+
 // findReferencers recursively finds all pointer types in a struct that satisfy
 // AttributeReferencer. It assesses only pointers, structs, and slices because
 // it is assumed that only struct fields or slice elements that are pointers to
@@ -18,13 +18,16 @@ package managed
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -266,6 +269,99 @@ func TestResolveReferences(t *testing.T) {
 	}
 }
 
+// A resolvable is a managed resource that resolves its own references.
+type resolvable struct {
+	fake.Managed
+
+	// Resolve causes the external name to be set when references are
+	// resolved, simulating the resolution of a reference.
+	Resolve string
+
+	// Fail causes reference resolution to fail.
+	Fail bool
+}
+
+func (r *resolvable) ResolveReferences(_ context.Context, _ client.Reader) error {
+	if r.Fail {
+		return errors.New("boom")
+	}
+	if r.Resolve != "" {
+		meta.SetExternalName(r, r.Resolve)
+	}
+	return nil
+}
+
+func (r *resolvable) DeepCopyObject() runtime.Object {
+	out := &resolvable{}
+	j, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+	_ = json.Unmarshal(j, out)
+	return out
+}
+
+func TestAPISimpleReferenceResolver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		ctx context.Context
+		res resource.CanReference
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		args   args
+		want   error
+	}{
+		"NoReferenceResolver": {
+			reason: "Should return early if the resource does not resolve its own references",
+			args: args{
+				res: &fake.Managed{},
+			},
+			want: nil,
+		},
+		"ResolveReferencesError": {
+			reason: "Should return errors encountered while resolving references",
+			args: args{
+				res: &resolvable{Fail: true},
+			},
+			want: errors.Wrap(errBoom, errResolveReferences),
+		},
+		"NothingResolved": {
+			reason: "Should not update the resource if nothing was resolved",
+			c: &test.MockClient{
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			args: args{
+				res: &resolvable{},
+			},
+			want: nil,
+		},
+		"UpdateError": {
+			reason: "Should return errors encountered while updating the resource",
+			c: &test.MockClient{
+				MockUpdate: test.NewMockUpdateFn(errBoom),
+			},
+			args: args{
+				res: &resolvable{Resolve: "cool"},
+			},
+			want: errors.Wrap(errBoom, errUpdateReferencer),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPISimpleReferenceResolver(tc.c)
+			got := r.ResolveReferences(tc.args.ctx, tc.args.res)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.ResolveReferences(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestFindReferencers(t *testing.T) {
 	cases := map[string]struct {
 		reason string
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errEncodeManaged = "cannot encode managed resource"
+	errDecodeManaged = "cannot decode managed resource returned by remote external client"
+)
+
+// An Invoker invokes unary gRPC methods. *grpc.ClientConn satisfies Invoker.
+type Invoker interface {
+	Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error
+}
+
+// A Connecter connects to ExternalClients served by a remote Server, or by
+// any other implementation of the External gRPC service.
+type Connecter struct {
+	conn Invoker
+	gvk  schema.GroupVersionKind
+}
+
+// NewConnecter returns an ExternalConnecter that connects to ExternalClients
+// for the supplied kind of managed resource, using the supplied gRPC
+// connection.
+func NewConnecter(conn Invoker, of resource.ManagedKind) *Connecter {
+	return &Connecter{conn: conn, gvk: schema.GroupVersionKind(of)}
+}
+
+// Connect to a remote ExternalClient for the supplied managed resource.
+func (c *Connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec := &external{conn: c.conn, gvk: c.gvk}
+	if _, err := ec.invoke(ctx, MethodConnect, mg); err != nil {
+		return nil, err
+	}
+	return ec, nil
+}
+
+type external struct {
+	conn Invoker
+	gvk  schema.GroupVersionKind
+}
+
+// invoke the supplied method, updating the supplied managed resource to
+// reflect the one returned by the remote ExternalClient.
+func (e *external) invoke(ctx context.Context, method string, mg resource.Managed) (*Response, error) {
+	j, err := json.Marshal(mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errEncodeManaged)
+	}
+	apiVersion, kind := e.gvk.ToAPIVersionAndKind()
+	req := &Request{APIVersion: apiVersion, Kind: kind, Managed: j}
+	rsp := &Response{}
+	if err := e.conn.Invoke(ctx, "/"+ServiceName+"/"+method, req, rsp, grpc.CallContentSubtype(CodecName)); err != nil {
+		return nil, fromStatus(err)
+	}
+	if len(rsp.Managed) == 0 {
+		return rsp, nil
+	}
+
+	// Decoding into the existing managed resource would merge rather than
+	// replace any maps it contains, so we start from an empty one.
+	v := reflect.ValueOf(mg).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return rsp, errors.Wrap(json.Unmarshal(rsp.Managed, mg), errDecodeManaged)
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	rsp, err := e.invoke(ctx, MethodObserve, mg)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	return managed.ExternalObservation{
+		ResourceExists:    rsp.ResourceExists,
+		ResourceUpToDate:  rsp.ResourceUpToDate,
+		ConnectionDetails: rsp.ConnectionDetails,
+		Conditions:        rsp.Conditions,
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	rsp, err := e.invoke(ctx, MethodCreate, mg)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	return managed.ExternalCreation{ConnectionDetails: rsp.ConnectionDetails}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	rsp, err := e.invoke(ctx, MethodUpdate, mg)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	return managed.ExternalUpdate{ConnectionDetails: rsp.ConnectionDetails}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	_, err := e.invoke(ctx, MethodDelete, mg)
+	return err
+}
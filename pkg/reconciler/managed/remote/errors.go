@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+)
+
+var classCodes = map[managed.ErrorClass]codes.Code{
+	managed.ClassNotFound:      codes.NotFound,
+	managed.ClassAlreadyExists: codes.AlreadyExists,
+	managed.ClassThrottled:     codes.ResourceExhausted,
+	managed.ClassUnauthorized:  codes.PermissionDenied,
+	managed.ClassInvalidSpec:   codes.InvalidArgument,
+	managed.ClassTransient:     codes.Unavailable,
+}
+
+var codeClasses = map[codes.Code]managed.ErrorClass{
+	codes.NotFound:          managed.ClassNotFound,
+	codes.AlreadyExists:     managed.ClassAlreadyExists,
+	codes.ResourceExhausted: managed.ClassThrottled,
+	codes.PermissionDenied:  managed.ClassUnauthorized,
+	codes.Unauthenticated:   managed.ClassUnauthorized,
+	codes.InvalidArgument:   managed.ClassInvalidSpec,
+	codes.Unavailable:       managed.ClassTransient,
+	codes.DeadlineExceeded:  managed.ClassTransient,
+}
+
+// CodeOf returns the gRPC status code that represents the supplied error. A
+// classified error is represented by the code corresponding to its class, for
+// example codes.ResourceExhausted for managed.ClassThrottled. Any other error
+// is represented by codes.Unknown.
+func CodeOf(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if c, ok := managed.ClassOf(err); ok {
+		if code, ok := classCodes[c]; ok {
+			return code
+		}
+	}
+	return codes.Unknown
+}
+
+// toStatus returns a gRPC status error representing the supplied error.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	return status.Error(CodeOf(err), err.Error())
+}
+
+// fromStatus returns an error representing the supplied gRPC status error,
+// classified per its code. Errors that are not gRPC status errors are
+// returned unchanged.
+func fromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() == codes.OK {
+		return err
+	}
+	e := errors.New(s.Message())
+	if c, ok := codeClasses[s.Code()]; ok {
+		return managed.Classify(e, c)
+	}
+	return e
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package remote allows the managed resource reconciler to use ExternalClients
+// served by another process, for example a provider plugin running as a
+// sidecar or written in another language.
+//
+// The protocol is a gRPC service named crossplane.external.v1alpha1.External
+// with the unary methods Connect, Observe, Create, Update, and Delete. Each
+// method accepts a Request and returns a Response, encoded as JSON using the
+// gRPC content-subtype "json", i.e. a content-type of application/grpc+json.
+// JSON is used rather than protocol buffers because managed resources are
+// already serialised as JSON by the Kubernetes API server; a plugin need only
+// understand the JSON representation of the managed resources it manages.
+//
+// The managed resource a Response carries replaces the one supplied by the
+// Request, allowing a plugin to update its status, annotations, or late
+// initialised spec just as an in-process ExternalClient would. Errors are
+// returned as gRPC status errors; see CodeOf for how classified errors are
+// represented.
+package remote
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+)
+
+// ServiceName is the fully qualified name of the gRPC service.
+const ServiceName = "crossplane.external.v1alpha1.External"
+
+// The methods of the gRPC service.
+const (
+	MethodConnect = "Connect"
+	MethodObserve = "Observe"
+	MethodCreate  = "Create"
+	MethodUpdate  = "Update"
+	MethodDelete  = "Delete"
+)
+
+// CodecName is the gRPC content-subtype used to encode Requests and
+// Responses.
+const CodecName = "json"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (codec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (codec) Name() string                               { return CodecName }
+
+// A Request to connect to, observe, create, update, or delete the external
+// resource represented by a managed resource.
+type Request struct {
+	// APIVersion of the managed resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the managed resource.
+	Kind string `json:"kind"`
+
+	// Managed resource, encoded as JSON.
+	Managed json.RawMessage `json:"managed"`
+}
+
+// GroupVersionKind returns the kind of managed resource the Request concerns.
+func (r *Request) GroupVersionKind() schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(r.APIVersion, r.Kind)
+}
+
+// A Response to a Request. Fields that are not relevant to a method are
+// omitted; for example only the response to Observe includes
+// ResourceExists.
+type Response struct {
+	// Managed resource, encoded as JSON, as updated by the ExternalClient.
+	Managed json.RawMessage `json:"managed,omitempty"`
+
+	// ResourceExists is true if the external resource exists.
+	ResourceExists bool `json:"resourceExists,omitempty"`
+
+	// ResourceUpToDate is true if the external resource does not need to
+	// be updated.
+	ResourceUpToDate bool `json:"resourceUpToDate,omitempty"`
+
+	// ConnectionDetails of the external resource.
+	ConnectionDetails map[string][]byte `json:"connectionDetails,omitempty"`
+
+	// Conditions observed of the external resource.
+	Conditions []v1alpha1.Condition `json:"conditions,omitempty"`
+}
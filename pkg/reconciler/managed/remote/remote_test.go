@@ -0,0 +1,189 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ managed.ExternalConnecter = &Connecter{}
+var _ Invoker = &grpc.ClientConn{}
+
+func TestRemoteExternalClient(t *testing.T) {
+	errBoom := errors.New("boom")
+	kind := resource.ManagedKind{Group: "cool.crossplane.io", Version: "v1", Kind: "Cool"}
+
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(schema.GroupVersionKind(kind), &fake.Managed{})
+
+	ec := managed.ExternalClientFns{
+		ObserveFn: func(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+			meta.SetExternalName(mg, "cool-"+mg.GetName())
+			return managed.ExternalObservation{
+				ResourceExists:    true,
+				ConnectionDetails: managed.ConnectionDetails{"password": []byte("secret")},
+			}, nil
+		},
+		CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+			return managed.ExternalCreation{}, managed.NewThrottled(errBoom)
+		},
+		UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+			return managed.ExternalUpdate{}, errBoom
+		},
+		DeleteFn: func(_ context.Context, _ resource.Managed) error {
+			return nil
+		},
+	}
+	srv := NewServer(s, WithConnecter(kind, managed.ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		if mg.GetName() == "unauthorized" {
+			return nil, managed.NewUnauthorized(errBoom)
+		}
+		return ec, nil
+	})))
+
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	srv.Register(g)
+	go func() { _ = g.Serve(lis) }()
+	defer g.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+	)
+	if err != nil {
+		t.Fatalf("grpc.DialContext(...): %s", err)
+	}
+	defer conn.Close()
+
+	named := func(name string) *fake.Managed {
+		mg := &fake.Managed{}
+		mg.SetName(name)
+		return mg
+	}
+
+	t.Run("ConnectError", func(t *testing.T) {
+		_, err := NewConnecter(conn, kind).Connect(context.Background(), named("unauthorized"))
+		if diff := cmp.Diff(managed.NewUnauthorized(errBoom), err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nConnect(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	t.Run("UnknownKind", func(t *testing.T) {
+		other := resource.ManagedKind{Group: "cool.crossplane.io", Version: "v1", Kind: "Uncool"}
+		_, err := NewConnecter(conn, other).Connect(context.Background(), named("cool"))
+		if diff := cmp.Diff(codes.Unknown, CodeOf(err)); diff != "" {
+			t.Errorf("\nConnect(...): -want code, +got code:\n%s", diff)
+		}
+		if err == nil {
+			t.Errorf("\nConnect(...): want error connecting to an unknown kind")
+		}
+	})
+
+	mg := named("cool")
+	c, err := NewConnecter(conn, kind).Connect(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+
+	t.Run("Observe", func(t *testing.T) {
+		o, err := c.Observe(context.Background(), mg)
+		if err != nil {
+			t.Fatalf("Observe(...): %s", err)
+		}
+		want := managed.ExternalObservation{
+			ResourceExists:    true,
+			ConnectionDetails: managed.ConnectionDetails{"password": []byte("secret")},
+		}
+		if diff := cmp.Diff(want, o); diff != "" {
+			t.Errorf("\nObserve(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff("cool-cool", meta.GetExternalName(mg)); diff != "" {
+			t.Errorf("\nObserve(...): -want external name, +got external name:\n%s", diff)
+		}
+	})
+
+	t.Run("CreateClassifiedError", func(t *testing.T) {
+		_, err := c.Create(context.Background(), mg)
+		if diff := cmp.Diff(managed.NewThrottled(errBoom), err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nCreate(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	t.Run("UpdateError", func(t *testing.T) {
+		_, err := c.Update(context.Background(), mg)
+		if diff := cmp.Diff(errBoom, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nUpdate(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := c.Delete(context.Background(), mg); err != nil {
+			t.Errorf("\nDelete(...): %s", err)
+		}
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   codes.Code
+	}{
+		"NoError": {
+			reason: "A nil error should be represented by codes.OK",
+			want:   codes.OK,
+		},
+		"Unclassified": {
+			reason: "An unclassified error should be represented by codes.Unknown",
+			err:    errBoom,
+			want:   codes.Unknown,
+		},
+		"Classified": {
+			reason: "A classified error should be represented by the code corresponding to its class",
+			err:    errors.Wrap(managed.NewInvalidSpec(errBoom), "wrapped"),
+			want:   codes.InvalidArgument,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CodeOf(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCodeOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
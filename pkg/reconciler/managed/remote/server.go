@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errFmtUnknownKind    = "cannot serve managed resource of unknown kind %s"
+	errFmtNotManaged     = "%s is not a managed resource"
+	errFmtDecodeManaged  = "cannot decode managed resource: %s"
+	errFmtEncodeManaged  = "cannot encode managed resource: %s"
+	errFmtUnknownHandler = "unexpected gRPC handler %T"
+)
+
+// A ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithConnecter configures a Server to serve the supplied kind of managed
+// resource using the supplied ExternalConnecter.
+func WithConnecter(of resource.ManagedKind, c managed.ExternalConnecter) ServerOption {
+	return func(s *Server) {
+		s.connecters[schema.GroupVersionKind(of)] = c
+	}
+}
+
+// A Server serves ExternalClients over gRPC. A Server connects to an
+// ExternalClient for every request it serves, because ExternalClients have no
+// lifecycle that would allow a connection to be shared safely between
+// requests.
+type Server struct {
+	scheme     *runtime.Scheme
+	connecters map[schema.GroupVersionKind]managed.ExternalConnecter
+}
+
+// NewServer returns a Server that serves the ExternalClients produced by the
+// supplied ExternalConnecters. The kinds of managed resource it serves must be
+// registered with the supplied scheme.
+func NewServer(s *runtime.Scheme, o ...ServerOption) *Server {
+	srv := &Server{scheme: s, connecters: make(map[schema.GroupVersionKind]managed.ExternalConnecter)}
+	for _, fn := range o {
+		fn(srv)
+	}
+	return srv
+}
+
+// Register the Server with the supplied gRPC server.
+func (s *Server) Register(g *grpc.Server) {
+	g.RegisterService(&serviceDesc, s)
+}
+
+// connect decodes the managed resource supplied by the request, and connects
+// to an ExternalClient for it.
+func (s *Server) connect(ctx context.Context, req *Request) (resource.Managed, managed.ExternalClient, error) {
+	gvk := req.GroupVersionKind()
+	c, ok := s.connecters[gvk]
+	if !ok {
+		return nil, nil, status.Errorf(codes.Unimplemented, errFmtUnknownKind, gvk)
+	}
+	o, err := s.scheme.New(gvk)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, errFmtUnknownKind, gvk)
+	}
+	mg, ok := o.(resource.Managed)
+	if !ok {
+		return nil, nil, status.Errorf(codes.Unimplemented, errFmtNotManaged, gvk)
+	}
+	if err := json.Unmarshal(req.Managed, mg); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, errFmtDecodeManaged, err)
+	}
+	ec, err := c.Connect(ctx, mg)
+	return mg, ec, toStatus(err)
+}
+
+// respond encodes the supplied managed resource into the supplied response.
+func respond(mg resource.Managed, rsp *Response) (*Response, error) {
+	j, err := json.Marshal(mg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, errFmtEncodeManaged, err)
+	}
+	rsp.Managed = j
+	return rsp, nil
+}
+
+// Connect to an ExternalClient for the supplied managed resource.
+func (s *Server) Connect(ctx context.Context, req *Request) (*Response, error) {
+	if _, _, err := s.connect(ctx, req); err != nil {
+		return nil, err
+	}
+	return &Response{}, nil
+}
+
+// Observe the external resource the supplied managed resource represents.
+func (s *Server) Observe(ctx context.Context, req *Request) (*Response, error) {
+	mg, ec, err := s.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	o, err := ec.Observe(ctx, mg)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return respond(mg, &Response{
+		ResourceExists:    o.ResourceExists,
+		ResourceUpToDate:  o.ResourceUpToDate,
+		ConnectionDetails: o.ConnectionDetails,
+		Conditions:        o.Conditions,
+	})
+}
+
+// Create the external resource the supplied managed resource represents.
+func (s *Server) Create(ctx context.Context, req *Request) (*Response, error) {
+	mg, ec, err := s.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ec.Create(ctx, mg)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return respond(mg, &Response{ConnectionDetails: c.ConnectionDetails})
+}
+
+// Update the external resource the supplied managed resource represents.
+func (s *Server) Update(ctx context.Context, req *Request) (*Response, error) {
+	mg, ec, err := s.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	u, err := ec.Update(ctx, mg)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return respond(mg, &Response{ConnectionDetails: u.ConnectionDetails})
+}
+
+// Delete the external resource the supplied managed resource represents.
+func (s *Server) Delete(ctx context.Context, req *Request) (*Response, error) {
+	mg, ec, err := s.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := ec.Delete(ctx, mg); err != nil {
+		return nil, toStatus(err)
+	}
+	return respond(mg, &Response{})
+}
+
+// externalServer is the interface a gRPC server must satisfy to serve the
+// External service.
+type externalServer interface {
+	Connect(ctx context.Context, req *Request) (*Response, error)
+	Observe(ctx context.Context, req *Request) (*Response, error)
+	Create(ctx context.Context, req *Request) (*Response, error)
+	Update(ctx context.Context, req *Request) (*Response, error)
+	Delete(ctx context.Context, req *Request) (*Response, error)
+}
+
+type unaryFn func(s externalServer, ctx context.Context, req *Request) (*Response, error)
+
+// handler returns a gRPC method handler that invokes the supplied method of
+// an externalServer. This is the handler protoc-gen-go would generate for each
+// method if the service were defined using protocol buffers.
+func handler(method string, fn unaryFn) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		s, ok := srv.(externalServer)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, errFmtUnknownHandler, srv)
+		}
+		req := &Request{}
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return fn(s, ctx, req)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/" + method}
+		return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return fn(s, ctx, req.(*Request))
+		})
+	}
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*externalServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: MethodConnect, Handler: handler(MethodConnect, externalServer.Connect)},
+		{MethodName: MethodObserve, Handler: handler(MethodObserve, externalServer.Observe)},
+		{MethodName: MethodCreate, Handler: handler(MethodCreate, externalServer.Create)},
+		{MethodName: MethodUpdate, Handler: handler(MethodUpdate, externalServer.Update)},
+		{MethodName: MethodDelete, Handler: handler(MethodDelete, externalServer.Delete)},
+	},
+	Streams: []grpc.StreamDesc{},
+}
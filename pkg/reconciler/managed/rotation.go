@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Annotations used to track credential rotation.
+const (
+	// AnnotationKeyCredentialsRotatedAt records when the credentials of a
+	// managed resource were last rotated, in RFC3339 format.
+	AnnotationKeyCredentialsRotatedAt = "crossplane.io/credentials-rotated-at"
+
+	// AnnotationKeyPreviousCredentials records the connection detail keys to
+	// which previous credentials were published, separated by commas.
+	AnnotationKeyPreviousCredentials = "crossplane.io/previous-credentials"
+)
+
+// PreviousCredentialsSuffix is appended to the key of each previous connection
+// detail published during a credential rotation's grace window.
+const PreviousCredentialsSuffix = ".previous"
+
+// Error strings.
+const (
+	errRotateCredentials  = "cannot rotate credentials"
+	errPublishRotated     = "cannot publish rotated credentials"
+	errUnpublishPrevious  = "cannot unpublish previous credentials"
+	errRecordRotationTime = "cannot record credential rotation"
+)
+
+// A CredentialRotator is an ExternalClient that can mint new credentials for
+// the external resources it manages. The Reconciler calls RotateCredentials
+// periodically when credential rotation is enabled; see
+// WithCredentialRotation.
+type CredentialRotator interface {
+	// RotateCredentials of the external resource represented by the supplied
+	// managed resource.
+	RotateCredentials(ctx context.Context, mg resource.Managed) (ExternalRotation, error)
+}
+
+// An ExternalRotation is the result of a credential rotation.
+type ExternalRotation struct {
+	// ConnectionDetails, including the new credentials.
+	ConnectionDetails ConnectionDetails
+
+	// Previous connection details that remain valid during the grace window,
+	// typically the credentials that were replaced. Each is published with
+	// its key suffixed by PreviousCredentialsSuffix.
+	Previous ConnectionDetails
+}
+
+type credentialRotation struct {
+	period time.Duration
+	grace  time.Duration
+	now    func() time.Time
+}
+
+// rotateCredentials rotates the credentials of the supplied managed resource if
+// they are due to be rotated, and unpublishes its previous credentials once
+// their grace window has passed. It returns true if credentials were rotated.
+// Previous credentials are unpublished by publishing nil connection details,
+// which the APISecretPublisher removes from the connection secret.
+func (r *Reconciler) rotateCredentials(ctx context.Context, mg resource.Managed, ec ExternalClient) (bool, error) {
+	cr, ok := ec.(CredentialRotator)
+	if !ok || r.rotation.period == 0 {
+		return false, nil
+	}
+
+	now := r.rotation.now()
+	last, err := time.Parse(time.RFC3339, mg.GetAnnotations()[AnnotationKeyCredentialsRotatedAt])
+	if err != nil {
+		// We don't know when these credentials were minted, so we start the
+		// rotation period now.
+		meta.AddAnnotations(mg, map[string]string{AnnotationKeyCredentialsRotatedAt: now.Format(time.RFC3339)})
+		return false, errors.Wrap(r.client.Update(ctx, mg), errRecordRotationTime)
+	}
+
+	if now.Sub(last) >= r.rotation.period {
+		rot, err := cr.RotateCredentials(ctx, mg)
+		if err != nil {
+			return false, errors.Wrap(err, errRotateCredentials)
+		}
+
+		cd := ConnectionDetails{}
+		for k, v := range rot.ConnectionDetails {
+			cd[k] = v
+		}
+		keys := make([]string, 0, len(rot.Previous))
+		for k, v := range rot.Previous {
+			cd[k+PreviousCredentialsSuffix] = v
+			keys = append(keys, k+PreviousCredentialsSuffix)
+		}
+		sort.Strings(keys)
+
+		if err := r.managed.PublishConnection(ctx, mg, cd); err != nil {
+			return false, errors.Wrap(err, errPublishRotated)
+		}
+
+		meta.AddAnnotations(mg, map[string]string{AnnotationKeyCredentialsRotatedAt: now.Format(time.RFC3339)})
+		meta.RemoveAnnotations(mg, AnnotationKeyPreviousCredentials)
+		if len(keys) > 0 {
+			meta.AddAnnotations(mg, map[string]string{AnnotationKeyPreviousCredentials: strings.Join(keys, ",")})
+		}
+		return true, errors.Wrap(r.client.Update(ctx, mg), errRecordRotationTime)
+	}
+
+	previous := mg.GetAnnotations()[AnnotationKeyPreviousCredentials]
+	if previous == "" || now.Sub(last) < r.rotation.grace {
+		return false, nil
+	}
+
+	cd := ConnectionDetails{}
+	for _, k := range strings.Split(previous, ",") {
+		cd[k] = nil
+	}
+	if err := r.managed.PublishConnection(ctx, mg, cd); err != nil {
+		return false, errors.Wrap(err, errUnpublishPrevious)
+	}
+	meta.RemoveAnnotations(mg, AnnotationKeyPreviousCredentials)
+	return false, errors.Wrap(r.client.Update(ctx, mg), errRecordRotationTime)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type rotatingClient struct {
+	ExternalClientFns
+	RotateCredentialsFn func(ctx context.Context, mg resource.Managed) (ExternalRotation, error)
+}
+
+func (c *rotatingClient) RotateCredentials(ctx context.Context, mg resource.Managed) (ExternalRotation, error) {
+	return c.RotateCredentialsFn(ctx, mg)
+}
+
+func TestRotateCredentials(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	rotatedAt := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+
+	type args struct {
+		client    client.Client
+		publisher ConnectionPublisher
+		ec        ExternalClient
+		mg        *fake.Managed
+	}
+	type want struct {
+		rotated     bool
+		err         error
+		annotations map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotARotator": {
+			reason: "Credentials should not be rotated if the ExternalClient is not a CredentialRotator.",
+			args: args{
+				ec: &ExternalClientFns{},
+				mg: &fake.Managed{},
+			},
+			want: want{},
+		},
+		"StartRotationPeriod": {
+			reason: "The rotation period should start now if we don't know when credentials were last rotated.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				ec:     &rotatingClient{},
+				mg:     &fake.Managed{},
+			},
+			want: want{
+				annotations: map[string]string{AnnotationKeyCredentialsRotatedAt: rotatedAt(0)},
+			},
+		},
+		"NotDue": {
+			reason: "Credentials should not be rotated before the rotation period has passed.",
+			args: args{
+				ec: &rotatingClient{},
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyCredentialsRotatedAt: rotatedAt(time.Hour),
+				}}},
+			},
+			want: want{
+				annotations: map[string]string{AnnotationKeyCredentialsRotatedAt: rotatedAt(time.Hour)},
+			},
+		},
+		"RotateError": {
+			reason: "Errors rotating credentials should be returned.",
+			args: args{
+				ec: &rotatingClient{RotateCredentialsFn: func(_ context.Context, _ resource.Managed) (ExternalRotation, error) {
+					return ExternalRotation{}, errBoom
+				}},
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyCredentialsRotatedAt: rotatedAt(48 * time.Hour),
+				}}},
+			},
+			want: want{
+				err:         errors.Wrap(errBoom, errRotateCredentials),
+				annotations: map[string]string{AnnotationKeyCredentialsRotatedAt: rotatedAt(48 * time.Hour)},
+			},
+		},
+		"Rotated": {
+			reason: "New and previous credentials should be published when credentials are due to be rotated.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				publisher: ConnectionPublisherFns{PublishConnectionFn: func(_ context.Context, _ resource.Managed, got ConnectionDetails) error {
+					want := ConnectionDetails{"password": []byte("new"), "password" + PreviousCredentialsSuffix: []byte("old")}
+					if diff := cmp.Diff(want, got); diff != "" {
+						t.Errorf("\nPublishConnection(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}},
+				ec: &rotatingClient{RotateCredentialsFn: func(_ context.Context, _ resource.Managed) (ExternalRotation, error) {
+					return ExternalRotation{
+						ConnectionDetails: ConnectionDetails{"password": []byte("new")},
+						Previous:          ConnectionDetails{"password": []byte("old")},
+					}, nil
+				}},
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyCredentialsRotatedAt: rotatedAt(48 * time.Hour),
+				}}},
+			},
+			want: want{
+				rotated: true,
+				annotations: map[string]string{
+					AnnotationKeyCredentialsRotatedAt: rotatedAt(0),
+					AnnotationKeyPreviousCredentials:  "password" + PreviousCredentialsSuffix,
+				},
+			},
+		},
+		"GraceWindowPassed": {
+			reason: "Previous credentials should be unpublished once the grace window has passed.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				publisher: ConnectionPublisherFns{PublishConnectionFn: func(_ context.Context, _ resource.Managed, got ConnectionDetails) error {
+					want := ConnectionDetails{"password" + PreviousCredentialsSuffix: nil}
+					if diff := cmp.Diff(want, got); diff != "" {
+						t.Errorf("\nPublishConnection(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}},
+				ec: &rotatingClient{},
+				mg: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyCredentialsRotatedAt: rotatedAt(2 * time.Hour),
+					AnnotationKeyPreviousCredentials:  "password" + PreviousCredentialsSuffix,
+				}}},
+			},
+			want: want{
+				annotations: map[string]string{AnnotationKeyCredentialsRotatedAt: rotatedAt(2 * time.Hour)},
+			},
+		},
+		"RecordRotationError": {
+			reason: "Errors recording the rotation time should be returned.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				ec:     &rotatingClient{},
+				mg:     &fake.Managed{},
+			},
+			want: want{
+				err:         errors.Wrap(errBoom, errRecordRotationTime),
+				annotations: map[string]string{AnnotationKeyCredentialsRotatedAt: rotatedAt(0)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				client:   tc.args.client,
+				managed:  mrManaged{ConnectionPublisher: tc.args.publisher},
+				rotation: credentialRotation{period: 24 * time.Hour, grace: time.Hour, now: func() time.Time { return now }},
+			}
+			rotated, err := r.rotateCredentials(context.Background(), tc.args.mg, tc.args.ec)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.rotateCredentials(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rotated, rotated); diff != "" {
+				t.Errorf("\nReason: %s\nr.rotateCredentials(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.annotations, tc.args.mg.GetAnnotations()); diff != "" {
+				t.Errorf("\nReason: %s\nr.rotateCredentials(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errNoProviderConfig = "managed resource does not reference a provider config"
+	errRouteKey         = "cannot determine which account to connect to"
+
+	errFmtConnectRoute = "cannot connect to account %q"
+	errFmtRateLimited  = "cannot wait for rate limiter of account %q"
+)
+
+// A RouteKeyFn returns a key identifying the account, project, subscription,
+// or similar, in which the external resource of the supplied managed resource
+// is managed. It typically resolves the managed resource's provider config.
+type RouteKeyFn func(ctx context.Context, mg resource.Managed) (string, error)
+
+// ProviderConfigName returns a RouteKeyFn that routes each managed resource
+// by the name of the provider config it references, or of the provider it
+// references if it does not reference a provider config. Use it when each
+// provider config corresponds to exactly one account.
+func ProviderConfigName() RouteKeyFn {
+	return func(_ context.Context, mg resource.Managed) (string, error) {
+		if pc, ok := mg.(resource.ProviderConfigReferencer); ok && pc.GetProviderConfigReference() != nil {
+			return pc.GetProviderConfigReference().Name, nil
+		}
+		if p := mg.GetProviderReference(); p != nil {
+			return p.Name, nil
+		}
+		return "", errors.New(errNoProviderConfig)
+	}
+}
+
+// A route caches the ExternalClient and rate limiter of an account.
+type route struct {
+	client  ExternalClient
+	expires time.Time
+	limiter *rate.Limiter
+}
+
+// A RoutingConnecter routes each managed resource to an ExternalClient for the
+// account in which its external resource is managed. ExternalClients are
+// created by an inner ExternalConnecter and cached per account, so that a
+// single provider may manage external resources in many accounts without
+// connecting to each account every time it reconciles. Calls to each
+// account's ExternalClients may optionally be rate limited per account.
+//
+// Cached ExternalClients are shared by every managed resource routed to the
+// same account, and thus must be safe for concurrent use and must not retain
+// state between calls, or any reference to the context or managed resource
+// they were connected with.
+type RoutingConnecter struct {
+	key     RouteKeyFn
+	connect ExternalConnecter
+	ttl     time.Duration
+	limit   func() *rate.Limiter
+	now     func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*route
+}
+
+// A RoutingConnecterOption configures a RoutingConnecter.
+type RoutingConnecterOption func(*RoutingConnecter)
+
+// WithClientTTL specifies how long a cached ExternalClient may be used before
+// a new one is connected, for example to pick up rotated credentials. Cached
+// ExternalClients never expire by default.
+func WithClientTTL(ttl time.Duration) RoutingConnecterOption {
+	return func(c *RoutingConnecter) {
+		c.ttl = ttl
+	}
+}
+
+// WithRouteRateLimit specifies that calls to the ExternalClient of each
+// account should be limited to the supplied average number of calls per
+// second, with bursts of up to the supplied number of calls. Calls wait for
+// the rate limiter; those that cannot proceed before their context is done
+// return a throttled error. Calls are not rate limited by default.
+func WithRouteRateLimit(rps float64, burst int) RoutingConnecterOption {
+	return func(c *RoutingConnecter) {
+		c.limit = func() *rate.Limiter { return rate.NewLimiter(rate.Limit(rps), burst) }
+	}
+}
+
+// NewRoutingConnecter returns a RoutingConnecter that uses the supplied
+// RouteKeyFn to determine the account of each managed resource, and the
+// supplied ExternalConnecter to connect to an account the first time a managed
+// resource is routed to it.
+func NewRoutingConnecter(key RouteKeyFn, c ExternalConnecter, o ...RoutingConnecterOption) *RoutingConnecter {
+	rc := &RoutingConnecter{key: key, connect: c, now: time.Now, routes: map[string]*route{}}
+	for _, fn := range o {
+		fn(rc)
+	}
+	return rc
+}
+
+// Connect returns the cached ExternalClient of the supplied managed resource's
+// account, connecting to the account if there is no unexpired cached client.
+func (c *RoutingConnecter) Connect(ctx context.Context, mg resource.Managed) (ExternalClient, error) {
+	key, err := c.key(ctx, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errRouteKey)
+	}
+
+	c.mu.Lock()
+	rt, ok := c.routes[key]
+	if ok && rt.client != nil && (c.ttl == 0 || c.now().Before(rt.expires)) {
+		c.mu.Unlock()
+		return rt.client, nil
+	}
+	c.mu.Unlock()
+
+	// We connect without holding the lock so that a slow connection to one
+	// account does not block connections to others. Concurrent connections to
+	// the same account may race; the last one wins.
+	ec, err := c.connect.Connect(ctx, mg)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtConnectRoute, key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rt, ok = c.routes[key]
+	if !ok {
+		rt = &route{}
+		if c.limit != nil {
+			rt.limiter = c.limit()
+		}
+		c.routes[key] = rt
+	}
+	rt.client = ec
+	rt.expires = c.now().Add(c.ttl)
+	if rt.limiter != nil {
+		rt.client = newRateLimitedClient(ec, key, rt.limiter)
+	}
+	return rt.client, nil
+}
+
+// newRateLimitedClient returns an ExternalClient that waits for the supplied
+// limiter before each call to the supplied ExternalClient. The returned client
+// is a CredentialRotator if the supplied client is.
+func newRateLimitedClient(ec ExternalClient, key string, l *rate.Limiter) ExternalClient {
+	c := &rateLimitedClient{client: ec, key: key, limiter: l}
+	if cr, ok := ec.(CredentialRotator); ok {
+		return &rateLimitedRotator{rateLimitedClient: c, rotator: cr}
+	}
+	return c
+}
+
+type rateLimitedClient struct {
+	client  ExternalClient
+	key     string
+	limiter *rate.Limiter
+}
+
+func (c *rateLimitedClient) wait(ctx context.Context) error {
+	return NewThrottled(errors.Wrapf(c.limiter.Wait(ctx), errFmtRateLimited, c.key))
+}
+
+func (c *rateLimitedClient) Observe(ctx context.Context, mg resource.Managed) (ExternalObservation, error) {
+	if err := c.wait(ctx); err != nil {
+		return ExternalObservation{}, err
+	}
+	return c.client.Observe(ctx, mg)
+}
+
+func (c *rateLimitedClient) Create(ctx context.Context, mg resource.Managed) (ExternalCreation, error) {
+	if err := c.wait(ctx); err != nil {
+		return ExternalCreation{}, err
+	}
+	return c.client.Create(ctx, mg)
+}
+
+func (c *rateLimitedClient) Update(ctx context.Context, mg resource.Managed) (ExternalUpdate, error) {
+	if err := c.wait(ctx); err != nil {
+		return ExternalUpdate{}, err
+	}
+	return c.client.Update(ctx, mg)
+}
+
+func (c *rateLimitedClient) Delete(ctx context.Context, mg resource.Managed) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	return c.client.Delete(ctx, mg)
+}
+
+type rateLimitedRotator struct {
+	*rateLimitedClient
+	rotator CredentialRotator
+}
+
+func (c *rateLimitedRotator) RotateCredentials(ctx context.Context, mg resource.Managed) (ExternalRotation, error) {
+	if err := c.wait(ctx); err != nil {
+		return ExternalRotation{}, err
+	}
+	return c.rotator.RotateCredentials(ctx, mg)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestProviderConfigName(t *testing.T) {
+	type want struct {
+		key string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   want
+	}{
+		"ProviderConfig": {
+			reason: "Managed resources should be routed by provider config if they reference one.",
+			mg: &fake.Managed{
+				ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &v1alpha1.Reference{Name: "config"}},
+				ProviderReferencer:       fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: "provider"}},
+			},
+			want: want{key: "config"},
+		},
+		"Provider": {
+			reason: "Managed resources should be routed by provider if they don't reference a provider config.",
+			mg: &fake.Managed{
+				ProviderReferencer: fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: "provider"}},
+			},
+			want: want{key: "provider"},
+		},
+		"Neither": {
+			reason: "An error should be returned if the managed resource references neither a provider config nor a provider.",
+			mg:     &fake.Managed{},
+			want:   want{err: errors.New(errNoProviderConfig)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := ProviderConfigName()(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProviderConfigName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.key, key); diff != "" {
+				t.Errorf("\n%s\nProviderConfigName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoutingConnecter(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	// keys returns a RouteKeyFn that returns each of the supplied keys in turn.
+	keys := func(k ...string) RouteKeyFn {
+		return func(_ context.Context, _ resource.Managed) (string, error) {
+			key := k[0]
+			k = k[1:]
+			return key, nil
+		}
+	}
+
+	type args struct {
+		key RouteKeyFn
+		err error
+		o   []RoutingConnecterOption
+	}
+	type want struct {
+		connects int
+		same     bool
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"KeyError": {
+			reason: "Errors determining the route key should be returned.",
+			args: args{
+				key: func(_ context.Context, _ resource.Managed) (string, error) { return "", errBoom },
+			},
+			want: want{err: errors.Wrap(errBoom, errRouteKey)},
+		},
+		"ConnectError": {
+			reason: "Errors connecting to an account should be returned.",
+			args: args{
+				key: keys("a", "a"),
+				err: errBoom,
+			},
+			want: want{connects: 1, err: errors.Wrapf(errBoom, errFmtConnectRoute, "a")},
+		},
+		"SameAccount": {
+			reason: "Managed resources routed to the same account should share a cached ExternalClient.",
+			args: args{
+				key: keys("a", "a"),
+			},
+			want: want{connects: 1, same: true},
+		},
+		"DifferentAccounts": {
+			reason: "Managed resources routed to different accounts should use different ExternalClients.",
+			args: args{
+				key: keys("a", "b"),
+			},
+			want: want{connects: 2},
+		},
+		"Expired": {
+			reason: "A new ExternalClient should be connected once the cached one expires.",
+			args: args{
+				key: keys("a", "a"),
+				o: []RoutingConnecterOption{
+					WithClientTTL(time.Minute),
+					func(c *RoutingConnecter) {
+						calls := 0
+						c.now = func() time.Time {
+							calls++
+							return now.Add(time.Duration(calls) * time.Hour)
+						}
+					},
+				},
+			},
+			want: want{connects: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			connects := 0
+			ec := ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (ExternalClient, error) {
+				connects++
+				return &ExternalClientFns{}, tc.args.err
+			})
+			c := NewRoutingConnecter(tc.args.key, ec, tc.args.o...)
+
+			first, err := c.Connect(context.Background(), &fake.Managed{})
+			if err == nil {
+				var second ExternalClient
+				second, err = c.Connect(context.Background(), &fake.Managed{})
+				if diff := cmp.Diff(tc.want.same, first == second); diff != "" {
+					t.Errorf("\n%s\nc.Connect(...): -want same client, +got same client:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.connects, connects); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want connects, +got connects:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRoutingConnecterRateLimit(t *testing.T) {
+	ec := ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (ExternalClient, error) {
+		return &ExternalClientFns{
+			ObserveFn: func(_ context.Context, _ resource.Managed) (ExternalObservation, error) {
+				return ExternalObservation{ResourceExists: true}, nil
+			},
+		}, nil
+	})
+	key := func(_ context.Context, _ resource.Managed) (string, error) { return "a", nil }
+	c := NewRoutingConnecter(key, ec, WithRouteRateLimit(0.001, 1))
+
+	client, err := c.Connect(context.Background(), &fake.Managed{})
+	if err != nil {
+		t.Fatalf("c.Connect(...): %s", err)
+	}
+	if _, err := client.Observe(context.Background(), &fake.Managed{}); err != nil {
+		t.Errorf("client.Observe(...): the first call should not be rate limited: %s", err)
+	}
+
+	// The limiter's only token has been spent, and the next won't be available
+	// before this context's deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = client.Observe(ctx, &fake.Managed{})
+	if !IsThrottled(err) {
+		t.Errorf("client.Observe(...): want throttled error, got: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/openapi"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultValidatedFieldPath is the field path of managed resources that is
+// validated by a CRDSchemaValidator by default.
+const DefaultValidatedFieldPath = "spec.forProvider"
+
+// Error strings.
+const (
+	errListCRDs = "cannot list custom resource definitions"
+
+	errFmtNoCRD = "no custom resource definition defines kind %s"
+)
+
+// CustomResourceDefinitionListKind is the kind of list used to find the
+// CustomResourceDefinition of a managed resource.
+var CustomResourceDefinitionListKind = schema.GroupVersionKind{
+	Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinitionList",
+}
+
+// A SpecValidator validates the spec of a managed resource before the
+// Reconciler makes any external API calls, so that a spec the external API
+// would reject doesn't waste API requests.
+type SpecValidator interface {
+	// ValidateSpec returns an error if the spec of the supplied managed
+	// resource is invalid. Errors that indicate an invalid spec should be
+	// classified using NewInvalidSpec.
+	ValidateSpec(ctx context.Context, mg resource.Managed) error
+}
+
+// A SpecValidatorFn is a function that satisfies the SpecValidator interface.
+type SpecValidatorFn func(ctx context.Context, mg resource.Managed) error
+
+// ValidateSpec calls SpecValidatorFn function.
+func (fn SpecValidatorFn) ValidateSpec(ctx context.Context, mg resource.Managed) error {
+	return fn(ctx, mg)
+}
+
+// A SpecValidatorChain chains multiple spec validators.
+type SpecValidatorChain []SpecValidator
+
+// ValidateSpec calls each SpecValidator serially. It returns the first error
+// it encounters, if any.
+func (vc SpecValidatorChain) ValidateSpec(ctx context.Context, mg resource.Managed) error {
+	for _, v := range vc {
+		if err := v.ValidateSpec(ctx, mg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A CRDSchemaValidator validates a field of a managed resource against the
+// OpenAPI schema of the version of its CustomResourceDefinition that defines
+// it. The CRDSchemaValidator's client must be able to list
+// CustomResourceDefinitions.
+type CRDSchemaValidator struct {
+	client client.Reader
+	kind   schema.GroupVersionKind
+	path   string
+}
+
+// A CRDSchemaValidatorOption configures a CRDSchemaValidator.
+type CRDSchemaValidatorOption func(*CRDSchemaValidator)
+
+// WithValidatedFieldPath specifies the field path of the managed resource that
+// should be validated, for example spec.forProvider. Field paths must be a
+// series of object fields; they may not include array indices.
+func WithValidatedFieldPath(path string) CRDSchemaValidatorOption {
+	return func(v *CRDSchemaValidator) {
+		v.path = path
+	}
+}
+
+// NewCRDSchemaValidator returns a CRDSchemaValidator that validates managed
+// resources of the supplied kind. It validates spec.forProvider by default.
+func NewCRDSchemaValidator(c client.Reader, of resource.ManagedKind, o ...CRDSchemaValidatorOption) *CRDSchemaValidator {
+	v := &CRDSchemaValidator{client: c, kind: schema.GroupVersionKind(of), path: DefaultValidatedFieldPath}
+	for _, fn := range o {
+		fn(v)
+	}
+	return v
+}
+
+// ValidateSpec validates the supplied managed resource against the OpenAPI
+// schema of its CustomResourceDefinition. It returns an error classified as an
+// invalid spec if the managed resource violates the schema.
+func (v *CRDSchemaValidator) ValidateSpec(ctx context.Context, mg resource.Managed) error {
+	s, err := v.schema(ctx)
+	if err != nil || s == nil {
+		return err
+	}
+
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return errors.Wrap(err, errConvertManaged)
+	}
+	value, err := fieldpath.Pave(m).GetValue(v.path)
+	if err != nil {
+		// The validated field is not set; there's nothing to validate.
+		return nil
+	}
+
+	fields := strings.Split(v.path, ".")
+	errs := openapi.Validate(s.Property(fields...), value, field.NewPath(fields[0], fields[1:]...))
+	if len(errs) == 0 {
+		return nil
+	}
+	return NewInvalidSpec(errs.ToAggregate())
+}
+
+// schema returns the OpenAPI schema of the served version of the validated
+// kind of managed resource, or nil if it has none.
+func (v *CRDSchemaValidator) schema(ctx context.Context) (openapi.Schema, error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(CustomResourceDefinitionListKind)
+	if err := v.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListCRDs)
+	}
+	for _, crd := range l.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if group == v.kind.Group && kind == v.kind.Kind {
+			return versionSchema(crd.Object, v.kind.Version), nil
+		}
+	}
+	return nil, errors.Errorf(errFmtNoCRD, v.kind.GroupKind())
+}
+
+// versionSchema returns the OpenAPI schema of the supplied version of the
+// supplied unstructured CustomResourceDefinition. Versions may have their own
+// schema, or share a top-level schema.
+func versionSchema(crd map[string]interface{}, version string) openapi.Schema {
+	versions, _, _ := unstructured.NestedSlice(crd, "spec", "versions")
+	for _, ver := range versions {
+		ver, ok := ver.(map[string]interface{})
+		if !ok || ver["name"] != version {
+			continue
+		}
+		if s, ok, _ := unstructured.NestedMap(ver, "schema", "openAPIV3Schema"); ok {
+			return s
+		}
+	}
+	if s, ok, _ := unstructured.NestedMap(crd, "spec", "validation", "openAPIV3Schema"); ok {
+		return s
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/openapi"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ SpecValidator = &CRDSchemaValidator{}
+var _ SpecValidator = SpecValidatorChain{}
+
+func TestSpecValidatorChain(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		vc     SpecValidatorChain
+		want   error
+	}{
+		"Empty": {
+			reason: "An empty chain should return nil.",
+			vc:     SpecValidatorChain{},
+			want:   nil,
+		},
+		"FirstError": {
+			reason: "The first error returned by a validator should be returned.",
+			vc: SpecValidatorChain{
+				SpecValidatorFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+				SpecValidatorFn(func(_ context.Context, _ resource.Managed) error { return errBoom }),
+				SpecValidatorFn(func(_ context.Context, _ resource.Managed) error { return errors.New("later") }),
+			},
+			want: errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.vc.ValidateSpec(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateSpec(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDSchemaValidator(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := fake.GVK(&fake.Managed{})
+
+	policySchema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"reclaimer": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"policy": map[string]interface{}{
+						"type": "string",
+						"enum": []interface{}{string(v1alpha1.ReclaimRetain), string(v1alpha1.ReclaimDelete)},
+					},
+				},
+			},
+		},
+	}
+
+	crd := func(group, kind string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"group": group,
+				"names": map[string]interface{}{"kind": kind},
+				"versions": []interface{}{
+					map[string]interface{}{
+						"name":   gvk.Version,
+						"schema": map[string]interface{}{"openAPIV3Schema": policySchema},
+					},
+				},
+			},
+		}}
+	}
+
+	list := func(crds ...unstructured.Unstructured) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			if l.GroupVersionKind() != CustomResourceDefinitionListKind {
+				t.Errorf("List(...): unexpected kind %s", l.GroupVersionKind())
+			}
+			l.Items = crds
+			return nil
+		}
+	}
+
+	type args struct {
+		c  client.Reader
+		of resource.ManagedKind
+		o  []CRDSchemaValidatorOption
+		mg resource.Managed
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"ListError": {
+			reason: "Errors listing CustomResourceDefinitions should be returned.",
+			args: args{
+				c:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				of: resource.ManagedKind(gvk),
+				mg: &fake.Managed{},
+			},
+			want: errors.Wrap(errBoom, errListCRDs),
+		},
+		"NoCRD": {
+			reason: "An error should be returned if no CustomResourceDefinition defines the managed resource kind.",
+			args: args{
+				c:  &test.MockClient{MockList: list(crd(gvk.Group, "Other"))},
+				of: resource.ManagedKind(gvk),
+				mg: &fake.Managed{},
+			},
+			want: errors.Errorf(errFmtNoCRD, gvk.GroupKind()),
+		},
+		"FieldNotSet": {
+			reason: "Nothing should be validated if the validated field is not set.",
+			args: args{
+				c:  &test.MockClient{MockList: list(crd(gvk.Group, gvk.Kind))},
+				of: resource.ManagedKind(gvk),
+				mg: &fake.Managed{},
+			},
+			want: nil,
+		},
+		"Valid": {
+			reason: "A managed resource that satisfies its schema should be valid.",
+			args: args{
+				c:  &test.MockClient{MockList: list(crd(gvk.Group, gvk.Kind))},
+				of: resource.ManagedKind(gvk),
+				o:  []CRDSchemaValidatorOption{WithValidatedFieldPath("reclaimer.policy")},
+				mg: &fake.Managed{Reclaimer: fake.Reclaimer{Policy: v1alpha1.ReclaimDelete}},
+			},
+			want: nil,
+		},
+		"Invalid": {
+			reason: "A managed resource that violates its schema should be classified as an invalid spec.",
+			args: args{
+				c:  &test.MockClient{MockList: list(crd(gvk.Group, gvk.Kind))},
+				of: resource.ManagedKind(gvk),
+				o:  []CRDSchemaValidatorOption{WithValidatedFieldPath("reclaimer.policy")},
+				mg: &fake.Managed{Reclaimer: fake.Reclaimer{Policy: v1alpha1.ReclaimPolicy("Orphan")}},
+			},
+			want: NewInvalidSpec(field.ErrorList{
+				field.NotSupported(field.NewPath("reclaimer", "policy"), "Orphan", []string{string(v1alpha1.ReclaimRetain), string(v1alpha1.ReclaimDelete)}),
+			}.ToAggregate()),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewCRDSchemaValidator(tc.args.c, tc.args.of, tc.args.o...)
+			err := v.ValidateSpec(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateSpec(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVersionSchema(t *testing.T) {
+	shared := map[string]interface{}{"type": "object"}
+	v1 := map[string]interface{}{"type": "string"}
+
+	cases := map[string]struct {
+		reason  string
+		crd     map[string]interface{}
+		version string
+		want    openapi.Schema
+	}{
+		"VersionSchema": {
+			reason: "The schema of the requested version should take precedence.",
+			crd: map[string]interface{}{"spec": map[string]interface{}{
+				"validation": map[string]interface{}{"openAPIV3Schema": shared},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1", "schema": map[string]interface{}{"openAPIV3Schema": v1}},
+				},
+			}},
+			version: "v1",
+			want:    v1,
+		},
+		"SharedSchema": {
+			reason: "The top-level schema should be returned if the requested version has none.",
+			crd: map[string]interface{}{"spec": map[string]interface{}{
+				"validation": map[string]interface{}{"openAPIV3Schema": shared},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1", "schema": map[string]interface{}{"openAPIV3Schema": v1}},
+				},
+			}},
+			version: "v2",
+			want:    shared,
+		},
+		"NoSchema": {
+			reason:  "A nil schema should be returned if the CustomResourceDefinition has none.",
+			crd:     map[string]interface{}{"spec": map[string]interface{}{}},
+			version: "v1",
+			want:    nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := versionSchema(tc.crd, tc.version)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nversionSchema(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultTagsFieldPath is the field path at which an APITagger writes tags by
+// default.
+const DefaultTagsFieldPath = "spec.forProvider.tags"
+
+// Error strings.
+const (
+	errGetTagsConfigMap = "cannot get default tags config map"
+	errConvertManaged   = "cannot convert managed resource"
+	errFmtGetTags       = "cannot get tags at field path %q"
+	errFmtSetTags       = "cannot set tags at field path %q"
+)
+
+// An APITagger is an Initializer that adds default tags to a managed resource
+// in the Kubernetes API server, so that they're applied to its external
+// resource. Default tags include organization-wide tags supplied by the
+// controller or read from a ConfigMap, and the identifying tags returned by
+// resource.GetExternalTags. Tags are written to an object of string values at
+// a well-known field path, DefaultTagsFieldPath unless otherwise configured.
+// Tags the managed resource already has are never overwritten.
+type APITagger struct {
+	client    client.Client
+	path      string
+	tags      map[string]string
+	configMap *types.NamespacedName
+}
+
+// An APITaggerOption configures an APITagger.
+type APITaggerOption func(*APITagger)
+
+// WithDefaultTags specifies tags that should be added to every managed
+// resource.
+func WithDefaultTags(tags map[string]string) APITaggerOption {
+	return func(t *APITagger) {
+		for k, v := range tags {
+			t.tags[k] = v
+		}
+	}
+}
+
+// WithDefaultTagsFromConfigMap specifies a ConfigMap whose data should be
+// added to every managed resource as tags. Tags read from the ConfigMap take
+// precedence over those supplied using WithDefaultTags.
+func WithDefaultTagsFromConfigMap(namespace, name string) APITaggerOption {
+	return func(t *APITagger) {
+		t.configMap = &types.NamespacedName{Namespace: namespace, Name: name}
+	}
+}
+
+// WithTagsFieldPath specifies the field path at which tags should be written.
+func WithTagsFieldPath(path string) APITaggerOption {
+	return func(t *APITagger) {
+		t.path = path
+	}
+}
+
+// NewAPITagger returns a new APITagger.
+func NewAPITagger(c client.Client, o ...APITaggerOption) *APITagger {
+	t := &APITagger{client: c, path: DefaultTagsFieldPath, tags: map[string]string{}}
+	for _, fn := range o {
+		fn(t)
+	}
+	return t
+}
+
+// Initialize the supplied managed resource by adding any default tags it does
+// not already have. The managed resource is only updated if tags were added.
+func (t *APITagger) Initialize(ctx context.Context, mg resource.Managed) error {
+	defaults, err := t.defaults(ctx, mg)
+	if err != nil {
+		return err
+	}
+
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return errors.Wrap(err, errConvertManaged)
+	}
+	p := fieldpath.Pave(m)
+
+	tags := map[string]string{}
+	if _, err := p.GetValue(t.path); err == nil {
+		// The field exists, so it must be an object of strings.
+		if tags, err = p.GetStringObject(t.path); err != nil {
+			return errors.Wrapf(err, errFmtGetTags, t.path)
+		}
+	}
+
+	added := false
+	for k, v := range defaults {
+		if _, ok := tags[k]; ok {
+			continue
+		}
+		tags[k] = v
+		added = true
+	}
+	if !added {
+		return nil
+	}
+
+	if err := p.SetValue(t.path, tags); err != nil {
+		return errors.Wrapf(err, errFmtSetTags, t.path)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(p.UnstructuredContent(), mg); err != nil {
+		return errors.Wrap(err, errConvertManaged)
+	}
+	return errors.Wrap(t.client.Update(ctx, mg), errUpdateManaged)
+}
+
+// defaults returns the default tags of the supplied managed resource.
+func (t *APITagger) defaults(ctx context.Context, mg resource.Managed) (map[string]string, error) {
+	tags := map[string]string{}
+	for k, v := range t.tags {
+		tags[k] = v
+	}
+	if t.configMap != nil {
+		cm := &corev1.ConfigMap{}
+		if err := t.client.Get(ctx, *t.configMap, cm); err != nil {
+			return nil, errors.Wrap(err, errGetTagsConfigMap)
+		}
+		for k, v := range cm.Data {
+			tags[k] = v
+		}
+	}
+	for k, v := range resource.GetExternalTags(mg) {
+		// Typed managed resources often have an empty kind, in which case
+		// we can't derive all identifying tags.
+		if v == "" {
+			continue
+		}
+		tags[k] = v
+	}
+	return tags, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type taggedManaged struct {
+	fake.Managed
+	Spec taggedSpec `json:"spec"`
+}
+
+type taggedSpec struct {
+	ForProvider taggedParameters `json:"forProvider"`
+}
+
+type taggedParameters struct {
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+func TestAPITaggerInitialize(t *testing.T) {
+	errBoom := errors.New("boom")
+	cm := func(o runtime.Object) error {
+		*o.(*corev1.ConfigMap) = corev1.ConfigMap{Data: map[string]string{"team": "platform", "env": "prod"}}
+		return nil
+	}
+	tagged := func(tags map[string]string) *taggedManaged {
+		mg := &taggedManaged{Managed: fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}}
+		mg.Spec.ForProvider.Tags = tags
+		return mg
+	}
+
+	type args struct {
+		client client.Client
+		o      []APITaggerOption
+		mg     *taggedManaged
+	}
+	type want struct {
+		err  error
+		tags map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetConfigMapError": {
+			reason: "Errors getting the default tags config map should be returned.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				o:      []APITaggerOption{WithDefaultTagsFromConfigMap("ns", "tags")},
+				mg:     tagged(nil),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetTagsConfigMap),
+			},
+		},
+		"AlreadyTagged": {
+			reason: "The managed resource should not be updated if it already has every default tag.",
+			args: args{
+				o:  []APITaggerOption{WithDefaultTags(map[string]string{"team": "platform"})},
+				mg: tagged(map[string]string{"team": "databases", resource.ExternalResourceTagKeyName: "cool"}),
+			},
+			want: want{
+				tags: map[string]string{"team": "databases", resource.ExternalResourceTagKeyName: "cool"},
+			},
+		},
+		"UpdateError": {
+			reason: "Errors updating the managed resource should be returned.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:     tagged(nil),
+			},
+			want: want{
+				err:  errors.Wrap(errBoom, errUpdateManaged),
+				tags: map[string]string{resource.ExternalResourceTagKeyName: "cool"},
+			},
+		},
+		"Tagged": {
+			reason: "Missing default tags should be added without overwriting existing tags.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(nil, cm),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				o: []APITaggerOption{
+					WithDefaultTags(map[string]string{"env": "dev", "org": "example"}),
+					WithDefaultTagsFromConfigMap("ns", "tags"),
+				},
+				mg: tagged(map[string]string{"team": "databases"}),
+			},
+			want: want{
+				tags: map[string]string{
+					"team":                              "databases",
+					"env":                               "prod",
+					"org":                               "example",
+					resource.ExternalResourceTagKeyName: "cool",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tg := NewAPITagger(tc.args.client, tc.args.o...)
+			err := tg.Initialize(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntg.Initialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tags, tc.args.mg.Spec.ForProvider.Tags); diff != "" {
+				t.Errorf("\n%s\ntg.Initialize(...): -want tags, +got tags:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
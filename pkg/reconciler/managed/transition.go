@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// A ConditionTransitionHook is notified when the status of a condition of a
+// managed resource changes during a reconcile.
+type ConditionTransitionHook interface {
+	Transition(gvk schema.GroupVersionKind, ct v1alpha1.ConditionType, from, to corev1.ConditionStatus)
+}
+
+// A ConditionTransitionHookFn is a function that satisfies the
+// ConditionTransitionHook interface.
+type ConditionTransitionHookFn func(gvk schema.GroupVersionKind, ct v1alpha1.ConditionType, from, to corev1.ConditionStatus)
+
+// Transition calls ConditionTransitionHookFn.
+func (fn ConditionTransitionHookFn) Transition(gvk schema.GroupVersionKind, ct v1alpha1.ConditionType, from, to corev1.ConditionStatus) {
+	fn(gvk, ct, from, to)
+}
+
+// A ConditionTransitionCounter is a Prometheus collector that counts condition
+// transitions by managed resource kind, condition type, and the statuses
+// transitioned from and to. It must be registered with a Prometheus registry,
+// for example controller-runtime's metrics.Registry, to be exported.
+type ConditionTransitionCounter struct {
+	*prometheus.CounterVec
+}
+
+// NewConditionTransitionCounter returns a ConditionTransitionCounter.
+func NewConditionTransitionCounter() *ConditionTransitionCounter {
+	return &ConditionTransitionCounter{CounterVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "crossplane",
+		Subsystem: "managed_resource",
+		Name:      "condition_transitions_total",
+		Help:      "Number of times a condition of a managed resource transitioned between statuses.",
+	}, []string{"gvk", "type", "from", "to"})}
+}
+
+// Transition increments the counter for the supplied transition.
+func (c *ConditionTransitionCounter) Transition(gvk schema.GroupVersionKind, ct v1alpha1.ConditionType, from, to corev1.ConditionStatus) {
+	c.WithLabelValues(gvk.String(), string(ct), string(from), string(to)).Inc()
+}
+
+// A transitionObserver notifies a ConditionTransitionHook of transitions of
+// the condition types it tracks.
+type transitionObserver struct {
+	gvk   schema.GroupVersionKind
+	hook  ConditionTransitionHook
+	types []v1alpha1.ConditionType
+}
+
+// track the conditions of the supplied managed resource. The returned function
+// notifies the hook of any tracked conditions that have changed status since
+// track was called; it is typically deferred until the end of a reconcile.
+func (o transitionObserver) track(mg resource.Managed) func() {
+	if o.hook == nil {
+		return func() {}
+	}
+
+	from := make([]corev1.ConditionStatus, len(o.types))
+	for i, ct := range o.types {
+		from[i] = mg.GetCondition(ct).Status
+	}
+
+	return func() {
+		for i, ct := range o.types {
+			if to := mg.GetCondition(ct).Status; to != from[i] {
+				o.hook.Transition(o.gvk, ct, from[i], to)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+type transition struct {
+	Type     v1alpha1.ConditionType
+	From, To corev1.ConditionStatus
+}
+
+func TestTransitionObserverTrack(t *testing.T) {
+	gvk := fake.GVK(&fake.Managed{})
+
+	cases := map[string]struct {
+		reason string
+		types  []v1alpha1.ConditionType
+		before []v1alpha1.Condition
+		after  []v1alpha1.Condition
+		want   []transition
+	}{
+		"NoTransitions": {
+			reason: "The hook should not be called if no tracked conditions changed status.",
+			types:  []v1alpha1.ConditionType{v1alpha1.TypeReady, v1alpha1.TypeSynced},
+			before: []v1alpha1.Condition{v1alpha1.Available(), v1alpha1.ReconcileSuccess()},
+			after:  []v1alpha1.Condition{v1alpha1.Available(), v1alpha1.ReconcileSuccess()},
+		},
+		"Transitions": {
+			reason: "The hook should be called for each tracked condition that changed status.",
+			types:  []v1alpha1.ConditionType{v1alpha1.TypeReady, v1alpha1.TypeSynced},
+			before: []v1alpha1.Condition{v1alpha1.Available()},
+			after:  []v1alpha1.Condition{v1alpha1.Unavailable(), v1alpha1.ReconcileSuccess()},
+			want: []transition{
+				{Type: v1alpha1.TypeReady, From: corev1.ConditionTrue, To: corev1.ConditionFalse},
+				{Type: v1alpha1.TypeSynced, From: corev1.ConditionUnknown, To: corev1.ConditionTrue},
+			},
+		},
+		"UntrackedType": {
+			reason: "The hook should not be called for conditions of untracked types.",
+			types:  []v1alpha1.ConditionType{v1alpha1.TypeSynced},
+			before: []v1alpha1.Condition{v1alpha1.Available()},
+			after:  []v1alpha1.Condition{v1alpha1.Unavailable()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []transition
+			o := transitionObserver{
+				gvk:   gvk,
+				types: tc.types,
+				hook: ConditionTransitionHookFn(func(_ schema.GroupVersionKind, ct v1alpha1.ConditionType, from, to corev1.ConditionStatus) {
+					got = append(got, transition{Type: ct, From: from, To: to})
+				}),
+			}
+
+			mg := &fake.Managed{}
+			mg.SetConditions(tc.before...)
+			done := o.track(mg)
+			mg.SetConditions(tc.after...)
+			done()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\no.track(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConditionTransitionCounter(t *testing.T) {
+	gvk := fake.GVK(&fake.Managed{})
+	c := NewConditionTransitionCounter()
+
+	c.Transition(gvk, v1alpha1.TypeReady, corev1.ConditionTrue, corev1.ConditionFalse)
+	c.Transition(gvk, v1alpha1.TypeReady, corev1.ConditionTrue, corev1.ConditionFalse)
+
+	got := testutil.ToFloat64(c.WithLabelValues(gvk.String(), string(v1alpha1.TypeReady), string(corev1.ConditionTrue), string(corev1.ConditionFalse)))
+	if diff := cmp.Diff(float64(2), got); diff != "" {
+		t.Errorf("\nc.Transition(...): -want, +got:\n%s", diff)
+	}
+}
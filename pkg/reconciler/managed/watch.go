@@ -0,0 +1,231 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultWatchRetryPeriod is the default period after which an
+// ExternalChangeSource calls an ExternalWatcher again if it returns.
+const DefaultWatchRetryPeriod = 30 * time.Second
+
+// Error strings.
+const (
+	errNoStopChannel     = "cannot start external change source without a stop channel"
+	errWatchExternal     = "cannot watch external resources for changes"
+	errGetChangedManaged = "cannot get managed resource whose external resource changed"
+)
+
+// An ExternalChange indicates that an external resource changed.
+type ExternalChange struct {
+	// Managed identifies the managed resource that represents the changed
+	// external resource, if it is known.
+	Managed types.NamespacedName
+
+	// ExternalName of the changed external resource. It is used to find the
+	// managed resource that represents the changed external resource when
+	// Managed is not set.
+	ExternalName string
+}
+
+// An ExternalWatcher watches an external system, for example a cloud
+// provider's event stream, for changes to external resources.
+type ExternalWatcher interface {
+	// Watch for changes to external resources, sending an ExternalChange for
+	// each until the supplied context is cancelled. Watch is called again
+	// after a short wait if it returns before the context is cancelled.
+	Watch(ctx context.Context, changes chan<- ExternalChange) error
+}
+
+// An ExternalWatcherFn is a function that satisfies the ExternalWatcher
+// interface.
+type ExternalWatcherFn func(ctx context.Context, changes chan<- ExternalChange) error
+
+// Watch for changes to external resources.
+func (fn ExternalWatcherFn) Watch(ctx context.Context, changes chan<- ExternalChange) error {
+	return fn(ctx, changes)
+}
+
+// An ExternalChangeSourceOption configures an ExternalChangeSource.
+type ExternalChangeSourceOption func(*ExternalChangeSource)
+
+// WithWatchRetryPeriod specifies how long an ExternalChangeSource waits before
+// calling its ExternalWatcher again if it returns.
+func WithWatchRetryPeriod(d time.Duration) ExternalChangeSourceOption {
+	return func(s *ExternalChangeSource) {
+		s.retry = d
+	}
+}
+
+// WithWatchLogger specifies how an ExternalChangeSource should log messages.
+func WithWatchLogger(l logging.Logger) ExternalChangeSourceOption {
+	return func(s *ExternalChangeSource) {
+		s.log = l
+	}
+}
+
+// An ExternalChangeSource is a source of controller events. It emits a generic
+// event for a managed resource whenever its ExternalWatcher reports that the
+// managed resource's external resource changed. A managed resource
+// controller may watch an ExternalChangeSource in addition to the managed
+// resources it reconciles, allowing it to correct drift as soon as it happens
+// rather than at its next poll, for example:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//	    For(&v1alpha1.CoolResource{}).
+//	    Watches(managed.NewExternalChangeSource(...), &handler.EnqueueRequestForObject{}).
+//	    Complete(r)
+type ExternalChangeSource struct {
+	client     client.Reader
+	newManaged func() resource.Managed
+	newList    func() resource.ManagedList
+	watcher    ExternalWatcher
+	retry      time.Duration
+	log        logging.Logger
+
+	stop <-chan struct{}
+}
+
+// NewExternalChangeSource returns an ExternalChangeSource that emits events
+// for the supplied kind of managed resource when the supplied ExternalWatcher
+// reports that their external resources changed. Managed resources are read
+// using the supplied client. Changes that identify an external resource only
+// by its external name require the client to be backed by a cache with
+// reference.IndexExternalName.
+func NewExternalChangeSource(c client.Reader, s *runtime.Scheme, of resource.ManagedKind, w ExternalWatcher, o ...ExternalChangeSourceOption) *ExternalChangeSource {
+	src := &ExternalChangeSource{
+		client: c,
+		newManaged: func() resource.Managed {
+			return resource.MustCreateObject(schema.GroupVersionKind(of), s).(resource.Managed)
+		},
+		newList: func() resource.ManagedList {
+			return resource.MustCreateObject(of.List(), s).(resource.ManagedList)
+		},
+		watcher: w,
+		retry:   DefaultWatchRetryPeriod,
+		log:     logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(src)
+	}
+	return src
+}
+
+// InjectStopChannel injects the channel that is closed when the
+// ExternalChangeSource should stop watching. It is called by the controller
+// that watches the ExternalChangeSource.
+func (s *ExternalChangeSource) InjectStopChannel(stop <-chan struct{}) error {
+	if s.stop == nil {
+		s.stop = stop
+	}
+	return nil
+}
+
+// Start watching for changes to external resources, emitting events to the
+// supplied handler.
+func (s *ExternalChangeSource) Start(h handler.EventHandler, q workqueue.RateLimitingInterface, ps ...predicate.Predicate) error {
+	if s.stop == nil {
+		return errors.New(errNoStopChannel)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-s.stop
+		cancel()
+	}()
+
+	changes := make(chan ExternalChange)
+	go wait.Until(func() {
+		if err := s.watcher.Watch(ctx, changes); err != nil {
+			s.log.Debug(errWatchExternal, "error", err, "retry-after", time.Now().Add(s.retry))
+		}
+	}, s.retry, s.stop)
+
+	go func() {
+		for {
+			select {
+			case <-s.stop:
+				return
+			case c := <-changes:
+				s.emit(ctx, c, h, q, ps...)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// emit a generic event for each managed resource affected by the supplied
+// change.
+func (s *ExternalChangeSource) emit(ctx context.Context, c ExternalChange, h handler.EventHandler, q workqueue.RateLimitingInterface, ps ...predicate.Predicate) {
+	changed, err := s.changed(ctx, c)
+	if err != nil {
+		s.log.Debug("Cannot process external resource change", "error", err, "managed", c.Managed, "external-name", c.ExternalName)
+		return
+	}
+
+	for _, mg := range changed {
+		e := ctrlevent.GenericEvent{Meta: mg, Object: mg}
+		if !allow(e, ps...) {
+			continue
+		}
+		h.Generic(e, q)
+	}
+}
+
+func allow(e ctrlevent.GenericEvent, ps ...predicate.Predicate) bool {
+	for _, p := range ps {
+		if !p.Generic(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// changed returns the managed resources affected by the supplied change.
+func (s *ExternalChangeSource) changed(ctx context.Context, c ExternalChange) ([]resource.Managed, error) {
+	if c.Managed.Name != "" {
+		mg := s.newManaged()
+		if err := s.client.Get(ctx, c.Managed, mg); err != nil {
+			return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetChangedManaged)
+		}
+		return []resource.Managed{mg}, nil
+	}
+
+	l := s.newList()
+	if err := reference.ListByExternalName(ctx, s.client, l, c.ExternalName); err != nil {
+		return nil, err
+	}
+	return l.GetItems(), nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ source.Source    = &ExternalChangeSource{}
+	_ inject.Stoppable = &ExternalChangeSource{}
+)
+
+type managedList struct {
+	metav1.ListMeta
+	Items []resource.Managed
+}
+
+func (m *managedList) GetObjectKind() schema.ObjectKind { return schema.EmptyObjectKind }
+func (m *managedList) DeepCopyObject() runtime.Object   { return m }
+func (m *managedList) GetItems() []resource.Managed     { return m.Items }
+
+func TestExternalChangeSource(t *testing.T) {
+	kind := resource.ManagedKind(fake.GVK(&fake.Managed{}))
+	s := fake.SchemeWith(&fake.Managed{})
+	s.AddKnownTypeWithName(kind.List(), &managedList{})
+
+	c := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			if key.Name == "gone" {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			obj.(*fake.Managed).SetName(key.Name)
+			return nil
+		},
+		MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "byext"}}
+			obj.(*managedList).Items = []resource.Managed{mg}
+			return nil
+		},
+	}
+
+	w := ExternalWatcherFn(func(ctx context.Context, changes chan<- ExternalChange) error {
+		changes <- ExternalChange{Managed: types.NamespacedName{Name: "cool"}}
+		changes <- ExternalChange{Managed: types.NamespacedName{Name: "gone"}}
+		changes <- ExternalChange{ExternalName: "coolext"}
+		<-ctx.Done()
+		return nil
+	})
+
+	src := NewExternalChangeSource(c, s, kind, w)
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	if diff := cmp.Diff(errors.New(errNoStopChannel), src.Start(&handler.EnqueueRequestForObject{}, q), test.EquateErrors()); diff != "" {
+		t.Errorf("\nsrc.Start(...): -want error, +got error:\n%s", diff)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	_ = src.InjectStopChannel(stop)
+	if err := src.Start(&handler.EnqueueRequestForObject{}, q); err != nil {
+		t.Fatalf("src.Start(...): %s", err)
+	}
+
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "cool"}},
+		{NamespacedName: types.NamespacedName{Namespace: "coolns", Name: "byext"}},
+	}
+	got := make([]reconcile.Request, 0, len(want))
+	for range want {
+		item, _ := q.Get()
+		got = append(got, item.(reconcile.Request))
+		q.Done(item)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nExternalChangeSource should enqueue the managed resources whose external resources changed: -want, +got:\n%s", diff)
+	}
+}
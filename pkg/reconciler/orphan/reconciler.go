@@ -0,0 +1,306 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	reconcileTimeout    = 1 * time.Minute
+	defaultPollInterval = 5 * time.Minute
+)
+
+// Error strings.
+const (
+	errGetManaged       = "cannot get managed resource"
+	errListCRDs         = "cannot list custom resource definitions"
+	errDetect           = "cannot determine whether managed resource is orphaned"
+	errRemoveFinalizers = "cannot remove finalizers from orphaned managed resource"
+)
+
+// Reasons a managed resource may be orphaned.
+const (
+	orphanedCRDGone    = "custom resource definition no longer exists"
+	orphanedCRDDeleted = "custom resource definition is being deleted"
+)
+
+// Event reasons.
+const (
+	reasonRemovedFinalizers event.Reason = "RemovedOrphanedFinalizers"
+)
+
+// RuntimeFinalizers are the finalizers this runtime adds to managed resources.
+// They may be used as the allowlist of a Policy.
+var RuntimeFinalizers = []string{"finalizer.managedresource.crossplane.io"}
+
+// CustomResourceDefinitionListKind is the kind of list used to find the
+// CustomResourceDefinition of a managed resource.
+var CustomResourceDefinitionListKind = schema.GroupVersionKind{
+	Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinitionList",
+}
+
+// ControllerName returns the recommended name for controllers that use this
+// package to remove finalizers from a particular kind of orphaned managed
+// resource.
+func ControllerName(kind string) string {
+	return "orphanedfinalizer/" + strings.ToLower(kind)
+}
+
+// A Policy determines which finalizers may be removed from an orphaned managed
+// resource, and when.
+type Policy struct {
+	// Finalizers that may be removed. Finalizers that are not in this
+	// allowlist are never removed; an empty allowlist removes nothing.
+	Finalizers []string
+
+	// MinTerminating is how long a managed resource must have been
+	// terminating before its finalizers may be removed, giving its controller
+	// a chance to finalize it.
+	MinTerminating time.Duration
+}
+
+// StripFinalizers removes each finalizer allowed by the supplied Policy from
+// the supplied object, and returns the finalizers it removed.
+func StripFinalizers(o metav1.Object, p Policy) []string {
+	var removed []string
+	for _, f := range p.Finalizers {
+		if !meta.FinalizerExists(o, f) {
+			continue
+		}
+		meta.RemoveFinalizer(o, f)
+		removed = append(removed, f)
+	}
+	return removed
+}
+
+// A Detector determines whether a managed resource is orphaned.
+type Detector interface {
+	// Orphaned returns the reason the supplied managed resource is orphaned,
+	// or an empty string if it is not.
+	Orphaned(ctx context.Context, mg resource.Managed) (string, error)
+}
+
+// A DetectorFn determines whether a managed resource is orphaned.
+type DetectorFn func(ctx context.Context, mg resource.Managed) (string, error)
+
+// Orphaned returns the reason the supplied managed resource is orphaned, or an
+// empty string if it is not.
+func (fn DetectorFn) Orphaned(ctx context.Context, mg resource.Managed) (string, error) {
+	return fn(ctx, mg)
+}
+
+// A CRDDetector considers a managed resource to be orphaned if the
+// CustomResourceDefinition that defines its kind no longer exists, or is being
+// deleted. This is typically the case when the provider that installed the
+// CustomResourceDefinition, and that ran the managed resource's controller,
+// was uninstalled.
+type CRDDetector struct {
+	client client.Reader
+	kind   schema.GroupKind
+}
+
+// NewCRDDetector returns a CRDDetector that detects orphaned managed
+// resources of the supplied kind.
+func NewCRDDetector(c client.Reader, of schema.GroupKind) *CRDDetector {
+	return &CRDDetector{client: c, kind: of}
+}
+
+// Orphaned returns the reason the supplied managed resource is orphaned, or an
+// empty string if it is not.
+func (d *CRDDetector) Orphaned(ctx context.Context, _ resource.Managed) (string, error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(CustomResourceDefinitionListKind)
+	if err := d.client.List(ctx, l); err != nil {
+		return "", errors.Wrap(err, errListCRDs)
+	}
+	for i := range l.Items {
+		crd := &l.Items[i]
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if group != d.kind.Group || kind != d.kind.Kind {
+			continue
+		}
+		if meta.WasDeleted(crd) {
+			return orphanedCRDDeleted, nil
+		}
+		return "", nil
+	}
+	return orphanedCRDGone, nil
+}
+
+// A Reconciler reconciles managed resources that are terminating by removing
+// the finalizers allowed by its Policy if they are orphaned. It never removes
+// finalizers from managed resources that are not terminating, or that are not
+// orphaned. A Reconciler is optional, and should run outside of the provider
+// whose managed resources it reconciles, for example:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//	    Named(orphan.ControllerName(kind)).
+//	    For(&v1alpha1.Example{}).
+//	    Complete(orphan.NewReconciler(mgr, resource.ManagedKind(gvk), orphan.Policy{
+//	        Finalizers:     orphan.RuntimeFinalizers,
+//	        MinTerminating: 10 * time.Minute,
+//	    }))
+type Reconciler struct {
+	client     client.Client
+	newManaged func() resource.Managed
+	policy     Policy
+	detector   Detector
+	poll       time.Duration
+	now        func() time.Time
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithDetector specifies how the Reconciler should determine whether a managed
+// resource is orphaned. By default a managed resource is orphaned if the
+// CustomResourceDefinition that defines its kind no longer exists, or is being
+// deleted.
+func WithDetector(d Detector) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.detector = d
+	}
+}
+
+// WithPollInterval specifies how often the Reconciler should check whether a
+// terminating managed resource has become orphaned.
+func WithPollInterval(after time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.poll = after
+	}
+}
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// NewReconciler returns a Reconciler that removes the finalizers allowed by
+// the supplied Policy from orphaned managed resources of the supplied kind.
+func NewReconciler(m manager.Manager, of resource.ManagedKind, p Policy, o ...ReconcilerOption) *Reconciler {
+	nm := func() resource.Managed {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+	}
+
+	// Panic early if we've been asked to reconcile a resource kind that has not
+	// been registered with our controller manager's scheme.
+	_ = nm()
+
+	r := &Reconciler{
+		client:     m.GetClient(),
+		newManaged: nm,
+		policy:     p,
+		detector:   NewCRDDetector(m.GetClient(), schema.GroupVersionKind(of).GroupKind()),
+		poll:       defaultPollInterval,
+		now:        time.Now,
+		log:        logging.NewNopLogger(),
+		record:     event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a managed resource by removing finalizers from it if it is
+// terminating and orphaned.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if err := r.client.Get(ctx, req.NamespacedName, mg); err != nil {
+		// There's nothing to do if the managed resource no longer exists.
+		log.Debug("Cannot get managed resource", "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetManaged)
+	}
+
+	if !meta.WasDeleted(mg) {
+		// We'll be requeued when the managed resource is deleted.
+		return reconcile.Result{}, nil
+	}
+
+	log = log.WithValues("deletion-timestamp", mg.GetDeletionTimestamp())
+
+	if d := r.now().Sub(mg.GetDeletionTimestamp().Time); d < r.policy.MinTerminating {
+		// Give the managed resource's controller a chance to finalize it.
+		return reconcile.Result{RequeueAfter: r.policy.MinTerminating - d}, nil
+	}
+
+	reason, err := r.detector.Orphaned(ctx, mg)
+	if err != nil {
+		// We'll be requeued implicitly because we return an error.
+		log.Debug("Cannot determine whether managed resource is orphaned", "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errDetect)
+	}
+	if reason == "" {
+		// Nothing will notify us if the managed resource's controller is
+		// uninstalled, so we check again after an interval.
+		return reconcile.Result{RequeueAfter: r.poll}, nil
+	}
+
+	removed := StripFinalizers(mg, r.policy)
+	if len(removed) == 0 {
+		// The managed resource is blocked by finalizers we're not allowed to
+		// remove. We'll be requeued if they change.
+		log.Debug("Orphaned managed resource has no removable finalizers", "reason", reason)
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.client.Update(ctx, mg); err != nil {
+		log.Debug("Cannot remove finalizers from orphaned managed resource", "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errRemoveFinalizers)
+	}
+
+	log.Debug("Removed finalizers from orphaned managed resource", "reason", reason, "finalizers", removed)
+	r.record.Event(mg, event.Normal(reasonRemovedFinalizers, "Removed finalizers "+strings.Join(removed, ", ")+" from orphaned managed resource: "+reason))
+	return reconcile.Result{Requeue: false}, nil
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Detector = &CRDDetector{}
+var _ Detector = DetectorFn(nil)
+
+func crd(group, kind string, deleted bool) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group": group,
+			"names": map[string]interface{}{"kind": kind},
+		},
+	}}
+	if deleted {
+		now := metav1.Now()
+		u.SetDeletionTimestamp(&now)
+	}
+	return u
+}
+
+func TestStripFinalizers(t *testing.T) {
+	type args struct {
+		o metav1.Object
+		p Policy
+	}
+	type want struct {
+		removed    []string
+		finalizers []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"EmptyAllowlist": {
+			reason: "No finalizers should be removed if the allowlist is empty.",
+			args: args{
+				o: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"a", "b"}}},
+			},
+			want: want{
+				finalizers: []string{"a", "b"},
+			},
+		},
+		"SomeAllowed": {
+			reason: "Only allowlisted finalizers should be removed.",
+			args: args{
+				o: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Finalizers: []string{"a", "b", "c"}}},
+				p: Policy{Finalizers: []string{"c", "a", "d"}},
+			},
+			want: want{
+				removed:    []string{"c", "a"},
+				finalizers: []string{"b"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			removed := StripFinalizers(tc.args.o, tc.args.p)
+			if diff := cmp.Diff(tc.want.removed, removed); diff != "" {
+				t.Errorf("\n%s\nStripFinalizers(...): -want removed, +got removed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.finalizers, tc.args.o.GetFinalizers()); diff != "" {
+				t.Errorf("\n%s\nStripFinalizers(...): -want finalizers, +got finalizers:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDDetector(t *testing.T) {
+	errBoom := errors.New("boom")
+	gk := schema.GroupKind{Group: "example.org", Kind: "Cool"}
+
+	withCRDs := func(crds ...unstructured.Unstructured) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			obj.(*unstructured.UnstructuredList).Items = crds
+			return nil
+		}
+	}
+
+	type want struct {
+		reason string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing CustomResourceDefinitions should be returned.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListCRDs),
+			},
+		},
+		"CRDGone": {
+			reason: "A managed resource whose CustomResourceDefinition does not exist is orphaned.",
+			client: &test.MockClient{MockList: test.NewMockListFn(nil, withCRDs(
+				crd("example.org", "Other", false),
+				crd("example.net", "Cool", false),
+			))},
+			want: want{
+				reason: orphanedCRDGone,
+			},
+		},
+		"CRDDeleted": {
+			reason: "A managed resource whose CustomResourceDefinition is being deleted is orphaned.",
+			client: &test.MockClient{MockList: test.NewMockListFn(nil, withCRDs(crd("example.org", "Cool", true)))},
+			want: want{
+				reason: orphanedCRDDeleted,
+			},
+		},
+		"CRDExists": {
+			reason: "A managed resource whose CustomResourceDefinition exists is not orphaned.",
+			client: &test.MockClient{MockList: test.NewMockListFn(nil, withCRDs(crd("example.org", "Cool", false)))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewCRDDetector(tc.client, gk)
+			got, err := d.Orphaned(context.Background(), &fake.Managed{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nd.Orphaned(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, got); diff != "" {
+				t.Errorf("\n%s\nd.Orphaned(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+	now := time.Now()
+	deleted := metav1.NewTime(now.Add(-1 * time.Hour))
+	policy := Policy{Finalizers: RuntimeFinalizers, MinTerminating: 30 * time.Minute}
+
+	terminating := func(finalizers ...string) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			mg := obj.(*fake.Managed)
+			mg.SetDeletionTimestamp(&deleted)
+			mg.SetFinalizers(append([]string{}, finalizers...))
+			return nil
+		}
+	}
+	orphaned := DetectorFn(func(_ context.Context, _ resource.Managed) (string, error) { return "gone", nil })
+
+	type args struct {
+		m manager.Manager
+		p Policy
+		o []ReconcilerOption
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "We should return early if the managed resource no longer exists.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errNotFound)},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"GetError": {
+			reason: "Errors getting the managed resource should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetManaged)},
+		},
+		"NotTerminating": {
+			reason: "We should return early if the managed resource is not terminating.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: policy,
+				o: []ReconcilerOption{WithDetector(orphaned)},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"NotTerminatingLongEnough": {
+			reason: "We should check again once the managed resource has been terminating long enough.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil, terminating(RuntimeFinalizers...))},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: Policy{Finalizers: RuntimeFinalizers, MinTerminating: 2 * time.Hour},
+				o: []ReconcilerOption{WithDetector(orphaned)},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: 1 * time.Hour}},
+		},
+		"DetectError": {
+			reason: "Errors determining whether the managed resource is orphaned should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil, terminating(RuntimeFinalizers...))},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: policy,
+				o: []ReconcilerOption{WithDetector(DetectorFn(func(_ context.Context, _ resource.Managed) (string, error) {
+					return "", errBoom
+				}))},
+			},
+			want: want{err: errors.Wrap(errBoom, errDetect)},
+		},
+		"NotOrphaned": {
+			reason: "We should check again after an interval if the managed resource is not orphaned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil, terminating(RuntimeFinalizers...))},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: policy,
+				o: []ReconcilerOption{
+					WithPollInterval(10 * time.Minute),
+					WithDetector(DetectorFn(func(_ context.Context, _ resource.Managed) (string, error) { return "", nil })),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: 10 * time.Minute}},
+		},
+		"NoRemovableFinalizers": {
+			reason: "We should not update an orphaned managed resource with no allowlisted finalizers.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, terminating("someone-elses")),
+						MockUpdate: func(_ context.Context, _ runtime.Object, _ ...client.UpdateOption) error {
+							t.Errorf("Update(...) called unexpectedly")
+							return nil
+						},
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: policy,
+				o: []ReconcilerOption{WithDetector(orphaned)},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"UpdateError": {
+			reason: "Errors removing finalizers should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil, terminating(RuntimeFinalizers...)),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: policy,
+				o: []ReconcilerOption{WithDetector(orphaned)},
+			},
+			want: want{err: errors.Wrap(errBoom, errRemoveFinalizers)},
+		},
+		"Success": {
+			reason: "Allowlisted finalizers should be removed from orphaned managed resources.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, terminating(append([]string{"someone-elses"}, RuntimeFinalizers...)...)),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj runtime.Object) error {
+							want := []string{"someone-elses"}
+							if diff := cmp.Diff(want, obj.(*fake.Managed).GetFinalizers()); diff != "" {
+								t.Errorf("Update(...): -want finalizers, +got finalizers:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+				p: policy,
+				o: []ReconcilerOption{WithDetector(orphaned)},
+			},
+			want: want{result: reconcile.Result{Requeue: false}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.m, resource.ManagedKind(fake.GVK(&fake.Managed{})), tc.args.p, tc.args.o...)
+			r.now = func() time.Time { return now }
+			got, err := r.Reconcile(reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
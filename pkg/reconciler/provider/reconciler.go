@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	finalizer = "in-use.crossplane.io"
+
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+)
+
+// Error strings.
+const (
+	errGetProvider    = "cannot get provider"
+	errListUsages     = "cannot list provider usages"
+	errUpdateProvider = "cannot update provider"
+
+	errFmtInUse = "provider is in use by %d resources"
+)
+
+// Event reasons.
+const (
+	reasonAccount event.Reason = "UsageAccounting"
+)
+
+// ControllerName returns the recommended name for controllers that use this
+// package to reconcile a particular kind of provider.
+func ControllerName(kind string) string {
+	return "provider/" + strings.ToLower(kind)
+}
+
+// Kinds specifies the kinds of provider and provider usage list a Reconciler
+// should be concerned with.
+type Kinds struct {
+	// Provider kind.
+	Provider schema.GroupVersionKind
+
+	// UsageList kind, i.e. the list kind of the provider's usages.
+	UsageList schema.GroupVersionKind
+}
+
+// A Reconciler reconciles providers by adding a finalizer that prevents them
+// from being deleted while they are in use by managed resources, and removing
+// it once they are not.
+type Reconciler struct {
+	client client.Client
+
+	newProvider  func() resource.Provider
+	newUsageList func() resource.ProviderUsageList
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// NewReconciler returns a Reconciler that reconciles providers of the supplied
+// kind. It panics if asked to reconcile a provider or usage list kind that is
+// not registered with the supplied manager's runtime.Scheme. Providers are
+// considered in use while any usage of the supplied list kind is labelled
+// with their name.
+func NewReconciler(m manager.Manager, of Kinds, o ...ReconcilerOption) *Reconciler {
+	np := func() resource.Provider {
+		return resource.MustCreateObject(of.Provider, m.GetScheme()).(resource.Provider)
+	}
+	nul := func() resource.ProviderUsageList {
+		return resource.MustCreateObject(of.UsageList, m.GetScheme()).(resource.ProviderUsageList)
+	}
+
+	// Panic early if we've been asked to reconcile a provider or usage kind
+	// that has not been registered with our controller manager's scheme.
+	_, _ = np(), nul()
+
+	r := &Reconciler{
+		client:       m.GetClient(),
+		newProvider:  np,
+		newUsageList: nul,
+		log:          logging.NewNopLogger(),
+		record:       event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a provider.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	p := r.newProvider()
+	if err := r.client.Get(ctx, req.NamespacedName, p); err != nil {
+		// There's no need to requeue if we no longer exist. Otherwise we'll be
+		// requeued implicitly because we return an error.
+		log.Debug(errGetProvider, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProvider)
+	}
+
+	log = log.WithValues(
+		"uid", p.GetUID(),
+		"version", p.GetResourceVersion(),
+		"name", p.GetName(),
+	)
+
+	l := r.newUsageList()
+	if err := r.client.List(ctx, l, client.MatchingLabels{v1alpha1.LabelKeyProviderName: p.GetName()}); err != nil {
+		log.Debug(errListUsages, "error", err)
+		r.record.Event(p, event.Warning(reasonAccount, errors.Wrap(err, errListUsages)))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+
+	users := len(l.GetItems())
+	log = log.WithValues("usages", users)
+
+	if meta.WasDeleted(p) {
+		if users > 0 {
+			// We're being deleted but we're still in use. We'll be requeued
+			// when our usages change, but we also check again after a short
+			// wait in case we miss a change.
+			msg := fmt.Sprintf(errFmtInUse, users)
+			log.Debug("Blocking deletion while usages still exist", "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(p, event.Warning(reasonAccount, errors.New(msg)))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+
+		// We're being deleted and we're no longer in use. Remove our
+		// finalizer so that deletion may proceed.
+		meta.RemoveFinalizer(p, finalizer)
+		if err := r.client.Update(ctx, p); err != nil {
+			log.Debug(errUpdateProvider, "error", err)
+			r.record.Event(p, event.Warning(reasonAccount, errors.Wrap(err, errUpdateProvider)))
+			return reconcile.Result{RequeueAfter: shortWait}, nil
+		}
+
+		// We've been finalized; there's nothing left to do.
+		log.Debug("Removed finalizer from unused provider")
+		return reconcile.Result{Requeue: false}, nil
+	}
+
+	// We add our finalizer regardless of whether we're in use, so that a
+	// usage that is created while we're being deleted still blocks our
+	// deletion.
+	if meta.FinalizerExists(p, finalizer) {
+		return reconcile.Result{Requeue: false}, nil
+	}
+	meta.AddFinalizer(p, finalizer)
+	if err := r.client.Update(ctx, p); err != nil {
+		log.Debug(errUpdateProvider, "error", err)
+		r.record.Event(p, event.Warning(reasonAccount, errors.Wrap(err, errUpdateProvider)))
+		return reconcile.Result{RequeueAfter: shortWait}, nil
+	}
+
+	return reconcile.Result{Requeue: false}, nil
+}
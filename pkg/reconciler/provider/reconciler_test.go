@@ -0,0 +1,276 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ reconcile.Reconciler = &Reconciler{}
+
+type usageList struct {
+	metav1.ListMeta
+	Items []resource.ProviderUsage
+}
+
+func (l *usageList) GetObjectKind() schema.ObjectKind   { return schema.EmptyObjectKind }
+func (l *usageList) DeepCopyObject() runtime.Object     { return l }
+func (l *usageList) GetItems() []resource.ProviderUsage { return l.Items }
+
+func TestReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+	name := "cool-provider"
+
+	s := fake.SchemeWith(&fake.Provider{})
+	s.AddKnownTypeWithName(fake.GV.WithKind("UsageList"), &usageList{})
+	of := Kinds{Provider: fake.GVK(&fake.Provider{}), UsageList: fake.GV.WithKind("UsageList")}
+
+	inUse := func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		if want := (labels.Set{v1alpha1.LabelKeyProviderName: name}); lo.LabelSelector == nil || !lo.LabelSelector.Matches(want) {
+			t.Errorf("List(...): unexpected label selector %q", lo.LabelSelector)
+		}
+		obj.(*usageList).Items = []resource.ProviderUsage{&fake.ProviderUsage{}}
+		return nil
+	}
+
+	type args struct {
+		m manager.Manager
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ProviderNotFound": {
+			reason: "We should not return an error if the provider was not found.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{},
+			},
+		},
+		"GetProviderError": {
+			reason: "We should return any other error encountered while getting the provider.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{},
+				err:    errors.Wrap(errBoom, errGetProvider),
+			},
+		},
+		"ListUsagesError": {
+			reason: "We should requeue after a short wait if we encounter an error listing usages.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:  test.NewMockGetFn(nil),
+						MockList: test.NewMockListFn(errBoom),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"DeletedButInUse": {
+			reason: "We should requeue after a short wait without removing our finalizer if we're deleted but still in use.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							p := obj.(*fake.Provider)
+							p.SetName(name)
+							p.SetDeletionTimestamp(&now)
+							p.SetFinalizers([]string{finalizer})
+							return nil
+						}),
+						MockList: inUse,
+						MockUpdate: test.NewMockUpdateFn(nil, func(_ runtime.Object) error {
+							t.Errorf("Update(...): called unexpectedly")
+							return nil
+						}),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"RemoveFinalizerError": {
+			reason: "We should requeue after a short wait if we encounter an error removing our finalizer.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							p := obj.(*fake.Provider)
+							p.SetDeletionTimestamp(&now)
+							p.SetFinalizers([]string{finalizer})
+							return nil
+						}),
+						MockList:   test.NewMockListFn(nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"RemoveFinalizerSuccess": {
+			reason: "We should remove our finalizer if we're deleted and no longer in use.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							p := obj.(*fake.Provider)
+							p.SetDeletionTimestamp(&now)
+							p.SetFinalizers([]string{finalizer})
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj runtime.Object) error {
+							if diff := cmp.Diff([]string{}, obj.(*fake.Provider).GetFinalizers()); diff != "" {
+								t.Errorf("Update(...): -want finalizers, +got finalizers:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: false},
+			},
+		},
+		"AddFinalizerError": {
+			reason: "We should requeue after a short wait if we encounter an error adding our finalizer.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    test.NewMockGetFn(nil),
+						MockList:   test.NewMockListFn(nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: shortWait},
+			},
+		},
+		"AddFinalizerSuccess": {
+			reason: "We should add our finalizer if we're not being deleted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(*fake.Provider).SetName(name)
+							return nil
+						}),
+						MockList: inUse,
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj runtime.Object) error {
+							if diff := cmp.Diff([]string{finalizer}, obj.(*fake.Provider).GetFinalizers()); diff != "" {
+								t.Errorf("Update(...): -want finalizers, +got finalizers:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: false},
+			},
+		},
+		"FinalizerExists": {
+			reason: "We should not update the provider if our finalizer already exists.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+							obj.(*fake.Provider).SetFinalizers([]string{finalizer})
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(_ runtime.Object) error {
+							t.Errorf("Update(...): called unexpectedly")
+							return nil
+						}),
+					},
+					Scheme: s,
+				},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.m, of)
+			got, err := r.Reconcile(reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	gcReconcileTimeout = 1 * time.Minute
+	defaultGCInterval  = 10 * time.Minute
+)
+
+// Error messages.
+const (
+	errGetController  = "cannot get controller of connection secret"
+	errGetPropagating = "cannot get propagating connection secret"
+	errDeleteSecret   = "cannot delete orphaned connection secret"
+)
+
+// Reasons a connection secret may be orphaned.
+const (
+	orphanedControllerGone  = "controlling managed resource no longer exists"
+	orphanedPropagatingGone = "propagating connection secret no longer exists"
+)
+
+// Event reasons
+const (
+	reasonDeletedOrphan event.Reason = "DeletedOrphanedSecret"
+)
+
+// GCControllerName returns the recommended name for controllers that use this
+// package to garbage collect the connection secrets of a particular kind of
+// managed resource.
+func GCControllerName(kind string) string {
+	return "secretgc/" + strings.ToLower(kind)
+}
+
+// A GarbageCollector reconciles connection secrets by deleting them if they
+// are orphaned, i.e. if the managed resource that controls them, or the
+// secret they are propagated from, no longer exists. Kubernetes garbage
+// collection usually deletes such secrets, but may not after unusual deletion
+// orderings, for example when a managed resource is deleted with an orphan
+// propagation policy. A GarbageCollector is optional, and should watch only
+// connection secrets:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//	    Named(secret.GCControllerName(kind)).
+//	    For(&corev1.Secret{}).
+//	    WithEventFilter(resource.NewPredicates(resource.AnyOf(
+//	        resource.IsControlledByKind(gvk),
+//	        resource.IsPropagated(),
+//	    ))).
+//	    Complete(secret.NewGarbageCollector(mgr, resource.ManagedKind(gvk)))
+type GarbageCollector struct {
+	client     client.Client
+	managed    schema.GroupKind
+	newManaged func() resource.Managed
+	interval   time.Duration
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// A GarbageCollectorOption configures a GarbageCollector.
+type GarbageCollectorOption func(*GarbageCollector)
+
+// WithGCInterval specifies how often the GarbageCollector should check
+// whether a connection secret is orphaned. Secrets are also checked whenever
+// they change.
+func WithGCInterval(d time.Duration) GarbageCollectorOption {
+	return func(r *GarbageCollector) {
+		r.interval = d
+	}
+}
+
+// WithGCLogger specifies how the GarbageCollector should log messages.
+func WithGCLogger(l logging.Logger) GarbageCollectorOption {
+	return func(r *GarbageCollector) {
+		r.log = l
+	}
+}
+
+// WithGCRecorder specifies how the GarbageCollector should record events.
+func WithGCRecorder(er event.Recorder) GarbageCollectorOption {
+	return func(r *GarbageCollector) {
+		r.record = er
+	}
+}
+
+// NewGarbageCollector returns a GarbageCollector that deletes connection
+// secrets that are orphaned by the supplied kind of managed resource.
+func NewGarbageCollector(m manager.Manager, of resource.ManagedKind, o ...GarbageCollectorOption) *GarbageCollector {
+	r := &GarbageCollector{
+		client:  m.GetClient(),
+		managed: schema.GroupVersionKind(of).GroupKind(),
+		newManaged: func() resource.Managed {
+			return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+		},
+		interval: defaultGCInterval,
+		log:      logging.NewNopLogger(),
+		record:   event.NewNopRecorder(),
+	}
+
+	for _, ro := range o {
+		ro(r)
+	}
+
+	return r
+}
+
+// Reconcile a connection secret by deleting it if it is orphaned.
+func (r *GarbageCollector) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), gcReconcileTimeout)
+	defer cancel()
+
+	s := &corev1.Secret{}
+	if err := r.client.Get(ctx, req.NamespacedName, s); err != nil {
+		// There's nothing to collect if the secret no longer exists.
+		log.Debug("Cannot get connection secret", "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetSecret)
+	}
+
+	if meta.WasDeleted(s) {
+		return reconcile.Result{}, nil
+	}
+
+	reason, err := r.orphaned(ctx, s)
+	if err != nil {
+		// We'll be requeued implicitly because we return an error.
+		log.Debug("Cannot determine whether connection secret is orphaned", "error", err)
+		return reconcile.Result{}, err
+	}
+	if reason == "" {
+		// Nothing will notify us when the controller of our secret or the
+		// secret it is propagated from is deleted, so we check again after
+		// an interval.
+		return reconcile.Result{RequeueAfter: r.interval}, nil
+	}
+
+	if err := r.client.Delete(ctx, s); resource.IgnoreNotFound(err) != nil {
+		log.Debug("Cannot delete orphaned connection secret", "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errDeleteSecret)
+	}
+
+	log.Debug("Deleted orphaned connection secret", "reason", reason)
+	r.record.Event(s, event.Normal(reasonDeletedOrphan, "Deleted orphaned connection secret: "+reason))
+	return reconcile.Result{Requeue: false}, nil
+}
+
+// orphaned returns the reason the supplied secret is orphaned, or an empty
+// string if it is not.
+func (r *GarbageCollector) orphaned(ctx context.Context, s *corev1.Secret) (string, error) {
+	if c := metav1.GetControllerOf(s); c != nil {
+		gv, err := schema.ParseGroupVersion(c.APIVersion)
+		if err == nil && gv.WithKind(c.Kind).GroupKind() == r.managed {
+			mg := r.newManaged()
+			err := r.client.Get(ctx, types.NamespacedName{Name: c.Name}, mg)
+			if resource.IgnoreNotFound(err) != nil {
+				return "", errors.Wrap(err, errGetController)
+			}
+			// A managed resource with a different UID is not the one that
+			// controlled our secret; it was deleted and recreated.
+			if err != nil || mg.GetUID() != c.UID {
+				return orphanedControllerGone, nil
+			}
+		}
+	}
+
+	if nn := meta.AllowsPropagationFrom(s); nn.Namespace != "" && nn.Name != "" {
+		err := r.client.Get(ctx, nn, &corev1.Secret{})
+		if resource.IgnoreNotFound(err) != nil {
+			return "", errors.Wrap(err, errGetPropagating)
+		}
+		if err != nil {
+			return orphanedPropagatingGone, nil
+		}
+	}
+
+	return "", nil
+}
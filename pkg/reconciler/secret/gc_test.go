@@ -0,0 +1,242 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestGarbageCollector(t *testing.T) {
+	type args struct {
+		m manager.Manager
+	}
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "coolmanaged", UID: types.UID("cool-uid")}}
+	controlled := func(obj runtime.Object) error {
+		s := obj.(*corev1.Secret)
+		s.SetNamespace("coolns")
+		s.SetName("coolsecret")
+		s.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(meta.ReferenceTo(mg, fake.GVK(mg)))})
+		return nil
+	}
+	propagated := func(obj runtime.Object) error {
+		s := obj.(*corev1.Secret)
+		s.SetNamespace("coolns")
+		s.SetName("coolsecret")
+		meta.AllowPropagation(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "otherns", Name: "from"}}, s)
+		return nil
+	}
+	mustNotDelete := func(_ context.Context, _ runtime.Object, _ ...client.DeleteOption) error {
+		t.Errorf("Delete(...) called unexpectedly")
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SecretNotFound": {
+			reason: "We should return early if the secret no longer exists.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errNotFound)},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{result: reconcile.Result{}},
+		},
+		"GetSecretError": {
+			reason: "Errors getting the secret should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetSecret)},
+		},
+		"ControllerExists": {
+			reason: "A secret whose controlling managed resource exists should be checked again after an interval.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+							if m, ok := obj.(*fake.Managed); ok {
+								m.SetUID(mg.GetUID())
+								return nil
+							}
+							return controlled(obj)
+						},
+						MockDelete: mustNotDelete,
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultGCInterval}},
+		},
+		"GetControllerError": {
+			reason: "Errors getting the controlling managed resource should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+							if _, ok := obj.(*fake.Managed); ok {
+								return errBoom
+							}
+							return controlled(obj)
+						},
+						MockDelete: mustNotDelete,
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetController)},
+		},
+		"ControllerGone": {
+			reason: "A secret whose controlling managed resource no longer exists should be deleted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+							if _, ok := obj.(*fake.Managed); ok {
+								return errNotFound
+							}
+							return controlled(obj)
+						},
+						MockDelete: test.NewMockDeleteFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: false}},
+		},
+		"ControllerRecreated": {
+			reason: "A secret whose controlling managed resource was deleted and recreated should be deleted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+							if m, ok := obj.(*fake.Managed); ok {
+								m.SetUID(types.UID("new-uid"))
+								return nil
+							}
+							return controlled(obj)
+						},
+						MockDelete: test.NewMockDeleteFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: false}},
+		},
+		"PropagatingGone": {
+			reason: "A secret whose propagating secret no longer exists should be deleted.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, n types.NamespacedName, obj runtime.Object) error {
+							if n.Name == "from" {
+								return errNotFound
+							}
+							return propagated(obj)
+						},
+						MockDelete: test.NewMockDeleteFn(nil),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{result: reconcile.Result{Requeue: false}},
+		},
+		"GetPropagatingError": {
+			reason: "Errors getting the propagating secret should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, n types.NamespacedName, obj runtime.Object) error {
+							if n.Name == "from" {
+								return errBoom
+							}
+							return propagated(obj)
+						},
+						MockDelete: mustNotDelete,
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetPropagating)},
+		},
+		"DeleteError": {
+			reason: "Errors deleting an orphaned secret should be returned.",
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, n types.NamespacedName, obj runtime.Object) error {
+							if n.Name == "from" {
+								return errNotFound
+							}
+							return propagated(obj)
+						},
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					},
+					Scheme: fake.SchemeWith(&fake.Managed{}),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errDeleteSecret)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewGarbageCollector(tc.args.m, resource.ManagedKind(fake.GVK(&fake.Managed{})))
+			got, err := r.Reconcile(reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -21,12 +21,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -40,6 +40,7 @@ const (
 	errGetSecret             = "cannot get connection secret"
 	errUpdateSecret          = "cannot update connection secret"
 	errPropagationNotAllowed = "the propagating connection secret does not allow propagation to the propagated connection secret"
+	errDecryptSecret         = "cannot decrypt propagating connection secret"
 )
 
 // Event reasons
@@ -59,12 +60,31 @@ func ControllerName(kind string) string {
 // annotations. The Reconciler assumes it has a watch on both propagating (from)
 // and propagated (to) secrets.
 type Reconciler struct {
-	client client.Client
+	client    client.Client
+	decrypter SecretDecrypter
 
 	log    logging.Logger
 	record event.Recorder
 }
 
+// A SecretDecrypter decrypts the data of a connection secret, for example
+// using an envelope.Encrypter.
+type SecretDecrypter interface {
+	// DecryptSecret replaces the data of the supplied secret with its
+	// decrypted data. Secrets that are not encrypted are returned unchanged.
+	DecryptSecret(ctx context.Context, s *corev1.Secret) error
+}
+
+// A SecretDecrypterFn is a function that satisfies the SecretDecrypter
+// interface.
+type SecretDecrypterFn func(ctx context.Context, s *corev1.Secret) error
+
+// DecryptSecret replaces the data of the supplied secret with its decrypted
+// data.
+func (fn SecretDecrypterFn) DecryptSecret(ctx context.Context, s *corev1.Secret) error {
+	return fn(ctx, s)
+}
+
 // A ReconcilerOption configures a Reconciler.
 type ReconcilerOption func(*Reconciler)
 
@@ -82,6 +102,16 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithSecretDecrypter specifies how the Reconciler should decrypt propagating
+// secrets that were encrypted when they were published, such that propagated
+// secrets contain their decrypted data. Propagating secrets are not decrypted
+// by default.
+func WithSecretDecrypter(d SecretDecrypter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.decrypter = d
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles secrets by propagating
 // their data from another secret. Both secrets must consent to this process by
 // including propagation annotations. The Reconciler assumes it has a watch on
@@ -149,6 +179,15 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 
 	}
 
+	if r.decrypter != nil {
+		if err := r.decrypter.DecryptSecret(ctx, from); err != nil {
+			// We don't update the propagated secret if we can't decrypt its
+			// data; we'd rather it have stale data than ciphertext.
+			log.Debug("Cannot decrypt propagating secret", "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errDecryptSecret)
+		}
+	}
+
 	to.Data = from.Data
 
 	// If our update was unsuccessful. Keep trying to update
@@ -21,7 +21,6 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -41,6 +41,7 @@ import (
 func TestReconciler(t *testing.T) {
 	type args struct {
 		m manager.Manager
+		o []ReconcilerOption
 	}
 
 	type want struct {
@@ -243,11 +244,85 @@ func TestReconciler(t *testing.T) {
 				result: reconcile.Result{},
 			},
 		},
+		"DecryptSecretError": {
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, n types.NamespacedName, o runtime.Object) error {
+							switch n.Name {
+							case to.GetName():
+								s := to.DeepCopy()
+								meta.AllowPropagation(from.DeepCopy(), s)
+								*o.(*corev1.Secret) = *s
+							case from.GetName():
+								s := from.DeepCopy()
+								s.Data = fromData
+								meta.AllowPropagation(s, to.DeepCopy())
+								*o.(*corev1.Secret) = *s
+							default:
+								return errors.New("unexpected secret name")
+							}
+							return nil
+						},
+						MockUpdate: test.NewMockUpdateFn(nil, func(got runtime.Object) error {
+							t.Errorf("Update should not be called when the propagating secret could not be decrypted")
+							return nil
+						}),
+					},
+				},
+				o: []ReconcilerOption{WithSecretDecrypter(SecretDecrypterFn(func(_ context.Context, _ *corev1.Secret) error {
+					return errBoom
+				}))},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDecryptSecret),
+			},
+		},
+		"SuccessfulDecrypted": {
+			args: args{
+				m: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, n types.NamespacedName, o runtime.Object) error {
+							switch n.Name {
+							case to.GetName():
+								s := to.DeepCopy()
+								meta.AllowPropagation(from.DeepCopy(), s)
+								*o.(*corev1.Secret) = *s
+							case from.GetName():
+								s := from.DeepCopy()
+								s.Data = map[string][]byte{"cool": []byte("encrypted")}
+								meta.AllowPropagation(s, to.DeepCopy())
+								*o.(*corev1.Secret) = *s
+							default:
+								return errors.New("unexpected secret name")
+							}
+							return nil
+						},
+						MockUpdate: test.NewMockUpdateFn(nil, func(got runtime.Object) error {
+							want := to.DeepCopy()
+							want.Data = fromData
+							meta.AllowPropagation(from.DeepCopy(), want)
+							if diff := cmp.Diff(want, got); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				o: []ReconcilerOption{WithSecretDecrypter(SecretDecrypterFn(func(_ context.Context, s *corev1.Secret) error {
+					s.Data = fromData
+					return nil
+				}))},
+			},
+			want: want{
+				result: reconcile.Result{},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewReconciler(tc.args.m)
+			r := NewReconciler(tc.args.m, tc.args.o...)
 			got, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: to.GetNamespace(), Name: to.GetName()}})
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
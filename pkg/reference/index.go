@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	xpmeta "github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Field indexes.
+const (
+	// IndexLabel indexes objects by each of their labels, in key=value form.
+	IndexLabel = "crossplane.io/label"
+
+	// IndexExternalName indexes objects by their external name.
+	IndexExternalName = "crossplane.io/external-name"
+)
+
+// Error strings.
+const (
+	errIndexLabel        = "cannot index managed resources by label"
+	errIndexExternalName = "cannot index managed resources by external name"
+	errListExternalName  = "cannot list managed resources by external name"
+
+	errFmtAmbiguousExternalName = "%d managed resources have external name %q"
+)
+
+// LabelIndexValue returns the value under which an object with the supplied
+// label is indexed by IndexLabel.
+func LabelIndexValue(key, value string) string {
+	return key + "=" + value
+}
+
+// IndexByLabel is a client.IndexerFunc that indexes the supplied object by
+// each of its labels.
+func IndexByLabel(o runtime.Object) []string {
+	a, err := meta.Accessor(o)
+	if err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(a.GetLabels()))
+	for k, v := range a.GetLabels() {
+		keys = append(keys, LabelIndexValue(k, v))
+	}
+	return keys
+}
+
+// IndexByExternalName is a client.IndexerFunc that indexes the supplied object
+// by its external name, if any.
+func IndexByExternalName(o runtime.Object) []string {
+	a, err := meta.Accessor(o)
+	if err != nil {
+		return nil
+	}
+	en := xpmeta.GetExternalName(a)
+	if en == "" {
+		return nil
+	}
+	return []string{en}
+}
+
+// SetupIndexes adds the indexes used by an APIResolver configured
+// WithIndexes to the supplied FieldIndexer, for each supplied kind of managed
+// resource. It should be called once per kind, typically with the field
+// indexer of a controller manager, before the manager is started.
+func SetupIndexes(fi client.FieldIndexer, kinds ...resource.Managed) error {
+	for _, mg := range kinds {
+		if err := fi.IndexField(mg, IndexLabel, IndexByLabel); err != nil {
+			return errors.Wrap(err, errIndexLabel)
+		}
+		if err := fi.IndexField(mg, IndexExternalName, IndexByExternalName); err != nil {
+			return errors.Wrap(err, errIndexExternalName)
+		}
+	}
+	return nil
+}
+
+// SetupExternalNameIndex adds IndexExternalName to the supplied FieldIndexer
+// for each supplied kind of managed resource, allowing managed resources to be
+// found by external name using ListByExternalName and GetByExternalName. It
+// is not necessary to call SetupExternalNameIndex for kinds that have been
+// passed to SetupIndexes.
+func SetupExternalNameIndex(fi client.FieldIndexer, kinds ...resource.Managed) error {
+	for _, mg := range kinds {
+		if err := fi.IndexField(mg, IndexExternalName, IndexByExternalName); err != nil {
+			return errors.Wrap(err, errIndexExternalName)
+		}
+	}
+	return nil
+}
+
+// ListByExternalName lists the managed resources with the supplied external
+// name into the supplied list. The supplied reader must be backed by a cache
+// that has IndexExternalName; the API server does not support it.
+func ListByExternalName(ctx context.Context, c client.Reader, l resource.ManagedList, name string, opts ...client.ListOption) error {
+	opts = append(opts, client.MatchingFields{IndexExternalName: name})
+	return errors.Wrap(c.List(ctx, l, opts...), errListExternalName)
+}
+
+// GetByExternalName returns the managed resource with the supplied external
+// name - i.e. the managed resource that owns the named external resource. It
+// returns nil if no managed resource has the external name, and an error if
+// more than one does. The supplied list is used to hold the managed resources
+// that are found. The supplied reader must be backed by a cache that has
+// IndexExternalName.
+func GetByExternalName(ctx context.Context, c client.Reader, l resource.ManagedList, name string, opts ...client.ListOption) (resource.Managed, error) {
+	if err := ListByExternalName(ctx, c, l, name, opts...); err != nil {
+		return nil, err
+	}
+	items := l.GetItems()
+	switch len(items) {
+	case 0:
+		return nil, nil
+	case 1:
+		return items[0], nil
+	default:
+		return nil, errors.Errorf(errFmtAmbiguousExternalName, len(items), name)
+	}
+}
+
+// indexedSelection returns list options that select objects with the supplied
+// labels using IndexLabel. The cache supports only one field selector, so the
+// index is used to find objects that match one label and the remainder are
+// matched by filtering those objects.
+func indexedSelection(labels map[string]string) []client.ListOption {
+	if len(labels) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return []client.ListOption{client.MatchingFields{IndexLabel: LabelIndexValue(keys[0], labels[keys[0]])}}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type mockFieldIndexer struct {
+	MockIndexField func(obj runtime.Object, field string, extractValue client.IndexerFunc) error
+}
+
+func (m *mockFieldIndexer) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	return m.MockIndexField(obj, field, extractValue)
+}
+
+func TestIndexByLabel(t *testing.T) {
+	cases := map[string]struct {
+		o    runtime.Object
+		want []string
+	}{
+		"NoLabels": {
+			o:    &fake.Managed{},
+			want: []string{},
+		},
+		"Labels": {
+			o:    &fake.Managed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"a": "1", "b": "2"}}},
+			want: []string{"a=1", "b=2"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexByLabel(tc.o)
+			if diff := cmp.Diff(tc.want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("IndexByLabel(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexByExternalName(t *testing.T) {
+	named := &fake.Managed{}
+	meta.SetExternalName(named, "cool")
+
+	cases := map[string]struct {
+		o    runtime.Object
+		want []string
+	}{
+		"NoExternalName": {
+			o:    &fake.Managed{},
+			want: nil,
+		},
+		"ExternalName": {
+			o:    named,
+			want: []string{"cool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexByExternalName(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IndexByExternalName(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetupIndexes(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		fi   client.FieldIndexer
+		want error
+	}{
+		"IndexLabelError": {
+			fi: &mockFieldIndexer{MockIndexField: func(_ runtime.Object, field string, _ client.IndexerFunc) error {
+				if field == IndexLabel {
+					return errBoom
+				}
+				return nil
+			}},
+			want: errors.Wrap(errBoom, errIndexLabel),
+		},
+		"IndexExternalNameError": {
+			fi: &mockFieldIndexer{MockIndexField: func(_ runtime.Object, field string, _ client.IndexerFunc) error {
+				if field == IndexExternalName {
+					return errBoom
+				}
+				return nil
+			}},
+			want: errors.Wrap(errBoom, errIndexExternalName),
+		},
+		"Success": {
+			fi: &mockFieldIndexer{MockIndexField: func(_ runtime.Object, _ string, _ client.IndexerFunc) error {
+				return nil
+			}},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SetupIndexes(tc.fi, &fake.Managed{})
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("SetupIndexes(...): -want error, +got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndexedSelection(t *testing.T) {
+	value := "coolv"
+	c := &test.MockClient{
+		MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.FieldSelector == nil {
+				return errors.New("expected a field selector")
+			}
+			if v, ok := lo.FieldSelector.RequiresExactMatch(IndexLabel); !ok || v != "a=1" {
+				return errors.Errorf("expected field selector %s=a=1, got %s", IndexLabel, lo.FieldSelector)
+			}
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+			meta.SetExternalName(mg, value)
+			obj.(*managedList).Items = []resource.Managed{mg}
+			return nil
+		},
+	}
+
+	r := NewAPIResolver(c, &fake.Managed{}, WithIndexes())
+	got, err := r.Resolve(context.Background(), ResolutionRequest{
+		Selector: &v1alpha1.Selector{MatchLabels: map[string]string{"b": "2", "a": "1"}},
+		To:       To{List: &managedList{}},
+		Extract:  ExternalName(),
+	})
+	if err != nil {
+		t.Fatalf("r.Resolve(...): %s", err)
+	}
+	want := ResolutionResponse{ResolvedValue: value, ResolvedReference: &v1alpha1.Reference{Name: "cool"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("r.Resolve(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGetByExternalName(t *testing.T) {
+	errBoom := errors.New("boom")
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+	meta.SetExternalName(mg, "coolext")
+
+	withItems := func(items ...resource.Managed) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if v, ok := lo.FieldSelector.RequiresExactMatch(IndexExternalName); !ok || v != "coolext" {
+				return errors.Errorf("expected field selector %s=coolext, got %s", IndexExternalName, lo.FieldSelector)
+			}
+			obj.(*managedList).Items = items
+			return nil
+		}
+	}
+
+	type want struct {
+		mg  resource.Managed
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing managed resources should be returned",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListExternalName),
+			},
+		},
+		"NotFound": {
+			reason: "No managed resource should be returned if none has the external name",
+			c:      &test.MockClient{MockList: withItems()},
+			want:   want{},
+		},
+		"Found": {
+			reason: "The managed resource with the external name should be returned",
+			c:      &test.MockClient{MockList: withItems(mg)},
+			want: want{
+				mg: mg,
+			},
+		},
+		"Ambiguous": {
+			reason: "An error should be returned if more than one managed resource has the external name",
+			c:      &test.MockClient{MockList: withItems(mg, mg)},
+			want: want{
+				err: errors.Errorf(errFmtAmbiguousExternalName, 2, "coolext"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := GetByExternalName(context.Background(), tc.c, &managedList{}, "coolext")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetByExternalName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, got); diff != "" {
+				t.Errorf("\n%s\nGetByExternalName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reference contains utilities for working with cross-resource
+// references.
+package reference
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errGetManaged  = "cannot get referenced managed resource"
+	errListManaged = "cannot list resources that match selector"
+	errNotFound    = "referenced managed resource does not exist"
+	errNoMatches   = "no resources matched selector"
+	errNoValue     = "referenced field was empty (referenced resource may not yet be ready)"
+
+	errFmtNamespaceNotAllowed = "references to resources in namespace %q are not allowed"
+)
+
+// FromPtrValue adapts a string pointer field for use as a CurrentValue.
+func FromPtrValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// ToPtrValue adapts a ResolvedValue for use as a string pointer field.
+func ToPtrValue(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// An UnresolvedError indicates that a reference could not be resolved because
+// the referenced managed resource does not exist, or does not yet have the
+// value the reference extracts from it.
+type UnresolvedError struct {
+	// Name of the referenced managed resource. Name is empty if no managed
+	// resource could be selected.
+	Name string
+
+	// Reason the reference could not be resolved.
+	Reason string
+}
+
+func (e *UnresolvedError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("cannot select reference: %s", e.Reason)
+	}
+	return fmt.Sprintf("cannot resolve reference to %q: %s", e.Name, e.Reason)
+}
+
+// IsUnresolved returns true if the supplied error indicates that a reference
+// could not be resolved.
+func IsUnresolved(err error) bool {
+	_, ok := errors.Cause(err).(*UnresolvedError)
+	return ok
+}
+
+// To indicates the kind of managed resource a reference is to. List is used
+// to select a managed resource when a reference is not supplied.
+type To struct {
+	Managed resource.Managed
+	List    resource.ManagedList
+}
+
+// An ExtractValueFn specifies how to extract a value from the resolved managed
+// resource.
+type ExtractValueFn func(resource.Managed) string
+
+// ExternalName extracts the resolved managed resource's external name from its
+// external name annotation.
+func ExternalName() ExtractValueFn {
+	return func(mg resource.Managed) string {
+		return meta.GetExternalName(mg)
+	}
+}
+
+// A ResolutionRequest requests that a reference to a particular kind of
+// managed resource be resolved.
+type ResolutionRequest struct {
+	CurrentValue string
+	Reference    *v1alpha1.Reference
+	Selector     *v1alpha1.Selector
+	To           To
+	Extract      ExtractValueFn
+}
+
+// namespace returns the namespace requested by the request's reference, if
+// any, or else that requested by its selector.
+func (rr ResolutionRequest) namespace() string {
+	if rr.Reference != nil && !rr.reselect() {
+		return rr.Reference.Namespace
+	}
+	if rr.Selector != nil {
+		return rr.Selector.Namespace
+	}
+	return ""
+}
+
+// policy returns the policy of the request's reference, if it has one, or
+// else that of its selector.
+func (rr ResolutionRequest) policy() *v1alpha1.Policy {
+	if rr.Reference != nil && rr.Reference.Policy != nil {
+		return rr.Reference.Policy
+	}
+	if rr.Selector != nil {
+		return rr.Selector.Policy
+	}
+	return nil
+}
+
+// reselect returns true if the request's selector should select a reference
+// even though one was already selected, i.e. if the selector's resolve policy
+// is Always.
+func (rr ResolutionRequest) reselect() bool {
+	return rr.Selector != nil && rr.Selector.Policy.IsResolvePolicyAlways()
+}
+
+// IsNoOp returns true if the supplied ResolutionRequest cannot or should not be
+// processed.
+func (rr ResolutionRequest) IsNoOp() bool {
+	// We don't resolve values that are already set unless asked to always
+	// resolve; we effectively cache resolved values. The CR author can
+	// invalidate the cache and trigger a new resolution by explicitly clearing
+	// the resolved value.
+	if rr.CurrentValue != "" && !rr.policy().IsResolvePolicyAlways() && !rr.reselect() {
+		return true
+	}
+
+	// We can't resolve anything if neither a reference nor a selector were
+	// provided.
+	return rr.Reference == nil && rr.Selector == nil
+}
+
+// ControllersMustMatch returns true if the supplied Selector requires that a
+// reference be to a managed resource whose controller reference matches the
+// referencing resource.
+func ControllersMustMatch(s *v1alpha1.Selector) bool {
+	if s == nil {
+		return false
+	}
+	return s.MatchControllerRef != nil && *s.MatchControllerRef
+}
+
+// A ResolutionResponse returns the result of a reference resolution. The
+// returned values are always safe to set if resolution was successful. The
+// ResolvedReference should be written back to the referencing resource so that
+// a reference that was selected continues to resolve to the same referent.
+type ResolutionResponse struct {
+	ResolvedValue     string
+	ResolvedReference *v1alpha1.Reference
+}
+
+// An APIResolver resolves references to managed resources in the Kubernetes
+// API server.
+type APIResolver struct {
+	client  client.Reader
+	from    resource.Managed
+	allowed map[string]bool
+	indexed bool
+}
+
+// An APIResolverOption configures an APIResolver.
+type APIResolverOption func(*APIResolver)
+
+// WithAllowedNamespaces allows references and selectors to target managed
+// resources in the supplied namespaces, in addition to the namespace of the
+// referencing managed resource.
+func WithAllowedNamespaces(namespaces ...string) APIResolverOption {
+	return func(r *APIResolver) {
+		for _, ns := range namespaces {
+			r.allowed[ns] = true
+		}
+	}
+}
+
+// WithIndexes causes selectors to be resolved using the field indexes added by
+// SetupIndexes, rather than by listing and filtering every managed resource of
+// the referenced kind. The APIResolver's client must be backed by a cache with
+// the appropriate indexes; the API server does not support them.
+func WithIndexes() APIResolverOption {
+	return func(r *APIResolver) {
+		r.indexed = true
+	}
+}
+
+// NewAPIResolver returns a Resolver that resolves references from the supplied
+// managed resource to other managed resources in the Kubernetes API server.
+// References may only target managed resources in the namespace of the
+// referencing managed resource unless other namespaces are explicitly allowed.
+func NewAPIResolver(c client.Reader, from resource.Managed, o ...APIResolverOption) *APIResolver {
+	r := &APIResolver{client: c, from: from, allowed: map[string]bool{}}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// namespace returns the namespace targeted by the supplied request, or an
+// error if the request targets a namespace that is not allowed.
+func (r *APIResolver) namespace(req ResolutionRequest) (string, error) {
+	ns := req.namespace()
+	if ns == "" || ns == r.from.GetNamespace() {
+		return r.from.GetNamespace(), nil
+	}
+	if !r.allowed[ns] {
+		return "", errors.Errorf(errFmtNamespaceNotAllowed, ns)
+	}
+	return ns, nil
+}
+
+// Resolve the supplied ResolutionRequest. A reference is selected if the
+// request includes a selector but no reference. The returned
+// ResolutionResponse always contains valid values unless an error was
+// returned. An UnresolvedError is returned if the referenced managed resource
+// does not exist, or if no value could be extracted from it, unless the
+// reference is optional. The current value is returned when an optional
+// reference cannot be resolved.
+func (r *APIResolver) Resolve(ctx context.Context, req ResolutionRequest) (ResolutionResponse, error) {
+	// Return early if from is being deleted, or the request is a no-op.
+	if meta.WasDeleted(r.from) || req.IsNoOp() {
+		return ResolutionResponse{ResolvedValue: req.CurrentValue, ResolvedReference: req.Reference}, nil
+	}
+
+	rsp, err := r.resolve(ctx, req)
+	if IsUnresolved(err) && req.policy().IsResolutionPolicyOptional() {
+		return ResolutionResponse{ResolvedValue: req.CurrentValue, ResolvedReference: req.Reference}, nil
+	}
+	return rsp, err
+}
+
+func (r *APIResolver) resolve(ctx context.Context, req ResolutionRequest) (ResolutionResponse, error) {
+	ns, err := r.namespace(req)
+	if err != nil {
+		return ResolutionResponse{}, err
+	}
+
+	// The reference was not set, but a selector was, or the selector must be
+	// used to select a reference every time. Select a reference.
+	if req.Reference == nil || req.reselect() {
+		return r.selectReference(ctx, ns, req)
+	}
+
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: req.Reference.Name}, req.To.Managed); err != nil {
+		if resource.IgnoreNotFound(err) == nil {
+			return ResolutionResponse{}, &UnresolvedError{Name: req.Reference.Name, Reason: errNotFound}
+		}
+		return ResolutionResponse{}, errors.Wrap(err, errGetManaged)
+	}
+
+	v := req.Extract(req.To.Managed)
+	if v == "" {
+		return ResolutionResponse{}, &UnresolvedError{Name: req.Reference.Name, Reason: errNoValue}
+	}
+
+	return ResolutionResponse{ResolvedValue: v, ResolvedReference: req.Reference}, nil
+}
+
+func (r *APIResolver) selectReference(ctx context.Context, ns string, req ResolutionRequest) (ResolutionResponse, error) {
+	opts := []client.ListOption{client.InNamespace(ns), client.MatchingLabels(req.Selector.MatchLabels)}
+	if r.indexed {
+		opts = append(opts, indexedSelection(req.Selector.MatchLabels)...)
+	}
+
+	var rsp *ResolutionResponse
+	err := resource.ListEach(ctx, r.client, req.To.List, func(o runtime.Object) error {
+		to, ok := o.(resource.Managed)
+		if !ok {
+			return nil
+		}
+		if ControllersMustMatch(req.Selector) && !meta.HaveSameController(r.from, to) {
+			return nil
+		}
+
+		// We only select referents that are ready to be referenced.
+		v := req.Extract(to)
+		if v == "" {
+			return nil
+		}
+
+		// We record the namespace of the selected resource, if any, so that
+		// it's clear where a reference resolved to. The selector's policy is
+		// recorded too, so that it continues to apply to the reference.
+		rsp = &ResolutionResponse{
+			ResolvedValue:     v,
+			ResolvedReference: &v1alpha1.Reference{Name: to.GetName(), Namespace: to.GetNamespace(), Policy: req.Selector.Policy},
+		}
+		return resource.ErrStopListing
+	}, opts...)
+	if err != nil {
+		return ResolutionResponse{}, errors.Wrap(err, errListManaged)
+	}
+	if rsp != nil {
+		return *rsp, nil
+	}
+
+	// We couldn't resolve anything.
+	return ResolutionResponse{}, &UnresolvedError{Reason: errNoMatches}
+}
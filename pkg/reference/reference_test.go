@@ -0,0 +1,546 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type managedList struct {
+	metav1.ListMeta
+	Items []resource.Managed
+}
+
+func (m *managedList) GetObjectKind() schema.ObjectKind { return schema.EmptyObjectKind }
+func (m *managedList) DeepCopyObject() runtime.Object   { return m }
+func (m *managedList) GetItems() []resource.Managed     { return m.Items }
+
+func TestToAndFromPtr(t *testing.T) {
+	cases := map[string]struct {
+		want string
+	}{
+		"Zero":    {want: ""},
+		"NonZero": {want: "pointy"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := FromPtrValue(ToPtrValue(tc.want))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FromPtrValue(ToPtrValue(%s): -want, +got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+	value := "coolv"
+	ref := &v1alpha1.Reference{Name: "cool"}
+	match := true
+	always := v1alpha1.ResolvePolicyAlways
+	optional := v1alpha1.ResolutionPolicyOptional
+
+	controlled := &fake.Managed{}
+	controlled.SetName(value)
+	meta.SetExternalName(controlled, value)
+	_ = meta.AddControllerReference(controlled, meta.AsController(&corev1.ObjectReference{UID: types.UID("very-unique")}))
+
+	type args struct {
+		ctx context.Context
+		req ResolutionRequest
+	}
+	type want struct {
+		rsp ResolutionResponse
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		from   resource.Managed
+		o      []APIResolverOption
+		args   args
+		want   want
+	}{
+		"FromDeleted": {
+			reason: "Should return early if the referencing managed resource was deleted",
+			from:   &fake.Managed{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			args: args{
+				req: ResolutionRequest{Reference: ref},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedReference: ref},
+			},
+		},
+		"AlreadyResolved": {
+			reason: "Should return early if the current value is non-zero",
+			from:   &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{CurrentValue: value, Reference: ref},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedValue: value, ResolvedReference: ref},
+			},
+		},
+		"Unresolvable": {
+			reason: "Should return early if neither a reference nor a selector were provided",
+			from:   &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{},
+			},
+			want: want{
+				rsp: ResolutionResponse{},
+			},
+		},
+		"AlwaysResolve": {
+			reason: "Should resolve a reference that is already resolved if the resolve policy is Always",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					meta.SetExternalName(obj.(metav1.Object), value)
+					return nil
+				}),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					CurrentValue: "stale",
+					Reference:    &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolve: &always}},
+					To:           To{Managed: &fake.Managed{}},
+					Extract:      ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedValue:     value,
+					ResolvedReference: &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolve: &always}},
+				},
+			},
+		},
+		"OptionalNotFound": {
+			reason: "Should return the current value if an optional reference cannot be resolved",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ref.Name)),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolution: &optional}},
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedReference: &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolution: &optional}},
+				},
+			},
+		},
+		"OptionalGetError": {
+			reason: "Should return errors other than unresolved references even if the reference is optional",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolution: &optional}},
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetManaged),
+			},
+		},
+		"OptionalNoMatches": {
+			reason: "Should return no error if an optional selector matches no managed resources",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{Policy: &v1alpha1.Policy{Resolution: &optional}},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{},
+			},
+		},
+		"NamespaceNotAllowed": {
+			reason: "Should return an error if the reference targets a namespace that is not allowed",
+			from:   &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}},
+			args: args{
+				req: ResolutionRequest{
+					Reference: &v1alpha1.Reference{Name: "cool", Namespace: "other"},
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNamespaceNotAllowed, "other"),
+			},
+		},
+		"AllowedNamespace": {
+			reason: "Should resolve a reference to a namespace that is explicitly allowed",
+			c: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if key.Namespace != "other" {
+						return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+					}
+					meta.SetExternalName(obj.(metav1.Object), value)
+					return nil
+				},
+			},
+			from: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}},
+			o:    []APIResolverOption{WithAllowedNamespaces("other")},
+			args: args{
+				req: ResolutionRequest{
+					Reference: &v1alpha1.Reference{Name: "cool", Namespace: "other"},
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedValue: value, ResolvedReference: &v1alpha1.Reference{Name: "cool", Namespace: "other"}},
+			},
+		},
+		"SelectInAllowedNamespace": {
+			reason: "Should record the namespace of a managed resource selected from an allowed namespace",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+					mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", Namespace: "other"}}
+					meta.SetExternalName(mg, value)
+					obj.(*managedList).Items = []resource.Managed{mg}
+					return nil
+				}),
+			},
+			from: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}},
+			o:    []APIResolverOption{WithAllowedNamespaces("other")},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{Namespace: "other"},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedValue: value, ResolvedReference: &v1alpha1.Reference{Name: "cool", Namespace: "other"}},
+			},
+		},
+		"GetError": {
+			reason: "Should return errors encountered while getting the referenced resource",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(errBoom),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: ref,
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetManaged),
+			},
+		},
+		"NotFound": {
+			reason: "Should return an UnresolvedError if the referenced resource does not exist",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ref.Name)),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: ref,
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				err: &UnresolvedError{Name: ref.Name, Reason: errNotFound},
+			},
+		},
+		"NoValue": {
+			reason: "Should return an UnresolvedError if the value extracted from the referenced resource is empty",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: ref,
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				err: &UnresolvedError{Name: ref.Name, Reason: errNoValue},
+			},
+		},
+		"SuccessfulResolve": {
+			reason: "Should resolve the value extracted from the referenced resource",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					meta.SetExternalName(obj.(metav1.Object), value)
+					return nil
+				}),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: ref,
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedValue: value, ResolvedReference: ref},
+			},
+		},
+		"ListError": {
+			reason: "Should return errors encountered while listing potential referenced resources",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(errBoom),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListManaged),
+			},
+		},
+		"NoMatches": {
+			reason: "Should return an error when no managed resources match the selector",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				err: &UnresolvedError{Reason: errNoMatches},
+			},
+		},
+		"NoReadyMatches": {
+			reason: "Should not select managed resources from which no value can be extracted",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+					obj.(*managedList).Items = []resource.Managed{&fake.Managed{}}
+					return nil
+				}),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				err: &UnresolvedError{Reason: errNoMatches},
+			},
+		},
+		"NoControllerMatches": {
+			reason: "Should not select managed resources with a different controller when MatchControllerRef is set",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+					obj.(*managedList).Items = []resource.Managed{controlled}
+					return nil
+				}),
+			},
+			from: &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{MatchControllerRef: &match},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				err: &UnresolvedError{Reason: errNoMatches},
+			},
+		},
+		"SuccessfulSelect": {
+			reason: "Should select a reference to the first matching managed resource",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+					obj.(*managedList).Items = []resource.Managed{&fake.Managed{}, controlled}
+					return nil
+				}),
+			},
+			from: controlled,
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{MatchControllerRef: &match},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedValue: value, ResolvedReference: &v1alpha1.Reference{Name: value}},
+			},
+		},
+		"SuccessfulSelectWithPolicy": {
+			reason: "Should record the selector's policy in the selected reference",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+					obj.(*managedList).Items = []resource.Managed{controlled}
+					return nil
+				}),
+			},
+			from: controlled,
+			args: args{
+				req: ResolutionRequest{
+					Selector: &v1alpha1.Selector{Policy: &v1alpha1.Policy{Resolution: &optional}},
+					To:       To{List: &managedList{}},
+					Extract:  ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedValue:     value,
+					ResolvedReference: &v1alpha1.Reference{Name: value, Policy: &v1alpha1.Policy{Resolution: &optional}},
+				},
+			},
+		},
+		"SelectedOptionalNotFound": {
+			reason: "Should honour the selector's optional policy once a reference has been selected",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			from:   &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					Reference: &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolution: &optional}},
+					Selector:  &v1alpha1.Selector{Policy: &v1alpha1.Policy{Resolution: &optional}},
+					To:        To{Managed: &fake.Managed{}},
+					Extract:   ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedReference: &v1alpha1.Reference{Name: "cool", Policy: &v1alpha1.Policy{Resolution: &optional}},
+				},
+			},
+		},
+		"SelectorAlwaysReselects": {
+			reason: "Should select a new reference if the selector's resolve policy is Always, even if a reference was already selected",
+			c: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+					obj.(*managedList).Items = []resource.Managed{controlled}
+					return nil
+				}),
+			},
+			from: controlled,
+			args: args{
+				req: ResolutionRequest{
+					CurrentValue: "stale",
+					Reference:    &v1alpha1.Reference{Name: "stale"},
+					Selector:     &v1alpha1.Selector{Policy: &v1alpha1.Policy{Resolve: &always}},
+					To:           To{List: &managedList{}},
+					Extract:      ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{
+					ResolvedValue:     value,
+					ResolvedReference: &v1alpha1.Reference{Name: value, Policy: &v1alpha1.Policy{Resolve: &always}},
+				},
+			},
+		},
+		"SelectorAlwaysOptionalNoMatches": {
+			reason: "Should keep the selected reference and current value if an optional selector with resolve policy Always matches nothing",
+			c:      &test.MockClient{MockList: test.NewMockListFn(nil)},
+			from:   &fake.Managed{},
+			args: args{
+				req: ResolutionRequest{
+					CurrentValue: "stale",
+					Reference:    &v1alpha1.Reference{Name: "stale"},
+					Selector:     &v1alpha1.Selector{Policy: &v1alpha1.Policy{Resolve: &always, Resolution: &optional}},
+					To:           To{List: &managedList{}},
+					Extract:      ExternalName(),
+				},
+			},
+			want: want{
+				rsp: ResolutionResponse{ResolvedValue: "stale", ResolvedReference: &v1alpha1.Reference{Name: "stale"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPIResolver(tc.c, tc.from, tc.o...)
+			got, err := r.Resolve(tc.args.ctx, tc.args.req)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rsp, got); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsUnresolved(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Unresolved":        {err: &UnresolvedError{Name: "cool"}, want: true},
+		"WrappedUnresolved": {err: errors.Wrap(&UnresolvedError{Name: "cool"}, "wrapped"), want: true},
+		"OtherError":        {err: errors.New("boom"), want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsUnresolved(tc.err); got != tc.want {
+				t.Errorf("IsUnresolved(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
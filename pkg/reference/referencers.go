@@ -0,0 +1,199 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// IndexReferences indexes managed resources by each of the objects they
+// reference, in the form returned by ReferenceIndexValue.
+const IndexReferences = "crossplane.io/references"
+
+const (
+	errIndexReferences = "cannot index managed resources by reference"
+	errListReferencers = "cannot list managed resources that reference object"
+
+	listReferencersTimeout = 30 * time.Second
+)
+
+// An Edge from a referencing managed resource to the object it references.
+type Edge struct {
+	// To is the kind of the referenced object.
+	To schema.GroupKind
+
+	// Namespace of the referenced object. Defaults to the namespace of the
+	// referencing managed resource.
+	Namespace string
+
+	// Name of the referenced object.
+	Name string
+}
+
+// An ExtractEdgesFn returns the edges from the supplied managed resource to
+// the objects it references. Edges are typically derived from a managed
+// resource's reference fields, e.g. its VPC reference.
+type ExtractEdgesFn func(mg resource.Managed) []Edge
+
+// ReferenceIndexValue returns the value under which a managed resource that
+// references the supplied object is indexed by IndexReferences.
+func ReferenceIndexValue(to schema.GroupKind, nn types.NamespacedName) string {
+	return to.String() + "/" + nn.String()
+}
+
+// IndexByReference returns a client.IndexerFunc that indexes managed
+// resources by each of the objects they reference, per the supplied
+// ExtractEdgesFn.
+func IndexByReference(fn ExtractEdgesFn) client.IndexerFunc {
+	return func(o runtime.Object) []string {
+		mg, ok := o.(resource.Managed)
+		if !ok {
+			return nil
+		}
+		edges := fn(mg)
+		keys := make([]string, 0, len(edges))
+		for _, e := range edges {
+			ns := e.Namespace
+			if ns == "" {
+				ns = mg.GetNamespace()
+			}
+			keys = append(keys, ReferenceIndexValue(e.To, types.NamespacedName{Namespace: ns, Name: e.Name}))
+		}
+		return keys
+	}
+}
+
+// SetupReferenceIndex adds IndexReferences to the supplied FieldIndexer for
+// the supplied kind of managed resource, whose references are extracted by
+// the supplied ExtractEdgesFn. EnqueueRequestsForReferencers requires this
+// index.
+func SetupReferenceIndex(fi client.FieldIndexer, of resource.Managed, fn ExtractEdgesFn) error {
+	return errors.Wrap(fi.IndexField(of, IndexReferences, IndexByReference(fn)), errIndexReferences)
+}
+
+// EnqueueRequestsForReferencers enqueues a reconcile.Request for each managed
+// resource that references the object of an event, allowing managed resources
+// to promptly re-resolve their references when their referents change, for
+// example when a referenced VPC gains an external name:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//	    For(&v1alpha1.Subnet{}).
+//	    Watches(&source.Kind{Type: &v1alpha1.VPC{}}, reference.NewEnqueueRequestsForReferencers(...)).
+//	    Complete(r)
+//
+// The managed resources are listed using a client that must be backed by a
+// cache with IndexReferences.
+type EnqueueRequestsForReferencers struct {
+	client  client.Reader
+	newList func() resource.ManagedList
+	to      schema.GroupKind
+	log     logging.Logger
+}
+
+// An EnqueueRequestsForReferencersOption configures an
+// EnqueueRequestsForReferencers.
+type EnqueueRequestsForReferencersOption func(*EnqueueRequestsForReferencers)
+
+// WithReferencersLogger specifies how the EnqueueRequestsForReferencers should
+// log messages.
+func WithReferencersLogger(l logging.Logger) EnqueueRequestsForReferencersOption {
+	return func(e *EnqueueRequestsForReferencers) {
+		e.log = l
+	}
+}
+
+// NewEnqueueRequestsForReferencers returns an event handler that enqueues
+// requests for managed resources of the supplied kind that reference objects
+// of the supplied kind.
+func NewEnqueueRequestsForReferencers(c client.Reader, s *runtime.Scheme, referencers resource.ManagedKind, to schema.GroupKind, o ...EnqueueRequestsForReferencersOption) *EnqueueRequestsForReferencers {
+	e := &EnqueueRequestsForReferencers{
+		client: c,
+		newList: func() resource.ManagedList {
+			return resource.MustCreateObject(referencers.List(), s).(resource.ManagedList)
+		},
+		to:  to,
+		log: logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Create enqueues a request for each managed resource that references the
+// object of the supplied CreateEvent.
+func (e *EnqueueRequestsForReferencers) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.addReferencers(evt.Meta, q)
+}
+
+// Update enqueues a request for each managed resource that references the
+// object of the supplied UpdateEvent.
+func (e *EnqueueRequestsForReferencers) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.addReferencers(evt.MetaNew, q)
+}
+
+// Delete enqueues a request for each managed resource that references the
+// object of the supplied DeleteEvent.
+func (e *EnqueueRequestsForReferencers) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.addReferencers(evt.Meta, q)
+}
+
+// Generic enqueues a request for each managed resource that references the
+// object of the supplied GenericEvent.
+func (e *EnqueueRequestsForReferencers) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.addReferencers(evt.Meta, q)
+}
+
+type adder interface {
+	Add(item interface{})
+}
+
+func (e *EnqueueRequestsForReferencers) addReferencers(o metav1.Object, queue adder) {
+	if o == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listReferencersTimeout)
+	defer cancel()
+
+	nn := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+	l := e.newList()
+	if err := e.client.List(ctx, l, client.MatchingFields{IndexReferences: ReferenceIndexValue(e.to, nn)}); err != nil {
+		// Event handlers can't return errors, so the best we can do is log.
+		// The referencers will still be reconciled at their next poll.
+		e.log.Debug(errListReferencers, "error", err, "referenced", nn)
+		return
+	}
+
+	for _, mg := range l.GetItems() {
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()}})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ handler.EventHandler = &EnqueueRequestsForReferencers{}
+
+type addFn func(item interface{})
+
+func (fn addFn) Add(item interface{}) {
+	fn(item)
+}
+
+func TestIndexByReference(t *testing.T) {
+	vpc := schema.GroupKind{Group: "example.org", Kind: "VPC"}
+	fn := func(_ resource.Managed) []Edge {
+		return []Edge{
+			{To: vpc, Name: "local"},
+			{To: vpc, Namespace: "elsewhere", Name: "remote"},
+		}
+	}
+
+	cases := map[string]struct {
+		o    runtime.Object
+		want []string
+	}{
+		"NotManaged": {
+			o:    &fake.Object{},
+			want: nil,
+		},
+		"Managed": {
+			o: &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "here"}},
+			want: []string{
+				"VPC.example.org/here/local",
+				"VPC.example.org/elsewhere/remote",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexByReference(fn)(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IndexByReference(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetupReferenceIndex(t *testing.T) {
+	errBoom := errors.New("boom")
+	fi := &mockFieldIndexer{MockIndexField: func(_ runtime.Object, field string, _ client.IndexerFunc) error {
+		if field != IndexReferences {
+			t.Errorf("IndexField(...): want field %q, got %q", IndexReferences, field)
+		}
+		return errBoom
+	}}
+
+	want := errors.Wrap(errBoom, errIndexReferences)
+	got := SetupReferenceIndex(fi, &fake.Managed{}, func(_ resource.Managed) []Edge { return nil })
+	if diff := cmp.Diff(want, got, test.EquateErrors()); diff != "" {
+		t.Errorf("SetupReferenceIndex(...): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestAddReferencers(t *testing.T) {
+	vpc := schema.GroupKind{Group: "example.org", Kind: "VPC"}
+	referenced := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "coolvpc"}}
+
+	cases := map[string]struct {
+		c     client.Reader
+		o     metav1.Object
+		queue adder
+	}{
+		"NilObject": {
+			queue: addFn(func(_ interface{}) { t.Errorf("queue.Add() called unexpectedly") }),
+		},
+		"ListError": {
+			c:     &test.MockClient{MockList: test.NewMockListFn(errors.New("boom"))},
+			o:     referenced,
+			queue: addFn(func(_ interface{}) { t.Errorf("queue.Add() called unexpectedly") }),
+		},
+		"Referencers": {
+			c: &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				want := fields.Set{IndexReferences: ReferenceIndexValue(vpc, types.NamespacedName{Namespace: "coolns", Name: "coolvpc"})}
+				if lo.FieldSelector == nil || !lo.FieldSelector.Matches(want) {
+					t.Errorf("List(...): unexpected field selector %q", lo.FieldSelector)
+				}
+				obj.(*managedList).Items = []resource.Managed{
+					&fake.Managed{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "coolsubnet"}},
+				}
+				return nil
+			}},
+			o: referenced,
+			queue: addFn(func(got interface{}) {
+				want := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "coolns", Name: "coolsubnet"}}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("-want, +got:\n%s", diff)
+				}
+			}),
+		},
+	}
+
+	s := fake.SchemeWith(&fake.Managed{})
+	kind := resource.ManagedKind(fake.GVK(&fake.Managed{}))
+	s.AddKnownTypeWithName(kind.List(), &managedList{})
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewEnqueueRequestsForReferencers(tc.c, s, kind, vpc)
+			e.addReferencers(tc.o, tc.queue)
+		})
+	}
+}
@@ -17,17 +17,21 @@ limitations under the License.
 package resource
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"reflect"
+	"sync"
 
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
@@ -43,15 +47,35 @@ const (
 // them from and writing them to a Kubernetes API server.
 type APIManagedConnectionPropagator struct {
 	client ClientApplicator
+	reader client.Reader
 	typer  runtime.ObjectTyper
 }
 
+// An APIManagedConnectionPropagatorOption configures an
+// APIManagedConnectionPropagator.
+type APIManagedConnectionPropagatorOption func(*APIManagedConnectionPropagator)
+
+// WithPropagationSecretReader specifies the reader used to read the connection
+// secrets of managed resources. Secrets are read using the propagator's client
+// by default. Supply a CachedReader to read secrets from a cache rather than
+// from the API server.
+func WithPropagationSecretReader(r client.Reader) APIManagedConnectionPropagatorOption {
+	return func(a *APIManagedConnectionPropagator) {
+		a.reader = r
+	}
+}
+
 // NewAPIManagedConnectionPropagator returns a new APIManagedConnectionPropagator.
-func NewAPIManagedConnectionPropagator(c client.Client, t runtime.ObjectTyper) *APIManagedConnectionPropagator {
-	return &APIManagedConnectionPropagator{
+func NewAPIManagedConnectionPropagator(c client.Client, t runtime.ObjectTyper, o ...APIManagedConnectionPropagatorOption) *APIManagedConnectionPropagator {
+	a := &APIManagedConnectionPropagator{
 		client: ClientApplicator{Client: c, Applicator: NewAPIUpdatingApplicator(c)},
+		reader: c,
 		typer:  t,
 	}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // PropagateConnection details from the supplied resource to the supplied claim.
@@ -67,7 +91,7 @@ func (a *APIManagedConnectionPropagator) PropagateConnection(ctx context.Context
 		Name:      mg.GetWriteConnectionSecretToReference().Name,
 	}
 	from := &corev1.Secret{}
-	if err := a.client.Get(ctx, n, from); err != nil {
+	if err := a.reader.Get(ctx, n, from); err != nil {
 		return errors.Wrap(err, errGetSecret)
 	}
 
@@ -79,7 +103,12 @@ func (a *APIManagedConnectionPropagator) PropagateConnection(ctx context.Context
 		return errors.New(errSecretConflict)
 	}
 
-	to := LocalConnectionSecretFor(o, MustGetKind(o, a.typer))
+	kind, err := GetKind(o, a.typer)
+	if err != nil {
+		return err
+	}
+
+	to := LocalConnectionSecretFor(o, kind)
 	to.Data = from.Data
 
 	meta.AllowPropagation(from, to)
@@ -103,6 +132,11 @@ func NewAPIPatchingApplicator(c client.Client) *APIPatchingApplicator {
 	return &APIPatchingApplicator{client: c}
 }
 
+// patchBuffers pools the buffers used to encode patches. Apply is on the hot
+// path when publishing connection secrets, so we avoid allocating a new buffer
+// for every call.
+var patchBuffers = sync.Pool{New: func() interface{} { return &bytes.Buffer{} }}
+
 // Apply changes to the supplied object. The object will be created if it does
 // not exist, or patched if it does.
 func (a *APIPatchingApplicator) Apply(ctx context.Context, o runtime.Object, ao ...ApplyOption) error {
@@ -111,7 +145,20 @@ func (a *APIPatchingApplicator) Apply(ctx context.Context, o runtime.Object, ao
 		return errors.New("cannot access object metadata")
 	}
 
-	desired := o.DeepCopyObject()
+	buf := patchBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer patchBuffers.Put(buf)
+
+	// Getting the current object overwrites the desired object. ApplyOptions
+	// need the desired object, so we must copy it when any were supplied.
+	// Otherwise we only need the patch we'll eventually send, so we encode it
+	// up front rather than copying the entire object.
+	var desired runtime.Object
+	if len(ao) > 0 {
+		desired = o.DeepCopyObject()
+	} else if err := json.NewEncoder(buf).Encode(o); err != nil {
+		return errors.Wrap(err, "cannot encode patch")
+	}
 
 	err := a.client.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, o)
 	if kerrors.IsNotFound(err) {
@@ -122,20 +169,25 @@ func (a *APIPatchingApplicator) Apply(ctx context.Context, o runtime.Object, ao
 		return errors.Wrap(err, "cannot get object")
 	}
 
-	for _, fn := range ao {
-		if err := fn(ctx, o, desired); err != nil {
-			return err
+	if desired != nil {
+		for _, fn := range ao {
+			if err := fn(ctx, o, desired); err != nil {
+				return err
+			}
+		}
+		if err := json.NewEncoder(buf).Encode(desired); err != nil {
+			return errors.Wrap(err, "cannot encode patch")
 		}
 	}
 
 	// TODO(negz): Allow callers to override the kind of patch used.
-	return errors.Wrap(a.client.Patch(ctx, o, &patch{desired}), "cannot patch object")
+	return errors.Wrap(a.client.Patch(ctx, o, &patch{buf.Bytes()}), "cannot patch object")
 }
 
-type patch struct{ from runtime.Object }
+type patch struct{ data []byte }
 
 func (p *patch) Type() types.PatchType                 { return types.MergePatchType }
-func (p *patch) Data(_ runtime.Object) ([]byte, error) { return json.Marshal(p.from) }
+func (p *patch) Data(_ runtime.Object) ([]byte, error) { return p.data, nil }
 
 // An APIUpdatingApplicator applies changes to an object by either creating or
 // updating it in a Kubernetes API server.
@@ -157,7 +209,7 @@ func (a *APIUpdatingApplicator) Apply(ctx context.Context, o runtime.Object, ao
 		return errors.New("cannot access object metadata")
 	}
 
-	current := o.DeepCopyObject()
+	current := empty(o)
 
 	err := a.client.Get(ctx, types.NamespacedName{Name: m.GetName(), Namespace: m.GetNamespace()}, current)
 	if kerrors.IsNotFound(err) {
@@ -176,3 +228,22 @@ func (a *APIUpdatingApplicator) Apply(ctx context.Context, o runtime.Object, ao
 
 	return errors.Wrap(a.client.Update(ctx, o), "cannot update object")
 }
+
+// empty returns a new, empty object of the same kind as the supplied object.
+// It's cheaper than a deep copy when the new object will be overwritten, for
+// example by getting it from the API server.
+func empty(o runtime.Object) runtime.Object {
+	if u, ok := o.(*unstructured.Unstructured); ok {
+		e := &unstructured.Unstructured{}
+		e.SetGroupVersionKind(u.GroupVersionKind())
+		return e
+	}
+	t := reflect.TypeOf(o)
+	if t.Kind() != reflect.Ptr {
+		return o.DeepCopyObject()
+	}
+	if e, ok := reflect.New(t.Elem()).Interface().(runtime.Object); ok {
+		return e
+	}
+	return o.DeepCopyObject()
+}
@@ -21,15 +21,16 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -218,7 +219,7 @@ func TestPropagateConnection(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			api := &APIManagedConnectionPropagator{client: tc.fields.client, typer: tc.fields.typer}
+			api := &APIManagedConnectionPropagator{client: tc.fields.client, reader: tc.fields.client, typer: tc.fields.typer}
 			err := api.PropagateConnection(tc.args.ctx, tc.args.o, tc.args.mg)
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\napi.PropagateConnection(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -490,3 +491,88 @@ func TestAPIUpdatingApplicator(t *testing.T) {
 		})
 	}
 }
+
+func TestEmpty(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("cool.crossplane.io/v1")
+	u.SetKind("Cool")
+	u.SetName("cool")
+
+	gvk := &unstructured.Unstructured{}
+	gvk.SetAPIVersion("cool.crossplane.io/v1")
+	gvk.SetKind("Cool")
+
+	cases := map[string]struct {
+		reason string
+		o      runtime.Object
+		want   runtime.Object
+	}{
+		"Typed": {
+			reason: "An empty object of the same type should be returned",
+			o:      &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cool"}, Data: map[string][]byte{"cool": {1}}},
+			want:   &corev1.Secret{},
+		},
+		"Unstructured": {
+			reason: "An empty unstructured object of the same kind should be returned",
+			o:      u,
+			want:   gvk,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := empty(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nempty(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func benchmarkSecret() *corev1.Secret {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "coolns", Name: "cool"},
+		Data:       map[string][]byte{},
+	}
+	for _, k := range []string{"username", "password", "endpoint", "port", "clientCert", "clientKey", "caCert"} {
+		s.Data[k] = make([]byte, 1024)
+	}
+	return s
+}
+
+func BenchmarkAPIPatchingApplicator(b *testing.B) {
+	c := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil),
+		MockPatch: func(_ context.Context, obj runtime.Object, p client.Patch, _ ...client.PatchOption) error {
+			_, err := p.Data(obj)
+			return err
+		},
+	}
+	a := NewAPIPatchingApplicator(c)
+	s := benchmarkSecret()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := a.Apply(context.Background(), s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAPIUpdatingApplicator(b *testing.B) {
+	c := &test.MockClient{
+		MockGet:    test.NewMockGetFn(nil),
+		MockUpdate: test.NewMockUpdateFn(nil),
+	}
+	a := NewAPIUpdatingApplicator(c)
+	s := benchmarkSecret()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := a.Apply(context.Background(), s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
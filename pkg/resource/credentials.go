@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errExtractEnv           = "cannot extract from environment variable when none specified"
+	errExtractFs            = "cannot extract from filesystem when no path specified"
+	errExtractSecretKey     = "cannot extract from secret key when none specified"
+	errGetCredentialsSecret = "cannot get credentials secret"
+	errReadCredentialsFile  = "cannot read credentials file"
+
+	errFmtNoHandlerForSource = "no extraction handler registered for source: %s"
+)
+
+// An EnvLookupFn looks up the value of the named environment variable.
+type EnvLookupFn func(name string) string
+
+// A ReadFileFn reads the file at the supplied path.
+type ReadFileFn func(path string) ([]byte, error)
+
+// ExtractEnv extracts credentials from the environment variable selected by
+// the supplied CommonCredentialSelectors.
+func ExtractEnv(_ context.Context, e EnvLookupFn, s v1alpha1.CommonCredentialSelectors) ([]byte, error) {
+	if s.Env == nil {
+		return nil, errors.New(errExtractEnv)
+	}
+	return []byte(e(s.Env.Name)), nil
+}
+
+// ExtractFs extracts credentials from the filesystem location selected by the
+// supplied CommonCredentialSelectors.
+func ExtractFs(_ context.Context, read ReadFileFn, s v1alpha1.CommonCredentialSelectors) ([]byte, error) {
+	if s.Fs == nil {
+		return nil, errors.New(errExtractFs)
+	}
+	b, err := read(s.Fs.Path)
+	return b, errors.Wrap(err, errReadCredentialsFile)
+}
+
+// ExtractSecret extracts credentials from the secret key selected by the
+// supplied CommonCredentialSelectors.
+func ExtractSecret(ctx context.Context, c client.Reader, s v1alpha1.CommonCredentialSelectors) ([]byte, error) {
+	if s.SecretRef == nil {
+		return nil, errors.New(errExtractSecretKey)
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: s.SecretRef.Namespace, Name: s.SecretRef.Name}, secret); err != nil {
+		return nil, errors.Wrap(err, errGetCredentialsSecret)
+	}
+	return secret.Data[s.SecretRef.Key], nil
+}
+
+// A CredentialExtractor extracts provider credentials from any of the common
+// credential sources.
+type CredentialExtractor struct {
+	client   client.Reader
+	env      EnvLookupFn
+	read     ReadFileFn
+	identity IdentityProvider
+}
+
+// A CredentialExtractorOption configures a CredentialExtractor.
+type CredentialExtractorOption func(*CredentialExtractor)
+
+// WithIdentityProvider configures the CredentialExtractor to extract a token
+// from the supplied IdentityProvider when credentials are to be acquired via
+// an injected identity. Consider wrapping the IdentityProvider with
+// NewCachingIdentityProvider, so that tokens are not requested every time
+// credentials are extracted.
+func WithIdentityProvider(p IdentityProvider) CredentialExtractorOption {
+	return func(e *CredentialExtractor) {
+		e.identity = p
+	}
+}
+
+// NewCredentialExtractor returns a CredentialExtractor that reads secrets
+// using the supplied client, environment variables from the process's
+// environment, and files from the local filesystem.
+func NewCredentialExtractor(c client.Reader, o ...CredentialExtractorOption) *CredentialExtractor {
+	e := &CredentialExtractor{client: c, env: os.Getenv, read: ioutil.ReadFile}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Extract credentials from the supplied source, using the supplied
+// CommonCredentialSelectors. Nothing is extracted from the None source, or
+// from the InjectedIdentity source unless the CredentialExtractor was
+// configured with an IdentityProvider. Providers using an injected identity
+// without an IdentityProvider authenticate using their (pod's) identity rather
+// than extracted credentials.
+func (e *CredentialExtractor) Extract(ctx context.Context, source v1alpha1.CredentialsSource, s v1alpha1.CommonCredentialSelectors) ([]byte, error) {
+	switch source {
+	case v1alpha1.CredentialsSourceEnvironment:
+		return ExtractEnv(ctx, e.env, s)
+	case v1alpha1.CredentialsSourceFilesystem:
+		return ExtractFs(ctx, e.read, s)
+	case v1alpha1.CredentialsSourceSecret:
+		return ExtractSecret(ctx, e.client, s)
+	case v1alpha1.CredentialsSourceInjectedIdentity:
+		if e.identity == nil {
+			return nil, nil
+		}
+		return ExtractInjectedIdentity(ctx, e.identity)
+	case v1alpha1.CredentialsSourceNone:
+		return nil, nil
+	}
+	return nil, errors.Errorf(errFmtNoHandlerForSource, source)
+}
+
+// ExtractInjectedIdentity extracts a token from the supplied IdentityProvider.
+func ExtractInjectedIdentity(ctx context.Context, p IdentityProvider) ([]byte, error) {
+	t, err := p.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(t.Value), nil
+}
+
+// CommonCredentialExtractor extracts credentials from the supplied source,
+// using the supplied CommonCredentialSelectors. Nothing is extracted from the
+// None and InjectedIdentity sources; providers using an injected identity
+// authenticate using their (pod's) identity rather than extracted credentials.
+// Use a CredentialExtractor to extract tokens for an injected identity.
+func CommonCredentialExtractor(ctx context.Context, source v1alpha1.CredentialsSource, c client.Reader, s v1alpha1.CommonCredentialSelectors) ([]byte, error) {
+	return NewCredentialExtractor(c).Extract(ctx, source, s)
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestExtractEnv(t *testing.T) {
+	creds := "super-secret"
+
+	type args struct {
+		e EnvLookupFn
+		s v1alpha1.CommonCredentialSelectors
+	}
+	type want struct {
+		b   []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSelector": {
+			reason: "We should return an error if no environment variable was selected.",
+			args:   args{},
+			want: want{
+				err: errors.New(errExtractEnv),
+			},
+		},
+		"Success": {
+			reason: "We should return the value of the selected environment variable.",
+			args: args{
+				e: func(name string) string {
+					if name != "CREDS" {
+						return ""
+					}
+					return creds
+				},
+				s: v1alpha1.CommonCredentialSelectors{Env: &v1alpha1.EnvSelector{Name: "CREDS"}},
+			},
+			want: want{
+				b: []byte(creds),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ExtractEnv(context.Background(), tc.args.e, tc.args.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExtractEnv(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.b, got); diff != "" {
+				t.Errorf("\n%s\nExtractEnv(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExtractFs(t *testing.T) {
+	errBoom := errors.New("boom")
+	creds := "super-secret"
+
+	type args struct {
+		read ReadFileFn
+		s    v1alpha1.CommonCredentialSelectors
+	}
+	type want struct {
+		b   []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSelector": {
+			reason: "We should return an error if no filesystem location was selected.",
+			args:   args{},
+			want: want{
+				err: errors.New(errExtractFs),
+			},
+		},
+		"ReadError": {
+			reason: "We should return any error encountered reading the selected file.",
+			args: args{
+				read: func(_ string) ([]byte, error) { return nil, errBoom },
+				s:    v1alpha1.CommonCredentialSelectors{Fs: &v1alpha1.FsSelector{Path: "/creds"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errReadCredentialsFile),
+			},
+		},
+		"Success": {
+			reason: "We should return the content of the selected file.",
+			args: args{
+				read: func(path string) ([]byte, error) {
+					if path != "/creds" {
+						return nil, errBoom
+					}
+					return []byte(creds), nil
+				},
+				s: v1alpha1.CommonCredentialSelectors{Fs: &v1alpha1.FsSelector{Path: "/creds"}},
+			},
+			want: want{
+				b: []byte(creds),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ExtractFs(context.Background(), tc.args.read, tc.args.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExtractFs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.b, got); diff != "" {
+				t.Errorf("\n%s\nExtractFs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExtractSecret(t *testing.T) {
+	errBoom := errors.New("boom")
+	creds := "super-secret"
+	sel := v1alpha1.CommonCredentialSelectors{SecretRef: &v1alpha1.SecretKeySelector{
+		SecretReference: v1alpha1.SecretReference{Namespace: "coolns", Name: "creds"},
+		Key:             "credentials",
+	}}
+
+	type args struct {
+		c client.Reader
+		s v1alpha1.CommonCredentialSelectors
+	}
+	type want struct {
+		b   []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSelector": {
+			reason: "We should return an error if no secret key was selected.",
+			args:   args{},
+			want: want{
+				err: errors.New(errExtractSecretKey),
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting the selected secret.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s: sel,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCredentialsSecret),
+			},
+		},
+		"Success": {
+			reason: "We should return the data at the selected secret key.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o runtime.Object) error {
+					o.(*corev1.Secret).Data = map[string][]byte{"credentials": []byte(creds)}
+					return nil
+				})},
+				s: sel,
+			},
+			want: want{
+				b: []byte(creds),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ExtractSecret(context.Background(), tc.args.c, tc.args.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExtractSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.b, got); diff != "" {
+				t.Errorf("\n%s\nExtractSecret(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCommonCredentialExtractor(t *testing.T) {
+	type args struct {
+		source v1alpha1.CredentialsSource
+		s      v1alpha1.CommonCredentialSelectors
+	}
+	type want struct {
+		b   []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"None": {
+			reason: "Nothing should be extracted from the None source.",
+			args:   args{source: v1alpha1.CredentialsSourceNone},
+			want:   want{},
+		},
+		"InjectedIdentity": {
+			reason: "Nothing should be extracted from the InjectedIdentity source.",
+			args:   args{source: v1alpha1.CredentialsSourceInjectedIdentity},
+			want:   want{},
+		},
+		"Environment": {
+			reason: "Credentials should be extracted from the environment.",
+			args: args{
+				source: v1alpha1.CredentialsSourceEnvironment,
+				s:      v1alpha1.CommonCredentialSelectors{Env: &v1alpha1.EnvSelector{Name: "CROSSPLANE_TEST_CREDS"}},
+			},
+			want: want{b: []byte("super-secret")},
+		},
+		"UnknownSource": {
+			reason: "We should return an error if no handler is registered for the source.",
+			args:   args{source: v1alpha1.CredentialsSource("Carrier Pigeon")},
+			want:   want{err: errors.Errorf(errFmtNoHandlerForSource, "Carrier Pigeon")},
+		},
+	}
+
+	os.Setenv("CROSSPLANE_TEST_CREDS", "super-secret") // nolint:errcheck
+	defer os.Unsetenv("CROSSPLANE_TEST_CREDS")         // nolint:errcheck
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CommonCredentialExtractor(context.Background(), tc.args.source, &test.MockClient{}, tc.args.s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCommonCredentialExtractor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.b, got); diff != "" {
+				t.Errorf("\n%s\nCommonCredentialExtractor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCredentialExtractor(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		o      []CredentialExtractorOption
+		source v1alpha1.CredentialsSource
+	}
+	type want struct {
+		b   []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InjectedIdentityWithoutProvider": {
+			reason: "Nothing should be extracted from the InjectedIdentity source if no IdentityProvider was configured.",
+			args:   args{source: v1alpha1.CredentialsSourceInjectedIdentity},
+			want:   want{},
+		},
+		"InjectedIdentityTokenError": {
+			reason: "We should return any error encountered getting a token from the IdentityProvider.",
+			args: args{
+				o: []CredentialExtractorOption{WithIdentityProvider(IdentityProviderFn(func(_ context.Context) (Token, error) {
+					return Token{}, errBoom
+				}))},
+				source: v1alpha1.CredentialsSourceInjectedIdentity,
+			},
+			want: want{err: errBoom},
+		},
+		"InjectedIdentity": {
+			reason: "A token should be extracted from the configured IdentityProvider.",
+			args: args{
+				o: []CredentialExtractorOption{WithIdentityProvider(IdentityProviderFn(func(_ context.Context) (Token, error) {
+					return Token{Value: "short-lived"}, nil
+				}))},
+				source: v1alpha1.CredentialsSourceInjectedIdentity,
+			},
+			want: want{b: []byte("short-lived")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewCredentialExtractor(&test.MockClient{}, tc.args.o...).Extract(context.Background(), tc.args.source, v1alpha1.CommonCredentialSelectors{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Extract(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.b, got); diff != "" {
+				t.Errorf("\n%s\ne.Extract(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,21 +17,39 @@ limitations under the License.
 package resource
 
 import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
+const getPropagatedSecretTimeout = 10 * time.Second
+
 type adder interface {
 	Add(item interface{})
 }
 
 // EnqueueRequestForClaim enqueues a reconcile.Request for the NamespacedName
-// of a ClaimReferencer's ClaimReference.
+// of a ClaimReferencer's ClaimReference. Claim controllers should use it to
+// watch the managed resources they bind to, so that claims are reconciled as
+// soon as their managed resource changes, for example becoming ready, rather
+// than when they're next resynced:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//	    For(&v1alpha1.CoolClaim{}).
+//	    Watches(&source.Kind{Type: &v1alpha1.CoolManaged{}}, &resource.EnqueueRequestForClaim{}).
+//	    Complete(r)
 type EnqueueRequestForClaim struct{}
 
 // Create adds a NamespacedName for the supplied CreateEvent if its Object is a
@@ -106,3 +124,161 @@ func addPropagated(obj runtime.Object, queue adder) {
 		queue.Add(reconcile.Request{NamespacedName: nn})
 	}
 }
+
+// EnqueueRequestForProvider enqueues a reconcile.Request for the name of the
+// provider referenced by a RequiredProviderReferencer, typically a
+// ProviderUsage.
+type EnqueueRequestForProvider struct{}
+
+// Create adds a NamespacedName for the supplied CreateEvent if its Object is a
+// RequiredProviderReferencer.
+func (e *EnqueueRequestForProvider) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	addProvider(evt.Object, q)
+}
+
+// Update adds a NamespacedName for the supplied UpdateEvent if its Objects are
+// RequiredProviderReferencers.
+func (e *EnqueueRequestForProvider) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	addProvider(evt.ObjectOld, q)
+	addProvider(evt.ObjectNew, q)
+}
+
+// Delete adds a NamespacedName for the supplied DeleteEvent if its Object is a
+// RequiredProviderReferencer.
+func (e *EnqueueRequestForProvider) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	addProvider(evt.Object, q)
+}
+
+// Generic adds a NamespacedName for the supplied GenericEvent if its Object is
+// a RequiredProviderReferencer.
+func (e *EnqueueRequestForProvider) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	addProvider(evt.Object, q)
+}
+
+func addProvider(obj runtime.Object, queue adder) {
+	pr, ok := obj.(RequiredProviderReferencer)
+	if !ok || pr.GetProviderReference().Name == "" {
+		return
+	}
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: pr.GetProviderReference().Name}})
+}
+
+// EnqueueRequestForConnectionSecretOwner enqueues a reconcile.Request for the
+// owner of a connection secret. The owner is the controller of the secret, or
+// the controller of any secret the secret is propagated to, if the controller
+// is of the supplied kind. Managed resource and claim controllers should use
+// it to watch connection secrets, so that out-of-band edits to or deletions of
+// the secrets they publish are promptly repaired:
+//
+//	ctrl.NewControllerManagedBy(mgr).
+//	    For(&v1alpha1.CoolManaged{}).
+//	    Watches(&source.Kind{Type: &corev1.Secret{}}, resource.NewEnqueueRequestForConnectionSecretOwner(...)).
+//	    Complete(r)
+//
+// A claim controller is enqueued both when its own connection secret changes
+// and when the connection secret of its bound managed resource, which is
+// propagated to its own, changes.
+type EnqueueRequestForConnectionSecretOwner struct {
+	client     client.Reader
+	owner      schema.GroupKind
+	namespaced bool
+	log        logging.Logger
+}
+
+// An EnqueueRequestForConnectionSecretOwnerOption configures an
+// EnqueueRequestForConnectionSecretOwner.
+type EnqueueRequestForConnectionSecretOwnerOption func(*EnqueueRequestForConnectionSecretOwner)
+
+// WithNamespacedOwner indicates that the owners of connection secrets are
+// namespaced, as claims are, and thus exist in the same namespace as their
+// connection secrets. Owners are assumed to be cluster scoped, as managed
+// resources are, by default.
+func WithNamespacedOwner() EnqueueRequestForConnectionSecretOwnerOption {
+	return func(e *EnqueueRequestForConnectionSecretOwner) {
+		e.namespaced = true
+	}
+}
+
+// WithConnectionSecretOwnerLogger specifies how the
+// EnqueueRequestForConnectionSecretOwner should log messages.
+func WithConnectionSecretOwnerLogger(l logging.Logger) EnqueueRequestForConnectionSecretOwnerOption {
+	return func(e *EnqueueRequestForConnectionSecretOwner) {
+		e.log = l
+	}
+}
+
+// NewEnqueueRequestForConnectionSecretOwner returns an event handler that
+// enqueues requests for owners of the supplied kind. Secrets that connection
+// secrets are propagated to are read using the supplied client.
+func NewEnqueueRequestForConnectionSecretOwner(c client.Reader, owner schema.GroupKind, o ...EnqueueRequestForConnectionSecretOwnerOption) *EnqueueRequestForConnectionSecretOwner {
+	e := &EnqueueRequestForConnectionSecretOwner{client: c, owner: owner, log: logging.NewNopLogger()}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Create enqueues a request for the owners of the connection secret of the
+// supplied CreateEvent.
+func (e *EnqueueRequestForConnectionSecretOwner) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.addOwners(evt.Meta, q)
+}
+
+// Update enqueues a request for the owners of the connection secret of the
+// supplied UpdateEvent.
+func (e *EnqueueRequestForConnectionSecretOwner) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.addOwners(evt.MetaNew, q)
+}
+
+// Delete enqueues a request for the owners of the connection secret of the
+// supplied DeleteEvent.
+func (e *EnqueueRequestForConnectionSecretOwner) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.addOwners(evt.Meta, q)
+}
+
+// Generic enqueues a request for the owners of the connection secret of the
+// supplied GenericEvent.
+func (e *EnqueueRequestForConnectionSecretOwner) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.addOwners(evt.Meta, q)
+}
+
+func (e *EnqueueRequestForConnectionSecretOwner) addOwners(o metav1.Object, queue adder) {
+	if o == nil {
+		return
+	}
+	e.addController(o, queue)
+
+	if len(meta.AllowsPropagationTo(o)) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), getPropagatedSecretTimeout)
+	defer cancel()
+
+	for nn := range meta.AllowsPropagationTo(o) {
+		s := &corev1.Secret{}
+		if err := e.client.Get(ctx, nn, s); err != nil {
+			// Event handlers can't return errors, so the best we can do is
+			// log. The owner will still be reconciled at its next poll.
+			e.log.Debug("Cannot get propagated connection secret", "error", err, "secret", nn)
+			continue
+		}
+		e.addController(s, queue)
+	}
+}
+
+func (e *EnqueueRequestForConnectionSecretOwner) addController(o metav1.Object, queue adder) {
+	c := metav1.GetControllerOf(o)
+	if c == nil {
+		return
+	}
+	gv, err := schema.ParseGroupVersion(c.APIVersion)
+	if err != nil || gv.WithKind(c.Kind).GroupKind() != e.owner {
+		return
+	}
+	nn := types.NamespacedName{Name: c.Name}
+	if e.namespaced {
+		nn.Namespace = o.GetNamespace()
+	}
+	queue.Add(reconcile.Request{NamespacedName: nn})
+}
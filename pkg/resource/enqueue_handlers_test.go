@@ -23,16 +23,23 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
 var (
 	_ handler.EventHandler = &EnqueueRequestForClaim{}
+	_ handler.EventHandler = &EnqueueRequestForProvider{}
+	_ handler.EventHandler = &EnqueueRequestForConnectionSecretOwner{}
 )
 
 type addFn func(item interface{})
@@ -114,3 +121,148 @@ func TestAddPropagated(t *testing.T) {
 		addPropagated(tc.obj, tc.queue)
 	}
 }
+
+func TestAddProvider(t *testing.T) {
+	name := "coolname"
+
+	cases := map[string]struct {
+		obj   runtime.Object
+		queue adder
+	}{
+		"ObjectIsNotAProviderReferencer": {
+			queue: addFn(func(_ interface{}) { t.Errorf("queue.Add() called unexpectedly") }),
+		},
+		"ObjectHasEmptyProviderReference": {
+			obj:   &fake.ProviderUsage{},
+			queue: addFn(func(_ interface{}) { t.Errorf("queue.Add() called unexpectedly") }),
+		},
+		"ObjectHasProviderReference": {
+			obj: &fake.ProviderUsage{RequiredProviderReferencer: fake.RequiredProviderReferencer{Ref: v1alpha1.Reference{Name: name}}},
+			queue: addFn(func(got interface{}) {
+				want := reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("-want, +got:\n%s", diff)
+				}
+			}),
+		},
+	}
+
+	for _, tc := range cases {
+		addProvider(tc.obj, tc.queue)
+	}
+}
+
+func TestAddConnectionSecretOwners(t *testing.T) {
+	ns := "coolns"
+	name := "coolname"
+	managed := schema.GroupKind{Group: "example.org", Kind: "CoolManaged"}
+	claim := schema.GroupKind{Group: "example.org", Kind: "CoolClaim"}
+	controlledBy := func(gk schema.GroupKind) []metav1.OwnerReference {
+		c := true
+		return []metav1.OwnerReference{{
+			APIVersion: gk.WithVersion("v1").GroupVersion().String(),
+			Kind:       gk.Kind,
+			Name:       name,
+			Controller: &c,
+		}}
+	}
+	errBoom := errors.New("boom")
+
+	type args struct {
+		owner      schema.GroupKind
+		namespaced bool
+		obj        metav1.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		args   args
+		want   []interface{}
+	}{
+		"NilObject": {
+			reason: "Nothing should be enqueued for an event without an object.",
+			args:   args{owner: managed},
+		},
+		"NotControlled": {
+			reason: "Nothing should be enqueued for a secret without a controller.",
+			args: args{
+				owner: managed,
+				obj:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns}},
+			},
+		},
+		"ControlledByOtherKind": {
+			reason: "Nothing should be enqueued for a secret controlled by an owner of a different kind.",
+			args: args{
+				owner: managed,
+				obj:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, OwnerReferences: controlledBy(claim)}},
+			},
+		},
+		"ControlledByClusterScopedOwner": {
+			reason: "The cluster scoped controller of a secret should be enqueued.",
+			args: args{
+				owner: managed,
+				obj:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, OwnerReferences: controlledBy(managed)}},
+			},
+			want: []interface{}{reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}},
+		},
+		"ControlledByNamespacedOwner": {
+			reason: "The namespaced controller of a secret should be enqueued.",
+			args: args{
+				owner:      claim,
+				namespaced: true,
+				obj:        &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, OwnerReferences: controlledBy(claim)}},
+			},
+			want: []interface{}{reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: name}}},
+		},
+		"PropagatedToSecretControlledByOwner": {
+			reason: "The controller of a secret that a secret is propagated to should be enqueued.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+				obj.(*corev1.Secret).SetNamespace(ns)
+				obj.(*corev1.Secret).SetOwnerReferences(controlledBy(claim))
+				return nil
+			})},
+			args: args{
+				owner:      claim,
+				namespaced: true,
+				obj: func() metav1.Object {
+					from := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other", OwnerReferences: controlledBy(managed)}}
+					to := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "secret"}}
+					meta.AllowPropagation(from, to)
+					return from
+				}(),
+			},
+			want: []interface{}{reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: name}}},
+		},
+		"GetPropagatedToSecretError": {
+			reason: "Nothing should be enqueued if a secret that a secret is propagated to cannot be read.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			args: args{
+				owner:      claim,
+				namespaced: true,
+				obj: func() metav1.Object {
+					from := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other", OwnerReferences: controlledBy(managed)}}
+					to := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "secret"}}
+					meta.AllowPropagation(from, to)
+					return from
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := []EnqueueRequestForConnectionSecretOwnerOption{}
+			if tc.args.namespaced {
+				o = append(o, WithNamespacedOwner())
+			}
+			e := NewEnqueueRequestForConnectionSecretOwner(tc.client, tc.args.owner, o...)
+
+			var got []interface{}
+			e.addOwners(tc.args.obj, addFn(func(item interface{}) { got = append(got, item) }))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.addOwners(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -132,6 +132,22 @@ func (m *ConnectionSecretWriterTo) GetWriteConnectionSecretToReference() *v1alph
 	return m.Ref
 }
 
+// ConnectionDetailsPublisherTo is a mock that implements
+// ConnectionDetailsPublisherTo interface.
+type ConnectionDetailsPublisherTo struct {
+	To *v1alpha1.PublishConnectionDetailsTo
+}
+
+// SetPublishConnectionDetailsTo sets the PublishConnectionDetailsTo.
+func (m *ConnectionDetailsPublisherTo) SetPublishConnectionDetailsTo(t *v1alpha1.PublishConnectionDetailsTo) {
+	m.To = t
+}
+
+// GetPublishConnectionDetailsTo gets the PublishConnectionDetailsTo.
+func (m *ConnectionDetailsPublisherTo) GetPublishConnectionDetailsTo() *v1alpha1.PublishConnectionDetailsTo {
+	return m.To
+}
+
 // Reclaimer is a mock that implements Reclaimer interface.
 type Reclaimer struct{ Policy v1alpha1.ReclaimPolicy }
 
@@ -155,6 +171,40 @@ func (m *CredentialsSecretReferencer) GetCredentialsSecretReference() v1alpha1.S
 	return m.Ref
 }
 
+// ProviderConfigReferencer is a mock that satisfies the
+// ProviderConfigReferencer interface.
+type ProviderConfigReferencer struct{ Ref *v1alpha1.Reference }
+
+// SetProviderConfigReference sets the ProviderConfigReference.
+func (m *ProviderConfigReferencer) SetProviderConfigReference(p *v1alpha1.Reference) { m.Ref = p }
+
+// GetProviderConfigReference gets the ProviderConfigReference.
+func (m *ProviderConfigReferencer) GetProviderConfigReference() *v1alpha1.Reference { return m.Ref }
+
+// RequiredProviderReferencer is a mock that satisfies the
+// RequiredProviderReferencer interface.
+type RequiredProviderReferencer struct{ Ref v1alpha1.Reference }
+
+// SetProviderReference sets the ProviderReference.
+func (m *RequiredProviderReferencer) SetProviderReference(p v1alpha1.Reference) { m.Ref = p }
+
+// GetProviderReference gets the ProviderReference.
+func (m *RequiredProviderReferencer) GetProviderReference() v1alpha1.Reference { return m.Ref }
+
+// RequiredTypedResourceReferencer is a mock that satisfies the
+// RequiredTypedResourceReferencer interface.
+type RequiredTypedResourceReferencer struct{ Ref v1alpha1.TypedReference }
+
+// SetResourceReference sets the ResourceReference.
+func (m *RequiredTypedResourceReferencer) SetResourceReference(r v1alpha1.TypedReference) {
+	m.Ref = r
+}
+
+// GetResourceReference gets the ResourceReference.
+func (m *RequiredTypedResourceReferencer) GetResourceReference() v1alpha1.TypedReference {
+	return m.Ref
+}
+
 // A WorkloadReferencer references an OAM Workload type.
 type WorkloadReferencer struct{ Ref v1alpha1.TypedReference }
 
@@ -245,7 +295,9 @@ type Managed struct {
 	ClassReferencer
 	ClaimReferencer
 	ProviderReferencer
+	ProviderConfigReferencer
 	ConnectionSecretWriterTo
+	ConnectionDetailsPublisherTo
 	Reclaimer
 	v1alpha1.ConditionedStatus
 	v1alpha1.BindingStatus
@@ -289,6 +341,57 @@ func (m *Provider) DeepCopyObject() runtime.Object {
 	return out
 }
 
+// ProviderUsage is a mock that satisfies the ProviderUsage interface.
+type ProviderUsage struct {
+	metav1.ObjectMeta
+
+	RequiredProviderReferencer
+	RequiredTypedResourceReferencer
+}
+
+// GetObjectKind returns schema.ObjectKind.
+func (p *ProviderUsage) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+// DeepCopyObject returns a deep copy of ProviderUsage as runtime.Object.
+func (p *ProviderUsage) DeepCopyObject() runtime.Object {
+	out := &ProviderUsage{}
+	j, err := json.Marshal(p)
+	if err != nil {
+		panic(err)
+	}
+	_ = json.Unmarshal(j, out)
+	return out
+}
+
+// StoreConfig is a mock that implements StoreConfig interface.
+type StoreConfig struct {
+	metav1.ObjectMeta
+	Config v1alpha1.SecretStoreConfig
+}
+
+// GetStoreConfig gets the SecretStoreConfig.
+func (m *StoreConfig) GetStoreConfig() v1alpha1.SecretStoreConfig {
+	return m.Config
+}
+
+// GetObjectKind returns schema.ObjectKind.
+func (m *StoreConfig) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+// DeepCopyObject returns a deep copy of StoreConfig as runtime.Object.
+func (m *StoreConfig) DeepCopyObject() runtime.Object {
+	out := &StoreConfig{}
+	j, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	_ = json.Unmarshal(j, out)
+	return out
+}
+
 // Target is a mock that implements Target interface.
 type Target struct {
 	metav1.ObjectMeta
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// DefaultTokenRefreshWindow is how long before it expires a cached token is
+// refreshed by default.
+const DefaultTokenRefreshWindow = 5 * time.Minute
+
+const errGetToken = "cannot get token from identity provider"
+
+// A Token is a short-lived credential obtained from an IdentityProvider.
+type Token struct {
+	// Value of the token.
+	Value string
+
+	// Expiry is the time at which the token expires. A token with a zero
+	// Expiry never expires.
+	Expiry time.Time
+}
+
+// validAt returns true if the token is still valid at the supplied time.
+func (t Token) validAt(at time.Time) bool {
+	return t.Value != "" && (t.Expiry.IsZero() || at.Before(t.Expiry))
+}
+
+// An IdentityProvider obtains short-lived tokens using the identity injected
+// into the provider's pod, for example via IRSA for AWS, Workload Identity for
+// GCP, or Managed Service Identity for Azure.
+type IdentityProvider interface {
+	// Token returns a token for the injected identity.
+	Token(ctx context.Context) (Token, error)
+}
+
+// An IdentityProviderFn is a function that satisfies the IdentityProvider
+// interface.
+type IdentityProviderFn func(ctx context.Context) (Token, error)
+
+// Token returns a token for the injected identity.
+func (fn IdentityProviderFn) Token(ctx context.Context) (Token, error) {
+	return fn(ctx)
+}
+
+// A CachingIdentityProvider caches the tokens returned by another
+// IdentityProvider, refreshing them shortly before they expire.
+type CachingIdentityProvider struct {
+	wrapped IdentityProvider
+	window  time.Duration
+	now     func() time.Time
+
+	mx     sync.Mutex
+	cached Token
+}
+
+// A CachingIdentityProviderOption configures a CachingIdentityProvider.
+type CachingIdentityProviderOption func(*CachingIdentityProvider)
+
+// WithTokenRefreshWindow configures how long before it expires a cached token
+// should be refreshed.
+func WithTokenRefreshWindow(d time.Duration) CachingIdentityProviderOption {
+	return func(p *CachingIdentityProvider) {
+		p.window = d
+	}
+}
+
+// NewCachingIdentityProvider returns an IdentityProvider that caches the
+// tokens returned by the supplied IdentityProvider.
+func NewCachingIdentityProvider(p IdentityProvider, o ...CachingIdentityProviderOption) *CachingIdentityProvider {
+	c := &CachingIdentityProvider{wrapped: p, window: DefaultTokenRefreshWindow, now: time.Now}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// Token returns the cached token, unless it is due to be refreshed. A cached
+// token that is due to be refreshed but has not yet expired is returned if it
+// cannot be refreshed.
+func (p *CachingIdentityProvider) Token(ctx context.Context) (Token, error) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	now := p.now()
+	if p.cached.validAt(now.Add(p.window)) {
+		return p.cached, nil
+	}
+
+	t, err := p.wrapped.Token(ctx)
+	if err != nil {
+		if p.cached.validAt(now) {
+			return p.cached, nil
+		}
+		return Token{}, errors.Wrap(err, errGetToken)
+	}
+
+	p.cached = t
+	return t, nil
+}
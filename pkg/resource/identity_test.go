@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ IdentityProvider = &CachingIdentityProvider{}
+
+func TestCachingIdentityProvider(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	fresh := Token{Value: "fresh", Expiry: now.Add(1 * time.Hour)}
+	expiring := Token{Value: "expiring", Expiry: now.Add(1 * time.Minute)}
+	expired := Token{Value: "expired", Expiry: now.Add(-1 * time.Minute)}
+	forever := Token{Value: "forever"}
+
+	type args struct {
+		wrapped IdentityProvider
+		cached  Token
+	}
+	type want struct {
+		t   Token
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NothingCached": {
+			reason: "We should get and return a new token if none is cached.",
+			args: args{
+				wrapped: IdentityProviderFn(func(_ context.Context) (Token, error) { return fresh, nil }),
+			},
+			want: want{t: fresh},
+		},
+		"FreshTokenCached": {
+			reason: "We should return a cached token that is not due to be refreshed.",
+			args: args{
+				wrapped: IdentityProviderFn(func(_ context.Context) (Token, error) {
+					t.Errorf("Token(...): called unexpectedly")
+					return Token{}, nil
+				}),
+				cached: fresh,
+			},
+			want: want{t: fresh},
+		},
+		"TokenNeverExpires": {
+			reason: "We should return a cached token that never expires.",
+			args: args{
+				wrapped: IdentityProviderFn(func(_ context.Context) (Token, error) {
+					t.Errorf("Token(...): called unexpectedly")
+					return Token{}, nil
+				}),
+				cached: forever,
+			},
+			want: want{t: forever},
+		},
+		"ExpiringTokenRefreshed": {
+			reason: "We should refresh a cached token that is due to be refreshed.",
+			args: args{
+				wrapped: IdentityProviderFn(func(_ context.Context) (Token, error) { return fresh, nil }),
+				cached:  expiring,
+			},
+			want: want{t: fresh},
+		},
+		"ExpiringTokenRefreshError": {
+			reason: "We should return a cached token that has not yet expired if we cannot refresh it.",
+			args: args{
+				wrapped: IdentityProviderFn(func(_ context.Context) (Token, error) { return Token{}, errBoom }),
+				cached:  expiring,
+			},
+			want: want{t: expiring},
+		},
+		"ExpiredTokenRefreshError": {
+			reason: "We should return an error if we cannot refresh a cached token that has expired.",
+			args: args{
+				wrapped: IdentityProviderFn(func(_ context.Context) (Token, error) { return Token{}, errBoom }),
+				cached:  expired,
+			},
+			want: want{err: errors.Wrap(errBoom, errGetToken)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewCachingIdentityProvider(tc.args.wrapped)
+			p.now = func() time.Time { return now }
+			p.cached = tc.args.cached
+
+			got, err := p.Token(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Token(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.t, got); diff != "" {
+				t.Errorf("\n%s\np.Token(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const errNewImpersonatingClient = "cannot create impersonating client"
+
+// serviceAccountUserPrefix prefixes the user names of service accounts.
+const serviceAccountUserPrefix = "system:serviceaccount:"
+
+// ServiceAccountUser returns the user name of the service account with the
+// supplied namespace and name.
+func ServiceAccountUser(namespace, name string) string {
+	return serviceAccountUserPrefix + namespace + ":" + name
+}
+
+// An ImpersonationFn returns the identity that should be impersonated when
+// reading and writing on behalf of the supplied object. It returns false if no
+// identity should be impersonated.
+type ImpersonationFn func(o metav1.Object) (rest.ImpersonationConfig, bool)
+
+// ImpersonateServiceAccount returns an ImpersonationFn that impersonates the
+// named service account in the namespace of the supplied object. Nothing is
+// impersonated on behalf of cluster scoped objects.
+func ImpersonateServiceAccount(name string) ImpersonationFn {
+	return func(o metav1.Object) (rest.ImpersonationConfig, bool) {
+		if o.GetNamespace() == "" {
+			return rest.ImpersonationConfig{}, false
+		}
+		return rest.ImpersonationConfig{UserName: ServiceAccountUser(o.GetNamespace(), name)}, true
+	}
+}
+
+// ImpersonateAnnotatedServiceAccount returns an ImpersonationFn that
+// impersonates the service account named by the supplied object's
+// meta.AnnotationKeyImpersonateServiceAccount annotation. The service account
+// must be in the namespace of the supplied object, so that an object may not
+// impersonate an identity belonging to another tenant. Nothing is
+// impersonated on behalf of cluster scoped objects, or objects without the
+// annotation.
+func ImpersonateAnnotatedServiceAccount() ImpersonationFn {
+	return func(o metav1.Object) (rest.ImpersonationConfig, bool) {
+		name := meta.GetImpersonatedServiceAccount(o)
+		if name == "" || o.GetNamespace() == "" {
+			return rest.ImpersonationConfig{}, false
+		}
+		return rest.ImpersonationConfig{UserName: ServiceAccountUser(o.GetNamespace(), name)}, true
+	}
+}
+
+// FirstImpersonation returns an ImpersonationFn that returns the identity
+// returned by the first of the supplied ImpersonationFns to return one.
+func FirstImpersonation(fns ...ImpersonationFn) ImpersonationFn {
+	return func(o metav1.Object) (rest.ImpersonationConfig, bool) {
+		for _, fn := range fns {
+			if ic, ok := fn(o); ok {
+				return ic, true
+			}
+		}
+		return rest.ImpersonationConfig{}, false
+	}
+}
+
+type impersonationKey struct{}
+
+// WithImpersonation returns a copy of the supplied context that causes an
+// ImpersonatingClient to impersonate the supplied identity.
+func WithImpersonation(ctx context.Context, ic rest.ImpersonationConfig) context.Context {
+	return context.WithValue(ctx, impersonationKey{}, ic)
+}
+
+// GetImpersonation returns the identity the supplied context should
+// impersonate, if any.
+func GetImpersonation(ctx context.Context) (rest.ImpersonationConfig, bool) {
+	ic, ok := ctx.Value(impersonationKey{}).(rest.ImpersonationConfig)
+	return ic, ok
+}
+
+// An ImpersonatingClientOption configures an ImpersonatingClient.
+type ImpersonatingClientOption func(*ImpersonatingClient)
+
+// WithImpersonatingClientFn specifies how an ImpersonatingClient should create
+// a client for the supplied impersonating REST config. By default a client
+// that uses the ImpersonatingClient's scheme is created.
+func WithImpersonatingClientFn(fn RemoteClientFn) ImpersonatingClientOption {
+	return func(c *ImpersonatingClient) {
+		c.newClient = fn
+	}
+}
+
+// An ImpersonatingClient is a client.Client that impersonates the identity,
+// if any, associated with the context of each request using
+// WithImpersonation. This allows multi-tenant deployments to enforce the RBAC
+// of each tenant on what a controller may read and write on its behalf.
+// Requests whose context has no identity are made using the wrapped client.
+// Impersonating clients are created on demand and reused for later requests
+// that impersonate the same identity. They read from the API server directly,
+// rather than from a cache.
+type ImpersonatingClient struct {
+	client.Client
+
+	config    *rest.Config
+	newClient RemoteClientFn
+
+	mu      sync.Mutex
+	clients map[string]client.Client
+}
+
+// NewImpersonatingClient returns a client.Client that makes requests using
+// the supplied client unless their context has an identity to impersonate.
+// Impersonating requests are made using the supplied REST config. Objects are
+// encoded and decoded using the supplied scheme.
+func NewImpersonatingClient(c client.Client, cfg *rest.Config, s *runtime.Scheme, o ...ImpersonatingClientOption) *ImpersonatingClient {
+	ic := &ImpersonatingClient{
+		Client: c,
+		config: cfg,
+		newClient: func(cfg *rest.Config) (client.Client, error) {
+			return client.New(cfg, client.Options{Scheme: s})
+		},
+		clients: make(map[string]client.Client),
+	}
+	for _, fn := range o {
+		fn(ic)
+	}
+	return ic
+}
+
+// clientFor returns the client that should be used to make requests with the
+// supplied context.
+func (c *ImpersonatingClient) clientFor(ctx context.Context) (client.Client, error) {
+	ic, ok := GetImpersonation(ctx)
+	if !ok {
+		return c.Client, nil
+	}
+
+	// fmt prints maps sorted by key, so equivalent identities have equal keys.
+	key := fmt.Sprintf("%#v", ic)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.clients[key]; ok {
+		return cached, nil
+	}
+
+	cfg := rest.CopyConfig(c.config)
+	cfg.Impersonate = ic
+	nc, err := c.newClient(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewImpersonatingClient)
+	}
+	c.clients[key] = nc
+	return nc, nil
+}
+
+// Get the supplied object, impersonating the context's identity if any.
+func (c *ImpersonatingClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Get(ctx, key, obj)
+}
+
+// List the supplied objects, impersonating the context's identity if any.
+func (c *ImpersonatingClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.List(ctx, list, opts...)
+}
+
+// Create the supplied object, impersonating the context's identity if any.
+func (c *ImpersonatingClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Create(ctx, obj, opts...)
+}
+
+// Delete the supplied object, impersonating the context's identity if any.
+func (c *ImpersonatingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Delete(ctx, obj, opts...)
+}
+
+// Update the supplied object, impersonating the context's identity if any.
+func (c *ImpersonatingClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Update(ctx, obj, opts...)
+}
+
+// Patch the supplied object, impersonating the context's identity if any.
+func (c *ImpersonatingClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Patch(ctx, obj, patch, opts...)
+}
+
+// DeleteAllOf the supplied kind of object, impersonating the context's
+// identity if any.
+func (c *ImpersonatingClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	ic, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Status returns a client.StatusWriter that impersonates the identity, if
+// any, associated with the context of each request.
+func (c *ImpersonatingClient) Status() client.StatusWriter {
+	return &impersonatingStatusWriter{client: c}
+}
+
+type impersonatingStatusWriter struct {
+	client *ImpersonatingClient
+}
+
+func (w *impersonatingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	ic, err := w.client.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Status().Update(ctx, obj, opts...)
+}
+
+func (w *impersonatingStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ic, err := w.client.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return ic.Status().Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestImpersonationFns(t *testing.T) {
+	annotated := func(ns string) metav1.Object {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   ns,
+			Annotations: map[string]string{meta.AnnotationKeyImpersonateServiceAccount: "annotated"},
+		}}
+	}
+
+	type want struct {
+		ic rest.ImpersonationConfig
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		fn     ImpersonationFn
+		o      metav1.Object
+		want   want
+	}{
+		"ServiceAccountInNamespace": {
+			reason: "The named service account should be impersonated in the object's namespace.",
+			fn:     ImpersonateServiceAccount("tenant"),
+			o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns"}},
+			want:   want{ic: rest.ImpersonationConfig{UserName: "system:serviceaccount:coolns:tenant"}, ok: true},
+		},
+		"ServiceAccountClusterScoped": {
+			reason: "Nothing should be impersonated on behalf of cluster scoped objects.",
+			fn:     ImpersonateServiceAccount("tenant"),
+			o:      &corev1.Pod{},
+			want:   want{ok: false},
+		},
+		"AnnotatedServiceAccount": {
+			reason: "The annotated service account should be impersonated in the object's namespace.",
+			fn:     ImpersonateAnnotatedServiceAccount(),
+			o:      annotated("coolns"),
+			want:   want{ic: rest.ImpersonationConfig{UserName: "system:serviceaccount:coolns:annotated"}, ok: true},
+		},
+		"AnnotatedServiceAccountClusterScoped": {
+			reason: "An annotated cluster scoped object should not be able to impersonate a service account.",
+			fn:     ImpersonateAnnotatedServiceAccount(),
+			o:      annotated(""),
+			want:   want{ok: false},
+		},
+		"NotAnnotated": {
+			reason: "Nothing should be impersonated on behalf of objects without the annotation.",
+			fn:     ImpersonateAnnotatedServiceAccount(),
+			o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns"}},
+			want:   want{ok: false},
+		},
+		"FirstImpersonation": {
+			reason: "The first identity returned should be impersonated.",
+			fn:     FirstImpersonation(ImpersonateAnnotatedServiceAccount(), ImpersonateServiceAccount("tenant")),
+			o:      &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "coolns"}},
+			want:   want{ic: rest.ImpersonationConfig{UserName: "system:serviceaccount:coolns:tenant"}, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ic, ok := tc.fn(tc.o)
+			if diff := cmp.Diff(tc.want.ic, ic); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nfn(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImpersonatingClient(t *testing.T) {
+	errBoom := errors.New("boom")
+	tenant := rest.ImpersonationConfig{UserName: ServiceAccountUser("coolns", "tenant")}
+
+	type args struct {
+		ctx       context.Context
+		newClient func(created *int) RemoteClientFn
+	}
+	type want struct {
+		err     error
+		created int
+		by      string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoImpersonation": {
+			reason: "Requests whose context has no identity should be made using the wrapped client.",
+			args: args{
+				ctx: context.Background(),
+				newClient: func(created *int) RemoteClientFn {
+					return func(_ *rest.Config) (client.Client, error) {
+						*created++
+						return nil, errBoom
+					}
+				},
+			},
+			want: want{by: "default"},
+		},
+		"NewClientError": {
+			reason: "Errors creating an impersonating client should be returned, and the client should not be cached.",
+			args: args{
+				ctx: WithImpersonation(context.Background(), tenant),
+				newClient: func(created *int) RemoteClientFn {
+					return func(_ *rest.Config) (client.Client, error) {
+						*created++
+						return nil, errBoom
+					}
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errNewImpersonatingClient), created: 2},
+		},
+		"Impersonation": {
+			reason: "Requests whose context has an identity should be made using a reused client that impersonates it.",
+			args: args{
+				ctx: WithImpersonation(context.Background(), tenant),
+				newClient: func(created *int) RemoteClientFn {
+					return func(cfg *rest.Config) (client.Client, error) {
+						*created++
+						if diff := cmp.Diff(tenant, cfg.Impersonate); diff != "" {
+							t.Errorf("newClient(...): -want impersonation, +got impersonation:\n%s", diff)
+						}
+						return &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+							obj.(*corev1.Pod).SetName(cfg.Impersonate.UserName)
+							return nil
+						}}, nil
+					}
+				},
+			},
+			want: want{created: 1, by: tenant.UserName},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			created := 0
+			wrapped := &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				obj.(*corev1.Pod).SetName("default")
+				return nil
+			}}
+			c := NewImpersonatingClient(wrapped, &rest.Config{Host: "https://example.org"}, runtime.NewScheme(), WithImpersonatingClientFn(tc.args.newClient(&created)))
+
+			// Make two requests to ensure impersonating clients are reused.
+			var err error
+			got := &corev1.Pod{}
+			for i := 0; i < 2; i++ {
+				err = c.Get(tc.args.ctx, client.ObjectKey{Name: "cool"}, got)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.by, got.GetName()); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want client, +got client:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, created); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want clients created, +got clients created:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -76,6 +76,13 @@ type ConnectionSecretWriterTo interface {
 	GetWriteConnectionSecretToReference() *v1alpha1.SecretReference
 }
 
+// A ConnectionDetailsPublisherTo may publish its connection details to a
+// secret store.
+type ConnectionDetailsPublisherTo interface {
+	SetPublishConnectionDetailsTo(r *v1alpha1.PublishConnectionDetailsTo)
+	GetPublishConnectionDetailsTo() *v1alpha1.PublishConnectionDetailsTo
+}
+
 // A Reclaimer may specify a ReclaimPolicy.
 type Reclaimer interface {
 	SetReclaimPolicy(p v1alpha1.ReclaimPolicy)
@@ -95,6 +102,48 @@ type ProviderReferencer interface {
 	SetProviderReference(p *corev1.ObjectReference)
 }
 
+// A ProviderConfigReferencer may reference a provider config resource.
+type ProviderConfigReferencer interface {
+	GetProviderConfigReference() *v1alpha1.Reference
+	SetProviderConfigReference(p *v1alpha1.Reference)
+}
+
+// A RequiredProviderReferencer must reference a provider resource.
+type RequiredProviderReferencer interface {
+	GetProviderReference() v1alpha1.Reference
+	SetProviderReference(p v1alpha1.Reference)
+}
+
+// A RequiredTypedResourceReferencer must reference a resource by kind.
+type RequiredTypedResourceReferencer interface {
+	GetResourceReference() v1alpha1.TypedReference
+	SetResourceReference(r v1alpha1.TypedReference)
+}
+
+// A CompositionSelector may select a composition.
+type CompositionSelector interface {
+	SetCompositionSelector(s *metav1.LabelSelector)
+	GetCompositionSelector() *metav1.LabelSelector
+}
+
+// A CompositionReferencer may reference a composition.
+type CompositionReferencer interface {
+	SetCompositionReference(r *corev1.ObjectReference)
+	GetCompositionReference() *corev1.ObjectReference
+}
+
+// A ComposedResourcesReferencer may reference the resources it composes.
+type ComposedResourcesReferencer interface {
+	SetResourceReferences(r []corev1.ObjectReference)
+	GetResourceReferences() []corev1.ObjectReference
+}
+
+// A CompositeResourceReferencer may reference a composite resource.
+type CompositeResourceReferencer interface {
+	SetResourceReference(r *corev1.ObjectReference)
+	GetResourceReference() *corev1.ObjectReference
+}
+
 // A WorkloadReferencer may reference an OAM workload.
 type WorkloadReferencer interface {
 	GetWorkloadReference() v1alpha1.TypedReference
@@ -122,6 +171,43 @@ type Claim interface {
 	Bindable
 }
 
+// A Composite is a Kubernetes object that composes other resources, typically
+// according to a composition.
+type Composite interface {
+	Object
+
+	CompositionSelector
+	CompositionReferencer
+	ComposedResourcesReferencer
+	ClaimReferencer
+	ConnectionSecretWriterTo
+
+	Conditioned
+}
+
+// A Composed resource is a Kubernetes object that is composed by a Composite
+// resource.
+type Composed interface {
+	Object
+
+	ConnectionSecretWriterTo
+
+	Conditioned
+}
+
+// A CompositeClaim is a Kubernetes object representing a claim for a
+// Composite resource.
+type CompositeClaim interface {
+	Object
+
+	CompositionSelector
+	CompositionReferencer
+	CompositeResourceReferencer
+	LocalConnectionSecretWriterTo
+
+	Conditioned
+}
+
 // A Class is a Kubernetes object representing configuration specifications for
 // a managed resource.
 type Class interface {
@@ -139,12 +225,21 @@ type Managed interface {
 	ClaimReferencer
 	ProviderReferencer
 	ConnectionSecretWriterTo
+	ConnectionDetailsPublisherTo
 	Reclaimer
 
 	Conditioned
 	Bindable
 }
 
+// A ManagedList is a list of managed resources.
+type ManagedList interface {
+	runtime.Object
+
+	// GetItems returns the list of managed resources.
+	GetItems() []Managed
+}
+
 // A Provider is a Kubernetes object that refers to credentials to connect
 // to an external system.
 type Provider interface {
@@ -153,6 +248,30 @@ type Provider interface {
 	CredentialsSecretReferencer
 }
 
+// A ProviderUsage indicates a usage of a provider.
+type ProviderUsage interface {
+	Object
+
+	RequiredProviderReferencer
+	RequiredTypedResourceReferencer
+}
+
+// A ProviderUsageList is a list of provider usages.
+type ProviderUsageList interface {
+	runtime.Object
+
+	// GetItems returns the list of provider usages.
+	GetItems() []ProviderUsage
+}
+
+// A StoreConfig is a Kubernetes object that configures a secret store to which
+// connection details may be published.
+type StoreConfig interface {
+	Object
+
+	GetStoreConfig() v1alpha1.SecretStoreConfig
+}
+
 // A Target is a Kubernetes object that refers to credentials to connect
 // to a deployment target. Target is a subset of the Claim interface.
 type Target interface {
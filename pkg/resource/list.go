@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// DefaultListChunkSize is the number of objects requested per page when
+// listing objects in chunks, unless a client.Limit is supplied.
+const DefaultListChunkSize = 500
+
+// Error strings.
+const (
+	errExtractChunk = "cannot extract objects from list"
+	errSetList      = "cannot set items of list"
+	errListMeta     = "cannot get list metadata"
+)
+
+// ErrStopListing may be returned by a function passed to ListEach to stop
+// listing without returning an error.
+var ErrStopListing = errors.New("stop listing")
+
+// ListEach pages through the objects matching the supplied options, calling
+// the supplied function once for each object. The supplied list is used to
+// hold each page, so at most one page of objects is held in memory at a time.
+// Objects are requested in pages of DefaultListChunkSize unless a client.Limit
+// is supplied. The function must not retain the objects it is passed without
+// copying them. Listing stops at the first error returned by the function,
+// which is returned unless it is ErrStopListing. Errors returned by the reader
+// are returned as is, as they would be by a single List call. Note that readers backed by
+// a cache ignore client.Limit, and return all objects in a single page.
+func ListEach(ctx context.Context, c client.Reader, list runtime.Object, fn func(o runtime.Object) error, opts ...client.ListOption) error {
+	opts = append([]client.ListOption{client.Limit(DefaultListChunkSize)}, opts...)
+	cont := ""
+	for {
+		if err := c.List(ctx, list, append(opts, client.Continue(cont))...); err != nil {
+			return err
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return errors.Wrap(err, errExtractChunk)
+		}
+		for _, o := range items {
+			if err := fn(o); err != nil {
+				if err == ErrStopListing {
+					return nil
+				}
+				return err
+			}
+		}
+
+		lm, err := meta.ListAccessor(list)
+		if err != nil {
+			return errors.Wrap(err, errListMeta)
+		}
+		if cont = lm.GetContinue(); cont == "" {
+			return nil
+		}
+	}
+}
+
+// ListAll pages through the objects matching the supplied options, storing
+// all of them in the supplied list. It is equivalent to a single List call,
+// except that objects are requested from the API server in pages. Prefer
+// ListEach when the objects need not all be held in memory at once.
+func ListAll(ctx context.Context, c client.Reader, list runtime.Object, opts ...client.ListOption) error {
+	all := make([]runtime.Object, 0)
+	err := ListEach(ctx, c, list, func(o runtime.Object) error {
+		all = append(all, o.DeepCopyObject())
+		return nil
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(meta.SetList(list, all), errSetList)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// pagedList returns a MockListFn that serves the supplied pages of Secrets.
+func pagedList(pages ...[]corev1.Secret) test.MockListFn {
+	return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+		lo := (&client.ListOptions{}).ApplyOptions(opts)
+		i := 0
+		if lo.Continue != "" {
+			i = int(lo.Continue[0] - '0')
+		}
+		l := obj.(*corev1.SecretList)
+		l.Items = pages[i]
+		l.Continue = ""
+		if i+1 < len(pages) {
+			l.Continue = string(rune('0' + i + 1))
+		}
+		return nil
+	}
+}
+
+func secret(name string) corev1.Secret {
+	return corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestListAll(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		items []corev1.Secret
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing a page of objects should be returned",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"SinglePage": {
+			reason: "All objects should be returned when there is only one page",
+			c:      &test.MockClient{MockList: pagedList([]corev1.Secret{secret("a"), secret("b")})},
+			want: want{
+				items: []corev1.Secret{secret("a"), secret("b")},
+			},
+		},
+		"MultiplePages": {
+			reason: "Objects from all pages should be returned",
+			c: &test.MockClient{MockList: pagedList(
+				[]corev1.Secret{secret("a")},
+				[]corev1.Secret{secret("b")},
+				[]corev1.Secret{secret("c")},
+			)},
+			want: want{
+				items: []corev1.Secret{secret("a"), secret("b"), secret("c")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l := &corev1.SecretList{}
+			err := ListAll(context.Background(), tc.c, l)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListAll(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.items, l.Items); diff != "" {
+				t.Errorf("\n%s\nListAll(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestListEachStop(t *testing.T) {
+	c := &test.MockClient{MockList: pagedList(
+		[]corev1.Secret{secret("a"), secret("b")},
+		[]corev1.Secret{secret("c")},
+	)}
+
+	seen := make([]string, 0)
+	err := ListEach(context.Background(), c, &corev1.SecretList{}, func(o runtime.Object) error {
+		seen = append(seen, o.(*corev1.Secret).GetName())
+		if len(seen) == 2 {
+			return ErrStopListing
+		}
+		return nil
+	})
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("ListEach(...): -want error, +got error:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, seen); diff != "" {
+		t.Errorf("ListEach(...): -want, +got:\n%s", diff)
+	}
+}
@@ -64,6 +64,26 @@ func AllOf(fn ...PredicateFn) PredicateFn {
 	}
 }
 
+// IsInNamespace accepts objects in any of the supplied namespaces. Objects that
+// are not namespaced are always accepted, as are all objects if no namespaces
+// are supplied.
+func IsInNamespace(namespaces ...string) PredicateFn {
+	allowed := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	return func(obj runtime.Object) bool {
+		if len(allowed) == 0 {
+			return true
+		}
+		mo, ok := obj.(metav1.Object)
+		if !ok {
+			return false
+		}
+		return mo.GetNamespace() == "" || allowed[mo.GetNamespace()]
+	}
+}
+
 // HasManagedResourceReferenceKind accepts objects that reference the supplied
 // managed resource kind.
 func HasManagedResourceReferenceKind(k ManagedKind) PredicateFn {
@@ -200,3 +220,85 @@ func HasNoManagedResourceReference() PredicateFn {
 		return cr.GetResourceReference() == nil
 	}
 }
+
+// GenerationChanged accepts updates that change an object's generation, which
+// typically indicates a change to its spec. Create, delete, and generic events
+// are always accepted.
+func GenerationChanged() predicate.Funcs {
+	return predicate.Funcs{UpdateFunc: generationChanged}
+}
+
+// AnnotationsChanged accepts updates that change any of the supplied
+// annotations. Create, delete, and generic events are always accepted.
+func AnnotationsChanged(keys ...string) predicate.Funcs {
+	return predicate.Funcs{UpdateFunc: annotationsChanged(keys...)}
+}
+
+// DeletionTimestampChanged accepts updates that change an object's deletion
+// timestamp, i.e. that request its deletion. Create, delete, and generic
+// events are always accepted.
+func DeletionTimestampChanged() predicate.Funcs {
+	return predicate.Funcs{UpdateFunc: deletionTimestampChanged}
+}
+
+// ExternalNameChanged accepts updates that change the external name of an
+// object whose external resource is already known to exist, i.e. updates that
+// would cause it to be reconciled against a different external resource.
+// Create, delete, and generic events are always accepted.
+func ExternalNameChanged() predicate.Funcs {
+	return predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.MetaOld == nil || e.MetaNew == nil {
+			return false
+		}
+		if meta.GetLastExternalName(e.MetaOld) == "" {
+			return false
+		}
+		return meta.GetExternalName(e.MetaOld) != meta.GetExternalName(e.MetaNew)
+	}}
+}
+
+// DesiredStateChanged accepts updates that change the desired state of an
+// object; i.e. that change its generation, its deletion timestamp, or any of
+// the annotations Crossplane uses to configure reconciliation. Updates that
+// change only an object's status are filtered out. Create, delete, and generic
+// events are always accepted.
+func DesiredStateChanged() predicate.Funcs {
+	ac := annotationsChanged(
+		meta.AnnotationKeyExternalName,
+		meta.AnnotationKeyReconciliationPaused,
+		meta.AnnotationKeyDryRun,
+	)
+	return predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+		return generationChanged(e) || ac(e) || deletionTimestampChanged(e)
+	}}
+}
+
+func generationChanged(e event.UpdateEvent) bool {
+	if e.MetaOld == nil || e.MetaNew == nil {
+		return false
+	}
+	return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+}
+
+func annotationsChanged(keys ...string) func(e event.UpdateEvent) bool {
+	return func(e event.UpdateEvent) bool {
+		if e.MetaOld == nil || e.MetaNew == nil {
+			return false
+		}
+		for _, k := range keys {
+			ov, ook := e.MetaOld.GetAnnotations()[k]
+			nv, nok := e.MetaNew.GetAnnotations()[k]
+			if ov != nv || ook != nok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func deletionTimestampChanged(e event.UpdateEvent) bool {
+	if e.MetaOld == nil || e.MetaNew == nil {
+		return false
+	}
+	return !e.MetaOld.GetDeletionTimestamp().Equal(e.MetaNew.GetDeletionTimestamp())
+}
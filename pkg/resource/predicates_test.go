@@ -20,13 +20,14 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -350,6 +351,47 @@ func TestIsPropagated(t *testing.T) {
 	}
 }
 
+func TestIsInNamespace(t *testing.T) {
+	cases := map[string]struct {
+		namespaces []string
+		obj        runtime.Object
+		want       bool
+	}{
+		"NoNamespaces": {
+			obj:  &fake.Claim{ObjectMeta: v1.ObjectMeta{Namespace: "coolns"}},
+			want: true,
+		},
+		"NotAnObject": {
+			namespaces: []string{"coolns"},
+			want:       false,
+		},
+		"NotNamespaced": {
+			namespaces: []string{"coolns"},
+			obj:        &fake.Managed{},
+			want:       true,
+		},
+		"InNamespace": {
+			namespaces: []string{"coolns", "otherns"},
+			obj:        &fake.Claim{ObjectMeta: v1.ObjectMeta{Namespace: "otherns"}},
+			want:       true,
+		},
+		"NotInNamespace": {
+			namespaces: []string{"coolns"},
+			obj:        &fake.Claim{ObjectMeta: v1.ObjectMeta{Namespace: "otherns"}},
+			want:       false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsInNamespace(tc.namespaces...)(tc.obj)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsInNamespace(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestHasClassSelector(t *testing.T) {
 	cases := map[string]struct {
 		obj  runtime.Object
@@ -461,3 +503,139 @@ func TestHasNoMangedResourceReference(t *testing.T) {
 		})
 	}
 }
+
+func TestDesiredStateChanged(t *testing.T) {
+	now := v1.Now()
+
+	cases := map[string]struct {
+		reason string
+		e      event.UpdateEvent
+		want   bool
+	}{
+		"NilMeta": {
+			reason: "Updates without object metadata should be filtered out.",
+			e:      event.UpdateEvent{},
+			want:   false,
+		},
+		"StatusChanged": {
+			reason: "Updates that change only an object's status should be filtered out.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Generation: 1, ResourceVersion: "1"}},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Generation: 1, ResourceVersion: "2"}},
+			},
+			want: false,
+		},
+		"IrrelevantAnnotationChanged": {
+			reason: "Updates that change only annotations Crossplane doesn't use should be filtered out.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{"cool": "true"}}},
+			},
+			want: false,
+		},
+		"GenerationChanged": {
+			reason: "Updates that change an object's generation should be accepted.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Generation: 1}},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Generation: 2}},
+			},
+			want: true,
+		},
+		"ExternalNameChanged": {
+			reason: "Updates that change an object's external name should be accepted.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyExternalName: "old"}}},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyExternalName: "new"}}},
+			},
+			want: true,
+		},
+		"PausedRemoved": {
+			reason: "Updates that remove an object's paused annotation should be accepted.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyReconciliationPaused: "true"}}},
+				MetaNew: &fake.Managed{},
+			},
+			want: true,
+		},
+		"DeletionTimestampChanged": {
+			reason: "Updates that request an object's deletion should be accepted.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{DeletionTimestamp: &now}},
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DesiredStateChanged().Update(tc.e)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDesiredStateChanged().Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	if !DesiredStateChanged().Create(event.CreateEvent{}) {
+		t.Errorf("DesiredStateChanged().Create(...): want true, got false")
+	}
+}
+
+func TestExternalNameChanged(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		e      event.UpdateEvent
+		want   bool
+	}{
+		"NilMeta": {
+			reason: "Updates without object metadata should be filtered out.",
+			e:      event.UpdateEvent{},
+			want:   false,
+		},
+		"NotYetCreated": {
+			reason: "Updates that change the external name of an object whose external resource was never observed should be filtered out.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyExternalName: "old"}}},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{meta.AnnotationKeyExternalName: "new"}}},
+			},
+			want: false,
+		},
+		"Unchanged": {
+			reason: "Updates that don't change the external name should be filtered out.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{
+					meta.AnnotationKeyExternalName:     "old",
+					meta.AnnotationKeyLastExternalName: "old",
+				}}},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Generation: 2, Annotations: map[string]string{
+					meta.AnnotationKeyExternalName:     "old",
+					meta.AnnotationKeyLastExternalName: "old",
+				}}},
+			},
+			want: false,
+		},
+		"Changed": {
+			reason: "Updates that change the external name of an object whose external resource exists should be accepted.",
+			e: event.UpdateEvent{
+				MetaOld: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{
+					meta.AnnotationKeyExternalName:     "old",
+					meta.AnnotationKeyLastExternalName: "old",
+				}}},
+				MetaNew: &fake.Managed{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{
+					meta.AnnotationKeyExternalName:     "new",
+					meta.AnnotationKeyLastExternalName: "old",
+				}}},
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ExternalNameChanged().Update(tc.e)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nExternalNameChanged().Update(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// DefaultProviderConfigName is the name of the provider config that managed
+// resources use by default, if configured to fall back to a default.
+const DefaultProviderConfigName = "default"
+
+// A MissingProviderConfigError indicates that a managed resource references
+// no provider config, and that no default provider config was configured.
+type MissingProviderConfigError struct{}
+
+func (e *MissingProviderConfigError) Error() string {
+	return "managed resource does not reference a provider config and no default provider config is configured"
+}
+
+// IsMissingProviderConfig returns true if the supplied error indicates that a
+// managed resource references no provider config.
+func IsMissingProviderConfig(err error) bool {
+	e := &MissingProviderConfigError{}
+	return errors.As(err, &e)
+}
+
+// A ProviderConfigResolver resolves the provider config used by a managed
+// resource.
+type ProviderConfigResolver struct {
+	defaultName string
+}
+
+// A ProviderConfigResolverOption configures a ProviderConfigResolver.
+type ProviderConfigResolverOption func(*ProviderConfigResolver)
+
+// WithDefaultProviderConfig configures the ProviderConfigResolver to fall back
+// to the named provider config when a managed resource references none. Use
+// DefaultProviderConfigName unless you have a reason not to.
+func WithDefaultProviderConfig(name string) ProviderConfigResolverOption {
+	return func(r *ProviderConfigResolver) {
+		r.defaultName = name
+	}
+}
+
+// NewProviderConfigResolver returns a ProviderConfigResolver. By default it
+// does not fall back to a default provider config.
+func NewProviderConfigResolver(o ...ProviderConfigResolverOption) *ProviderConfigResolver {
+	r := &ProviderConfigResolver{}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Resolve the provider config used by the supplied managed resource. The
+// managed resource's provider config reference is used if it is set. If it is
+// not, its deprecated provider reference is used, then the default provider
+// config if one is configured. The resolved reference is written back to the
+// managed resource, if it supports provider config references, so that the
+// resolution is persisted the next time the managed resource is updated. A
+// MissingProviderConfigError is returned if no provider config can be
+// resolved.
+func (r *ProviderConfigResolver) Resolve(mg Managed) (*v1alpha1.Reference, error) {
+	pcr, ok := mg.(ProviderConfigReferencer)
+	if ok && pcr.GetProviderConfigReference() != nil {
+		return pcr.GetProviderConfigReference(), nil
+	}
+
+	var ref *v1alpha1.Reference
+	switch {
+	case mg.GetProviderReference() != nil:
+		// The managed resource predates provider config references. We migrate
+		// its deprecated provider reference, which named the provider config
+		// to use.
+		ref = &v1alpha1.Reference{Name: mg.GetProviderReference().Name}
+	case r.defaultName != "":
+		ref = &v1alpha1.Reference{Name: r.defaultName}
+	default:
+		return nil, &MissingProviderConfigError{}
+	}
+
+	if ok {
+		pcr.SetProviderConfigReference(ref)
+	}
+	return ref, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestProviderConfigResolver(t *testing.T) {
+	type args struct {
+		o  []ProviderConfigResolverOption
+		mg Managed
+	}
+	type want struct {
+		ref *v1alpha1.Reference
+		mg  Managed
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ProviderConfigReference": {
+			reason: "A managed resource's provider config reference should be used if it is set.",
+			args: args{
+				o: []ProviderConfigResolverOption{WithDefaultProviderConfig(DefaultProviderConfigName)},
+				mg: &fake.Managed{
+					ProviderReferencer:       fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: "legacy"}},
+					ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &v1alpha1.Reference{Name: "cool"}},
+				},
+			},
+			want: want{
+				ref: &v1alpha1.Reference{Name: "cool"},
+				mg: &fake.Managed{
+					ProviderReferencer:       fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: "legacy"}},
+					ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &v1alpha1.Reference{Name: "cool"}},
+				},
+			},
+		},
+		"LegacyProviderReference": {
+			reason: "A managed resource's deprecated provider reference should be migrated if no provider config reference is set.",
+			args: args{
+				o: []ProviderConfigResolverOption{WithDefaultProviderConfig(DefaultProviderConfigName)},
+				mg: &fake.Managed{
+					ProviderReferencer: fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: "legacy"}},
+				},
+			},
+			want: want{
+				ref: &v1alpha1.Reference{Name: "legacy"},
+				mg: &fake.Managed{
+					ProviderReferencer:       fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: "legacy"}},
+					ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &v1alpha1.Reference{Name: "legacy"}},
+				},
+			},
+		},
+		"DefaultProviderConfig": {
+			reason: "The default provider config should be used if the managed resource references none.",
+			args: args{
+				o:  []ProviderConfigResolverOption{WithDefaultProviderConfig(DefaultProviderConfigName)},
+				mg: &fake.Managed{},
+			},
+			want: want{
+				ref: &v1alpha1.Reference{Name: DefaultProviderConfigName},
+				mg: &fake.Managed{
+					ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &v1alpha1.Reference{Name: DefaultProviderConfigName}},
+				},
+			},
+		},
+		"MissingProviderConfig": {
+			reason: "A MissingProviderConfigError should be returned if the managed resource references none and there is no default.",
+			args: args{
+				mg: &fake.Managed{},
+			},
+			want: want{
+				mg:  &fake.Managed{},
+				err: &MissingProviderConfigError{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, err := NewProviderConfigResolver(tc.args.o...).Resolve(tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, ref); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+				t.Errorf("\n%s\nr.Resolve(...): -want managed, +got managed:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsMissingProviderConfig(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"Missing": {
+			err:  errors.Wrap(&MissingProviderConfigError{}, "cannot connect"),
+			want: true,
+		},
+		"Other": {
+			err:  errors.New("boom"),
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsMissingProviderConfig(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IsMissingProviderConfig(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
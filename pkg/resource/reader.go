@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A FreshnessFn returns true if the supplied object, as read from a cache, is
+// fresh enough to be used.
+type FreshnessFn func(cached runtime.Object) bool
+
+// A CachedReaderOption configures a CachedReader.
+type CachedReaderOption func(*CachedReader)
+
+// WithFreshnessCheck specifies a function used to determine whether an object
+// read from the cache is fresh enough to be used. Objects that are not are
+// read from the live reader instead. Cached objects are always used by
+// default.
+func WithFreshnessCheck(fn FreshnessFn) CachedReaderOption {
+	return func(r *CachedReader) {
+		r.fresh = fn
+	}
+}
+
+// A CachedReader reads objects from a cache - for example a controller
+// manager's informer cache, or a SecretCache - falling back to a live reader
+// when an object is not cached or is not fresh enough to be used.
+type CachedReader struct {
+	cache client.Reader
+	live  client.Reader
+	fresh FreshnessFn
+}
+
+// NewCachedReader returns a CachedReader that reads from the supplied cache,
+// falling back to the supplied live reader.
+func NewCachedReader(cache, live client.Reader, o ...CachedReaderOption) *CachedReader {
+	r := &CachedReader{
+		cache: cache,
+		live:  live,
+		fresh: func(_ runtime.Object) bool { return true },
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Get the object identified by the supplied key from the cache if it is cached
+// and fresh, or otherwise from the live reader.
+func (r *CachedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if err := r.cache.Get(ctx, key, obj); err == nil && r.fresh(obj) {
+		return nil
+	}
+	return r.live.Get(ctx, key, obj)
+}
+
+// List objects from the cache.
+func (r *CachedReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return r.cache.List(ctx, list, opts...)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCachedReaderGet(t *testing.T) {
+	withVersion := func(rv string) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			obj.(metav1.Object).SetResourceVersion(rv)
+			return nil
+		}
+	}
+	cached := &test.MockClient{MockGet: test.NewMockGetFn(nil, withVersion("cached"))}
+	live := &test.MockClient{MockGet: test.NewMockGetFn(nil, withVersion("live"))}
+	missing := &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool"))}
+
+	cases := map[string]struct {
+		reason string
+		r      client.Reader
+		want   string
+	}{
+		"Cached": {
+			reason: "A cached object should be read from the cache",
+			r:      NewCachedReader(cached, live),
+			want:   "cached",
+		},
+		"NotCached": {
+			reason: "An object that is not cached should be read from the live reader",
+			r:      NewCachedReader(missing, live),
+			want:   "live",
+		},
+		"Stale": {
+			reason: "A cached object that is not fresh should be read from the live reader",
+			r:      NewCachedReader(cached, live, WithFreshnessCheck(func(_ runtime.Object) bool { return false })),
+			want:   "live",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &corev1.Secret{}
+			if err := tc.r.Get(context.Background(), types.NamespacedName{Name: "cool"}, s); err != nil {
+				t.Fatalf("\n%s\nr.Get(...): %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, s.GetResourceVersion()); diff != "" {
+				t.Errorf("\n%s\nr.Get(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
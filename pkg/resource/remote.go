@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errGetKubeconfig   = "cannot get kubeconfig secret"
+	errParseKubeconfig = "cannot parse kubeconfig"
+	errNewRemoteClient = "cannot create client for remote cluster"
+	errFmtNoKubeconfig = "kubeconfig secret has no data at key %q"
+	errApplyRemote     = "cannot apply object to remote cluster"
+)
+
+// kubeconfig returns the kubeconfig stored at the supplied secret key. The
+// kubeconfig is read from v1alpha1.ResourceCredentialsSecretKubeconfigKey if
+// the selector does not specify a key.
+func kubeconfig(ctx context.Context, c client.Reader, ref v1alpha1.SecretKeySelector) ([]byte, error) {
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(err, errGetKubeconfig)
+	}
+	key := ref.Key
+	if key == "" {
+		key = v1alpha1.ResourceCredentialsSecretKubeconfigKey
+	}
+	kc := s.Data[key]
+	if len(kc) == 0 {
+		return nil, errors.Errorf(errFmtNoKubeconfig, key)
+	}
+	return kc, nil
+}
+
+// RESTConfigFromSecret returns a REST config for the cluster described by the
+// kubeconfig stored at the supplied secret key. The kubeconfig is read from
+// v1alpha1.ResourceCredentialsSecretKubeconfigKey if the selector does not
+// specify a key.
+func RESTConfigFromSecret(ctx context.Context, c client.Reader, ref v1alpha1.SecretKeySelector) (*rest.Config, error) {
+	kc, err := kubeconfig(ctx, c, ref)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kc)
+	return cfg, errors.Wrap(err, errParseKubeconfig)
+}
+
+// NewRemoteClient returns a client for the cluster described by the kubeconfig
+// stored at the supplied secret key. Objects are encoded and decoded using the
+// supplied scheme.
+func NewRemoteClient(ctx context.Context, c client.Reader, ref v1alpha1.SecretKeySelector, s *runtime.Scheme) (client.Client, error) {
+	cfg, err := RESTConfigFromSecret(ctx, c, ref)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := client.New(cfg, client.Options{Scheme: s})
+	return rc, errors.Wrap(err, errNewRemoteClient)
+}
+
+// A RemoteClientFn returns a client for the cluster described by the supplied
+// REST config.
+type RemoteClientFn func(cfg *rest.Config) (client.Client, error)
+
+// A RemoteApplicatorOption configures a RemoteApplicator.
+type RemoteApplicatorOption func(*RemoteApplicator)
+
+// WithRemoteClientFn specifies how a RemoteApplicator should create a client
+// for the remote cluster. By default a client that uses the RemoteApplicator's
+// scheme is created.
+func WithRemoteClientFn(fn RemoteClientFn) RemoteApplicatorOption {
+	return func(a *RemoteApplicator) {
+		a.newClient = fn
+	}
+}
+
+// WithRemoteApplicatorFn specifies how a RemoteApplicator should apply objects
+// to the remote cluster, given a client for it. An APIPatchingApplicator is
+// used by default.
+func WithRemoteApplicatorFn(fn func(c client.Client) Applicator) RemoteApplicatorOption {
+	return func(a *RemoteApplicator) {
+		a.newApplicator = fn
+	}
+}
+
+// A RemoteApplicator applies changes to objects in a remote cluster, for
+// example a workload cluster, whose kubeconfig is stored in a secret in the
+// local cluster. The kubeconfig is read each time an object is applied, so
+// that changes to it, such as rotated credentials, take effect immediately.
+// The client for the remote cluster is reused while the kubeconfig is
+// unchanged.
+type RemoteApplicator struct {
+	local         client.Reader
+	secret        v1alpha1.SecretKeySelector
+	newClient     RemoteClientFn
+	newApplicator func(c client.Client) Applicator
+
+	mu         sync.Mutex
+	kubeconfig []byte
+	applicator Applicator
+}
+
+// NewRemoteApplicator returns an Applicator that applies changes to objects
+// in the remote cluster described by the kubeconfig stored at the supplied
+// secret key. The secret is read using the supplied local client. Objects are
+// encoded and decoded using the supplied scheme.
+func NewRemoteApplicator(local client.Reader, ref v1alpha1.SecretKeySelector, s *runtime.Scheme, o ...RemoteApplicatorOption) *RemoteApplicator {
+	a := &RemoteApplicator{
+		local:  local,
+		secret: ref,
+		newClient: func(cfg *rest.Config) (client.Client, error) {
+			return client.New(cfg, client.Options{Scheme: s})
+		},
+		newApplicator: func(c client.Client) Applicator { return NewAPIPatchingApplicator(c) },
+	}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
+}
+
+// Apply changes to the supplied object in the remote cluster.
+func (a *RemoteApplicator) Apply(ctx context.Context, o runtime.Object, ao ...ApplyOption) error {
+	kc, err := kubeconfig(ctx, a.local, a.secret)
+	if err != nil {
+		return errors.Wrap(err, errApplyRemote)
+	}
+	ra, err := a.remote(kc)
+	if err != nil {
+		return errors.Wrap(err, errApplyRemote)
+	}
+	return ra.Apply(ctx, o, ao...)
+}
+
+// remote returns an Applicator for the cluster described by the supplied
+// kubeconfig, reusing the previous one if the kubeconfig has not changed.
+func (a *RemoteApplicator) remote(kc []byte) (Applicator, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.applicator != nil && bytes.Equal(a.kubeconfig, kc) {
+		return a.applicator, nil
+	}
+
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kc)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseKubeconfig)
+	}
+	c, err := a.newClient(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewRemoteClient)
+	}
+
+	a.kubeconfig = kc
+	a.applicator = a.newApplicator(c)
+	return a.applicator, nil
+}
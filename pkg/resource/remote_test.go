@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Applicator = &RemoteApplicator{}
+
+func kubeconfigFor(server string) []byte {
+	return []byte(fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: cool
+  cluster:
+    server: %s
+contexts:
+- name: cool
+  context:
+    cluster: cool
+    user: cool
+current-context: cool
+users:
+- name: cool
+  user:
+    token: hunter2
+`, server))
+}
+
+func withSecretData(data map[string][]byte) test.MockGetFn {
+	return test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		obj.(*corev1.Secret).Data = data
+		return nil
+	})
+}
+
+func TestRESTConfigFromSecret(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := v1alpha1.SecretKeySelector{SecretReference: v1alpha1.SecretReference{Namespace: "coolns", Name: "cool"}}
+
+	type want struct {
+		host string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		ref    v1alpha1.SecretKeySelector
+		want   want
+	}{
+		"GetError": {
+			reason: "Errors getting the kubeconfig secret should be returned",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ref:    ref,
+			want:   want{err: errors.Wrap(errBoom, errGetKubeconfig)},
+		},
+		"NoKubeconfig": {
+			reason: "An error should be returned if the secret has no kubeconfig at the selected key",
+			c:      &test.MockClient{MockGet: withSecretData(map[string][]byte{"other": kubeconfigFor("https://cool.example.org")})},
+			ref:    ref,
+			want:   want{err: errors.Errorf(errFmtNoKubeconfig, v1alpha1.ResourceCredentialsSecretKubeconfigKey)},
+		},
+		"DefaultKey": {
+			reason: "The kubeconfig should be read from the default key if none is selected",
+			c:      &test.MockClient{MockGet: withSecretData(map[string][]byte{v1alpha1.ResourceCredentialsSecretKubeconfigKey: kubeconfigFor("https://cool.example.org")})},
+			ref:    ref,
+			want:   want{host: "https://cool.example.org"},
+		},
+		"SelectedKey": {
+			reason: "The kubeconfig should be read from the selected key",
+			c:      &test.MockClient{MockGet: withSecretData(map[string][]byte{"other": kubeconfigFor("https://other.example.org")})},
+			ref:    v1alpha1.SecretKeySelector{SecretReference: ref.SecretReference, Key: "other"},
+			want:   want{host: "https://other.example.org"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := RESTConfigFromSecret(context.Background(), tc.c, tc.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRESTConfigFromSecret(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			host := ""
+			if cfg != nil {
+				host = cfg.Host
+			}
+			if diff := cmp.Diff(tc.want.host, host); diff != "" {
+				t.Errorf("\n%s\nRESTConfigFromSecret(...): -want host, +got host:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemoteApplicator(t *testing.T) {
+	data := map[string][]byte{v1alpha1.ResourceCredentialsSecretKubeconfigKey: kubeconfigFor("https://cool.example.org")}
+	local := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		obj.(*corev1.Secret).Data = data
+		return nil
+	})}
+
+	hosts := make([]string, 0)
+	applied := 0
+	a := NewRemoteApplicator(local, v1alpha1.SecretKeySelector{}, runtime.NewScheme(),
+		WithRemoteClientFn(func(cfg *rest.Config) (client.Client, error) {
+			hosts = append(hosts, cfg.Host)
+			return &test.MockClient{}, nil
+		}),
+		WithRemoteApplicatorFn(func(_ client.Client) Applicator {
+			return ApplyFn(func(_ context.Context, _ runtime.Object, _ ...ApplyOption) error {
+				applied++
+				return nil
+			})
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		if err := a.Apply(context.Background(), &corev1.ConfigMap{}); err != nil {
+			t.Fatalf("a.Apply(...): %s", err)
+		}
+	}
+
+	// Rotate the kubeconfig.
+	data = map[string][]byte{v1alpha1.ResourceCredentialsSecretKubeconfigKey: kubeconfigFor("https://rotated.example.org")}
+	if err := a.Apply(context.Background(), &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("a.Apply(...): %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"https://cool.example.org", "https://rotated.example.org"}, hosts); diff != "" {
+		t.Errorf("\nA remote client should be created only when the kubeconfig changes: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(3, applied); diff != "" {
+		t.Errorf("\nEvery object should be applied to the remote cluster: -want, +got:\n%s", diff)
+	}
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"strings"
 
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +29,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
@@ -63,6 +64,19 @@ func (k ClassKind) List() schema.GroupVersionKind {
 // A ManagedKind contains the type metadata for a kind of managed.
 type ManagedKind schema.GroupVersionKind
 
+// List returns the list kind associated with a ManagedKind.
+func (k ManagedKind) List() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   k.Group,
+		Version: k.Version,
+		Kind:    k.Kind + "List",
+	}
+}
+
+// A StoreConfigKind contains the type metadata for a kind of secret store
+// config.
+type StoreConfigKind schema.GroupVersionKind
+
 // A TargetKind contains the type metadata for a kind of target resource.
 type TargetKind schema.GroupVersionKind
 
@@ -101,10 +115,64 @@ func (fn ManagedConnectionPropagatorFn) PropagateConnection(ctx context.Context,
 	return fn(ctx, o, mg)
 }
 
+// A ManagedConnectionPropagatorChain chains multiple
+// ManagedConnectionPropagators, for example to propagate connection details
+// both to a claim's connection secret and to an external secret store.
+type ManagedConnectionPropagatorChain []ManagedConnectionPropagator
+
+// PropagateConnection calls each ManagedConnectionPropagator serially. It
+// returns the first error it encounters, if any.
+func (pc ManagedConnectionPropagatorChain) PropagateConnection(ctx context.Context, o LocalConnectionSecretOwner, mg Managed) error {
+	for _, p := range pc {
+		if err := p.PropagateConnection(ctx, o, mg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A ConnectionSecretOption configures a connection secret for the supplied
+// owner.
+type ConnectionSecretOption func(owner metav1.Object, s *corev1.Secret)
+
+// WithSecretLabels adds the supplied labels to a connection secret.
+func WithSecretLabels(l map[string]string) ConnectionSecretOption {
+	return func(_ metav1.Object, s *corev1.Secret) {
+		meta.AddLabels(s, l)
+	}
+}
+
+// WithSecretAnnotations adds the supplied annotations to a connection secret.
+func WithSecretAnnotations(a map[string]string) ConnectionSecretOption {
+	return func(_ metav1.Object, s *corev1.Secret) {
+		meta.AddAnnotations(s, a)
+	}
+}
+
+// WithOwnerLabels copies the labels with the supplied keys, if they exist,
+// from the owner of a connection secret to the secret.
+func WithOwnerLabels(keys ...string) ConnectionSecretOption {
+	return func(owner metav1.Object, s *corev1.Secret) {
+		for _, k := range keys {
+			if v, ok := owner.GetLabels()[k]; ok {
+				meta.AddLabels(s, map[string]string{k: v})
+			}
+		}
+	}
+}
+
+// WithSecretType sets the type of a connection secret, for example
+// kubernetes.io/basic-auth. The type of an existing secret cannot be changed.
+func WithSecretType(t corev1.SecretType) ConnectionSecretOption {
+	return func(_ metav1.Object, s *corev1.Secret) {
+		s.Type = t
+	}
+}
+
 // LocalConnectionSecretFor creates a connection secret in the namespace of the
 // supplied LocalConnectionSecretOwner, assumed to be of the supplied kind.
-func LocalConnectionSecretFor(o LocalConnectionSecretOwner, kind schema.GroupVersionKind) *corev1.Secret {
-	return &corev1.Secret{
+func LocalConnectionSecretFor(o LocalConnectionSecretOwner, kind schema.GroupVersionKind, so ...ConnectionSecretOption) *corev1.Secret {
+	s := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       o.GetNamespace(),
 			Name:            o.GetWriteConnectionSecretToReference().Name,
@@ -113,6 +181,10 @@ func LocalConnectionSecretFor(o LocalConnectionSecretOwner, kind schema.GroupVer
 		Type: SecretTypeConnection,
 		Data: make(map[string][]byte),
 	}
+	for _, fn := range so {
+		fn(o, s)
+	}
+	return s
 }
 
 // A ConnectionSecretOwner may create and manage a connection secret in an
@@ -128,8 +200,8 @@ type ConnectionSecretOwner interface {
 // ConnectionSecretOwner, assumed to be of the supplied kind. The secret is
 // written to 'default' namespace if the ConnectionSecretOwner does not specify
 // a namespace.
-func ConnectionSecretFor(o ConnectionSecretOwner, kind schema.GroupVersionKind) *corev1.Secret {
-	return &corev1.Secret{
+func ConnectionSecretFor(o ConnectionSecretOwner, kind schema.GroupVersionKind, so ...ConnectionSecretOption) *corev1.Secret {
+	s := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       o.GetWriteConnectionSecretToReference().Namespace,
 			Name:            o.GetWriteConnectionSecretToReference().Name,
@@ -138,6 +210,25 @@ func ConnectionSecretFor(o ConnectionSecretOwner, kind schema.GroupVersionKind)
 		Type: SecretTypeConnection,
 		Data: make(map[string][]byte),
 	}
+	for _, fn := range so {
+		fn(o, s)
+	}
+	return s
+}
+
+// ConnectionConfigMapFor creates a config map for the non-sensitive connection
+// details of the supplied ConnectionSecretOwner, assumed to be of the supplied
+// kind. The config map has the same namespace and name as the owner's
+// connection secret.
+func ConnectionConfigMapFor(o ConnectionSecretOwner, kind schema.GroupVersionKind) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       o.GetWriteConnectionSecretToReference().Namespace,
+			Name:            o.GetWriteConnectionSecretToReference().Name,
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.ReferenceTo(o, kind))},
+		},
+		Data: make(map[string]string),
+	}
 }
 
 // MustCreateObject returns a new Object of the supplied kind. It panics if the
@@ -169,7 +260,8 @@ func GetKind(obj runtime.Object, ot runtime.ObjectTyper) (schema.GroupVersionKin
 
 // MustGetKind returns the GroupVersionKind of the supplied object. It panics if
 // the object is unknown to the supplied ObjectTyper, the object is unversioned,
-// or the object does not have exactly one registered kind.
+// or the object does not have exactly one registered kind. Reconcilers should
+// prefer GetKind, so that one misregistered kind cannot crash a controller.
 func MustGetKind(obj runtime.Object, ot runtime.ObjectTyper) schema.GroupVersionKind {
 	gvk, err := GetKind(obj, ot)
 	if err != nil {
@@ -325,6 +417,56 @@ func ControllersMustMatch() ApplyOption {
 	}
 }
 
+// PreserveFields copies the values at the supplied field paths from the
+// current object to the desired object, for example to preserve immutable
+// fields or fields that are populated by the API server. Paths may include
+// wildcards, per fieldpath.Paved.ExpandWildcards. Fields that do not exist in
+// the current object are left untouched in the desired object.
+func PreserveFields(paths ...string) ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		c, err := pave(current)
+		if err != nil {
+			return errors.Wrap(err, "cannot convert current object to unstructured")
+		}
+		d, err := pave(desired)
+		if err != nil {
+			return errors.Wrap(err, "cannot convert desired object to unstructured")
+		}
+
+		for _, path := range paths {
+			expanded, err := c.ExpandWildcards(path)
+			if err != nil {
+				return errors.Wrapf(err, "cannot expand field path %q", path)
+			}
+			for _, p := range expanded {
+				v, err := c.GetValue(p)
+				if err != nil {
+					return errors.Wrapf(err, "cannot get current value of field path %q", p)
+				}
+				if err := d.SetValue(p, runtime.DeepCopyJSONValue(v)); err != nil {
+					return errors.Wrapf(err, "cannot preserve value of field path %q", p)
+				}
+			}
+		}
+
+		if u, ok := desired.(runtime.Unstructured); ok {
+			u.SetUnstructuredContent(d.UnstructuredContent())
+			return nil
+		}
+		return errors.Wrap(runtime.DefaultUnstructuredConverter.FromUnstructured(d.UnstructuredContent(), desired), "cannot convert unstructured to desired object")
+	}
+}
+
+// pave returns a Paved view of the supplied object. Unstructured objects are
+// paved in place, while typed objects are converted to unstructured.
+func pave(o runtime.Object) (*fieldpath.Paved, error) {
+	if u, ok := o.(runtime.Unstructured); ok {
+		return fieldpath.Pave(u.UnstructuredContent()), nil
+	}
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	return fieldpath.Pave(m), err
+}
+
 // Apply changes to the supplied object. The object will be created if it does
 // not exist, or patched if it does.
 //
@@ -22,14 +22,16 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
@@ -135,6 +137,7 @@ func TestConnectionSecretFor(t *testing.T) {
 	type args struct {
 		o    ConnectionSecretOwner
 		kind schema.GroupVersionKind
+		so   []ConnectionSecretOption
 	}
 
 	controller := true
@@ -143,6 +146,43 @@ func TestConnectionSecretFor(t *testing.T) {
 		args args
 		want *corev1.Secret
 	}{
+		"WithOptions": {
+			args: args{
+				o: &MockOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      name,
+						UID:       uid,
+						Labels:    map[string]string{"team": "cool", "ignored": "yes"},
+					},
+					Ref: &v1alpha1.SecretReference{Namespace: namespace, Name: secretName},
+				},
+				kind: MockOwnerGVK,
+				so: []ConnectionSecretOption{
+					WithSecretLabels(map[string]string{"app": "cool"}),
+					WithSecretAnnotations(map[string]string{"cool": "very"}),
+					WithOwnerLabels("team", "missing"),
+					WithSecretType(corev1.SecretTypeBasicAuth),
+				},
+			},
+			want: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   namespace,
+					Name:        secretName,
+					Labels:      map[string]string{"app": "cool", "team": "cool"},
+					Annotations: map[string]string{"cool": "very"},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: MockOwnerGVK.GroupVersion().String(),
+						Kind:       MockOwnerGVK.Kind,
+						Name:       name,
+						UID:        uid,
+						Controller: &controller,
+					}},
+				},
+				Type: corev1.SecretTypeBasicAuth,
+				Data: map[string][]byte{},
+			},
+		},
 		"Success": {
 			args: args{
 				o: &MockOwner{
@@ -174,7 +214,7 @@ func TestConnectionSecretFor(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := ConnectionSecretFor(tc.args.o, tc.args.kind)
+			got := ConnectionSecretFor(tc.args.o, tc.args.kind, tc.args.so...)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("ConnectionSecretFor(): -want, +got:\n%s", diff)
 			}
@@ -182,6 +222,58 @@ func TestConnectionSecretFor(t *testing.T) {
 	}
 }
 
+func TestConnectionConfigMapFor(t *testing.T) {
+	configMapName := "coolconfigmap"
+
+	type args struct {
+		o    ConnectionSecretOwner
+		kind schema.GroupVersionKind
+	}
+
+	controller := true
+
+	cases := map[string]struct {
+		args args
+		want *corev1.ConfigMap
+	}{
+		"Success": {
+			args: args{
+				o: &MockOwner{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace,
+						Name:      name,
+						UID:       uid,
+					},
+					Ref: &v1alpha1.SecretReference{Namespace: namespace, Name: configMapName},
+				},
+				kind: MockOwnerGVK,
+			},
+			want: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      configMapName,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: MockOwnerGVK.GroupVersion().String(),
+						Kind:       MockOwnerGVK.Kind,
+						Name:       name,
+						UID:        uid,
+						Controller: &controller,
+					}},
+				},
+				Data: map[string]string{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ConnectionConfigMapFor(tc.args.o, tc.args.kind)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ConnectionConfigMapFor(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 type MockTyper struct {
 	GVKs        []schema.GroupVersionKind
 	Unversioned bool
@@ -332,6 +424,50 @@ func TestIgnore(t *testing.T) {
 	}
 }
 
+func TestManagedConnectionPropagatorChain(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		pc     ManagedConnectionPropagatorChain
+		want   error
+	}{
+		"EmptyChain": {
+			reason: "An empty chain should do nothing.",
+			pc:     ManagedConnectionPropagatorChain{},
+			want:   nil,
+		},
+		"SuccessfulPropagator": {
+			reason: "A chain of successful propagators should succeed.",
+			pc: ManagedConnectionPropagatorChain{
+				ManagedConnectionPropagatorFn(func(_ context.Context, _ LocalConnectionSecretOwner, _ Managed) error { return nil }),
+				ManagedConnectionPropagatorFn(func(_ context.Context, _ LocalConnectionSecretOwner, _ Managed) error { return nil }),
+			},
+			want: nil,
+		},
+		"PropagatorReturnsError": {
+			reason: "The first error returned by a propagator should be returned, and subsequent propagators not called.",
+			pc: ManagedConnectionPropagatorChain{
+				ManagedConnectionPropagatorFn(func(_ context.Context, _ LocalConnectionSecretOwner, _ Managed) error { return errBoom }),
+				ManagedConnectionPropagatorFn(func(_ context.Context, _ LocalConnectionSecretOwner, _ Managed) error {
+					t.Errorf("PropagateConnection(...) called unexpectedly")
+					return nil
+				}),
+			},
+			want: errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.pc.PropagateConnection(context.Background(), &fake.Claim{}, &fake.Managed{})
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntc.pc.PropagateConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestResolveClassClaimValues(t *testing.T) {
 	type args struct {
 		classValue string
@@ -518,6 +654,104 @@ func TestControllersMustMatch(t *testing.T) {
 	}
 }
 
+func TestPreserveFields(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		current runtime.Object
+		desired runtime.Object
+	}
+
+	type want struct {
+		desired runtime.Object
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		paths  []string
+		args   args
+		want   want
+	}{
+		"Typed": {
+			reason: "Fields of typed objects should be preserved, while other fields are left untouched.",
+			paths:  []string{"spec.clusterIP", "spec.healthCheckNodePort", "spec.sessionAffinity"},
+			args: args{
+				current: &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "10.0.0.1", HealthCheckNodePort: 30000, Type: corev1.ServiceTypeClusterIP}},
+				desired: &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, SessionAffinity: corev1.ServiceAffinityClientIP}},
+			},
+			want: want{
+				desired: &corev1.Service{Spec: corev1.ServiceSpec{
+					ClusterIP:           "10.0.0.1",
+					HealthCheckNodePort: 30000,
+					Type:                corev1.ServiceTypeNodePort,
+					SessionAffinity:     corev1.ServiceAffinityClientIP,
+				}},
+			},
+		},
+		"UnstructuredWildcard": {
+			reason: "Fields of unstructured objects should be preserved, including those matched by wildcards.",
+			paths:  []string{"spec.ports[*].nodePort"},
+			args: args{
+				current: &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"ports": []interface{}{
+							map[string]interface{}{"port": int64(80), "nodePort": int64(30080)},
+							map[string]interface{}{"port": int64(443), "nodePort": int64(30443)},
+						},
+					},
+				}},
+				desired: &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"ports": []interface{}{
+							map[string]interface{}{"port": int64(80)},
+							map[string]interface{}{"port": int64(443)},
+						},
+					},
+				}},
+			},
+			want: want{
+				// Preserved values are set as JSON values, so numbers become
+				// float64s.
+				desired: &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"ports": []interface{}{
+							map[string]interface{}{"port": int64(80), "nodePort": float64(30080)},
+							map[string]interface{}{"port": int64(443), "nodePort": float64(30443)},
+						},
+					},
+				}},
+			},
+		},
+		"InvalidPath": {
+			reason: "Errors expanding a field path should be returned.",
+			paths:  []string{"spec["},
+			args: args{
+				current: &unstructured.Unstructured{Object: map[string]interface{}{}},
+				desired: &unstructured.Unstructured{Object: map[string]interface{}{}},
+			},
+			want: want{
+				desired: &unstructured.Unstructured{Object: map[string]interface{}{}},
+				err: func() error {
+					_, err := fieldpath.Pave(map[string]interface{}{}).ExpandWildcards("spec[")
+					return errors.Wrapf(err, "cannot expand field path %q", "spec[")
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PreserveFields(tc.paths...)(tc.args.ctx, tc.args.current, tc.args.desired)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPreserveFields(...)(...): -want error, +got error\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.desired, tc.args.desired); diff != "" {
+				t.Errorf("\n%s\nPreserveFields(...)(...): -want, +got\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestMustBeControllableBy(t *testing.T) {
 	uid := types.UID("very-unique-string")
 	controller := true
@@ -0,0 +1,194 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// DefaultStatusWindow is the default window within which a
+// DebouncedStatusWriter coalesces status updates to the same object.
+const DefaultStatusWindow = 2 * time.Second
+
+// Error strings.
+const (
+	errUpdateStatus = "cannot update status"
+)
+
+// A DebouncedStatusWriterOption configures a DebouncedStatusWriter.
+type DebouncedStatusWriterOption func(*DebouncedStatusWriter)
+
+// WithStatusWindow specifies the window within which a DebouncedStatusWriter
+// coalesces status updates to the same object.
+func WithStatusWindow(d time.Duration) DebouncedStatusWriterOption {
+	return func(w *DebouncedStatusWriter) {
+		w.window = d
+	}
+}
+
+// WithStatusLogger specifies how a DebouncedStatusWriter should log errors
+// encountered while writing coalesced status updates. These errors cannot be
+// returned to the caller, because the write happens after the caller's update
+// has returned.
+func WithStatusLogger(l logging.Logger) DebouncedStatusWriterOption {
+	return func(w *DebouncedStatusWriter) {
+		w.log = l
+	}
+}
+
+type statusKey struct {
+	t   reflect.Type
+	gvk schema.GroupVersionKind
+	nn  types.NamespacedName
+}
+
+type pendingStatus struct {
+	obj  runtime.Object
+	opts []client.UpdateOption
+}
+
+// A DebouncedStatusWriter coalesces status updates to the same object. The
+// first update to an object is written immediately. Any further updates to
+// that object within the window that follows are deferred until the window
+// closes, at which point only the most recent update is written. This cuts
+// API server and etcd writes for resources whose status changes rapidly,
+// while ensuring their final status is always written.
+type DebouncedStatusWriter struct {
+	client client.StatusWriter
+	window time.Duration
+	log    logging.Logger
+
+	mu      sync.Mutex
+	windows map[statusKey]*pendingStatus
+}
+
+// NewDebouncedStatusWriter returns a DebouncedStatusWriter that writes status
+// updates using the supplied StatusWriter, typically that of a client.Client.
+func NewDebouncedStatusWriter(c client.StatusWriter, o ...DebouncedStatusWriterOption) *DebouncedStatusWriter {
+	w := &DebouncedStatusWriter{
+		client:  c,
+		window:  DefaultStatusWindow,
+		log:     logging.NewNopLogger(),
+		windows: make(map[statusKey]*pendingStatus),
+	}
+	for _, fn := range o {
+		fn(w)
+	}
+	return w
+}
+
+// Update the status of the supplied object. The update is written immediately
+// unless the object's status was updated within the current window, in which
+// case it is deferred until the window closes. Deferred updates always return
+// nil; any error encountered writing them is logged.
+func (w *DebouncedStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	m, ok := obj.(metav1.Object)
+	if !ok {
+		return w.client.Update(ctx, obj, opts...)
+	}
+	k := statusKey{
+		t:   reflect.TypeOf(obj),
+		gvk: obj.GetObjectKind().GroupVersionKind(),
+		nn:  types.NamespacedName{Namespace: m.GetNamespace(), Name: m.GetName()},
+	}
+
+	w.mu.Lock()
+	if p, open := w.windows[k]; open {
+		p.obj = obj.DeepCopyObject()
+		p.opts = opts
+		w.mu.Unlock()
+		return nil
+	}
+	p := &pendingStatus{}
+	w.windows[k] = p
+	w.mu.Unlock()
+
+	time.AfterFunc(w.window, func() {
+		if err := w.flush(context.Background(), k, p); err != nil {
+			w.log.Debug(errUpdateStatus, "error", err, "namespace", k.nn.Namespace, "name", k.nn.Name)
+		}
+	})
+
+	return w.client.Update(ctx, obj, opts...)
+}
+
+// Patch the status of the supplied object. Patches are never deferred.
+func (w *DebouncedStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Flush immediately writes any deferred status updates, closing all open
+// windows. It may be called before a process exits to avoid losing updates.
+func (w *DebouncedStatusWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	windows := make(map[statusKey]*pendingStatus, len(w.windows))
+	for k, p := range w.windows {
+		windows[k] = p
+	}
+	w.mu.Unlock()
+
+	var err error
+	for k, p := range windows {
+		if ferr := w.flush(ctx, k, p); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// flush closes the supplied window, writing its deferred update if there is
+// one. It's a no-op if the window was already closed.
+func (w *DebouncedStatusWriter) flush(ctx context.Context, k statusKey, p *pendingStatus) error {
+	w.mu.Lock()
+	if w.windows[k] != p {
+		w.mu.Unlock()
+		return nil
+	}
+	delete(w.windows, k)
+	obj, opts := p.obj, p.opts
+	w.mu.Unlock()
+
+	if obj == nil {
+		return nil
+	}
+	return errors.Wrap(w.client.Update(ctx, obj, opts...), errUpdateStatus)
+}
+
+// A StatusClient may be used to build a single 'client' whose status updates
+// are written by the supplied StatusWriter, for example a
+// DebouncedStatusWriter.
+type StatusClient struct {
+	client.Client
+	StatusWriter client.StatusWriter
+}
+
+// Status returns the StatusClient's StatusWriter.
+func (c *StatusClient) Status() client.StatusWriter {
+	return c.StatusWriter
+}
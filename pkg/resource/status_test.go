@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ client.StatusWriter = &DebouncedStatusWriter{}
+
+func TestDebouncedStatusWriter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cool := func(phase corev1.NamespacePhase) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cool"}, Status: corev1.NamespaceStatus{Phase: phase}}
+	}
+	uncool := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "uncool"}}
+
+	type want struct {
+		updateErrs []error
+		flushErr   error
+		written    []runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason  string
+		err     error
+		updates []runtime.Object
+		want    want
+	}{
+		"FirstUpdateWritten": {
+			reason:  "The first update to an object should be written immediately",
+			updates: []runtime.Object{cool(corev1.NamespaceActive)},
+			want: want{
+				updateErrs: []error{nil},
+				written:    []runtime.Object{cool(corev1.NamespaceActive)},
+			},
+		},
+		"LatestUpdateWritten": {
+			reason: "Only the latest of several updates made within a window should be written when it closes",
+			updates: []runtime.Object{
+				cool(corev1.NamespaceActive),
+				cool(corev1.NamespaceTerminating),
+				cool(corev1.NamespaceActive),
+			},
+			want: want{
+				updateErrs: []error{nil, nil, nil},
+				written:    []runtime.Object{cool(corev1.NamespaceActive), cool(corev1.NamespaceActive)},
+			},
+		},
+		"DistinctObjects": {
+			reason:  "Updates to different objects should not be coalesced",
+			updates: []runtime.Object{cool(corev1.NamespaceActive), uncool},
+			want: want{
+				updateErrs: []error{nil, nil},
+				written:    []runtime.Object{cool(corev1.NamespaceActive), uncool},
+			},
+		},
+		"Errors": {
+			reason:  "Errors writing immediate updates should be returned by Update, and errors writing deferred updates by Flush",
+			err:     errBoom,
+			updates: []runtime.Object{cool(corev1.NamespaceActive), cool(corev1.NamespaceTerminating)},
+			want: want{
+				updateErrs: []error{errBoom, nil},
+				flushErr:   errors.Wrap(errBoom, errUpdateStatus),
+				written:    []runtime.Object{cool(corev1.NamespaceActive), cool(corev1.NamespaceTerminating)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			written := make([]runtime.Object, 0)
+			c := &test.MockStatusWriter{MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+				written = append(written, obj.DeepCopyObject())
+				return tc.err
+			}}
+
+			// Use a window long enough that it will never close during the
+			// test; we close it explicitly by flushing.
+			w := NewDebouncedStatusWriter(c, WithStatusWindow(1*time.Hour))
+
+			errs := make([]error, 0, len(tc.updates))
+			for _, u := range tc.updates {
+				errs = append(errs, w.Update(context.Background(), u))
+			}
+			if diff := cmp.Diff(tc.want.updateErrs, errs, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nw.Update(...): -want errors, +got errors:\n%s", tc.reason, diff)
+			}
+
+			err := w.Flush(context.Background())
+			if diff := cmp.Diff(tc.want.flushErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nw.Flush(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.written, written); diff != "" {
+				t.Errorf("\n%s\nw.Flush(...): -want written, +got written:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// Labels added to a composite resource to indicate which claim it is bound to.
+const (
+	LabelKeyClaimName      = "crossplane.io/claim-name"
+	LabelKeyClaimNamespace = "crossplane.io/claim-namespace"
+)
+
+// Error strings.
+const (
+	errNotObject     = "spec is not an object"
+	errClaimSpec     = "cannot propagate claim spec to composite resource"
+	errCompositeSpec = "cannot propagate composite resource spec to claim"
+)
+
+// A FieldFilter returns true if the supplied top-level spec field should be
+// propagated.
+type FieldFilter func(field string) bool
+
+// ExceptFields returns a FieldFilter that accepts all but the supplied fields.
+func ExceptFields(fields ...string) FieldFilter {
+	except := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		except[f] = true
+	}
+	return func(field string) bool { return !except[field] }
+}
+
+// OnlyFields returns a FieldFilter that accepts only the supplied fields.
+func OnlyFields(fields ...string) FieldFilter {
+	only := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		only[f] = true
+	}
+	return func(field string) bool { return only[field] }
+}
+
+// The spec fields that describe a claim's relationship with its composite
+// resource, or vice versa, rather than the desired state of the composite
+// resource. They are not propagated by default.
+var (
+	claimOnlyFields     = []string{"resourceRef", "writeConnectionSecretToRef"}
+	compositeOnlyFields = []string{"resourceRefs", "claimRef", "writeConnectionSecretToRef"}
+)
+
+// A Propagator propagates user-editable spec fields, the external name, and
+// selected metadata between a namespaced composite resource claim and its
+// cluster scoped composite resource.
+type Propagator struct {
+	toComposite FieldFilter
+	toClaim     FieldFilter
+	labels      []string
+	annotations []string
+}
+
+// A PropagatorOption configures a Propagator.
+type PropagatorOption func(*Propagator)
+
+// WithCompositeFieldFilter specifies which top-level spec fields of a claim
+// should be propagated to its composite resource. Fields that describe the
+// claim's relationship with its composite resource are never propagated.
+func WithCompositeFieldFilter(f FieldFilter) PropagatorOption {
+	return func(p *Propagator) {
+		p.toComposite = f
+	}
+}
+
+// WithClaimFieldFilter specifies which top-level spec fields of a composite
+// resource should be propagated to its claim. Fields that describe the
+// composite resource's relationship with its claim are never propagated.
+func WithClaimFieldFilter(f FieldFilter) PropagatorOption {
+	return func(p *Propagator) {
+		p.toClaim = f
+	}
+}
+
+// WithPropagatedLabels specifies labels that should be propagated, if set.
+func WithPropagatedLabels(keys ...string) PropagatorOption {
+	return func(p *Propagator) {
+		p.labels = append(p.labels, keys...)
+	}
+}
+
+// WithPropagatedAnnotations specifies annotations that should be propagated,
+// if set. The external name annotation is always propagated.
+func WithPropagatedAnnotations(keys ...string) PropagatorOption {
+	return func(p *Propagator) {
+		p.annotations = append(p.annotations, keys...)
+	}
+}
+
+// NewPropagator returns a Propagator that propagates all spec fields except
+// those that describe the relationship between a claim and its composite
+// resource.
+func NewPropagator(o ...PropagatorOption) *Propagator {
+	p := &Propagator{
+		toComposite: func(string) bool { return true },
+		toClaim:     func(string) bool { return true },
+	}
+	for _, fn := range o {
+		fn(p)
+	}
+	return p
+}
+
+// ToComposite propagates the supplied claim to the supplied composite
+// resource. The composite resource is labelled with, and references, the
+// claim it is bound to.
+func (p *Propagator) ToComposite(cm *Unstructured, cp *composite.Unstructured) error {
+	if err := copySpec(cm.Object, cp.Object, p.toComposite, claimOnlyFields); err != nil {
+		return errors.Wrap(err, errClaimSpec)
+	}
+	p.copyMeta(cm, cp)
+	meta.AddLabels(cp, map[string]string{
+		LabelKeyClaimName:      cm.GetName(),
+		LabelKeyClaimNamespace: cm.GetNamespace(),
+	})
+	cp.SetClaimReference(meta.ReferenceTo(cm, cm.GroupVersionKind()))
+	return nil
+}
+
+// ToClaim propagates the supplied composite resource to the supplied claim,
+// for example to reflect an external name or spec field that was late
+// initialized. The claim references the composite resource it is bound to.
+func (p *Propagator) ToClaim(cp *composite.Unstructured, cm *Unstructured) error {
+	if err := copySpec(cp.Object, cm.Object, p.toClaim, compositeOnlyFields); err != nil {
+		return errors.Wrap(err, errCompositeSpec)
+	}
+	p.copyMeta(cp, cm)
+	cm.SetResourceReference(meta.ReferenceTo(cp, cp.GroupVersionKind()))
+	return nil
+}
+
+func (p *Propagator) copyMeta(from, to metav1.Object) {
+	if en := meta.GetExternalName(from); en != "" {
+		meta.SetExternalName(to, en)
+	}
+	if a := selected(from.GetAnnotations(), p.annotations); len(a) > 0 {
+		meta.AddAnnotations(to, a)
+	}
+	if l := selected(from.GetLabels(), p.labels); len(l) > 0 {
+		meta.AddLabels(to, l)
+	}
+}
+
+// selected returns the subset of the supplied map with the supplied keys.
+func selected(m map[string]string, keys []string) map[string]string {
+	out := make(map[string]string)
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// copySpec copies the top-level spec fields of from that are accepted by the
+// supplied filter, and are not excluded, to the spec of to.
+func copySpec(from, to map[string]interface{}, accept FieldFilter, exclude []string) error {
+	fs, ok := from["spec"]
+	if !ok {
+		return nil
+	}
+	fspec, ok := fs.(map[string]interface{})
+	if !ok {
+		return errors.New(errNotObject)
+	}
+
+	if _, ok := to["spec"]; !ok {
+		to["spec"] = make(map[string]interface{})
+	}
+	tspec, ok := to["spec"].(map[string]interface{})
+	if !ok {
+		return errors.New(errNotObject)
+	}
+
+	excluded := ExceptFields(exclude...)
+	for k, v := range fspec {
+		if !excluded(k) || !accept(k) {
+			continue
+		}
+		tspec[k] = runtime.DeepCopyJSONValue(v)
+	}
+	return nil
+}
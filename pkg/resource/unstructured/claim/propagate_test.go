@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestToComposite(t *testing.T) {
+	type args struct {
+		cm *Unstructured
+		cp *composite.Unstructured
+	}
+	type want struct {
+		cp  *composite.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []PropagatorOption
+		args   args
+		want   want
+	}{
+		"SpecNotAnObject": {
+			reason: "An error should be returned if the claim's spec is not an object.",
+			args: args{
+				cm: &Unstructured{unstructured.Unstructured{Object: map[string]interface{}{"spec": "wat"}}},
+				cp: composite.New(),
+			},
+			want: want{
+				cp:  composite.New(),
+				err: errors.Wrap(errors.New(errNotObject), errClaimSpec),
+			},
+		},
+		"Propagated": {
+			reason: "User-editable spec fields, the external name, and selected metadata should be propagated.",
+			o: []PropagatorOption{
+				WithCompositeFieldFilter(ExceptFields("secret")),
+				WithPropagatedLabels("cool"),
+				WithPropagatedAnnotations("cooler"),
+			},
+			args: args{
+				cm: &Unstructured{unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "example.org/v1",
+					"kind":       "Claim",
+					"metadata": map[string]interface{}{
+						"namespace": "ns",
+						"name":      "cm",
+						"uid":       "cm-uid",
+						"labels":    map[string]interface{}{"cool": "very", "boring": "yes"},
+						"annotations": map[string]interface{}{
+							"crossplane.io/external-name": "ext",
+							"cooler":                      "very",
+						},
+					},
+					"spec": map[string]interface{}{
+						"coolness":                   "very",
+						"secret":                     "shh",
+						"resourceRef":                map[string]interface{}{"name": "cp"},
+						"writeConnectionSecretToRef": map[string]interface{}{"name": "s"},
+					},
+				}}},
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"resourceRefs": []interface{}{},
+					},
+				}}},
+			},
+			want: want{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{
+							"cool":                 "very",
+							LabelKeyClaimName:      "cm",
+							LabelKeyClaimNamespace: "ns",
+						},
+						"annotations": map[string]interface{}{
+							"crossplane.io/external-name": "ext",
+							"cooler":                      "very",
+						},
+					},
+					"spec": map[string]interface{}{
+						"coolness":     "very",
+						"resourceRefs": []interface{}{},
+						"claimRef": map[string]interface{}{
+							"apiVersion": "example.org/v1",
+							"kind":       "Claim",
+							"namespace":  "ns",
+							"name":       "cm",
+							"uid":        "cm-uid",
+						},
+					},
+				}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewPropagator(tc.o...).ToComposite(tc.args.cm, tc.args.cp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nToComposite(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nToComposite(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestToClaim(t *testing.T) {
+	type args struct {
+		cp *composite.Unstructured
+		cm *Unstructured
+	}
+	type want struct {
+		cm  *Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []PropagatorOption
+		args   args
+		want   want
+	}{
+		"SpecNotAnObject": {
+			reason: "An error should be returned if the claim's spec is not an object.",
+			args: args{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{},
+				}}},
+				cm: &Unstructured{unstructured.Unstructured{Object: map[string]interface{}{"spec": "wat"}}},
+			},
+			want: want{
+				cm:  &Unstructured{unstructured.Unstructured{Object: map[string]interface{}{"spec": "wat"}}},
+				err: errors.Wrap(errors.New(errNotObject), errCompositeSpec),
+			},
+		},
+		"Propagated": {
+			reason: "Late initialized spec fields and the external name should be propagated.",
+			o: []PropagatorOption{
+				WithClaimFieldFilter(OnlyFields("coolness")),
+			},
+			args: args{
+				cp: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "example.org/v1",
+					"kind":       "Composite",
+					"metadata": map[string]interface{}{
+						"name":        "cp",
+						"uid":         "cp-uid",
+						"annotations": map[string]interface{}{"crossplane.io/external-name": "ext"},
+					},
+					"spec": map[string]interface{}{
+						"coolness":     "very",
+						"region":       "us-west",
+						"claimRef":     map[string]interface{}{"name": "cm"},
+						"resourceRefs": []interface{}{},
+					},
+				}}},
+				cm: New(),
+			},
+			want: want{
+				cm: &Unstructured{unstructured.Unstructured{Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]interface{}{"crossplane.io/external-name": "ext"},
+					},
+					"spec": map[string]interface{}{
+						"coolness": "very",
+						"resourceRef": map[string]interface{}{
+							"apiVersion": "example.org/v1",
+							"kind":       "Composite",
+							"name":       "cp",
+							"uid":        "cp-uid",
+						},
+					},
+				}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewPropagator(tc.o...).ToClaim(tc.args.cp, tc.args.cm)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nToClaim(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cm, tc.args.cm); diff != "" {
+				t.Errorf("\n%s\nToClaim(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
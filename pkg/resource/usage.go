@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+// Error strings.
+const (
+	errMissingProviderRef = "managed resource does not reference a provider"
+	errApplyUsage         = "cannot apply provider usage"
+)
+
+// A Tracker tracks managed resources.
+type Tracker interface {
+	// Track the supplied managed resource.
+	Track(ctx context.Context, mg Managed) error
+}
+
+// A TrackerFn is a function that tracks managed resources.
+type TrackerFn func(ctx context.Context, mg Managed) error
+
+// Track the supplied managed resource.
+func (fn TrackerFn) Track(ctx context.Context, mg Managed) error {
+	return fn(ctx, mg)
+}
+
+// A ProviderUsageTracker tracks usages of a provider by creating or updating
+// the appropriate ProviderUsage.
+type ProviderUsageTracker struct {
+	c  Applicator
+	t  runtime.ObjectTyper
+	of ProviderUsage
+}
+
+// NewProviderUsageTracker returns a ProviderUsageTracker that tracks usages of
+// a provider by creating or updating provider usages of the supplied kind.
+func NewProviderUsageTracker(c client.Client, t runtime.ObjectTyper, of ProviderUsage) *ProviderUsageTracker {
+	return &ProviderUsageTracker{c: NewAPIUpdatingApplicator(c), t: t, of: of}
+}
+
+// Track that the supplied managed resource is using the provider it
+// references by creating or updating a ProviderUsage. Track should be called
+// _before_ attempting to use the provider. This ensures the managed resource's
+// usage is updated if the managed resource is updated to reference a
+// different provider. The usage is named after, and controlled by, the
+// managed resource, so it is garbage collected when the managed resource is
+// deleted.
+func (u *ProviderUsageTracker) Track(ctx context.Context, mg Managed) error {
+	name := providerName(mg)
+	if name == "" {
+		return errors.New(errMissingProviderRef)
+	}
+
+	gvk, err := GetKind(mg, u.t)
+	if err != nil {
+		return errors.Wrap(err, errApplyUsage)
+	}
+
+	pu := u.of.DeepCopyObject().(ProviderUsage)
+	pu.SetName(string(mg.GetUID()))
+	pu.SetLabels(map[string]string{v1alpha1.LabelKeyProviderName: name})
+	pu.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(meta.ReferenceTo(mg, gvk))})
+	pu.SetProviderReference(v1alpha1.Reference{Name: name})
+	pu.SetResourceReference(v1alpha1.TypedReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       mg.GetName(),
+		UID:        mg.GetUID(),
+	})
+
+	return errors.Wrap(u.c.Apply(ctx, pu, MustBeControllableBy(mg.GetUID())), errApplyUsage)
+}
+
+// providerName returns the name of the provider config referenced by the
+// supplied managed resource, falling back to its deprecated provider
+// reference.
+func providerName(mg Managed) string {
+	if pcr, ok := mg.(ProviderConfigReferencer); ok && pcr.GetProviderConfigReference() != nil {
+		return pcr.GetProviderConfigReference().Name
+	}
+	if mg.GetProviderReference() != nil {
+		return mg.GetProviderReference().Name
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Tracker = &ProviderUsageTracker{}
+var _ Tracker = TrackerFn(func(_ context.Context, _ Managed) error { return nil })
+
+func TestTrack(t *testing.T) {
+	errBoom := errors.New("boom")
+	name := "provisional"
+	uid := types.UID("so-unique")
+	provider := "cool-provider"
+
+	mg := &fake.Managed{
+		ObjectMeta:         metav1.ObjectMeta{Name: name, UID: uid},
+		ProviderReferencer: fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: provider}},
+	}
+	gvk := fake.GVK(mg)
+	ctrl := true
+	_, errKind := GetKind(mg, fake.SchemeWith())
+
+	type fields struct {
+		c  Applicator
+		t  runtime.ObjectTyper
+		of ProviderUsage
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields fields
+		mg     Managed
+		want   error
+	}{
+		"MissingRef": {
+			reason: "An error should be returned if the managed resource does not reference a provider",
+			fields: fields{
+				of: &fake.ProviderUsage{},
+			},
+			mg:   &fake.Managed{},
+			want: errors.New(errMissingProviderRef),
+		},
+		"ProviderConfigReference": {
+			reason: "A ProviderUsage should reference the provider config referenced by the managed resource, in preference to its deprecated provider reference",
+			fields: fields{
+				c: ApplyFn(func(c context.Context, o runtime.Object, _ ...ApplyOption) error {
+					want := v1alpha1.Reference{Name: "cool-config"}
+					if diff := cmp.Diff(want, o.(ProviderUsage).GetProviderReference()); diff != "" {
+						t.Errorf("Apply(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				t:  fake.SchemeWith(&fake.Managed{}),
+				of: &fake.ProviderUsage{},
+			},
+			mg: &fake.Managed{
+				ProviderReferencer:       fake.ProviderReferencer{Ref: &corev1.ObjectReference{Name: provider}},
+				ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &v1alpha1.Reference{Name: "cool-config"}},
+			},
+			want: nil,
+		},
+		"UnknownKind": {
+			reason: "Errors determining the kind of the managed resource should be returned",
+			fields: fields{
+				t:  fake.SchemeWith(),
+				of: &fake.ProviderUsage{},
+			},
+			mg:   mg,
+			want: errors.Wrap(errKind, errApplyUsage),
+		},
+		"ApplyError": {
+			reason: "Errors applying the ProviderUsage should be returned",
+			fields: fields{
+				c: ApplyFn(func(c context.Context, o runtime.Object, _ ...ApplyOption) error {
+					return errBoom
+				}),
+				t:  fake.SchemeWith(&fake.Managed{}),
+				of: &fake.ProviderUsage{},
+			},
+			mg:   mg,
+			want: errors.Wrap(errBoom, errApplyUsage),
+		},
+		"Success": {
+			reason: "A ProviderUsage named after and controlled by the managed resource should be applied",
+			fields: fields{
+				c: ApplyFn(func(c context.Context, o runtime.Object, _ ...ApplyOption) error {
+					want := &fake.ProviderUsage{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   string(uid),
+							Labels: map[string]string{v1alpha1.LabelKeyProviderName: provider},
+							OwnerReferences: []metav1.OwnerReference{{
+								APIVersion: gvk.GroupVersion().String(),
+								Kind:       gvk.Kind,
+								Name:       name,
+								UID:        uid,
+								Controller: &ctrl,
+							}},
+						},
+						RequiredProviderReferencer: fake.RequiredProviderReferencer{Ref: v1alpha1.Reference{Name: provider}},
+						RequiredTypedResourceReferencer: fake.RequiredTypedResourceReferencer{Ref: v1alpha1.TypedReference{
+							APIVersion: gvk.GroupVersion().String(),
+							Kind:       gvk.Kind,
+							Name:       name,
+							UID:        uid,
+						}},
+					}
+					if diff := cmp.Diff(want, o); diff != "" {
+						t.Errorf("Apply(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+				t:  fake.SchemeWith(&fake.Managed{}),
+				of: &fake.ProviderUsage{},
+			},
+			mg:   mg,
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ut := &ProviderUsageTracker{c: tc.fields.c, t: tc.fields.t, of: tc.fields.of}
+			got := ut.Track(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nut.Track(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statemetrics exports Prometheus metrics that describe the state of
+// the managed resources known to a controller manager.
+package statemetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// DefaultInterval at which managed resource state is recorded.
+const DefaultInterval = 30 * time.Second
+
+// Error strings.
+const (
+	errListManaged   = "cannot list managed resources"
+	errConvertStatus = "cannot convert managed resource status"
+)
+
+// MRStateMetrics are gauges of the number of managed resources that exist, by
+// kind, of the number that have each status of the Ready and Synced
+// conditions, and of the number that are paused or parked. They must be
+// registered with a Prometheus registry, for example controller-runtime's
+// metrics.Registry, to be exported.
+type MRStateMetrics struct {
+	Exists *prometheus.GaugeVec
+	Ready  *prometheus.GaugeVec
+	Synced *prometheus.GaugeVec
+	Paused *prometheus.GaugeVec
+	Parked *prometheus.GaugeVec
+}
+
+// NewMRStateMetrics returns a new MRStateMetrics.
+func NewMRStateMetrics() *MRStateMetrics {
+	return &MRStateMetrics{
+		Exists: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "exists",
+			Help:      "The number of managed resources that exist.",
+		}, []string{"gvk"}),
+		Ready: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "ready",
+			Help:      "The number of managed resources with each status of the Ready condition.",
+		}, []string{"gvk", "status"}),
+		Synced: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "synced",
+			Help:      "The number of managed resources with each status of the Synced condition.",
+		}, []string{"gvk", "status"}),
+		Paused: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "paused",
+			Help:      "The number of managed resources whose reconciliation is paused.",
+		}, []string{"gvk"}),
+		Parked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "crossplane",
+			Subsystem: "managed_resource",
+			Name:      "parked",
+			Help:      "The number of managed resources that are parked after exhausting their retry budget.",
+		}, []string{"gvk"}),
+	}
+}
+
+// Describe sends the descriptors of each metric to the supplied channel.
+func (m *MRStateMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.Exists.Describe(ch)
+	m.Ready.Describe(ch)
+	m.Synced.Describe(ch)
+	m.Paused.Describe(ch)
+	m.Parked.Describe(ch)
+}
+
+// Collect sends each metric to the supplied channel.
+func (m *MRStateMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.Exists.Collect(ch)
+	m.Ready.Collect(ch)
+	m.Synced.Collect(ch)
+	m.Paused.Collect(ch)
+	m.Parked.Collect(ch)
+}
+
+// A MRStateRecorder periodically records the state of a kind of managed
+// resource to MRStateMetrics.
+type MRStateRecorder struct {
+	client   client.Reader
+	metrics  *MRStateMetrics
+	kind     resource.ManagedKind
+	log      logging.Logger
+	interval time.Duration
+}
+
+// A MRStateRecorderOption configures a MRStateRecorder.
+type MRStateRecorderOption func(*MRStateRecorder)
+
+// WithLogger specifies how the MRStateRecorder should log messages.
+func WithLogger(l logging.Logger) MRStateRecorderOption {
+	return func(r *MRStateRecorder) {
+		r.log = l
+	}
+}
+
+// WithInterval specifies how frequently the MRStateRecorder should record the
+// state of managed resources.
+func WithInterval(d time.Duration) MRStateRecorderOption {
+	return func(r *MRStateRecorder) {
+		r.interval = d
+	}
+}
+
+// NewMRStateRecorder returns a MRStateRecorder that records the state of the
+// supplied kind of managed resource. The supplied client should be backed by
+// the controller manager's cache, which typically already holds the managed
+// resources being reconciled. Managed resources are read as unstructured
+// data; a metadata-only read would be cheaper but would omit the status
+// conditions that these metrics describe.
+func NewMRStateRecorder(c client.Reader, m *MRStateMetrics, of resource.ManagedKind, o ...MRStateRecorderOption) *MRStateRecorder {
+	r := &MRStateRecorder{
+		client:   c,
+		metrics:  m,
+		kind:     of,
+		log:      logging.NewNopLogger(),
+		interval: DefaultInterval,
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// status is the subset of a managed resource that we record.
+type status struct {
+	Status v1alpha1.ConditionedStatus `json:"status"`
+}
+
+// Record the current state of all managed resources of the recorder's kind.
+func (r *MRStateRecorder) Record(ctx context.Context) error {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(r.kind.List())
+	if err := r.client.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListManaged)
+	}
+
+	ready := map[corev1.ConditionStatus]float64{corev1.ConditionTrue: 0, corev1.ConditionFalse: 0, corev1.ConditionUnknown: 0}
+	synced := map[corev1.ConditionStatus]float64{corev1.ConditionTrue: 0, corev1.ConditionFalse: 0, corev1.ConditionUnknown: 0}
+	paused, parked := 0.0, 0.0
+	for i := range l.Items {
+		s := &status{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(l.Items[i].Object, s); err != nil {
+			return errors.Wrap(err, errConvertStatus)
+		}
+		ready[s.Status.GetCondition(v1alpha1.TypeReady).Status]++
+		synced[s.Status.GetCondition(v1alpha1.TypeSynced).Status]++
+		if meta.IsPaused(&l.Items[i]) {
+			paused++
+		}
+		if isParked(&l.Items[i], s) {
+			parked++
+		}
+	}
+
+	gvk := schema.GroupVersionKind(r.kind).String()
+	r.metrics.Exists.WithLabelValues(gvk).Set(float64(len(l.Items)))
+	for st, n := range ready {
+		r.metrics.Ready.WithLabelValues(gvk, string(st)).Set(n)
+	}
+	for st, n := range synced {
+		r.metrics.Synced.WithLabelValues(gvk, string(st)).Set(n)
+	}
+	r.metrics.Paused.WithLabelValues(gvk).Set(paused)
+	r.metrics.Parked.WithLabelValues(gvk).Set(parked)
+	return nil
+}
+
+// isParked returns true if the current generation of the supplied managed
+// resource was parked after exhausting its retry budget.
+func isParked(o metav1.Object, s *status) bool {
+	c := s.Status.GetCondition(v1alpha1.TypeSynced)
+	return c.Reason == v1alpha1.ReasonRetryBudgetExhausted && c.ObservedGeneration == o.GetGeneration()
+}
+
+// Start recording the state of managed resources at the recorder's interval
+// until the supplied channel is closed. Start satisfies controller-runtime's
+// manager.Runnable interface, so a MRStateRecorder may be added to a manager.
+func (r *MRStateRecorder) Start(stop <-chan struct{}) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		if err := r.Record(context.Background()); err != nil {
+			r.log.Debug("Cannot record managed resource state", "error", err)
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+		}
+	}
+}
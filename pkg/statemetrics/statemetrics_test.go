@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statemetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRecord(t *testing.T) {
+	errBoom := errors.New("boom")
+	kind := resource.ManagedKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"})
+	gvk := schema.GroupVersionKind(kind).String()
+
+	withConditions := func(conditions ...interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": conditions},
+		}}
+	}
+	condition := func(ct, status string) interface{} {
+		return map[string]interface{}{"type": ct, "status": status}
+	}
+	paused := func(u unstructured.Unstructured) unstructured.Unstructured {
+		u.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+		return u
+	}
+	parked := func(generation, observed int64) unstructured.Unstructured {
+		u := withConditions(map[string]interface{}{
+			"type":               string(v1alpha1.TypeSynced),
+			"status":             "False",
+			"reason":             string(v1alpha1.ReasonRetryBudgetExhausted),
+			"observedGeneration": observed,
+		})
+		u.SetGeneration(generation)
+		return u
+	}
+
+	type want struct {
+		err    error
+		exists float64
+		ready  map[string]float64
+		synced map[string]float64
+		paused float64
+		parked float64
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing managed resources should be returned",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListManaged),
+			},
+		},
+		"Success": {
+			reason: "Managed resources should be counted by kind and by the status of their Ready and Synced conditions",
+			c: &test.MockClient{MockList: test.NewMockListFn(nil, func(o runtime.Object) error {
+				l := o.(*unstructured.UnstructuredList)
+				if diff := cmp.Diff(kind.List(), l.GroupVersionKind()); diff != "" {
+					t.Errorf("\nList(...): -want kind, +got kind:\n%s", diff)
+				}
+				l.Items = []unstructured.Unstructured{
+					withConditions(condition("Ready", "True"), condition("Synced", "True")),
+					withConditions(condition("Ready", "False"), condition("Synced", "True")),
+					withConditions(),
+				}
+				return nil
+			})},
+			want: want{
+				exists: 3,
+				ready:  map[string]float64{"True": 1, "False": 1, "Unknown": 1},
+				synced: map[string]float64{"True": 2, "False": 0, "Unknown": 1},
+			},
+		},
+		"PausedAndParked": {
+			reason: "Managed resources that are paused, or that are parked at their current generation, should be counted",
+			c: &test.MockClient{MockList: test.NewMockListFn(nil, func(o runtime.Object) error {
+				o.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{
+					paused(withConditions(condition("Ready", "True"), condition("Synced", "True"))),
+					paused(parked(2, 2)),
+					parked(3, 3),
+					parked(4, 3),
+				}
+				return nil
+			})},
+			want: want{
+				exists: 4,
+				ready:  map[string]float64{"True": 1, "False": 0, "Unknown": 3},
+				synced: map[string]float64{"True": 1, "False": 3, "Unknown": 0},
+				paused: 2,
+				parked: 2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMRStateMetrics()
+			r := NewMRStateRecorder(tc.c, m, kind)
+			err := r.Record(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\nr.Record(...): %s\n-want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.exists, testutil.ToFloat64(m.Exists.WithLabelValues(gvk))); diff != "" {
+				t.Errorf("\nr.Record(...): %s\n-want exists, +got exists:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.paused, testutil.ToFloat64(m.Paused.WithLabelValues(gvk))); diff != "" {
+				t.Errorf("\nr.Record(...): %s\n-want paused, +got paused:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.parked, testutil.ToFloat64(m.Parked.WithLabelValues(gvk))); diff != "" {
+				t.Errorf("\nr.Record(...): %s\n-want parked, +got parked:\n%s", tc.reason, diff)
+			}
+			for st, n := range tc.want.ready {
+				if diff := cmp.Diff(n, testutil.ToFloat64(m.Ready.WithLabelValues(gvk, st))); diff != "" {
+					t.Errorf("\nr.Record(...): %s\n-want ready %s, +got ready %s:\n%s", tc.reason, st, st, diff)
+				}
+			}
+			for st, n := range tc.want.synced {
+				if diff := cmp.Diff(n, testutil.ToFloat64(m.Synced.WithLabelValues(gvk, st))); diff != "" {
+					t.Errorf("\nr.Record(...): %s\n-want synced %s, +got synced %s:\n%s", tc.reason, st, st, diff)
+				}
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package test
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -52,3 +53,15 @@ func EquateErrors() cmp.Option {
 func EquateConditions() cmp.Option {
 	return cmpopts.SortSlices(func(i, j corev1alpha1.Condition) bool { return i.Type < j.Type })
 }
+
+// EquateConditionsWithin sorts any slices of Condition before comparing them,
+// and considers conditions to be equal if their LastTransitionTimes differ by
+// no more than the supplied tolerance. By default conditions are compared
+// ignoring their LastTransitionTimes entirely.
+func EquateConditionsWithin(tolerance time.Duration) cmp.Option {
+	return cmp.Options{
+		EquateConditions(),
+		cmp.Comparer(func(a, b corev1alpha1.Condition) bool { return a.EqualWithin(b, tolerance) }),
+		cmp.Comparer(func(a, b corev1alpha1.ConditionedStatus) bool { return a.EqualWithin(&b, tolerance) }),
+	}
+}
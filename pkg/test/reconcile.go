@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// DefaultReconcileSteps is the number of times RunUntil will invoke a
+// Reconciler before giving up if no budget is specified.
+const DefaultReconcileSteps = 10
+
+// Error strings.
+const (
+	errGetObject        = "cannot get reconciled object"
+	errFmtStepsExceeded = "predicate not satisfied after %d reconciles"
+)
+
+// A ReconcileStep records the outcome of a single invocation of a Reconciler.
+type ReconcileStep struct {
+	Result reconcile.Result
+	Err    error
+}
+
+// A ReconcilePredicate returns true if the supplied object, as read back from
+// a client after a reconcile, is in the desired state.
+type ReconcilePredicate func(obj runtime.Object) bool
+
+// A RunUntilOption configures RunUntil.
+type RunUntilOption func(*runUntil)
+
+type runUntil struct {
+	steps  int
+	ignore func(error) bool
+}
+
+// WithSteps specifies the maximum number of times RunUntil will invoke the
+// Reconciler before giving up.
+func WithSteps(n int) RunUntilOption {
+	return func(r *runUntil) {
+		r.steps = n
+	}
+}
+
+// WithIgnoreReconcileErrors causes RunUntil to continue reconciling when the
+// Reconciler returns an error that satisfies the supplied function. By default
+// RunUntil stops at the first error returned by the Reconciler.
+func WithIgnoreReconcileErrors(fn func(error) bool) RunUntilOption {
+	return func(r *runUntil) {
+		r.ignore = fn
+	}
+}
+
+// RunUntil repeatedly invokes the supplied Reconciler with the supplied
+// Request, reading the reconciled object back into obj using the supplied
+// client after each pass, until the supplied predicate holds. It returns the
+// sequence of reconcile results observed, including the final one. An error is
+// returned if the Reconciler returns an error, if the object cannot be read,
+// or if the predicate does not hold once the step budget is exhausted. The
+// client is typically the same (fake) client used by the Reconciler, which
+// allows multi-pass behaviours such as adding a finalizer, then creating an
+// external resource, then becoming ready to be verified in a single test.
+func RunUntil(r reconcile.Reconciler, c client.Reader, req reconcile.Request, obj runtime.Object, until ReconcilePredicate, o ...RunUntilOption) ([]ReconcileStep, error) {
+	ru := &runUntil{
+		steps:  DefaultReconcileSteps,
+		ignore: func(error) bool { return false },
+	}
+	for _, fn := range o {
+		fn(ru)
+	}
+
+	steps := make([]ReconcileStep, 0, ru.steps)
+	for i := 0; i < ru.steps; i++ {
+		result, err := r.Reconcile(req)
+		steps = append(steps, ReconcileStep{Result: result, Err: err})
+		if err != nil && !ru.ignore(err) {
+			return steps, err
+		}
+
+		if err := c.Get(context.Background(), req.NamespacedName, obj); err != nil {
+			return steps, errors.Wrap(err, errGetObject)
+		}
+
+		if until(obj) {
+			return steps, nil
+		}
+	}
+
+	return steps, errors.Errorf(errFmtStepsExceeded, ru.steps)
+}
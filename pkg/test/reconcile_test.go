@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestRunUntil(t *testing.T) {
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}
+
+	// A fake reconciler that labels an object with the number of times it
+	// has been invoked, and a client that reads that label back.
+	passes := func(n *int) reconcile.Reconciler {
+		return reconcile.Func(func(_ reconcile.Request) (reconcile.Result, error) {
+			*n++
+			return reconcile.Result{Requeue: true}, nil
+		})
+	}
+	reader := func(n *int) client.Reader {
+		return &MockClient{MockGet: NewMockGetFn(nil, func(obj runtime.Object) error {
+			obj.(*corev1.ConfigMap).SetLabels(map[string]string{"passes": strconv.Itoa(*n)})
+			return nil
+		})}
+	}
+	after := func(passes string) ReconcilePredicate {
+		return func(obj runtime.Object) bool {
+			return obj.(*corev1.ConfigMap).GetLabels()["passes"] == passes
+		}
+	}
+
+	type args struct {
+		r     func(n *int) reconcile.Reconciler
+		c     func(n *int) client.Reader
+		until ReconcilePredicate
+		o     []RunUntilOption
+	}
+	type want struct {
+		steps []ReconcileStep
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"PredicateSatisfied": {
+			reason: "RunUntil should stop reconciling once the predicate is satisfied.",
+			args: args{
+				r:     passes,
+				c:     reader,
+				until: after("3"),
+			},
+			want: want{
+				steps: []ReconcileStep{
+					{Result: reconcile.Result{Requeue: true}},
+					{Result: reconcile.Result{Requeue: true}},
+					{Result: reconcile.Result{Requeue: true}},
+				},
+			},
+		},
+		"StepsExceeded": {
+			reason: "RunUntil should return an error if the predicate is not satisfied within the step budget.",
+			args: args{
+				r:     passes,
+				c:     reader,
+				until: after("3"),
+				o:     []RunUntilOption{WithSteps(2)},
+			},
+			want: want{
+				steps: []ReconcileStep{
+					{Result: reconcile.Result{Requeue: true}},
+					{Result: reconcile.Result{Requeue: true}},
+				},
+				err: errors.Errorf(errFmtStepsExceeded, 2),
+			},
+		},
+		"ReconcileError": {
+			reason: "RunUntil should stop at the first error returned by the Reconciler.",
+			args: args{
+				r: func(_ *int) reconcile.Reconciler {
+					return reconcile.Func(func(_ reconcile.Request) (reconcile.Result, error) {
+						return reconcile.Result{}, errBoom
+					})
+				},
+				c:     reader,
+				until: after("1"),
+			},
+			want: want{
+				steps: []ReconcileStep{{Err: errBoom}},
+				err:   errBoom,
+			},
+		},
+		"IgnoredReconcileError": {
+			reason: "RunUntil should continue reconciling after an ignored error.",
+			args: args{
+				r: func(n *int) reconcile.Reconciler {
+					return reconcile.Func(func(_ reconcile.Request) (reconcile.Result, error) {
+						*n++
+						return reconcile.Result{}, errBoom
+					})
+				},
+				c:     reader,
+				until: after("2"),
+				o:     []RunUntilOption{WithIgnoreReconcileErrors(func(err error) bool { return errors.Is(err, errBoom) })},
+			},
+			want: want{
+				steps: []ReconcileStep{{Err: errBoom}, {Err: errBoom}},
+			},
+		},
+		"GetError": {
+			reason: "RunUntil should return an error if the reconciled object cannot be read.",
+			args: args{
+				r:     passes,
+				c:     func(_ *int) client.Reader { return &MockClient{MockGet: NewMockGetFn(errBoom)} },
+				until: after("1"),
+			},
+			want: want{
+				steps: []ReconcileStep{{Result: reconcile.Result{Requeue: true}}},
+				err:   errors.Wrap(errBoom, errGetObject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n := 0
+			got, err := RunUntil(tc.args.r(&n), tc.args.c(&n), req, &corev1.ConfigMap{}, tc.args.until, tc.args.o...)
+			if diff := cmp.Diff(tc.want.err, err, EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunUntil(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.steps, got, EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunUntil(...): -want steps, +got steps:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap provisions, distributes, and rotates the TLS certificates
+// used to serve admission and conversion webhooks.
+package bootstrap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/certificates"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// Defaults.
+const (
+	DefaultValidity    = 365 * 24 * time.Hour
+	DefaultRenewBefore = 30 * 24 * time.Hour
+	DefaultInterval    = 1 * time.Hour
+)
+
+// Error strings.
+const (
+	errGenerateKey       = "cannot generate private key"
+	errCreateCertificate = "cannot create certificate"
+	errMarshalKey        = "cannot marshal private key"
+	errParseCertificate  = "cannot parse certificate"
+	errGetSecret         = "cannot get certificate secret"
+	errCreateSecret      = "cannot create certificate secret"
+	errUpdateSecret      = "cannot update certificate secret"
+	errWriteCertificate  = "cannot write serving certificate"
+	errWriteKey          = "cannot write serving key"
+	errMkdir             = "cannot create certificate directory"
+	errGetConfig         = "cannot get webhook configuration"
+	errInjectCABundle    = "cannot inject CA bundle"
+	errUpdateConfig      = "cannot update webhook configuration"
+)
+
+// Kinds of object into which a CA bundle may be injected.
+var (
+	MutatingWebhookConfigurationKind = schema.GroupVersionKind{
+		Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration",
+	}
+	ValidatingWebhookConfigurationKind = schema.GroupVersionKind{
+		Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration",
+	}
+	CustomResourceDefinitionKind = schema.GroupVersionKind{
+		Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition",
+	}
+)
+
+// Generate a self-signed CA, and a serving certificate for the supplied DNS
+// names signed by that CA. Both are valid for the supplied duration.
+func Generate(dnsNames []string, validity time.Duration) (*certificates.Material, error) {
+	now := time.Now()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateKey)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          serial(),
+		Subject:               pkix.Name{CommonName: "crossplane-webhook-ca"},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateCertificate)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, errGenerateKey)
+	}
+	cn := ""
+	if len(dnsNames) > 0 {
+		cn = dnsNames[0]
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial(),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, caTmpl, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateCertificate)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalKey)
+	}
+
+	return &certificates.Material{
+		CA:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+func serial() *big.Int {
+	// A random 128 bit serial number, per RFC 5280's recommendation that
+	// serial numbers be unpredictable. We fall back to the current time in
+	// the unlikely event we can't read random data.
+	s, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return s
+}
+
+// expiresWithin returns true if the serving certificate of the supplied
+// material cannot be parsed, or expires within the supplied duration.
+func expiresWithin(m *certificates.Material, d time.Duration) bool {
+	b, _ := pem.Decode(m.Cert)
+	if b == nil {
+		return true
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return true
+	}
+	return time.Now().Add(d).After(c.NotAfter)
+}
+
+// A Bootstrapper ensures a webhook server has a valid serving certificate. The
+// certificate and the CA that signed it are stored in a Secret, so that all
+// replicas of a provider serve the same certificate, and written to the
+// webhook server's certificate directory. The CA is injected into the webhook
+// configurations and CRDs that refer to the webhook server.
+type Bootstrapper struct {
+	client   client.Client
+	secret   types.NamespacedName
+	dir      string
+	dnsNames []string
+
+	validity    time.Duration
+	renewBefore time.Duration
+	interval    time.Duration
+	inject      []injection
+
+	log logging.Logger
+}
+
+// An injection identifies the webhook client configs of an object into which
+// a CA bundle should be injected. Client configs that do not exist are not
+// created.
+type injection struct {
+	kind         schema.GroupVersionKind
+	name         string
+	clientConfig string
+}
+
+// A BootstrapperOption configures a Bootstrapper.
+type BootstrapperOption func(*Bootstrapper)
+
+// WithLogger specifies how the Bootstrapper should log messages.
+func WithLogger(l logging.Logger) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.log = l
+	}
+}
+
+// WithValidity specifies how long generated certificates are valid for.
+func WithValidity(d time.Duration) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.validity = d
+	}
+}
+
+// WithRenewBefore specifies how long before it expires a certificate should be
+// replaced.
+func WithRenewBefore(d time.Duration) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.renewBefore = d
+	}
+}
+
+// WithInterval specifies how frequently a started Bootstrapper should check
+// whether its certificate needs to be replaced.
+func WithInterval(d time.Duration) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.interval = d
+	}
+}
+
+// WithMutatingWebhookConfiguration injects the CA bundle into every webhook of
+// the named MutatingWebhookConfiguration.
+func WithMutatingWebhookConfiguration(name string) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.inject = append(b.inject, injection{kind: MutatingWebhookConfigurationKind, name: name, clientConfig: "webhooks[*].clientConfig"})
+	}
+}
+
+// WithValidatingWebhookConfiguration injects the CA bundle into every webhook
+// of the named ValidatingWebhookConfiguration.
+func WithValidatingWebhookConfiguration(name string) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.inject = append(b.inject, injection{kind: ValidatingWebhookConfigurationKind, name: name, clientConfig: "webhooks[*].clientConfig"})
+	}
+}
+
+// WithConversionWebhook injects the CA bundle into the conversion webhook
+// client config of the named CustomResourceDefinition.
+func WithConversionWebhook(crd string) BootstrapperOption {
+	return func(b *Bootstrapper) {
+		b.inject = append(b.inject, injection{kind: CustomResourceDefinitionKind, name: crd, clientConfig: "spec.conversion.webhookClientConfig"})
+	}
+}
+
+// NewBootstrapper returns a Bootstrapper that stores certificates for the
+// supplied DNS names in the supplied Secret, and writes them to the supplied
+// directory. The directory is typically the CertDir of a controller-runtime
+// webhook.Server, which reloads its serving certificate when it changes.
+func NewBootstrapper(c client.Client, secret types.NamespacedName, dir string, dnsNames []string, o ...BootstrapperOption) *Bootstrapper {
+	b := &Bootstrapper{
+		client:      c,
+		secret:      secret,
+		dir:         dir,
+		dnsNames:    dnsNames,
+		validity:    DefaultValidity,
+		renewBefore: DefaultRenewBefore,
+		interval:    DefaultInterval,
+		log:         logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(b)
+	}
+	return b
+}
+
+// Bootstrap ensures a valid serving certificate exists, generating a new one
+// if none exists or the existing one is due to expire, then writes it to disk
+// and injects its CA bundle. Bootstrap must be called before the webhook
+// server is started, because the server requires its certificate to exist.
+func (b *Bootstrapper) Bootstrap(ctx context.Context) error {
+	m, err := b.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.write(m); err != nil {
+		return err
+	}
+	for _, i := range b.inject {
+		if err := b.injectCABundle(ctx, i, m.CA); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start bootstrapping at the Bootstrapper's interval until the supplied
+// channel is closed, rotating the serving certificate before it expires.
+// Start satisfies controller-runtime's manager.Runnable interface.
+func (b *Bootstrapper) Start(stop <-chan struct{}) error {
+	t := time.NewTicker(b.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+			if err := b.Bootstrap(context.Background()); err != nil {
+				b.log.Info("Cannot bootstrap webhook serving certificate", "error", err)
+			}
+		}
+	}
+}
+
+func (b *Bootstrapper) ensureSecret(ctx context.Context) (*certificates.Material, error) {
+	s := &corev1.Secret{}
+	err := b.client.Get(ctx, b.secret, s)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, errGetSecret)
+	}
+	exists := err == nil
+
+	if exists {
+		m := &certificates.Material{CA: s.Data[certificates.KeyCA], Cert: s.Data[certificates.KeyCert], Key: s.Data[certificates.KeyKey]}
+		if !expiresWithin(m, b.renewBefore) {
+			return m, nil
+		}
+	}
+
+	m, err := Generate(b.dnsNames, b.validity)
+	if err != nil {
+		return nil, err
+	}
+	b.log.Debug("Generated webhook serving certificate", "secret", b.secret)
+
+	s.SetNamespace(b.secret.Namespace)
+	s.SetName(b.secret.Name)
+	s.Type = corev1.SecretTypeTLS
+	s.Data = map[string][]byte{certificates.KeyCA: m.CA, certificates.KeyCert: m.Cert, certificates.KeyKey: m.Key}
+
+	if !exists {
+		return m, errors.Wrap(b.client.Create(ctx, s), errCreateSecret)
+	}
+	return m, errors.Wrap(b.client.Update(ctx, s), errUpdateSecret)
+}
+
+func (b *Bootstrapper) write(m *certificates.Material) error {
+	if err := os.MkdirAll(b.dir, 0700); err != nil {
+		return errors.Wrap(err, errMkdir)
+	}
+	// We write the key before the certificate so that a webhook server that
+	// reloads when its certificate changes finds a matching key.
+	if err := ioutil.WriteFile(filepath.Join(b.dir, certificates.KeyKey), m.Key, 0600); err != nil {
+		return errors.Wrap(err, errWriteKey)
+	}
+	return errors.Wrap(ioutil.WriteFile(filepath.Join(b.dir, certificates.KeyCert), m.Cert, 0600), errWriteCertificate)
+}
+
+func (b *Bootstrapper) injectCABundle(ctx context.Context, i injection, ca []byte) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(i.kind)
+	if err := b.client.Get(ctx, types.NamespacedName{Name: i.name}, u); err != nil {
+		return errors.Wrap(err, errGetConfig)
+	}
+
+	p := fieldpath.Pave(u.UnstructuredContent())
+	paths, err := p.ExpandWildcards(i.clientConfig)
+	if err != nil {
+		return errors.Wrap(err, errInjectCABundle)
+	}
+	bundle := base64.StdEncoding.EncodeToString(ca)
+	for _, path := range paths {
+		if err := p.SetString(path+".caBundle", bundle); err != nil {
+			return errors.Wrap(err, errInjectCABundle)
+		}
+	}
+	return errors.Wrap(b.client.Update(ctx, u), errUpdateConfig)
+}
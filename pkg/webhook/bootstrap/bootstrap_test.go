@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/certificates"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestGenerate(t *testing.T) {
+	m, err := Generate([]string{"webhook.crossplane-system.svc"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(m.CA) {
+		t.Fatalf("Generate(...): cannot parse CA certificate")
+	}
+	b, _ := pem.Decode(m.Cert)
+	if b == nil {
+		t.Fatalf("Generate(...): cannot decode serving certificate")
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate(...): %v", err)
+	}
+	if _, err := c.Verify(x509.VerifyOptions{DNSName: "webhook.crossplane-system.svc", Roots: pool}); err != nil {
+		t.Errorf("c.Verify(...): the serving certificate should be valid for the supplied DNS name and signed by the CA: %v", err)
+	}
+
+	if expiresWithin(m, time.Minute) {
+		t.Errorf("expiresWithin(...): a certificate valid for an hour should not expire within a minute")
+	}
+	if !expiresWithin(m, 2*time.Hour) {
+		t.Errorf("expiresWithin(...): a certificate valid for an hour should expire within two hours")
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	errBoom := errors.New("boom")
+	secret := types.NamespacedName{Namespace: "crossplane-system", Name: "webhook-tls"}
+	dns := []string{"webhook.crossplane-system.svc"}
+
+	valid, err := Generate(dns, DefaultValidity)
+	if err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+	expiring, err := Generate(dns, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+
+	withSecret := func(m *certificates.Material) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *corev1.Secret:
+				o.Data = map[string][]byte{certificates.KeyCA: m.CA, certificates.KeyCert: m.Cert, certificates.KeyKey: m.Key}
+			case *unstructured.Unstructured:
+				o.Object["webhooks"] = []interface{}{
+					map[string]interface{}{"name": "a", "clientConfig": map[string]interface{}{}},
+					map[string]interface{}{"name": "b", "clientConfig": map[string]interface{}{}},
+				}
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		err           error
+		secretCreated bool
+		secretUpdated bool
+		injected      []byte
+	}
+
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		want   want
+	}{
+		"GetSecretError": {
+			reason: "Errors getting the certificate secret should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want: want{
+				err: errors.Wrap(errBoom, errGetSecret),
+			},
+		},
+		"NoSecret": {
+			reason: "A certificate should be generated and stored if none exists",
+			get: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					return kerrors.NewNotFound(schema.GroupResource{}, "")
+				}
+				return withSecret(valid)(obj)
+			},
+			want: want{
+				secretCreated: true,
+			},
+		},
+		"ValidSecret": {
+			reason: "An existing certificate that is not due to expire should be used as is",
+			get:    test.NewMockGetFn(nil, withSecret(valid)),
+			want: want{
+				injected: valid.CA,
+			},
+		},
+		"ExpiringSecret": {
+			reason: "An existing certificate that is due to expire should be replaced",
+			get:    test.NewMockGetFn(nil, withSecret(expiring)),
+			want: want{
+				secretUpdated: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "bootstrap")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir(...): %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			got := want{}
+			var stored *corev1.Secret
+			c := &test.MockClient{
+				MockGet: tc.get,
+				MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+					got.secretCreated = true
+					stored = obj.(*corev1.Secret)
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					switch o := obj.(type) {
+					case *corev1.Secret:
+						got.secretUpdated = true
+						stored = o
+					case *unstructured.Unstructured:
+						for _, w := range o.Object["webhooks"].([]interface{}) {
+							b64 := w.(map[string]interface{})["clientConfig"].(map[string]interface{})["caBundle"].(string)
+							ca, err := base64.StdEncoding.DecodeString(b64)
+							if err != nil {
+								t.Errorf("cannot decode CA bundle: %v", err)
+							}
+							got.injected = ca
+						}
+					}
+					return nil
+				},
+			}
+
+			b := NewBootstrapper(c, secret, dir, dns, WithMutatingWebhookConfiguration("crossplane"))
+			err = b.Bootstrap(context.Background())
+			got.err = err
+
+			// A newly generated CA should be injected.
+			if stored != nil {
+				tc.want.injected = stored.Data[certificates.KeyCA]
+			}
+
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nb.Bootstrap(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			cert, err := ioutil.ReadFile(filepath.Join(dir, certificates.KeyCert))
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile(...): %v", err)
+			}
+			if stored != nil && !cmp.Equal(stored.Data[certificates.KeyCert], cert) {
+				t.Errorf("\n%s\nb.Bootstrap(...): the stored certificate should be written to disk", tc.reason)
+			}
+		})
+	}
+}
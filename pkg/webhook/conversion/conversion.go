@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion serves CRD conversion webhooks for managed resources
+// that are served at multiple API versions.
+package conversion
+
+import (
+	"net/http"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Path at which the conversion webhook is served. CRDs that use the webhook
+// conversion strategy should specify this path in their webhook client config.
+const Path = "/convert"
+
+// Error strings.
+const (
+	errCheckConvertible  = "cannot determine whether kind is convertible"
+	errFmtNotConvertible = "%T is not convertible: one version of its kind must be a conversion.Hub and all others conversion.Convertible"
+	errInjectScheme      = "cannot inject scheme into conversion webhook"
+)
+
+// Setup registers a conversion webhook with the supplied manager's webhook
+// server. See Register.
+func Setup(mgr manager.Manager, kinds ...runtime.Object) error {
+	return Register(mgr.GetWebhookServer(), mgr.GetScheme(), kinds...)
+}
+
+// Register a conversion webhook with the supplied webhook server. The webhook
+// converts between versions of any kind registered with the supplied scheme by
+// converting to and from the kind's hub version. One version of each kind must
+// implement conversion.Hub, and all other versions conversion.Convertible. An
+// error is returned if any of the supplied kinds, which may be of any version,
+// do not meet these requirements. Register is a no-op if a webhook is already
+// registered at Path, so it may be called once for each kind of managed
+// resource a provider serves.
+func Register(srv *webhook.Server, s *runtime.Scheme, kinds ...runtime.Object) error {
+	for _, k := range kinds {
+		ok, err := conversion.IsConvertible(s, k)
+		if err != nil {
+			return errors.Wrap(err, errCheckConvertible)
+		}
+		if !ok {
+			return errors.Errorf(errFmtNotConvertible, k)
+		}
+	}
+
+	if registered(srv, Path) {
+		return nil
+	}
+
+	wh := &conversion.Webhook{}
+	if err := wh.InjectScheme(s); err != nil {
+		return errors.Wrap(err, errInjectScheme)
+	}
+	srv.Register(Path, wh)
+	return nil
+}
+
+func registered(srv *webhook.Server, path string) bool {
+	if srv.WebhookMux == nil {
+		return false
+	}
+	h, p := srv.WebhookMux.Handler(&http.Request{URL: &url.URL{Path: path}})
+	return h != nil && p == path
+}
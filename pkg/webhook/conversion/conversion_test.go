@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type hub struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+}
+
+func (h *hub) DeepCopyObject() runtime.Object { c := *h; return &c }
+func (h *hub) Hub()                           {}
+
+type spoke struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+}
+
+func (s *spoke) DeepCopyObject() runtime.Object     { c := *s; return &c }
+func (s *spoke) ConvertTo(_ conversion.Hub) error   { return nil }
+func (s *spoke) ConvertFrom(_ conversion.Hub) error { return nil }
+
+type lonely struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+}
+
+func (l *lonely) DeepCopyObject() runtime.Object { c := *l; return &c }
+
+func TestRegister(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}, &spoke{})
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "example.org", Version: "v2", Kind: "Cool"}, &hub{})
+	s.AddKnownTypeWithName(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Lonely"}, &lonely{})
+
+	type args struct {
+		srv   *webhook.Server
+		kinds []runtime.Object
+	}
+	type want struct {
+		err        error
+		registered bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotConvertible": {
+			reason: "An error should be returned if a kind is not convertible",
+			args: args{
+				srv:   &webhook.Server{},
+				kinds: []runtime.Object{&lonely{}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNotConvertible, &lonely{}),
+			},
+		},
+		"Registered": {
+			reason: "A conversion webhook should be registered for convertible kinds",
+			args: args{
+				srv:   &webhook.Server{},
+				kinds: []runtime.Object{&spoke{}, &hub{}},
+			},
+			want: want{
+				registered: true,
+			},
+		},
+		"AlreadyRegistered": {
+			reason: "Registering a conversion webhook when one is already registered should be a no-op",
+			args: args{
+				srv: func() *webhook.Server {
+					srv := &webhook.Server{}
+					srv.Register(Path, http.NotFoundHandler())
+					return srv
+				}(),
+				kinds: []runtime.Object{&spoke{}},
+			},
+			want: want{
+				registered: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Register(tc.args.srv, s, tc.args.kinds...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRegister(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.registered, registered(tc.args.srv, Path)); diff != "" {
+				t.Errorf("\n%s\nRegister(...): -want registered, +got registered:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package defaulting serves mutating admission webhooks that set default
+// values on managed resources.
+package defaulting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errDecode  = "cannot decode object"
+	errEncode  = "cannot encode defaulted object"
+	errDefault = "cannot set default values"
+)
+
+// A Defaulter sets default values on a managed resource.
+type Defaulter interface {
+	Default(ctx context.Context, u *unstructured.Unstructured) error
+}
+
+// A DefaulterFn is a function that satisfies the Defaulter interface.
+type DefaulterFn func(ctx context.Context, u *unstructured.Unstructured) error
+
+// Default calls DefaulterFn.
+func (fn DefaulterFn) Default(ctx context.Context, u *unstructured.Unstructured) error {
+	return fn(ctx, u)
+}
+
+// ExternalNameFromName sets the external name annotation of a managed resource
+// to its name, unless the external name is already set. It is the admission
+// time equivalent of the managed.NameAsExternalName Initializer. Managed
+// resources created with a generated name are not defaulted, because their
+// name is not generated until after admission.
+func ExternalNameFromName() DefaulterFn {
+	return func(_ context.Context, u *unstructured.Unstructured) error {
+		if meta.GetExternalName(u) != "" || u.GetName() == "" {
+			return nil
+		}
+		meta.SetExternalName(u, u.GetName())
+		return nil
+	}
+}
+
+// A Handler sets default values on managed resources using a series of
+// Defaulters when they are created or updated.
+type Handler struct {
+	defaulters []Defaulter
+}
+
+// NewHandler returns a Handler that applies the supplied Defaulters in order.
+func NewHandler(d ...Defaulter) *Handler {
+	return &Handler{defaulters: d}
+}
+
+// Handle the supplied admission request.
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecode))
+	}
+
+	for _, d := range h.defaulters {
+		if err := d.Default(ctx, u); err != nil {
+			return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errDefault))
+		}
+	}
+
+	defaulted, err := json.Marshal(u)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errEncode))
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, defaulted)
+}
+
+// Path returns the path at which the defaulting webhook for the supplied kind
+// of managed resource is served. It matches the path used by controller-runtime,
+// e.g. /mutate-example-org-v1-bucket.
+func Path(of resource.ManagedKind) string {
+	return "/mutate-" + strings.Replace(of.Group, ".", "-", -1) + "-" + of.Version + "-" + strings.ToLower(of.Kind)
+}
+
+// Register a defaulting webhook for the supplied kind of managed resource with
+// the supplied webhook server, for example:
+//
+//	defaulting.Register(srv, bucketKind, defaulting.ExternalNameFromName())
+func Register(srv *webhook.Server, of resource.ManagedKind, d ...Defaulter) {
+	srv.Register(Path(of), &admission.Webhook{Handler: NewHandler(d...)})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaulting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+func TestExternalNameFromName(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		u      *unstructured.Unstructured
+		want   string
+	}{
+		"NoExternalName": {
+			reason: "The external name should be defaulted to the name",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{}
+				u.SetName("cool")
+				return u
+			}(),
+			want: "cool",
+		},
+		"ExternalNameSet": {
+			reason: "An existing external name should not be overwritten",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{}
+				u.SetName("cool")
+				meta.SetExternalName(u, "cooler")
+				return u
+			}(),
+			want: "cooler",
+		},
+		"GeneratedName": {
+			reason: "A resource whose name is yet to be generated should not be defaulted",
+			u: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{}
+				u.SetGenerateName("cool-")
+				return u
+			}(),
+			want: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := ExternalNameFromName().Default(context.Background(), tc.u); err != nil {
+				t.Fatalf("Default(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, meta.GetExternalName(tc.u)); diff != "" {
+				t.Errorf("\n%s\nDefault(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+	raw := []byte(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"name":"cool"}}`)
+
+	type want struct {
+		allowed bool
+		patches []string
+	}
+	cases := map[string]struct {
+		reason string
+		d      []Defaulter
+		req    admission.Request
+		want   want
+	}{
+		"Delete": {
+			reason: "Operations other than creates and updates should be allowed without modification",
+			d:      []Defaulter{ExternalNameFromName()},
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Delete,
+			}},
+			want: want{allowed: true},
+		},
+		"DefaultError": {
+			reason: "Errors setting default values should be returned",
+			d: []Defaulter{DefaulterFn(func(_ context.Context, _ *unstructured.Unstructured) error {
+				return errBoom
+			})},
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}},
+			want: want{allowed: false},
+		},
+		"Defaulted": {
+			reason: "Default values should be returned as a patch",
+			d:      []Defaulter{ExternalNameFromName()},
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}},
+			want: want{allowed: true, patches: []string{"/metadata/annotations"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rsp := NewHandler(tc.d...).Handle(context.Background(), tc.req)
+			got := want{allowed: rsp.Allowed}
+			for _, p := range rsp.Patches {
+				got.patches = append(got.patches, p.Path)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nh.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
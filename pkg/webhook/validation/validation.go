@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation serves validating admission webhooks that enforce
+// invariants common to managed resources.
+package validation
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errDecodeOld     = "cannot decode existing object"
+	errDecodeUpdated = "cannot decode updated object"
+
+	errExternalNameImmutable = "the external name annotation cannot be changed once set"
+	errFmtFieldImmutable     = "field %s is immutable once set"
+	errSpecWhileDeleting     = "spec cannot be changed while the resource is being deleted"
+)
+
+// A Validator validates an update to a managed resource. It returns an error
+// explaining why the update should be denied, if it should be.
+type Validator interface {
+	ValidateUpdate(ctx context.Context, old, updated *unstructured.Unstructured) error
+}
+
+// A ValidatorFn is a function that satisfies the Validator interface.
+type ValidatorFn func(ctx context.Context, old, updated *unstructured.Unstructured) error
+
+// ValidateUpdate calls ValidatorFn.
+func (fn ValidatorFn) ValidateUpdate(ctx context.Context, old, updated *unstructured.Unstructured) error {
+	return fn(ctx, old, updated)
+}
+
+// ImmutableExternalName denies updates that change or remove the external name
+// annotation of a managed resource once it has been set.
+func ImmutableExternalName() ValidatorFn {
+	return func(_ context.Context, old, updated *unstructured.Unstructured) error {
+		if en := meta.GetExternalName(old); en != "" && meta.GetExternalName(updated) != en {
+			return errors.New(errExternalNameImmutable)
+		}
+		return nil
+	}
+}
+
+// ImmutableFields denies updates that change or remove the value at any of the
+// supplied field paths, for example spec.forProvider.region, once a value has
+// been set.
+func ImmutableFields(paths ...string) ValidatorFn {
+	return func(_ context.Context, old, updated *unstructured.Unstructured) error {
+		op, np := fieldpath.Pave(old.Object), fieldpath.Pave(updated.Object)
+		for _, p := range paths {
+			ov, err := op.GetValue(p)
+			if err != nil {
+				// The field was not set, and may thus be set by this update.
+				continue
+			}
+			nv, _ := np.GetValue(p)
+			if !reflect.DeepEqual(ov, nv) {
+				return errors.Errorf(errFmtFieldImmutable, p)
+			}
+		}
+		return nil
+	}
+}
+
+// NoSpecUpdatesWhileDeleting denies updates that change the spec of a managed
+// resource once its deletion has been requested. Other updates, for example
+// the removal of finalizers, are allowed.
+func NoSpecUpdatesWhileDeleting() ValidatorFn {
+	return func(_ context.Context, old, updated *unstructured.Unstructured) error {
+		if !meta.WasDeleted(old) {
+			return nil
+		}
+		if !reflect.DeepEqual(old.Object["spec"], updated.Object["spec"]) {
+			return errors.New(errSpecWhileDeleting)
+		}
+		return nil
+	}
+}
+
+// A Handler validates updates to managed resources using a series of
+// Validators. Operations other than updates are always allowed.
+type Handler struct {
+	validators []Validator
+}
+
+// NewHandler returns a Handler that denies any update that any of the supplied
+// Validators reject.
+func NewHandler(v ...Validator) *Handler {
+	return &Handler{validators: v}
+}
+
+// Handle the supplied admission request.
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	old := &unstructured.Unstructured{}
+	if err := old.UnmarshalJSON(req.OldObject.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOld))
+	}
+	updated := &unstructured.Unstructured{}
+	if err := updated.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeUpdated))
+	}
+
+	for _, v := range h.validators {
+		if err := v.ValidateUpdate(ctx, old, updated); err != nil {
+			return admission.Denied(err.Error())
+		}
+	}
+	return admission.Allowed("")
+}
+
+// Path returns the path at which the validating webhook for the supplied kind
+// of managed resource is served. It matches the path used by controller-runtime,
+// e.g. /validate-example-org-v1-bucket.
+func Path(of resource.ManagedKind) string {
+	return "/validate-" + strings.Replace(of.Group, ".", "-", -1) + "-" + of.Version + "-" + strings.ToLower(of.Kind)
+}
+
+// Register a validating webhook for the supplied kind of managed resource with
+// the supplied webhook server. Each kind of managed resource may be validated
+// by different Validators, for example:
+//
+//	validation.Register(srv, bucketKind,
+//		validation.ImmutableExternalName(),
+//		validation.ImmutableFields("spec.forProvider.region"),
+//		validation.NoSpecUpdatesWhileDeleting(),
+//	)
+func Register(srv *webhook.Server, of resource.ManagedKind, v ...Validator) {
+	srv.Register(Path(of), &admission.Webhook{Handler: NewHandler(v...)})
+}
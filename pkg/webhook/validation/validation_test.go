@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func object(j string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON([]byte(j)); err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestValidators(t *testing.T) {
+	type args struct {
+		v   Validator
+		old *unstructured.Unstructured
+		new *unstructured.Unstructured
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"ExternalNameSet": {
+			reason: "Setting the external name annotation should be allowed",
+			args: args{
+				v:   ImmutableExternalName(),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"name":"cool"}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"name":"cool","annotations":{"crossplane.io/external-name":"cool"}}}`),
+			},
+		},
+		"ExternalNameChanged": {
+			reason: "Changing the external name annotation once set should be denied",
+			args: args{
+				v:   ImmutableExternalName(),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"name":"cool","annotations":{"crossplane.io/external-name":"cool"}}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"name":"cool","annotations":{"crossplane.io/external-name":"lame"}}}`),
+			},
+			want: errors.New(errExternalNameImmutable),
+		},
+		"ImmutableFieldSet": {
+			reason: "Setting an immutable field that was not previously set should be allowed",
+			args: args{
+				v:   ImmutableFields("spec.forProvider.region"),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{"region":"us-west-2"}}}`),
+			},
+		},
+		"ImmutableFieldUnchanged": {
+			reason: "Updates that do not change an immutable field should be allowed",
+			args: args{
+				v:   ImmutableFields("spec.forProvider.region"),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{"region":"us-west-2","size":1}}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{"region":"us-west-2","size":2}}}`),
+			},
+		},
+		"ImmutableFieldChanged": {
+			reason: "Changing an immutable field once set should be denied",
+			args: args{
+				v:   ImmutableFields("spec.forProvider.region"),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{"region":"us-west-2"}}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{"region":"us-east-1"}}}`),
+			},
+			want: errors.Errorf(errFmtFieldImmutable, "spec.forProvider.region"),
+		},
+		"ImmutableFieldRemoved": {
+			reason: "Removing an immutable field once set should be denied",
+			args: args{
+				v:   ImmutableFields("spec.forProvider.region"),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{"region":"us-west-2"}}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"forProvider":{}}}`),
+			},
+			want: errors.Errorf(errFmtFieldImmutable, "spec.forProvider.region"),
+		},
+		"FinalizerRemovedWhileDeleting": {
+			reason: "Updates that do not change the spec of a resource being deleted should be allowed",
+			args: args{
+				v:   NoSpecUpdatesWhileDeleting(),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"deletionTimestamp":"2019-01-01T00:00:00Z","finalizers":["cool"]},"spec":{"size":1}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"deletionTimestamp":"2019-01-01T00:00:00Z"},"spec":{"size":1}}`),
+			},
+		},
+		"SpecChangedWhileDeleting": {
+			reason: "Updates that change the spec of a resource being deleted should be denied",
+			args: args{
+				v:   NoSpecUpdatesWhileDeleting(),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"deletionTimestamp":"2019-01-01T00:00:00Z"},"spec":{"size":1}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"deletionTimestamp":"2019-01-01T00:00:00Z"},"spec":{"size":2}}`),
+			},
+			want: errors.New(errSpecWhileDeleting),
+		},
+		"SpecChanged": {
+			reason: "Updates that change the spec of a resource that is not being deleted should be allowed",
+			args: args{
+				v:   NoSpecUpdatesWhileDeleting(),
+				old: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"size":1}}`),
+				new: object(`{"kind":"Cool","apiVersion":"example.org/v1","spec":{"size":2}}`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.v.ValidateUpdate(context.Background(), tc.args.old, tc.args.new)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	old := []byte(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"annotations":{"crossplane.io/external-name":"cool"}}}`)
+	updated := []byte(`{"kind":"Cool","apiVersion":"example.org/v1","metadata":{"annotations":{"crossplane.io/external-name":"lame"}}}`)
+
+	type want struct {
+		allowed bool
+		reason  string
+	}
+	cases := map[string]struct {
+		reason string
+		req    admission.Request
+		want   want
+	}{
+		"Create": {
+			reason: "Operations other than updates should be allowed",
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: updated},
+			}},
+			want: want{allowed: true},
+		},
+		"Denied": {
+			reason: "Updates that a validator rejects should be denied",
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Update,
+				OldObject: runtime.RawExtension{Raw: old},
+				Object:    runtime.RawExtension{Raw: updated},
+			}},
+			want: want{allowed: false, reason: errExternalNameImmutable},
+		},
+		"Allowed": {
+			reason: "Updates that no validator rejects should be allowed",
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Update,
+				OldObject: runtime.RawExtension{Raw: old},
+				Object:    runtime.RawExtension{Raw: old},
+			}},
+			want: want{allowed: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(ImmutableExternalName())
+			rsp := h.Handle(context.Background(), tc.req)
+			got := want{allowed: rsp.Allowed}
+			if rsp.Result != nil {
+				got.reason = string(rsp.Result.Reason)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nh.Handle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	of := resource.ManagedKind(schema.GroupVersionKind{Group: "storage.example.org", Version: "v1beta1", Kind: "Bucket"})
+	want := "/validate-storage-example-org-v1beta1-bucket"
+	if diff := cmp.Diff(want, Path(of)); diff != "" {
+		t.Errorf("\nPath(...): -want, +got:\n%s", diff)
+	}
+}